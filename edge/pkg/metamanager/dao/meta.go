@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -102,6 +103,24 @@ func QueryMeta(key string, condition string) (*[]string, error) {
 	return &result, nil
 }
 
+// QueryMetaWithContext is QueryMeta which returns the error of ctx once ctx is done
+func QueryMetaWithContext(ctx context.Context, key string, condition string) (*[]string, error) {
+	meta := new([]Meta)
+	_, err := dbm.DBAccess.QueryTable(MetaTableName).Filter(key, condition).AllWithCtx(ctx, meta)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, v := range *meta {
+		result = append(result, v.Value)
+	}
+	return &result, nil
+}
+
 // QueryAllMeta return all meta, if no error, Meta not null
 func QueryAllMeta(key string, condition string) (*[]Meta, error) {
 	meta := new([]Meta)
@@ -112,3 +131,17 @@ func QueryAllMeta(key string, condition string) (*[]Meta, error) {
 
 	return meta, nil
 }
+
+// QueryAllMetaWithContext is QueryAllMeta which returns the error of ctx once ctx is done
+func QueryAllMetaWithContext(ctx context.Context, key string, condition string) (*[]Meta, error) {
+	meta := new([]Meta)
+	_, err := dbm.DBAccess.QueryTable(MetaTableName).Filter(key, condition).AllWithCtx(ctx, meta)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
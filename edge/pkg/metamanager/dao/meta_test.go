@@ -17,6 +17,7 @@ limitations under the License.
 package dao
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
@@ -430,6 +431,58 @@ func TestQueryMeta(t *testing.T) {
 	}
 }
 
+// TestQueryMetaWithContext is function to test QueryMetaWithContext
+func TestQueryMetaWithContext(t *testing.T) {
+	//Initialize Global Variables (Mocks)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	ormerMock := beego.NewMockOrmer(mockCtrl)
+	querySeterMock := beego.NewMockQuerySeter(mockCtrl)
+	dbm.DBAccess = ormerMock
+
+	fakeDao := []Meta{{Key: "Test"}}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cases := []struct {
+		name         string
+		ctx          context.Context
+		allReturnErr error
+		expectedErr  error
+	}{{
+		name: "SuccessCase",
+		ctx:  context.Background(),
+	}, {
+		name:         "FailureCase",
+		ctx:          context.Background(),
+		allReturnErr: errFailedDBOperation,
+		expectedErr:  errFailedDBOperation,
+	}, {
+		name:         "CanceledCase",
+		ctx:          canceled,
+		allReturnErr: errFailedDBOperation,
+		expectedErr:  context.Canceled,
+	},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			querySeterMock.EXPECT().AllWithCtx(test.ctx, gomock.Any()).SetArg(1, fakeDao).Return(int64(1), test.allReturnErr).Times(1)
+			querySeterMock.EXPECT().Filter(gomock.Any(), gomock.Any()).Return(querySeterMock).Times(1)
+			ormerMock.EXPECT().QueryTable(gomock.Any()).Return(querySeterMock).Times(1)
+			meta, err := QueryMetaWithContext(test.ctx, "test", "test")
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("Query Meta With Context Case Failed : wanted error %v and got error %v", test.expectedErr, err)
+				return
+			}
+
+			if err == nil && len(*meta) != 1 {
+				t.Errorf("Query Meta With Context Case failed: wanted length 1 and got length %v", len(*meta))
+			}
+		})
+	}
+}
+
 // TestQueryAllMeta is function to test QueryAllMeta
 func TestQueryAllMeta(t *testing.T) {
 	//Initialize Global Variables (Mocks)
@@ -533,3 +586,55 @@ func TestIsNonUniqueNameError(t *testing.T) {
 		})
 	}
 }
+
+// TestQueryAllMetaWithContext is function to test QueryAllMetaWithContext
+func TestQueryAllMetaWithContext(t *testing.T) {
+	//Initialize Global Variables (Mocks)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	ormerMock := beego.NewMockOrmer(mockCtrl)
+	querySeterMock := beego.NewMockQuerySeter(mockCtrl)
+	dbm.DBAccess = ormerMock
+
+	fakeDao := []Meta{{Key: "Test"}}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cases := []struct {
+		name         string
+		ctx          context.Context
+		allReturnErr error
+		expectedErr  error
+	}{{
+		name: "SuccessCase",
+		ctx:  context.Background(),
+	}, {
+		name:         "FailureCase",
+		ctx:          context.Background(),
+		allReturnErr: errFailedDBOperation,
+		expectedErr:  errFailedDBOperation,
+	}, {
+		name:         "CanceledCase",
+		ctx:          canceled,
+		allReturnErr: errFailedDBOperation,
+		expectedErr:  context.Canceled,
+	},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			querySeterMock.EXPECT().AllWithCtx(test.ctx, gomock.Any()).SetArg(1, fakeDao).Return(int64(1), test.allReturnErr).Times(1)
+			querySeterMock.EXPECT().Filter(gomock.Any(), gomock.Any()).Return(querySeterMock).Times(1)
+			ormerMock.EXPECT().QueryTable(gomock.Any()).Return(querySeterMock).Times(1)
+			meta, err := QueryAllMetaWithContext(test.ctx, "test", "test")
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("Query All Meta With Context Case Failed : wanted error %v and got error %v", test.expectedErr, err)
+				return
+			}
+
+			if err == nil && len(*meta) != 1 {
+				t.Errorf("Query All Meta With Context Case failed: wanted length 1 and got length %v", len(*meta))
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ package cmd
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/debug"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/edge"
 )
 
@@ -67,6 +68,7 @@ func NewKubeedgeCommand() *cobra.Command {
 	// recommended cmds
 	cmds.AddCommand(edge.NewEdgeJoin())
 	cmds.AddCommand(NewKubeEdgeReset())
+	cmds.AddCommand(debug.NewEdgeDebug())
 
 	// beta cmds
 	//cmds.AddCommand(edge.NewEdgeUpgrade())
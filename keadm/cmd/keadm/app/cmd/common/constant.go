@@ -16,6 +16,8 @@ limitations under the License.
 
 package common
 
+import "time"
+
 // Common flag names
 const (
 	// FlagNameForce force install
@@ -146,6 +148,14 @@ const (
 	CmdPing             = "ping %s -w %d |grep 'packets transmitted' |awk '{print $6}'"
 	CmdGetMaxProcessNum = "sysctl kernel.pid_max|awk '{print $3}'"
 	CmdGetProcessNum    = "ps -A|wc -l"
+	// CmdGetTimeSyncProcess gets the running time synchronization process, the command name is truncated to 15 characters by ps
+	CmdGetTimeSyncProcess = "ps -A -o comm= | grep -E '^(ntpd|chronyd|systemd-timesyn)' | head -n1"
+	// CmdShowEdgecoreUnit gets the state and the restart count of the edgecore systemd unit
+	CmdShowEdgecoreUnit = "systemctl show edgecore.service -p LoadState,ActiveState,SubState,UnitFileState,NRestarts,ActiveEnterTimestampMonotonic"
+	// CmdShowKubeletUnit gets the state of the kubelet systemd unit, which conflicts with edged
+	CmdShowKubeletUnit = "systemctl show kubelet.service -p LoadState,ActiveState,UnitFileState"
+	// CmdGetEdgecoreJournal gets the messages of the edgecore systemd unit since the time, which are the klog lines of edgecore
+	CmdGetEdgecoreJournal = "journalctl -u edgecore.service --no-pager -o cat --since '%s'"
 
 	EdgecoreConfig = "config"
 
@@ -197,8 +207,49 @@ const (
 	ArgDiagnosePod  = "pod"
 	DescDiagnosePod = "Diagnose pod"
 
+	ArgDiagnoseDeployment  = "deployment"
+	DescDiagnoseDeployment = "Diagnose the pods of a deployment"
+
+	ArgDiagnoseWorkload  = "workload"
+	DescDiagnoseWorkload = "Diagnose the pods of a deployment, statefulset, daemonset or job"
+
+	ArgDiagnoseCluster  = "cluster"
+	DescDiagnoseCluster = "Diagnose the edge nodes and their pods of the cluster from the cloud side"
+
+	ArgDiagnoseDevice  = "device"
+	DescDiagnoseDevice = "Diagnose whether the twins of a device are synced"
+
 	ArgDiagnoseInstall  = "install"
 	DescDiagnoseInstall = "Diagnose install"
+
+	ArgDiagnoseModule  = "module"
+	DescDiagnoseModule = "Diagnose edgecore modules"
+
+	ArgDiagnoseJoin  = "join"
+	DescDiagnoseJoin = "Diagnose the join token of a node which is not joined yet"
+
+	ArgDiagnoseAll  = "all"
+	DescDiagnoseAll = "Diagnose install, edge node and all the pods in one shot"
+
+	DiagnoseOutputText = "text"
+	DiagnoseOutputJSON = "json"
+	DiagnoseOutputYAML = "yaml"
+	// DiagnoseOutputJSONL streams every result as a json object on its own line as soon as it is recorded
+	DiagnoseOutputJSONL = "jsonl"
+	// DiagnoseOutputGoTemplate executes the go template set by --template on the report
+	DiagnoseOutputGoTemplate = "go-template"
+
+	DiagnoseStatusPass = "pass"
+	DiagnoseStatusWarn = "warn"
+	DiagnoseStatusFail = "fail"
+	// DiagnoseStatusSkip is the status of a check which is not run, e.g. its module is disabled, its message is the reason
+	DiagnoseStatusSkip = "skip"
+
+	// DiagnoseOverallHealthy, DiagnoseOverallDegraded and DiagnoseOverallUnhealthy are the overall statuses of a report,
+	// which is unhealthy if any check fails, degraded if any check warns but none fails, and healthy otherwise
+	DiagnoseOverallHealthy   = "healthy"
+	DiagnoseOverallDegraded  = "degraded"
+	DiagnoseOverallUnhealthy = "unhealthy"
 	/****/
 
 	ArgCheckAll     = "all"
@@ -210,6 +261,37 @@ const (
 	ArgCheckRuntime = "runtime"
 	ArgCheckNetwork = "network"
 	ArgCheckPID     = "pid"
+	ArgCheckCert    = "cert"
+
+	// DefaultMinCPU, DefaultMinMemory and DefaultMinDisk are the default minimum logical cores, memory in MB
+	// and disk in MB of the node in the cpu, memory and disk checks
+	DefaultMinCPU    = AllowedValueCPU
+	DefaultMinMemory = AllowedValueMemory / MB
+	DefaultMinDisk   = AllowedValueDisk / MB
+
+	// DefaultMinDiskFree is the default minimum free space in MB of the filesystems holding the edgecore data and logs
+	DefaultMinDiskFree = AllowedCurrentValueDisk / MB
+
+	// DefaultMaxClockSkew is the default max clock skew between the edge node and cloudcore
+	DefaultMaxClockSkew = 60 * time.Second
+
+	// DefaultMaxLatency is the default max tls handshake time and first byte latency to the cloudhub server
+	DefaultMaxLatency = time.Second
+
+	// DefaultLogWindow is the default window of the recent panics and fatal errors in the edgecore logs
+	DefaultLogWindow = time.Hour
+
+	// DefaultMinMTU is the default min path MTU to the cloudhub server, which is the min MTU of ipv6
+	DefaultMinMTU = 1280
+
+	// DefaultCertWarnDays is the default number of days before the certificate expiry to warn in the certificate check
+	DefaultCertWarnDays = 30
+
+	// IPFamilyIPv4, IPFamilyIPv6 and IPFamilyDual are the ip families of the network checks,
+	// both families are tried with dual, the same as the dial of edgecore
+	IPFamilyIPv4 = "ipv4"
+	IPFamilyIPv6 = "ipv6"
+	IPFamilyDual = "dual"
 
 	KB = 1024
 	MB = KB * 1024
@@ -224,6 +306,9 @@ const (
 	AllowedCurrentValueMemRate  = 0.9
 	AllowedCurrentValueDiskRate = 0.9
 
+	// WarnCurrentValueDiskRate is the disk usage rate above which the disk check warns
+	WarnCurrentValueDiskRate = 0.8
+
 	AllowedCurrentValueMem  = 128 * MB
 	AllowedCurrentValueDisk = 512 * MB
 )
@@ -273,10 +358,38 @@ var (
 			Use:  ArgDiagnosePod,
 			Desc: DescDiagnosePod,
 		},
+		{
+			Use:  ArgDiagnoseDeployment,
+			Desc: DescDiagnoseDeployment,
+		},
+		{
+			Use:  ArgDiagnoseWorkload,
+			Desc: DescDiagnoseWorkload,
+		},
+		{
+			Use:  ArgDiagnoseDevice,
+			Desc: DescDiagnoseDevice,
+		},
+		{
+			Use:  ArgDiagnoseCluster,
+			Desc: DescDiagnoseCluster,
+		},
 		{
 			Use:  ArgDiagnoseInstall,
 			Desc: DescDiagnoseInstall,
 		},
+		{
+			Use:  ArgDiagnoseModule,
+			Desc: DescDiagnoseModule,
+		},
+		{
+			Use:  ArgDiagnoseJoin,
+			Desc: DescDiagnoseJoin,
+		},
+		{
+			Use:  ArgDiagnoseAll,
+			Desc: DescDiagnoseAll,
+		},
 	}
 
 	// DefaultKubeConfig is the default path of kubeconfig
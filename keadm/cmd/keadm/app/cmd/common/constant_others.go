@@ -25,4 +25,7 @@ const (
 	KubeEdgeSocketPath  = "/var/lib/kubeedge/"
 	EdgeRootDir         = "/var/lib/edged"
 	SystemdBootPath     = "/run/systemd/system"
+
+	// CmdGetEdgecoreVersion gets the version of the edgecore binary, which is installed in /usr/local/bin by keadm
+	CmdGetEdgecoreVersion = "edgecore --version 2>/dev/null || /usr/local/bin/edgecore --version"
 )
@@ -29,6 +29,11 @@ const (
 	KubeEdgeLogPath     = "C:\\var\\log\\kubeedge\\"
 	KubeEdgeSocketPath  = "C:\\var\\lib\\kubeedge\\"
 	EdgeRootDir         = "C:\\var\\lib\\edged"
+
+	// CmdGetEdgecoreVersion gets the version of the edgecore binary, which is installed in C:\usr\local\bin by keadm
+	CmdGetEdgecoreVersion = "& 'C:\\usr\\local\\bin\\edgecore.exe' --version"
+	// CmdGetProcessList lists the running processes by tasklist, one process per line in the csv format without the header
+	CmdGetProcessList = "tasklist /NH /FO CSV"
 )
 
 func init() {
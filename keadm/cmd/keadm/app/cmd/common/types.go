@@ -19,6 +19,7 @@ package common
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/blang/semver"
 )
@@ -109,6 +110,50 @@ type CheckOptions struct {
 	CloudHubServer string
 	EdgecoreServer string
 	Config         string
+	CertWarnDays   int
+	// MinCPU, MinMemory and MinDisk are the minimum logical cores, memory in MB and disk of the first partition in MB of the node
+	MinCPU    int
+	MinMemory int
+	MinDisk   int
+	// MinDiskFree is the minimum free space in MB of the filesystems holding the edgecore data and logs
+	MinDiskFree int
+	// CNIConfDir and CNIBinDir are the CNI config dir and the comma-separated CNI plugin dirs of the container runtime
+	CNIConfDir string
+	CNIBinDir  string
+	// EdgecoreLogFile is the log file of edgecore, the journal of the edgecore unit is read if it is not exists
+	EdgecoreLogFile string
+	// LogWindow is the window of the recent panics and fatal errors in the edgecore logs
+	LogWindow time.Duration
+	// Registries are the image registries checked for reachability, the registries of the images of the cached pods are checked if empty
+	Registries []string
+	// MaxClockSkew is the max clock skew between the edge node and cloudcore
+	MaxClockSkew time.Duration
+	// ExpectedTZ is the time zone the node is expected to be in, e.g. UTC, the time zone is only reported if it is empty
+	ExpectedTZ string
+	// MaxLatency is the max tls handshake time and first byte latency to the cloudhub server
+	MaxLatency time.Duration
+	// MinMTU is the min path MTU to the cloudhub server
+	MinMTU int
+
+	// InsecureSkipTLSVerify skips the verification of the server certificates in the http checks
+	InsecureSkipTLSVerify bool
+	// Proxy is the proxy of the http checks, the HTTP_PROXY, HTTPS_PROXY and NO_PROXY env vars are used if not set
+	Proxy string
+	// NoFollowRedirects fails the http checks if the servers answer with a redirect instead of following it
+	NoFollowRedirects bool
+	// Retries is the number of attempts of the network connectivity checks, with exponential backoff between attempts
+	Retries int
+	// Verbose prints the result of each attempt of the network connectivity checks
+	Verbose bool
+	// KernelModules are the kernel modules which must be loaded or built into the kernel
+	KernelModules []string
+	// Sysctls are the sysctls in the form of key=value which must have the values
+	Sysctls []string
+	// IPFamily is the ip family the network checks resolve and dial the servers with, one of ipv4, ipv6 and dual
+	IPFamily string
+	// Only and Skip select the checks to run by name, all the checks are run if neither is set
+	Only []string
+	Skip []string
 }
 
 type CheckObject struct {
@@ -143,11 +188,78 @@ type DiagnoseOptions struct {
 	Config       string
 	CheckOptions *CheckOptions
 	DBPath       string
+	Output       string
+	// Template is the go template executed on the report in the go-template output format
+	Template string
+	// Explain records the remediation hints of the failed checks in the results and prints them in text mode
+	Explain bool
+	// ReportFile is the file which the diagnose report is written to in addition to stdout
+	ReportFile string
+	// MetricsFile is the file which the results are written to in the prometheus text format
+	MetricsFile string
+	// ScoreWeights overrides the weights of the checks by name in the health score of the report
+	ScoreWeights map[string]int
+
+	LabelSelector string
+	// UID is the metadata.uid of the cached pod to diagnose instead of a pod name, which tells the recreated pods of a name apart
+	UID string
+	// WarningsAsErrors fails the diagnose if any check warns
+	WarningsAsErrors bool
+	// AllNamespaces enumerates the pods of all the namespaces in the edge database instead of the pods of Namespace
+	AllNamespaces bool
+	FailFast      bool
+	// MQTTConnect sends a CONNECT packet to the mqtt brokers besides the tcp connection check
+	MQTTConnect bool
+	// Watch re-runs the node diagnose every WatchInterval until interrupted
+	Watch         bool
+	WatchInterval time.Duration
+	// Since is the window of the recent container restarts, the older ones are reported as stabilized
+	Since time.Duration
+	// Logs prints the last TailLines lines of the logs of the containers which are not ready
+	Logs      bool
+	TailLines int
+	// Host is the ssh destination of the remote node to diagnose, the local node is diagnosed if it is empty
+	Host string
+	// Container is the name of the container edgecore runs in, which is found by the container runtime of RuntimeEndpoint,
+	// the process and file checks are run in the container instead of the host if it is set
+	Container       string
+	RuntimeEndpoint string
+	// Token is the join token diagnosed by the join diagnose, which is checked with the cloudcore of CloudCoreIPPort
+	// and the certificate port CertPort if CloudCoreIPPort is set
+	Token           string
+	CloudCoreIPPort string
+	CertPort        string
+	// Live compares the pods in the edge database with the pods in the apiserver reached by KubeConfig
+	Live       bool
+	KubeConfig string
+	// Verbose prints the extra details of the checks, Quiet prints only the final result of the diagnose
+	Verbose bool
+	Quiet   bool
+	// DryRun prints the checks the diagnose would run and their targets without running them
+	DryRun bool
+	// Object is the object RunDiagnostics diagnoses, e.g. node or pod, and Args are the names of the object,
+	// which are the args of the diagnose command
+	Object string
+	Args   []string
+}
+
+// DiagnoseResult is the result of a single diagnose check
+type DiagnoseResult struct {
+	Check  string `json:"check"`
+	Status string `json:"status"`
+	// Code is the code of the error of a failed check, e.g. NETWORK_UNREACHABLE
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+	Detail  string `json:"detail,omitempty"`
+	// ElapsedMs is the duration of the check in milliseconds, it is only recorded for the checks which are run
+	ElapsedMs int64 `json:"elapsedMs,omitempty"`
+	// Hint is the remediation hint of a failed check, it is only recorded in explain mode
+	Hint string `json:"hint,omitempty"`
 }
 
 type DiagnoseObject struct {
-	Desc string
-	Use  string
+	Desc string `json:"desc"`
+	Use  string `json:"use"`
 }
 
 // BatchProcessOptions has the kubeedge batch process information filled by CLI
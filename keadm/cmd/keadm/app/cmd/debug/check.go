@@ -1,24 +1,42 @@
 package debug
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/gorilla/websocket"
+	"github.com/lucas-clemente/quic-go"
 	"github.com/shirou/gopsutil/cpu"
 	"github.com/shirou/gopsutil/disk"
 	"github.com/shirou/gopsutil/mem"
+	gonet "github.com/shirou/gopsutil/v3/net"
+	"github.com/shirou/gopsutil/v3/process"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/trace/noop"
+	internalapi "k8s.io/cri-api/pkg/apis"
+	"k8s.io/kubernetes/pkg/kubelet/cri/remote"
 
 	"github.com/kubeedge/api/apis/common/constants"
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/util/files"
 )
 
 var (
@@ -36,6 +54,9 @@ and maintenance personnel to locate the problem`
         # Check whether the node memory meets  requirements.
         keadm debug check mem
 
+        # Check whether the node has at least 2 cores and 1024 MB of memory.
+        keadm debug check all --min-cpu 2 --min-memory 1024
+
         # check whether the node disk meets  requirements.
         keadm debug check disk
 
@@ -53,8 +74,175 @@ and maintenance personnel to locate the problem`
 `
 )
 
+const (
+	// defaultHTTPTimeout is the timeout in seconds of CheckHTTP if no timeout is specified
+	defaultHTTPTimeout = 3
+	// maxHTTPRedirects is the max number of the redirects CheckHTTP follows
+	maxHTTPRedirects = 10
+	// criAPIVersion is the CRI api version sent in the Version request, the same as kubelet
+	criAPIVersion = "0.1.0"
+)
+
+const (
+	// systemdFlapRestarts is the restart count of the edgecore unit, with which the unit is flapping
+	// if it has been active for less than systemdFlapWindow
+	systemdFlapRestarts = 3
+	systemdFlapWindow   = 10 * time.Minute
+)
+
+// retryBackoff is the wait before the second attempt of a network check, it doubles after each attempt
+var retryBackoff = time.Second
+
+// cpuPercent, cpuCounts and virtualMemory read the cpu and the memory of the node
+var (
+	cpuPercent    = cpu.Percent
+	cpuCounts     = cpu.Counts
+	virtualMemory = mem.VirtualMemory
+)
+
 type CheckObject common.CheckObject
 
+// HTTPCheckOptions is the options of CheckHTTP
+type HTTPCheckOptions struct {
+	// Timeout is the timeout in seconds of the request
+	Timeout int
+	// InsecureSkipTLSVerify skips the verification of the server certificate
+	InsecureSkipTLSVerify bool
+	// CAFile is the CA certificate to verify the server certificate, the system roots are used if empty
+	CAFile string
+	// Proxy is the proxy of the requests, which overrides the HTTP_PROXY and HTTPS_PROXY env vars if set
+	Proxy string
+	// NoFollowRedirects fails the request if the server answers with a redirect instead of following it
+	NoFollowRedirects bool
+	// Retries is the number of attempts of the checks in CheckNetWork, one attempt is made if it is not positive
+	Retries int
+	// Verbose prints the result of each attempt
+	Verbose bool
+	// IPFamily is the ip family the servers are dialed with, either family is dialed if it is empty or dual
+	IPFamily string
+}
+
+// noFollowRedirectsUsage is the usage of the --no-follow-redirects flag of the network checks
+const noFollowRedirectsUsage = "fail the http checks if the servers answer with a redirect instead of following it, e.g. to assert cloudhub is not behind a redirecting gateway"
+
+// NewHTTPCheckOptions returns the options of CheckHTTP from the check options
+func NewHTTPCheckOptions(ob *common.CheckOptions) HTTPCheckOptions {
+	return HTTPCheckOptions{
+		Timeout:               ob.Timeout,
+		InsecureSkipTLSVerify: ob.InsecureSkipTLSVerify,
+		Proxy:                 ob.Proxy,
+		NoFollowRedirects:     ob.NoFollowRedirects,
+		Retries:               ob.Retries,
+		Verbose:               ob.Verbose,
+		IPFamily:              ob.IPFamily,
+	}
+}
+
+// proxyFunc returns the proxy function of the requests, the local addresses are never proxied
+func (opts HTTPCheckOptions) proxyFunc() func(*http.Request) (*url.URL, error) {
+	if opts.Proxy == "" {
+		return http.ProxyFromEnvironment
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		host := req.URL.Hostname()
+		if ip := net.ParseIP(host); host == "localhost" || ip != nil && ip.IsLoopback() {
+			return nil, nil
+		}
+		return url.Parse(opts.Proxy)
+	}
+}
+
+// timeout returns the timeout in seconds of the requests, the default timeout is used if opts.Timeout is not positive
+func (opts HTTPCheckOptions) timeout() int {
+	if opts.Timeout <= 0 {
+		return defaultHTTPTimeout
+	}
+	return opts.Timeout
+}
+
+// newHTTPClient returns the http client of the checks, which verifies the servers, proxies and dials as the options
+func (opts HTTPCheckOptions) newHTTPClient() (*http.Client, error) {
+	tlsConfig, err := opts.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	return opts.httpClient(tlsConfig), nil
+}
+
+// httpClient returns the http client of the checks with the tls config, which proxies and dials as the options
+func (opts HTTPCheckOptions) httpClient(tlsConfig *tls.Config) *http.Client {
+	httpTransport := &http.Transport{TLSClientConfig: tlsConfig, Proxy: opts.proxyFunc(), DialContext: opts.dialContext()}
+	return &http.Client{Transport: httpTransport, Timeout: time.Duration(opts.timeout()) * time.Second}
+}
+
+// tlsConfig returns the tls config which verifies the server certificate with the CA
+func (opts HTTPCheckOptions) tlsConfig() (*tls.Config, error) {
+	pool, err := opts.rootCAs()
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{InsecureSkipVerify: opts.InsecureSkipTLSVerify, RootCAs: pool}, nil
+}
+
+// rootCAs returns the pool of opts.CAFile, or nil to use the system roots if the CA is not specified or not verified
+func (opts HTTPCheckOptions) rootCAs() (*x509.CertPool, error) {
+	if opts.InsecureSkipTLSVerify || opts.CAFile == "" {
+		return nil, nil
+	}
+	caCert, err := os.ReadFile(opts.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf(" read CA certificate %s failed: %v", opts.CAFile, err)
+	}
+	pool := x509.NewCertPool()
+	if ok := pool.AppendCertsFromPEM(caCert); !ok {
+		return nil, fmt.Errorf(" cannot parse the CA certificate %s", opts.CAFile)
+	}
+	return pool, nil
+}
+
+// retry runs check up to opts.Retries times until it succeeds, waiting with exponential backoff between the attempts,
+// the error of the last attempt is returned if all the attempts fail.
+func (opts HTTPCheckOptions) retry(w io.Writer, name string, check func() error) error {
+	attempts := opts.Retries
+	if attempts <= 0 {
+		attempts = 1
+	}
+	backoff := retryBackoff
+	var err error
+	for i := 1; i <= attempts; i++ {
+		if err = check(); err == nil {
+			if opts.Verbose {
+				fmt.Fprintf(w, "%s attempt %d/%d succeeded\n", name, i, attempts)
+			}
+			return nil
+		}
+		if opts.Verbose {
+			fmt.Fprintf(w, "%s attempt %d/%d failed, %v\n", name, i, attempts, err)
+		}
+		if i < attempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	if attempts > 1 {
+		return fmt.Errorf("%v (after %d attempts)", err, attempts)
+	}
+	return err
+}
+
+// viaProxy returns " via proxy <proxy>" if the request of rawURL goes through a proxy, otherwise ""
+func (opts HTTPCheckOptions) viaProxy(rawURL string) string {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return ""
+	}
+	proxy, err := opts.proxyFunc()(req)
+	if err != nil || proxy == nil {
+		return ""
+	}
+	return " via proxy " + proxy.Redacted()
+}
+
 // NewCheck returns KubeEdge edge check command.
 func NewCheck() *cobra.Command {
 	cmd := &cobra.Command{
@@ -87,14 +275,34 @@ func NewSubEdgeCheck(object CheckObject) *cobra.Command {
 		cmd.Flags().StringVarP(&co.DNSIP, "dns-ip", "D", co.DNSIP, "specify test dns ip")
 		cmd.Flags().StringVarP(&co.Config, common.EdgecoreConfig, "c", co.Config,
 			fmt.Sprintf("Specify configuration file, default is %s", constants.EdgecoreConfigPath))
+		cmd.Flags().IntVar(&co.Retries, "retries", co.Retries,
+			"the number of attempts of the network connectivity checks, with exponential backoff between attempts")
+		cmd.Flags().BoolVar(&co.Verbose, "verbose", co.Verbose, "print the result of each attempt of the network connectivity checks")
+		cmd.Flags().StringVar(&co.IPFamily, "ip-family", co.IPFamily, ipFamilyUsage)
+		cmd.Flags().BoolVar(&co.NoFollowRedirects, "no-follow-redirects", co.NoFollowRedirects, noFollowRedirectsUsage)
+		cmd.Flags().IntVar(&co.MinCPU, "min-cpu", co.MinCPU, "fail the cpu check if the node has less logical cores")
+		cmd.Flags().IntVar(&co.MinMemory, "min-memory", co.MinMemory, "fail the memory check if the node has less memory in MB")
+		cmd.Flags().IntVar(&co.MinDisk, "min-disk", co.MinDisk, "fail the disk check if the first disk partition of the node is smaller in MB")
+	case common.ArgCheckCPU:
+		cmd.Flags().IntVar(&co.MinCPU, "min-cpu", co.MinCPU, "fail the cpu check if the node has less logical cores")
+	case common.ArgCheckMemory:
+		cmd.Flags().IntVar(&co.MinMemory, "min-memory", co.MinMemory, "fail the memory check if the node has less memory in MB")
+	case common.ArgCheckDisk:
+		cmd.Flags().IntVar(&co.MinDisk, "min-disk", co.MinDisk, "fail the disk check if the first disk partition of the node is smaller in MB")
 	case common.ArgCheckDNS:
 		cmd.Flags().StringVarP(&co.Domain, "domain", "d", co.Domain, "specify test domain")
 		cmd.Flags().StringVarP(&co.DNSIP, "dns-ip", "D", co.DNSIP, "specify test dns ip")
+		cmd.Flags().StringVar(&co.IPFamily, "ip-family", co.IPFamily, ipFamilyUsage)
 	case common.ArgCheckNetwork:
 		cmd.Flags().StringVarP(&co.IP, "ip", "i", co.IP, "specify test ip")
 		cmd.Flags().StringVarP(&co.CloudHubServer, "cloud-hub-server", "s", co.CloudHubServer, "specify cloudhub server")
 		cmd.Flags().StringVarP(&co.Config, common.EdgecoreConfig, "c", co.Config,
 			fmt.Sprintf("Specify configuration file, default is %s", constants.EdgecoreConfigPath))
+		cmd.Flags().IntVar(&co.Retries, "retries", co.Retries,
+			"the number of attempts of the network connectivity checks, with exponential backoff between attempts")
+		cmd.Flags().BoolVar(&co.Verbose, "verbose", co.Verbose, "print the result of each attempt of the network connectivity checks")
+		cmd.Flags().StringVar(&co.IPFamily, "ip-family", co.IPFamily, ipFamilyUsage)
+		cmd.Flags().BoolVar(&co.NoFollowRedirects, "no-follow-redirects", co.NoFollowRedirects, noFollowRedirectsUsage)
 	}
 
 	return cmd
@@ -105,6 +313,13 @@ func NewCheckOptions() *common.CheckOptions {
 	co := &common.CheckOptions{}
 	co.Domain = "www.github.com"
 	co.Timeout = 1
+	// the check command has never verified the server certificates
+	co.InsecureSkipTLSVerify = true
+	co.Retries = 1
+	co.IPFamily = common.IPFamilyDual
+	co.MinCPU = common.DefaultMinCPU
+	co.MinMemory = common.DefaultMinMemory
+	co.MinDisk = common.DefaultMinDisk
 	return co
 }
 
@@ -118,22 +333,23 @@ func (co *CheckObject) ExecuteCheck(use string, ob *common.CheckOptions) {
 
 	switch use {
 	case common.ArgCheckAll:
-		err = CheckAll(ob)
+		err = CheckAll(os.Stdout, ob)
 	case common.ArgCheckCPU:
-		err = CheckCPU()
+		err = CheckCPU(os.Stdout, ob.MinCPU)
 	case common.ArgCheckMemory:
-		err = CheckMemory()
+		err = CheckMemory(os.Stdout, ob.MinMemory)
 	case common.ArgCheckDisk:
-		err = CheckDisk()
+		err = CheckDisk(os.Stdout, ob.MinDisk)
 	case common.ArgCheckDNS:
-		err = CheckDNSSpecify(ob.Domain, ob.DNSIP)
+		err = CheckDNSSpecify(os.Stdout, ob.Domain, ob.DNSIP, ob.IPFamily)
 	case common.ArgCheckNetwork:
-		err = CheckNetWork(ob.IP, ob.Timeout, ob.CloudHubServer, ob.EdgecoreServer, ob.Config)
+		err = CheckNetWork(os.Stdout, ob.IP, NewHTTPCheckOptions(ob), ob.CloudHubServer, ob.EdgecoreServer, ob.Config)
 	case common.ArgCheckRuntime:
 		err = CheckRuntime()
 	case common.ArgCheckPID:
-		err = CheckPid()
+		err = CheckPid(os.Stdout)
 	}
+	err = printWarning(os.Stdout, err)
 
 	if err != nil {
 		fmt.Println(err)
@@ -143,33 +359,34 @@ func (co *CheckObject) ExecuteCheck(use string, ob *common.CheckOptions) {
 	}
 }
 
-func CheckAll(ob *common.CheckOptions) error {
-	err := CheckCPU()
+// CheckAll runs all checks, the check progress is written to w
+func CheckAll(w io.Writer, ob *common.CheckOptions) error {
+	err := CheckCPU(w, ob.MinCPU)
 	if err != nil {
 		return err
 	}
 
-	err = CheckMemory()
+	err = CheckMemory(w, ob.MinMemory)
 	if err != nil {
 		return err
 	}
 
-	err = CheckDisk()
+	err = printWarning(w, CheckDisk(w, ob.MinDisk))
 	if err != nil {
 		return err
 	}
 
-	err = CheckDNSSpecify(ob.Domain, ob.DNSIP)
+	err = CheckDNSSpecify(w, ob.Domain, ob.DNSIP, ob.IPFamily)
 	if err != nil {
 		return err
 	}
 
-	err = CheckNetWork(ob.IP, ob.Timeout, ob.CloudHubServer, ob.EdgecoreServer, ob.Config)
+	err = CheckNetWork(w, ob.IP, NewHTTPCheckOptions(ob), ob.CloudHubServer, ob.EdgecoreServer, ob.Config)
 	if err != nil {
 		return err
 	}
 
-	err = CheckPid()
+	err = CheckPid(w)
 	if err != nil {
 		return err
 	}
@@ -181,38 +398,40 @@ func CheckAll(ob *common.CheckOptions) error {
 	return nil
 }
 
-func CheckCPU() error {
-	percent, err := cpu.Percent(time.Second, false)
+// CheckCPU checks the node has at least minCPU logical cores and the cpu is not overloaded
+func CheckCPU(w io.Writer, minCPU int) error {
+	percent, err := cpuPercent(time.Second, false)
 	if err != nil {
 		return err
 	}
 
-	cpuNum, err := cpu.Counts(true)
+	cpuNum, err := cpuCounts(true)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("CPU total: %v core, Allowed > %v core\n", cpuNum, common.AllowedValueCPU)
-	fmt.Printf("CPU usage rate: %.2f, Allowed rate < %v\n", percent[0]/100, common.AllowedCurrentValueCPURate)
+	fmt.Fprintf(w, "CPU total: %v core, Allowed > %v core\n", cpuNum, minCPU)
+	fmt.Fprintf(w, "CPU usage rate: %.2f, Allowed rate < %v\n", percent[0]/100, common.AllowedCurrentValueCPURate)
 
-	if cpuNum < common.AllowedValueCPU || percent[0]/100 > common.AllowedCurrentValueCPURate {
+	if cpuNum < minCPU || percent[0]/100 > common.AllowedCurrentValueCPURate {
 		return errors.New("cpu check failed")
 	}
 	return nil
 }
 
-func CheckMemory() error {
-	memoryInfo, err := mem.VirtualMemory()
+// CheckMemory checks the node has at least minMemory MB of memory and enough of it is free
+func CheckMemory(w io.Writer, minMemory int) error {
+	memoryInfo, err := virtualMemory()
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Memory total: %.2f MB, Allowed > %v MB\n", float32(memoryInfo.Total)/common.MB, common.AllowedValueMemory/common.MB)
-	fmt.Printf("Memory Free total: %.2f MB, Allowed > %v MB\n", float32(memoryInfo.Free)/common.MB, common.AllowedCurrentValueMem/common.MB)
-	fmt.Printf("Memory usage rate: %.2f, Allowed rate < %v\n", memoryInfo.UsedPercent/100,
+	fmt.Fprintf(w, "Memory total: %.2f MB, Allowed > %v MB\n", float32(memoryInfo.Total)/common.MB, minMemory)
+	fmt.Fprintf(w, "Memory Free total: %.2f MB, Allowed > %v MB\n", float32(memoryInfo.Free)/common.MB, common.AllowedCurrentValueMem/common.MB)
+	fmt.Fprintf(w, "Memory usage rate: %.2f, Allowed rate < %v\n", memoryInfo.UsedPercent/100,
 		common.AllowedCurrentValueMemRate)
 
-	if memoryInfo.Total < common.AllowedValueMemory ||
+	if memoryInfo.Total < uint64(minMemory)*common.MB ||
 		memoryInfo.Free < common.AllowedCurrentValueMem ||
 		memoryInfo.UsedPercent/100 > common.AllowedCurrentValueMemRate {
 		return errors.New("memory check failed")
@@ -221,63 +440,183 @@ func CheckMemory() error {
 	return nil
 }
 
-func CheckDisk() error {
-	parts, err := disk.Partitions(false)
+// CheckDisk checks the system disk has at least minDisk MB in total and enough of it is free,
+// which is the first disk partition on linux and the system drive on windows
+func CheckDisk(w io.Writer, minDisk int) error {
+	path, err := systemDiskPath()
 	if err != nil {
 		return err
 	}
 
-	diskInfo, err := disk.Usage(parts[0].Mountpoint)
+	diskInfo, err := disk.Usage(path)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Disk total: %.2f MB, Allowed > %v MB\n", float32(diskInfo.Total)/common.MB, common.AllowedValueDisk/common.MB)
-	fmt.Printf("Disk Free total: %.2f MB, Allowed > %vMB\n", float32(diskInfo.Free)/common.MB, common.AllowedCurrentValueDisk/common.MB)
-	fmt.Printf("Disk usage rate: %.2f, Allowed rate < %v\n", diskInfo.UsedPercent/100, common.AllowedCurrentValueDiskRate)
+	fmt.Fprintf(w, "Disk total: %.2f MB, Allowed > %v MB\n", float32(diskInfo.Total)/common.MB, minDisk)
+	fmt.Fprintf(w, "Disk Free total: %.2f MB, Allowed > %vMB\n", float32(diskInfo.Free)/common.MB, common.AllowedCurrentValueDisk/common.MB)
+	fmt.Fprintf(w, "Disk usage rate: %.2f, Allowed rate < %v\n", diskInfo.UsedPercent/100, common.AllowedCurrentValueDiskRate)
 
-	if diskInfo.Total < common.AllowedValueDisk ||
+	if diskInfo.Total < uint64(minDisk)*common.MB ||
 		diskInfo.Free < common.AllowedCurrentValueDisk ||
 		diskInfo.UsedPercent/100 > common.AllowedCurrentValueDiskRate {
 		return errors.New("disk check failed")
 	}
+	if diskInfo.UsedPercent/100 > common.WarnCurrentValueDiskRate {
+		return warningError(fmt.Errorf("disk usage rate %.2f is above %v, free the disk before it fills up",
+			diskInfo.UsedPercent/100, common.WarnCurrentValueDiskRate))
+	}
 
 	return nil
 }
 
-func CheckDNS(domain string) error {
-	r, err := net.LookupHost(domain)
+// printWarning prints the warning err to w and returns nil, the check commands print the warnings without failing.
+// It returns err as is if it is not a warning.
+func printWarning(w io.Writer, err error) error {
+	if err == nil || !isDiagnoseWarning(err) {
+		return err
+	}
+	for _, line := range strings.Split(err.Error(), "\n") {
+		fmt.Fprintf(w, "WARNING: %s\n", line)
+	}
+	return nil
+}
+
+// CheckPathDisk checks the free space of the filesystems backing the paths, it fails if any of them has less than minFreeMB MB free.
+// A path which does not exist yet is checked by its closest existing parent directory.
+func CheckPathDisk(w io.Writer, paths []string, minFreeMB int) error {
+	var errs []error
+	for _, p := range paths {
+		dir := existingParent(p)
+		usage, err := disk.Usage(dir)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("get disk usage of %s failed: %v", p, err))
+			continue
+		}
+		free := float32(usage.Free) / common.MB
+		fmt.Fprintf(w, "Disk of %s: total %.2f MB, free %.2f MB, usage rate %.2f, Allowed free > %v MB\n",
+			p, float32(usage.Total)/common.MB, free, usage.UsedPercent/100, minFreeMB)
+		if free < float32(minFreeMB) {
+			errs = append(errs, fmt.Errorf("disk free of %s is %.2f MB, less than %v MB", p, free, minFreeMB))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// existingParent returns p if it exists, otherwise its closest existing parent directory
+func existingParent(p string) string {
+	for {
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+		parent := filepath.Dir(p)
+		if parent == p {
+			return p
+		}
+		p = parent
+	}
+}
+
+// CheckDNS resolves the domain with the system resolver and reports the first address of the ip family,
+// and whether the domain also resolves to the addresses of the other family.
+func CheckDNS(w io.Writer, domain, family string) error {
+	ips, err := net.LookupIP(domain)
+	return reportDNS(w, domain, family, ips, err)
+}
+
+// reportDNS reports the addresses the domain resolves to by CheckDNS and CheckDNSSpecify
+func reportDNS(w io.Writer, domain, family string, ips []net.IP, err error) error {
 	if err != nil {
 		return fmt.Errorf("dns resolution failed, domain: %s err: %s", domain, err)
 	}
-	if len(r) > 0 {
-		fmt.Printf("dns resolution success, domain: %s ip: %s\n", domain, r[0])
+	if len(ips) == 0 {
+		fmt.Fprintf(w, "dns resolution success, domain: %s ip: null\n", domain)
+		return nil
+	}
+	v4, v6 := splitIPFamilies(ips)
+	used := addrIPFamily(&net.IPAddr{IP: ips[0]})
+	if family == common.IPFamilyIPv4 || family == common.IPFamilyIPv6 {
+		used = family
+	}
+	addrs, other, otherAddrs := v4, common.IPFamilyIPv6, v6
+	if used == common.IPFamilyIPv6 {
+		addrs, other, otherAddrs = v6, common.IPFamilyIPv4, v4
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("dns resolution failed, domain: %s has no %s addresses, it resolves to %s addresses [%s]",
+			domain, used, other, strings.Join(otherAddrs, ","))
+	}
+	fmt.Fprintf(w, "dns resolution success, domain: %s ip: %s (%s)\n", domain, addrs[0], used)
+	if len(otherAddrs) > 0 {
+		fmt.Fprintf(w, "domain %s also resolves to %s addresses [%s]\n", domain, other, strings.Join(otherAddrs, ","))
 	} else {
-		fmt.Printf("dns resolution success, domain: %s ip: null\n", domain)
+		fmt.Fprintf(w, "domain %s does not resolve to %s addresses\n", domain, other)
 	}
-	return err
+	return nil
 }
 
-func CheckDNSSpecify(domain string, dns string) error {
-	if dns != "" {
-		net.DefaultResolver = &net.Resolver{
-			PreferGo: true,
-			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
-				d := net.Dialer{
-					Timeout: time.Millisecond * time.Duration(4000),
-				}
-				return d.DialContext(ctx, "udp", fmt.Sprintf("%s:53", dns))
-			},
+// CheckCloudHubDNS resolves the host of the cloudhub server address with the system resolver,
+// and reports the A and AAAA records. It fails if the host has no records of the ip family,
+// and is skipped if the host is an ip address.
+func CheckCloudHubDNS(w io.Writer, server, family string) error {
+	host, _, err := net.SplitHostPort(server)
+	if err != nil {
+		host = server
+	}
+	if net.ParseIP(host) != nil {
+		fmt.Fprintf(w, "cloudhub server host %s is an ip address, skip dns resolution\n", host)
+		return nil
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return fmt.Errorf("dns resolution of cloudhub server host %s failed, %v", host, err)
+	}
+	a, aaaa := splitIPFamilies(ips)
+	if family == common.IPFamilyIPv4 && len(a) == 0 || family == common.IPFamilyIPv6 && len(aaaa) == 0 {
+		return fmt.Errorf("cloudhub server host %s has no %s addresses, A: [%s], AAAA: [%s]",
+			host, family, strings.Join(a, ","), strings.Join(aaaa, ","))
+	}
+	fmt.Fprintf(w, "dns resolution of cloudhub server host %s success, A: [%s], AAAA: [%s]\n",
+		host, strings.Join(a, ","), strings.Join(aaaa, ","))
+	return nil
+}
+
+// CheckDNSSpecify resolves the domain with the dns server if it is specified, otherwise with the system resolver.
+// The dns server is dialed by a resolver of its own, the default resolver of the process is not changed.
+func CheckDNSSpecify(w io.Writer, domain, dns, family string) error {
+	if dns == "" {
+		return CheckDNS(w, domain, family)
+	}
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			d := net.Dialer{
+				Timeout: time.Millisecond * time.Duration(4000),
+			}
+			return d.DialContext(ctx, "udp", net.JoinHostPort(dns, "53"))
+		},
+	}
+	hosts, err := resolver.LookupHost(context.Background(), domain)
+	var ips []net.IP
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			ips = append(ips, ip)
 		}
 	}
-	return CheckDNS(domain)
+	return reportDNS(w, domain, family, ips, err)
 }
 
-func CheckNetWork(IP string, timeout int, cloudhubServer string, edgecoreServer string, config string) error {
+func CheckNetWork(w io.Writer, IP string, opts HTTPCheckOptions, cloudhubServer string, edgecoreServer string, config string) error {
+	if err := validateIPFamily(opts.IPFamily); err != nil {
+		return err
+	}
 	if edgecoreServer == "" {
 		edgecoreServer = common.EdgeCoreServer
 	}
 
+	// the server certificate of cloudhub is issued by the CA of the edge config, it is not verified without the CA
+	hubOpts := opts
 	if config != "" {
 		edgeConfig, err := util.ParseEdgecoreConfig(config)
 		if err != nil {
@@ -286,9 +625,22 @@ func CheckNetWork(IP string, timeout int, cloudhubServer string, edgecoreServer
 		if cloudhubServer == "" {
 			cloudhubServer = edgeConfig.Modules.EdgeHub.WebSocket.Server
 		}
+		if caFile := edgeConfig.Modules.EdgeHub.TLSCAFile; caFile != "" && files.FileExists(caFile) {
+			hubOpts.CAFile = caFile
+		}
 	}
 
-	if IP == "" {
+	if IP == "" && opts.IPFamily == common.IPFamilyIPv6 {
+		// CmdGetDNSIP only returns the ipv4 nameservers
+		ns, err := ipFamilyNameserver(common.PathDNSResolv, opts.IPFamily)
+		if err != nil {
+			return err
+		}
+		if ns == "" {
+			fmt.Fprintf(w, "no ipv6 nameserver is found in %s, skip ping\n", common.PathDNSResolv)
+		}
+		IP = ns
+	} else if IP == "" {
 		result, err := util.ExecShellFilter(common.CmdGetDNSIP)
 		if err != nil {
 			return err
@@ -296,72 +648,909 @@ func CheckNetWork(IP string, timeout int, cloudhubServer string, edgecoreServer
 		IP = result
 	}
 	if IP != "" {
-		result, err := util.ExecShellFilter(fmt.Sprintf(common.CmdPing, IP, timeout))
-
+		err := opts.retry(w, "ping "+IP, func() error {
+			result, err := util.ExecShellFilter(fmt.Sprintf(common.CmdPing, IP, opts.Timeout))
+			if err != nil {
+				return err
+			}
+			if result != "0%" {
+				return fmt.Errorf("ping %s timeout", IP)
+			}
+			return nil
+		})
 		if err != nil {
 			return err
 		}
-		if result != "0%" {
-			return fmt.Errorf("ping %s timeout", IP)
-		}
-		fmt.Printf("ping %s success\n", IP)
+		fmt.Fprintf(w, "ping %s success\n", IP)
 	}
 
 	if cloudhubServer != "" {
-		err := CheckHTTP("https://" + cloudhubServer)
+		if hubOpts.CAFile == "" && !hubOpts.InsecureSkipTLSVerify {
+			fmt.Fprintf(w, "no CA is found in the edge config, the certificate of cloudhubServer %s is not verified\n", cloudhubServer)
+			hubOpts.InsecureSkipTLSVerify = true
+		}
+		var res *httpCheckResult
+		err := opts.retry(w, "check cloudhubServer "+cloudhubServer, func() error {
+			var err error
+			res, err = checkHTTPConn("https://"+cloudhubServer, hubOpts)
+			return err
+		})
+		via := opts.viaProxy("https://" + cloudhubServer)
 		if err != nil {
-			return fmt.Errorf("check cloudhubServer %s failed, %v", cloudhubServer, err)
+			return fmt.Errorf("check cloudhubServer %s%s failed, %v", cloudhubServer, via, err)
+		}
+		res.print(w, "https://"+cloudhubServer)
+		fmt.Fprintf(w, "check cloudhubServer %s success%s\n", cloudhubServer, via)
+		// the family of a proxied connection is the family of the proxy
+		if res.remote != nil && via == "" {
+			used := addrIPFamily(res.remote)
+			fmt.Fprintf(w, "cloudhubServer %s is connected over %s at %s\n", cloudhubServer, used, res.remote)
+			host, _, err := net.SplitHostPort(cloudhubServer)
+			if err != nil {
+				host = cloudhubServer
+			}
+			reportOtherIPFamily(w, host, used)
 		}
-		fmt.Printf("check cloudhubServer %s success\n", cloudhubServer)
 	}
 
 	if edgecoreServer != "" {
-		err := CheckHTTP("http://" + edgecoreServer)
+		// edgecore serves on the local node, which is dialed with either family
+		localOpts := opts
+		localOpts.IPFamily = common.IPFamilyDual
+		err := opts.retry(w, "check edgecoreServer "+edgecoreServer, func() error {
+			return CheckHTTP(w, "http://"+edgecoreServer, localOpts)
+		})
 		if err != nil {
-			return fmt.Errorf("check edgecoreServer %s failed, %v", edgecoreServer, err)
+			return fmt.Errorf("check edgecoreServer %s%s failed, %v", edgecoreServer, opts.viaProxy("http://"+edgecoreServer), err)
 		}
-		fmt.Printf("check edgecoreServer %s success\n", edgecoreServer)
+		fmt.Fprintf(w, "check edgecoreServer %s success%s\n", edgecoreServer, opts.viaProxy("http://"+edgecoreServer))
 	}
 
 	return nil
 }
 
-func CheckHTTP(url string) error {
-	cfg := &tls.Config{InsecureSkipVerify: false}
-	httpTransport := &http.Transport{TLSClientConfig: cfg}
-	// setup a http client
-	httpClient := &http.Client{Transport: httpTransport, Timeout: time.Second * 3}
-	response, err := httpClient.Get(url)
+// CheckHTTP checks whether the url can be reached within opts.Timeout seconds,
+// the default timeout is used if opts.Timeout is not positive. The redirects are followed unless
+// opts.NoFollowRedirects is set, and the redirect chain and the status of the final response are printed.
+func CheckHTTP(w io.Writer, url string, opts HTTPCheckOptions) error {
+	res, err := checkHTTPConn(url, opts)
+	if err != nil {
+		return err
+	}
+	res.print(w, url)
+	return nil
+}
+
+// httpRedirect is a redirect response of the http check
+type httpRedirect struct {
+	// status is the status of the redirect response, e.g. 302 Found
+	status string
+	// to is the url the request is redirected to
+	to string
+}
+
+// httpCheckResult is the result of the http check
+type httpCheckResult struct {
+	// remote is the remote address of the connection the final request is sent over
+	remote net.Addr
+	// redirects are the redirects followed in order, the url of the last one is the final url
+	redirects []httpRedirect
+	// status is the status of the final response, e.g. 200 OK
+	status string
+}
+
+// finalURL returns the url of the final response, which is url if no redirect is followed
+func (res *httpCheckResult) finalURL(url string) string {
+	if len(res.redirects) == 0 {
+		return url
+	}
+	return res.redirects[len(res.redirects)-1].to
+}
+
+// summary returns the status of the final response and the final url if the request is redirected
+func (res *httpCheckResult) summary(url string) string {
+	if len(res.redirects) == 0 {
+		return "status " + res.status
+	}
+	return fmt.Sprintf("status %s of %s after %d redirects", res.status, res.finalURL(url), len(res.redirects))
+}
+
+// print prints the redirect chain from url and the status of the final response
+func (res *httpCheckResult) print(w io.Writer, url string) {
+	from := url
+	for _, redirect := range res.redirects {
+		fmt.Fprintf(w, "%s is redirected with status %s to %s\n", from, redirect.status, redirect.to)
+		from = redirect.to
+	}
+	fmt.Fprintf(w, "%s answered with status %s\n", from, res.status)
+}
+
+// checkHTTPConn checks whether the url can be reached the same as CheckHTTP, and returns the result of the check.
+// It fails if the server answers with a redirect and opts.NoFollowRedirects is set.
+func checkHTTPConn(url string, opts HTTPCheckOptions) (*httpCheckResult, error) {
+	timeout := opts.timeout()
+	res := &httpCheckResult{}
+	httpClient, err := opts.newHTTPClient()
 	if err != nil {
-		if !strings.Contains(err.Error(), "x509") {
-			return fmt.Errorf(" connect fail: %s", err.Error())
+		return nil, err
+	}
+	httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if opts.NoFollowRedirects {
+			return http.ErrUseLastResponse
 		}
-	} else {
-		defer response.Body.Close()
+		// the same limit as the default policy of http.Client
+		if len(via) >= maxHTTPRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxHTTPRedirects)
+		}
+		res.redirects = append(res.redirects, httpRedirect{status: req.Response.Status, to: req.URL.String()})
+		return nil
+	}
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) { res.remote = info.Conn.RemoteAddr() },
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := httpClient.Do(req)
+	if err != nil {
+		return nil, connectError(err, timeout)
+	}
+	defer response.Body.Close()
+	res.status = response.Status
+	if opts.NoFollowRedirects && isHTTPRedirect(response.StatusCode) {
+		return nil, fmt.Errorf("%s answered with status %s redirecting to %s instead of answering directly, which --no-follow-redirects requires",
+			url, response.Status, response.Header.Get("Location"))
+	}
+	return res, nil
+}
+
+// isHTTPRedirect returns whether the status code is a redirect the http client follows
+func isHTTPRedirect(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// CheckTimeSync checks whether a time synchronization process, i.e. ntpd, chronyd or systemd-timesyncd, is running.
+// It warns instead of failing if none is running, since the clock is right until it drifts,
+// and then the certificates and the node leases break.
+func CheckTimeSync(w io.Writer) error {
+	proc, err := util.ExecShellFilter(common.CmdGetTimeSyncProcess)
+	if err != nil {
+		return err
+	}
+	if proc != "" {
+		fmt.Fprintf(w, "time synchronization process %s is running\n", proc)
+		return nil
+	}
+	return warningError(errors.New("no ntpd, chronyd or systemd-timesyncd process is running, the clock is likely to drift " +
+		"and break the certificates and the node leases, enable a time synchronization service"))
+}
+
+// CheckClockSkew compares the local clock with the Date header of the response from the cloudhub server,
+// it fails if the absolute skew exceeds maxSkew, and warns if it exceeds half of maxSkew.
+func CheckClockSkew(w io.Writer, server string, opts CloudHubCheckOptions, maxSkew time.Duration) error {
+	if server == "" {
+		fmt.Fprintf(w, "cloudhub server is not specified, skip clock skew check\n")
+		return nil
+	}
+	timeout := opts.timeout()
+	httpClient, err := opts.newHTTPClient()
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	response, err := httpClient.Get("https://" + server)
+	if err != nil {
+		return fmt.Errorf("check clock skew with cloudhub server %s%s failed,%v", server,
+			opts.viaProxy("https://"+server), connectError(err, timeout))
+	}
+	defer response.Body.Close()
+	rtt := time.Since(start)
+
+	date, err := http.ParseTime(response.Header.Get("Date"))
+	if err != nil {
+		return fmt.Errorf("check clock skew with cloudhub server %s failed, invalid Date header %q",
+			server, response.Header.Get("Date"))
+	}
+	// the server generates the Date header about halfway through the round trip
+	skew := start.Add(rtt / 2).Sub(date).Round(time.Second)
+	if skew > maxSkew || -skew > maxSkew {
+		return fmt.Errorf("clock skew with cloudhub server %s is %v, exceeds the max skew %v", server, skew, maxSkew)
 	}
+	if skew > maxSkew/2 || -skew > maxSkew/2 {
+		return warningError(fmt.Errorf("clock skew with cloudhub server %s is %v, more than half of the max skew %v, "+
+			"check the time synchronization of the node", server, skew, maxSkew))
+	}
+	fmt.Fprintf(w, "clock skew with cloudhub server %s is %v\n", server, skew)
 	return nil
 }
 
-func CheckRuntime() error {
-	// TODO: check runtime status
+// CheckCloudHubLatency measures the tls handshake time and the first byte latency of a request to the cloudhub server,
+// it fails if either exceeds maxLatency, which tells a slow link from an unreachable server. No threshold is checked if maxLatency is not positive.
+func CheckCloudHubLatency(w io.Writer, server string, opts CloudHubCheckOptions, maxLatency time.Duration) error {
+	timeout := opts.timeout()
+	httpClient, err := opts.newHTTPClient()
+	if err != nil {
+		return err
+	}
+	// every request measures a new tls handshake
+	httpClient.Transport.(*http.Transport).DisableKeepAlives = true
+
+	var tlsStart, tlsDone, firstByte time.Time
+	trace := &httptrace.ClientTrace{
+		TLSHandshakeStart:    func() { tlsStart = time.Now() },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { tlsDone = time.Now() },
+		GotFirstResponseByte: func() { firstByte = time.Now() },
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace),
+		http.MethodGet, "https://"+server, nil)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	response, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("check latency of cloudhub server %s%s failed,%v", server,
+			opts.viaProxy("https://"+server), connectError(err, timeout))
+	}
+	defer response.Body.Close()
+
+	handshake := tlsDone.Sub(tlsStart).Round(time.Millisecond)
+	latency := firstByte.Sub(start).Round(time.Millisecond)
+	fmt.Fprintf(w, "cloudhub server %s tls handshake time: %v, first byte latency: %v\n", server, handshake, latency)
+	if maxLatency > 0 && (handshake > maxLatency || latency > maxLatency) {
+		return fmt.Errorf("cloudhub server %s is connected, but the latency exceeds the max latency %v, "+
+			"the link is too slow to sync the pods in time", server, maxLatency)
+	}
+	return nil
+}
+
+// CloudHubCheckOptions is the options of the cloudhub connection checks CheckWebSocket and CheckQUIC
+type CloudHubCheckOptions struct {
+	HTTPCheckOptions
+	// CertFile and KeyFile are the client certificate sent in the handshake, no certificate is sent if empty
+	CertFile string
+	KeyFile  string
+}
+
+// CheckWebSocket performs the websocket upgrade handshake against url within opts.Timeout seconds.
+// The node_id and project_id headers are not sent, so that cloudhub never replaces the session of the running edgecore.
+func CheckWebSocket(url string, opts CloudHubCheckOptions) error {
+	timeout := opts.timeout()
+	tlsConfig, err := opts.tlsConfig()
+	if err != nil {
+		return err
+	}
+
+	dialer := &websocket.Dialer{
+		Proxy:            opts.proxyFunc(),
+		NetDialContext:   opts.dialContext(),
+		TLSClientConfig:  tlsConfig,
+		HandshakeTimeout: time.Duration(timeout) * time.Second,
+	}
+	conn, resp, err := dialer.Dial(url, nil)
+	if err != nil {
+		if resp != nil {
+			return fmt.Errorf(" websocket handshake failed, http status: %s, subprotocol: %q, %v",
+				resp.Status, resp.Header.Get("Sec-WebSocket-Protocol"), err)
+		}
+		return connectError(err, timeout)
+	}
+	return conn.Close()
+}
+
+// edgeStreamConnectPath is the path of the tunnel server of cloudstream which edgestream connects to
+const edgeStreamConnectPath = "/v1/kubeedge/connect"
+
+// CheckEdgeStream performs the websocket handshake of the edgestream tunnel against the tunnel server of cloudstream,
+// the server certificate is not verified and the tunnel certificate is sent the same as edgestream does.
+// The hostname header is not sent, so that cloudstream closes the tunnel and never replaces the tunnel of the running edgecore.
+func CheckEdgeStream(w io.Writer, es *v1alpha2.EdgeStream, opts HTTPCheckOptions) error {
+	if es.TunnelServer == "" {
+		return fmt.Errorf("edgestream tunnel server is not configured")
+	}
+	opts.InsecureSkipTLSVerify = true
+	tunnelURL := edgeStreamTunnelURL(es)
+	if err := CheckWebSocket(tunnelURL, CloudHubCheckOptions{
+		HTTPCheckOptions: opts,
+		CertFile:         es.TLSTunnelCertFile,
+		KeyFile:          es.TLSTunnelPrivateKeyFile,
+	}); err != nil {
+		return fmt.Errorf("edgestream tunnel to %s%s failed,%v", tunnelURL, opts.viaProxy("https://"+es.TunnelServer), err)
+	}
+	fmt.Fprintf(w, "edgestream tunnel to %s is established, kubectl logs and exec can reach the node\n", tunnelURL)
+	return nil
+}
+
+// edgeStreamTunnelURL returns the url of the tunnel server which edgestream connects to
+func edgeStreamTunnelURL(es *v1alpha2.EdgeStream) string {
+	tunnelURL := url.URL{Scheme: "wss", Host: es.TunnelServer, Path: edgeStreamConnectPath}
+	return tunnelURL.String()
+}
+
+// CheckQUIC performs the quic handshake against address within opts.Timeout seconds.
+// The session is closed before the header is sent, so that cloudhub never replaces the session of the running edgecore.
+func CheckQUIC(address string, opts CloudHubCheckOptions) error {
+	timeout := opts.timeout()
+	tlsConfig, err := opts.tlsConfig()
+	if err != nil {
+		return err
+	}
+
+	address, host, err := resolveUDPAddr(address, opts.IPFamily)
+	if err != nil {
+		return err
+	}
+	// the server name is the host of the address before it is resolved to an ip of the family
+	if tlsConfig.ServerName == "" && net.ParseIP(host) == nil {
+		tlsConfig.ServerName = host
+	}
+	session, err := quic.DialAddr(address, tlsConfig, &quic.Config{
+		HandshakeTimeout: time.Duration(timeout) * time.Second,
+	})
+	if err != nil {
+		return connectError(err, timeout)
+	}
+	return session.Close()
+}
+
+// newHTTPClient returns the http client of the checks, which sends the client certificate as well
+func (opts CloudHubCheckOptions) newHTTPClient() (*http.Client, error) {
+	tlsConfig, err := opts.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	return opts.httpClient(tlsConfig), nil
+}
+
+// tlsConfig returns the tls config which verifies the server certificate with the CA and sends the client certificate
+func (opts CloudHubCheckOptions) tlsConfig() (*tls.Config, error) {
+	tlsConfig, err := opts.HTTPCheckOptions.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	if opts.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf(" load client certificate %s failed: %v", opts.CertFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// MQTTBrokers returns the mqtt brokers used by the mqtt mode of eventbus
+func MQTTBrokers(eb *v1alpha2.EventBus) []string {
+	switch eb.MqttMode {
+	case v1alpha2.MqttModeInternal:
+		return []string{eb.MqttServerInternal}
+	case v1alpha2.MqttModeBoth:
+		return []string{eb.MqttServerInternal, eb.MqttServerExternal}
+	default:
+		return []string{eb.MqttServerExternal}
+	}
+}
+
+// mqttModeName returns the readable name of the mqtt mode
+func mqttModeName(mode v1alpha2.MqttMode) string {
+	switch mode {
+	case v1alpha2.MqttModeInternal:
+		return "internal"
+	case v1alpha2.MqttModeBoth:
+		return "both"
+	default:
+		return "external"
+	}
+}
+
+// CheckMQTT checks whether the mqtt brokers of eventbus are reachable within timeout seconds,
+// a CONNECT packet is sent to each broker if connect is true. The check is skipped if eventbus is disabled.
+func CheckMQTT(w io.Writer, eb *v1alpha2.EventBus, timeout int, connect bool) error {
+	if eb == nil || !eb.Enable {
+		return skippedError("eventbus is disabled")
+	}
+
+	mode := mqttModeName(eb.MqttMode)
+	for _, broker := range MQTTBrokers(eb) {
+		u, err := url.Parse(broker)
+		if err != nil {
+			return fmt.Errorf("invalid mqtt server %s: %v", broker, err)
+		}
+		if connect {
+			err = connectMQTT(u.Host, eb, timeout)
+		} else {
+			err = CheckTCP(u.Host, timeout)
+		}
+		if err != nil {
+			return fmt.Errorf("check mqtt broker %s (mode %s) failed,%v", broker, mode, err)
+		}
+		fmt.Fprintf(w, "check mqtt broker %s (mode %s) success\n", broker, mode)
+	}
 	return nil
 }
 
-func CheckPid() error {
-	rMax, err := util.ExecShellFilter(common.CmdGetMaxProcessNum)
+// connectMQTT sends a CONNECT packet to the mqtt broker and waits for the CONNACK within timeout seconds
+func connectMQTT(address string, eb *v1alpha2.EventBus, timeout int) error {
+	d := time.Duration(timeout) * time.Second
+	conn, err := net.DialTimeout("tcp", address, d)
 	if err != nil {
+		return fmt.Errorf(" connect fail: %s", err.Error())
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(d)); err != nil {
 		return err
 	}
-	r, err := util.ExecShellFilter(common.CmdGetProcessNum)
+
+	cp := packets.NewControlPacket(packets.Connect).(*packets.ConnectPacket)
+	cp.ProtocolName = "MQTT"
+	cp.ProtocolVersion = 4
+	cp.CleanSession = true
+	cp.Keepalive = uint16(timeout)
+	cp.ClientIdentifier = fmt.Sprintf("keadm-diagnose-%d", os.Getpid())
+	if eb.MqttUsername != "" {
+		cp.UsernameFlag = true
+		cp.Username = eb.MqttUsername
+	}
+	if eb.MqttPassword != "" {
+		cp.PasswordFlag = true
+		cp.Password = []byte(eb.MqttPassword)
+	}
+	if err := cp.Write(conn); err != nil {
+		return fmt.Errorf(" send CONNECT failed: %v", err)
+	}
+
+	p, err := packets.ReadPacket(conn)
+	if err != nil {
+		return fmt.Errorf(" read CONNACK failed: %v", err)
+	}
+	ca, ok := p.(*packets.ConnackPacket)
+	if !ok {
+		return fmt.Errorf(" unexpected packet %s", p.String())
+	}
+	if ca.ReturnCode != packets.Accepted {
+		return fmt.Errorf(" %s", packets.ConnackReturnCodes[ca.ReturnCode])
+	}
+	return packets.NewControlPacket(packets.Disconnect).Write(conn)
+}
+
+// connectError converts the error of a failed connection to a readable error
+func connectError(err error, timeout int) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf(" connection timed out after %ds", timeout)
+	}
+	if strings.Contains(err.Error(), "x509") {
+		return fmt.Errorf(" certificate verification failed: %s, specify --insecure-skip-tls-verify to skip the verification", err.Error())
+	}
+	return fmt.Errorf(" connect fail: %s", err.Error())
+}
+
+// CheckTCP checks whether a tcp connection to address can be established within timeout seconds
+func CheckTCP(address string, timeout int) error {
+	conn, err := net.DialTimeout("tcp", address, time.Duration(timeout)*time.Second)
+	if err != nil {
+		return fmt.Errorf(" connect fail: %s", err.Error())
+	}
+	return conn.Close()
+}
+
+// CheckEdgedPort checks whether edged is listening on its read-only port,
+// and whether the listening socket is bound by the edgecore process.
+// The check is skipped if the read-only port is disabled.
+func CheckEdgedPort(w io.Writer, address string, port int32, timeout int) error {
+	if port == 0 {
+		fmt.Fprintf(w, "edged read-only port is disabled, skip edged port check\n")
+		return nil
+	}
+	return checkEdgecorePort(w, "edged", edgedPortAddress(address, port), uint32(port), timeout)
+}
+
+// CheckServiceBusPort checks whether servicebus is listening on its http port, which the edge apps send the
+// edge-to-edge http requests to, and whether the listening socket is bound by the edgecore process
+func CheckServiceBusPort(w io.Writer, sb *v1alpha2.ServiceBus, timeout int) error {
+	return checkEdgecorePort(w, "servicebus", edgedPortAddress(sb.Server, int32(sb.Port)), uint32(sb.Port), timeout)
+}
+
+// checkEdgecorePort checks whether the module of edgecore is listening on addr, and whether the socket listening
+// on the port is bound by the edgecore process. The check passes if the listening process is not found.
+func checkEdgecorePort(w io.Writer, module, addr string, port uint32, timeout int) error {
+	if err := CheckTCP(addr, timeout); err != nil {
+		return fmt.Errorf("%s is not listening on %s,%v", module, addr, err)
+	}
+
+	pid, name, err := listeningProcess(port)
+	if err != nil {
+		fmt.Fprintf(w, "%s is listening on %s, %v\n", module, addr, err)
+		return nil
+	}
+	if name != constants.KubeEdgeBinaryName+executableSuffix {
+		return fmt.Errorf("port %d is bound by %s (pid %d), not %s", port, name, pid, constants.KubeEdgeBinaryName)
+	}
+	fmt.Fprintf(w, "%s is listening on %s, bound by %s (pid %d)\n", module, addr, name, pid)
+	return nil
+}
+
+// edgedPortAddress returns the address the read-only port of edged, or another port of edgecore, is reached at,
+// the loopback address is used if edgecore listens on all addresses
+func edgedPortAddress(address string, port int32) string {
+	host := address
+	if host == "" || net.ParseIP(host).IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(port)))
+}
+
+// listeningProcess returns the pid and the name of the process listening on the tcp port
+func listeningProcess(port uint32) (int32, string, error) {
+	conns, err := gonet.Connections("tcp")
+	if err != nil {
+		return 0, "", fmt.Errorf("list tcp connections failed: %v", err)
+	}
+	for _, conn := range conns {
+		if conn.Status != "LISTEN" || conn.Laddr.Port != port {
+			continue
+		}
+		if conn.Pid == 0 {
+			return 0, "", fmt.Errorf("the process of port %d is unknown, run as root to find it", port)
+		}
+		proc, err := process.NewProcess(conn.Pid)
+		if err != nil {
+			return conn.Pid, "", fmt.Errorf("get process %d failed: %v", conn.Pid, err)
+		}
+		name, err := proc.Name()
+		if err != nil {
+			return conn.Pid, "", fmt.Errorf("get name of process %d failed: %v", conn.Pid, err)
+		}
+		return conn.Pid, name, nil
+	}
+	return 0, "", fmt.Errorf("no process is found listening on port %d", port)
+}
+
+// CheckSystemd checks whether the edgecore systemd unit is active and enabled, and whether it is flapping,
+// i.e. it has restarted many times and been active for a short time, which passes the process check between the crashes.
+// The check is skipped on the hosts without systemd or the edgecore unit, the process check covers them.
+func CheckSystemd(w io.Writer) error {
+	if !util.HasSystemd() {
+		fmt.Fprintf(w, "systemd is not found, skip edgecore unit check\n")
+		return nil
+	}
+	out, err := util.ExecShellFilter(common.CmdShowEdgecoreUnit)
 	if err != nil {
 		return err
 	}
-	vMax, err := strconv.ParseFloat(rMax, 32)
-	v, err := strconv.ParseFloat(r, 32)
-	rate := (1 - v/vMax)
-	if rate > common.AllowedValuePIDRate {
-		fmt.Printf("Maximum PIDs: %s; Running processes: %s\n", rMax, r)
+	unit := parseSystemdProperties(out)
+	if unit["LoadState"] == "not-found" {
+		fmt.Fprintf(w, "edgecore.service is not found, skip edgecore unit check\n")
 		return nil
 	}
-	return fmt.Errorf("Maximum PIDs: %s; Running processes: %s", rMax, r)
+
+	if unit["ActiveState"] != "active" {
+		return fmt.Errorf("edgecore.service is %s (%s)", unit["ActiveState"], unit["SubState"])
+	}
+	if unit["UnitFileState"] != "enabled" {
+		return fmt.Errorf("edgecore.service is %s, edgecore will not start after reboot, run 'systemctl enable edgecore'", unit["UnitFileState"])
+	}
+
+	restarts, _ := strconv.Atoi(unit["NRestarts"])
+	activeFor, err := systemdActiveDuration(unit["ActiveEnterTimestampMonotonic"])
+	if err != nil {
+		fmt.Fprintf(w, "edgecore.service is active (%s), enabled, restarted %d times\n", unit["SubState"], restarts)
+		return nil
+	}
+	if restarts >= systemdFlapRestarts && activeFor < systemdFlapWindow {
+		return fmt.Errorf("edgecore.service is flapping, restarted %d times and active for only %v, check the logs by 'journalctl -u edgecore.service -xe'",
+			restarts, activeFor)
+	}
+	fmt.Fprintf(w, "edgecore.service is active (%s), enabled, restarted %d times, active for %v\n", unit["SubState"], restarts, activeFor)
+	return nil
+}
+
+// parseSystemdProperties parses the key=value lines of systemctl show
+func parseSystemdProperties(out string) map[string]string {
+	properties := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+			properties[key] = value
+		}
+	}
+	return properties
+}
+
+// systemdActiveDuration returns how long the unit has been active from its monotonic activation timestamp in microseconds
+func systemdActiveDuration(monotonic string) (time.Duration, error) {
+	enter, err := strconv.ParseInt(monotonic, 10, 64)
+	if err != nil || enter == 0 {
+		return 0, fmt.Errorf("invalid active enter timestamp %q", monotonic)
+	}
+	uptime, err := systemUptime()
+	if err != nil {
+		return 0, err
+	}
+	return (uptime - time.Duration(enter)*time.Microsecond).Round(time.Second), nil
+}
+
+// systemUptime returns the time since boot in /proc/uptime
+func systemUptime() (time.Duration, error) {
+	data, err := os.ReadFile("/proc/uptime")
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("invalid /proc/uptime %q", data)
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid /proc/uptime %q", data)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// CheckCertExpiry checks the CA and the edge certificates referenced by the edgecore config,
+// it fails if a certificate is expired, and warns if it will expire within warnDays days.
+// The edge certificate is then validated by checkEdgeCert, see there for the sub-findings.
+// The check is skipped if the edgecore config does not exist, e.g. before the node is joined.
+func CheckCertExpiry(w io.Writer, config string, warnDays int) error {
+	if !files.FileExists(config) {
+		fmt.Fprintf(w, "edge config %s is not exists, skip certificate check\n", config)
+		return nil
+	}
+	edgeConfig, err := util.ParseEdgecoreConfig(config)
+	if err != nil {
+		return fmt.Errorf("parse Edgecore config failed")
+	}
+
+	certFiles := []string{
+		edgeConfig.Modules.EdgeHub.TLSCAFile,
+		edgeConfig.Modules.EdgeHub.TLSCertFile,
+	}
+	var errs []error
+	for _, certFile := range certFiles {
+		if certFile == "" {
+			continue
+		}
+		if !files.FileExists(certFile) {
+			fmt.Fprintf(w, "certificate %s is not exists, skip\n", certFile)
+			continue
+		}
+		cert, err := parseCertFile(certFile)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := checkCertNotAfter(w, certFile, cert, warnDays); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, checkEdgeCert(w, edgeConfig)...)
+	return errors.Join(errs...)
+}
+
+// checkCertNotAfter prints the expiry of the certificate of the name, it fails if the certificate is expired,
+// and warns if it will expire within warnDays days
+func checkCertNotAfter(w io.Writer, name string, cert *x509.Certificate, warnDays int) error {
+	remaining := time.Until(cert.NotAfter)
+	days := int(remaining.Hours() / 24)
+	fmt.Fprintf(w, "certificate %s NotAfter: %s, remaining days: %d\n",
+		name, cert.NotAfter.Format(time.RFC3339), days)
+	if remaining <= 0 {
+		return fmt.Errorf("certificate %s is expired at %s", name, cert.NotAfter.Format(time.RFC3339))
+	}
+	if days < warnDays {
+		return warningError(fmt.Errorf("certificate %s will expire in %d days, less than %d days, renew it",
+			name, days, warnDays))
+	}
+	return nil
+}
+
+// checkEdgeCert validates the edge certificate of edgehub, and reports the sub-findings separately:
+// whether the chain validates against the configured CA, whether the certificate identifies the node
+// by the CN system:node:<nodeName> that cloudcore issues or by the SANs, and whether the private key
+// matches the certificate. The findings are skipped if the certificate or the CA does not exist.
+func checkEdgeCert(w io.Writer, edgeConfig *v1alpha2.EdgeCoreConfig) []error {
+	eh := edgeConfig.Modules.EdgeHub
+	if eh.TLSCertFile == "" || !files.FileExists(eh.TLSCertFile) || eh.TLSCAFile == "" || !files.FileExists(eh.TLSCAFile) {
+		return nil
+	}
+	certs, err := parseCertsFile(eh.TLSCertFile)
+	if err != nil {
+		return []error{err}
+	}
+	cas, err := parseCertsFile(eh.TLSCAFile)
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	if err := verifyCertChain(certs, cas); err != nil {
+		errs = append(errs, fmt.Errorf("chain: certificate %s does not validate against the CA %s, %v", eh.TLSCertFile, eh.TLSCAFile, err))
+	} else {
+		fmt.Fprintf(w, "chain: certificate %s is issued by the CA %s\n", eh.TLSCertFile, eh.TLSCAFile)
+	}
+
+	nodeName, nodeIP := edgeCertNodeIdentity(edgeConfig)
+	if certIdentifiesNode(certs[0], nodeName, nodeIP) {
+		fmt.Fprintf(w, "san: certificate %s identifies the node %s\n", eh.TLSCertFile, nodeName)
+	} else {
+		errs = append(errs, fmt.Errorf("san: certificate %s identifies neither the node %s nor the IP %s, CN: %s, SANs: %s",
+			eh.TLSCertFile, nodeName, nodeIP, certs[0].Subject.CommonName, strings.Join(certSANs(certs[0]), ",")))
+	}
+
+	switch {
+	case eh.TLSPrivateKeyFile == "":
+	case !files.FileExists(eh.TLSPrivateKeyFile):
+		errs = append(errs, fmt.Errorf("key: private key %s of certificate %s is not exists", eh.TLSPrivateKeyFile, eh.TLSCertFile))
+	default:
+		if _, err := tls.LoadX509KeyPair(eh.TLSCertFile, eh.TLSPrivateKeyFile); err != nil {
+			errs = append(errs, fmt.Errorf("key: private key %s does not match certificate %s, %v", eh.TLSPrivateKeyFile, eh.TLSCertFile, err))
+		} else {
+			fmt.Fprintf(w, "key: private key %s matches certificate %s\n", eh.TLSPrivateKeyFile, eh.TLSCertFile)
+		}
+	}
+	return errs
+}
+
+// verifyCertChain verifies the first certificate of certs against the CAs, the rest of certs are the intermediates.
+// The chain is verified at a time within the validity of the certificate, so that an expired certificate is
+// reported by the expiry finding only, and a self-signed certificate is reported as such.
+func verifyCertChain(certs, cas []*x509.Certificate) error {
+	cert := certs[0]
+	roots := x509.NewCertPool()
+	for _, ca := range cas {
+		roots.AddCert(ca)
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   cert.NotBefore.Add(cert.NotAfter.Sub(cert.NotBefore) / 2),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil && bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil {
+		return fmt.Errorf("the certificate is self-signed")
+	}
+	return err
+}
+
+// edgeCertNodeIdentity returns the node name and the node IP that the edge certificate should identify
+func edgeCertNodeIdentity(edgeConfig *v1alpha2.EdgeCoreConfig) (string, string) {
+	var nodeName, nodeIP string
+	if edged := edgeConfig.Modules.Edged; edged != nil {
+		nodeName, nodeIP = edged.HostnameOverride, edged.NodeIP
+	}
+	if nodeName == "" {
+		nodeName, _ = os.Hostname()
+	}
+	return nodeName, nodeIP
+}
+
+// certIdentifiesNode returns whether the certificate identifies the node by the CN or by the SANs
+func certIdentifiesNode(cert *x509.Certificate, nodeName, nodeIP string) bool {
+	if nodeName != "" && cert.Subject.CommonName == "system:node:"+nodeName {
+		return true
+	}
+	for _, name := range cert.DNSNames {
+		if nodeName != "" && name == nodeName {
+			return true
+		}
+	}
+	ip := net.ParseIP(nodeIP)
+	for _, addr := range cert.IPAddresses {
+		if ip != nil && addr.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// certSANs returns the DNS names and the IP addresses of the certificate
+func certSANs(cert *x509.Certificate) []string {
+	sans := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	if len(sans) == 0 {
+		return []string{"none"}
+	}
+	return sans
+}
+
+// parseCertsFile parses all the PEM encoded certificates in certFile
+func parseCertsFile(certFile string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("read certificate %s failed: %v", certFile, err)
+	}
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse certificate %s failed: %v", certFile, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("certificate %s is not a PEM encoded certificate", certFile)
+	}
+	return certs, nil
+}
+
+// parseCertFile parses the first PEM encoded certificate in certFile
+func parseCertFile(certFile string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("read certificate %s failed: %v", certFile, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("certificate %s is not a PEM encoded certificate", certFile)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse certificate %s failed: %v", certFile, err)
+	}
+	return cert, nil
+}
+
+func CheckRuntime() error {
+	// TODO: check runtime status
+	return nil
+}
+
+// CheckContainerRuntime checks whether the CRI runtime serving endpoint is responding,
+// by dialing the endpoint and issuing a Version request within timeout seconds.
+func CheckContainerRuntime(w io.Writer, endpoint string, timeout int) error {
+	rs, endpoint, err := connectContainerRuntime(endpoint, timeout)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	version, err := rs.Version(ctx, criAPIVersion)
+	if err != nil {
+		return fmt.Errorf("get version of container runtime endpoint %s failed, %v", endpoint, err)
+	}
+	fmt.Fprintf(w, "container runtime %s %s is running, endpoint: %s\n",
+		version.RuntimeName, version.RuntimeVersion, endpoint)
+	return nil
+}
+
+// connectContainerRuntime connects the CRI runtime serving endpoint, the default endpoint is used if it is empty.
+// It returns the endpoint connected as well.
+func connectContainerRuntime(endpoint string, timeout int) (internalapi.RuntimeService, string, error) {
+	if endpoint == "" {
+		endpoint = constants.DefaultRemoteRuntimeEndpoint
+	}
+	if sock, ok := strings.CutPrefix(endpoint, "unix://"); ok && !files.FileExists(sock) {
+		return nil, endpoint, fmt.Errorf("container runtime endpoint %s is not exists", endpoint)
+	}
+
+	rs, err := remote.NewRemoteRuntimeService(endpoint, time.Duration(timeout)*time.Second, noop.NewTracerProvider())
+	if err != nil {
+		return nil, endpoint, fmt.Errorf("connect container runtime endpoint %s failed, %v", endpoint, err)
+	}
+	return rs, endpoint, nil
+}
+
+// tasklistProcessCount counts the processes in the csv output of tasklist without the header, one process per line
+func tasklistProcessCount(out string) int {
+	var count int
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), `"`) {
+			count++
+		}
+	}
+	return count
 }
@@ -0,0 +1,68 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultCgroupDriver is the cgroup driver of edged if cgroupDriver is not configured
+const defaultCgroupDriver = "cgroupfs"
+
+// CheckCgroupDriver checks whether the cgroup driver of edged matches the one the container runtime reports by
+// the RuntimeConfig request of CRI. The pod sandboxes fail to start on a mismatch with the errors like
+// "expected cgroupsPath to be of format slice:prefix:name", which do not tell the drivers differ.
+// The runtimes before CRI v1.28 do not implement RuntimeConfig, whose driver can not be compared.
+func CheckCgroupDriver(w io.Writer, endpoint, driver string, timeout int) error {
+	if driver == "" {
+		driver = defaultCgroupDriver
+	}
+	rs, endpoint, err := connectContainerRuntime(endpoint, timeout)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	resp, err := rs.RuntimeConfig(ctx)
+	if status.Code(err) == codes.Unimplemented {
+		fmt.Fprintf(w, "container runtime endpoint %s does not report its cgroup driver, make sure it is %s as edged\n", endpoint, driver)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get runtime config of container runtime endpoint %s failed, %v", endpoint, err)
+	}
+	if resp.GetLinux() == nil {
+		fmt.Fprintf(w, "container runtime endpoint %s does not report its cgroup driver, make sure it is %s as edged\n", endpoint, driver)
+		return nil
+	}
+
+	runtimeDriver := strings.ToLower(resp.GetLinux().GetCgroupDriver().String())
+	if !strings.EqualFold(runtimeDriver, driver) {
+		return fmt.Errorf("the cgroup driver of edged is %s, but %s of the container runtime, the pods fail to start, "+
+			"set modules.edged.tailoredKubeletConfig.cgroupDriver in the config to the same driver as the container runtime",
+			driver, runtimeDriver)
+	}
+	fmt.Fprintf(w, "edged and the container runtime use the same cgroup driver %s\n", driver)
+	return nil
+}
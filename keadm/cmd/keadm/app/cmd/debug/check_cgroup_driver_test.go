@@ -0,0 +1,85 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// fakeRuntimeConfigServer reports the cgroup driver, or implements no RuntimeConfig if driver is nil
+type fakeRuntimeConfigServer struct {
+	fakeRuntimeServer
+	driver *runtimeapi.CgroupDriver
+}
+
+func (s *fakeRuntimeConfigServer) RuntimeConfig(ctx context.Context, req *runtimeapi.RuntimeConfigRequest) (*runtimeapi.RuntimeConfigResponse, error) {
+	if s.driver == nil {
+		return s.fakeRuntimeServer.RuntimeConfig(ctx, req)
+	}
+	return &runtimeapi.RuntimeConfigResponse{Linux: &runtimeapi.LinuxRuntimeConfiguration{CgroupDriver: *s.driver}}, nil
+}
+
+func serveFakeRuntime(t *testing.T, server runtimeapi.RuntimeServiceServer) string {
+	sock := filepath.Join(t.TempDir(), "cri.sock")
+	listener, err := net.Listen("unix", sock)
+	require.NoError(t, err)
+	s := grpc.NewServer()
+	runtimeapi.RegisterRuntimeServiceServer(s, server)
+	go func() {
+		_ = s.Serve(listener)
+	}()
+	t.Cleanup(s.Stop)
+	return "unix://" + sock
+}
+
+func TestCheckCgroupDriver(t *testing.T) {
+	systemd := runtimeapi.CgroupDriver_SYSTEMD
+
+	t.Run("same driver", func(t *testing.T) {
+		endpoint := serveFakeRuntime(t, &fakeRuntimeConfigServer{driver: &systemd})
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckCgroupDriver(buf, endpoint, "systemd", 3))
+		assert.Equal(t, "edged and the container runtime use the same cgroup driver systemd\n", buf.String())
+	})
+
+	t.Run("mismatched driver", func(t *testing.T) {
+		endpoint := serveFakeRuntime(t, &fakeRuntimeConfigServer{driver: &systemd})
+		err := CheckCgroupDriver(&bytes.Buffer{}, endpoint, "", 3)
+		require.ErrorContains(t, err, "the cgroup driver of edged is cgroupfs, but systemd of the container runtime")
+	})
+
+	t.Run("runtime config is not implemented", func(t *testing.T) {
+		endpoint := serveFakeRuntime(t, &fakeRuntimeConfigServer{})
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckCgroupDriver(buf, endpoint, "systemd", 3))
+		assert.Contains(t, buf.String(), "does not report its cgroup driver, make sure it is systemd as edged")
+	})
+
+	t.Run("runtime endpoint is not exists", func(t *testing.T) {
+		endpoint := "unix://" + filepath.Join(t.TempDir(), "not-exists.sock")
+		require.ErrorContains(t, CheckCgroupDriver(&bytes.Buffer{}, endpoint, "systemd", 1), "is not exists")
+	})
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/kubeedge/api/apis/common/constants"
+)
+
+// cniConfig is the part of a CNI network config file used by the check,
+// a .conflist file has the plugins, a .conf or .json file is a single plugin.
+type cniConfig struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Plugins []struct {
+		Type string `json:"type"`
+	} `json:"plugins"`
+}
+
+// pluginTypes returns the plugin types the config references
+func (c cniConfig) pluginTypes() []string {
+	if len(c.Plugins) == 0 {
+		if c.Type == "" {
+			return nil
+		}
+		return []string{c.Type}
+	}
+	types := make([]string, 0, len(c.Plugins))
+	for _, p := range c.Plugins {
+		types = append(types, p.Type)
+	}
+	return types
+}
+
+// CheckCNI checks the CNI network config which the container runtime loads, which is the first valid config file
+// in confDir in lexical order the same as libcni, and that the plugin binaries it references are executable in binDirs,
+// a comma-separated list of directories. The default dirs are used if empty. Pods are stuck in ContainerCreating if either is missing.
+func CheckCNI(w io.Writer, confDir, binDirs string) error {
+	if confDir == "" {
+		confDir = constants.DefaultCNIConfDir
+	}
+	if binDirs == "" {
+		binDirs = constants.DefaultCNIBinDir
+	}
+	entries, err := os.ReadDir(confDir)
+	if err != nil {
+		return fmt.Errorf("read CNI config dir %s failed: %v, pods are stuck in ContainerCreating without a CNI config", confDir, err)
+	}
+	var names []string
+	for _, e := range entries {
+		switch filepath.Ext(e.Name()) {
+		case ".conflist", ".conf", ".json":
+			if !e.IsDir() {
+				names = append(names, e.Name())
+			}
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		file := filepath.Join(confDir, name)
+		data, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(w, "skip CNI config %s, %v\n", file, err)
+			continue
+		}
+		var conf cniConfig
+		if err := json.Unmarshal(data, &conf); err != nil {
+			fmt.Fprintf(w, "skip CNI config %s, invalid json: %v\n", file, err)
+			continue
+		}
+		types := conf.pluginTypes()
+		if conf.Name == "" || len(types) == 0 || slices.Contains(types, "") {
+			fmt.Fprintf(w, "skip CNI config %s, the network name or the plugin type is missing\n", file)
+			continue
+		}
+
+		fmt.Fprintf(w, "CNI config %s is used, network: %s\n", file, conf.Name)
+		var missing []string
+		for _, t := range types {
+			path, err := findCNIPlugin(t, binDirs)
+			if err != nil {
+				missing = append(missing, err.Error())
+				continue
+			}
+			fmt.Fprintf(w, "CNI plugin %s: %s\n", t, path)
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("CNI network %s of %s is broken, %s", conf.Name, file, strings.Join(missing, "; "))
+		}
+		return nil
+	}
+	return fmt.Errorf("no valid CNI config is found in %s, pods are stuck in ContainerCreating without a CNI config", confDir)
+}
+
+// findCNIPlugin returns the path of the executable plugin binary in the first of binDirs holding it
+func findCNIPlugin(pluginType, binDirs string) (string, error) {
+	for _, dir := range strings.Split(binDirs, ",") {
+		dir = strings.TrimSpace(dir)
+		if dir == "" {
+			continue
+		}
+		path := filepath.Join(dir, pluginType)
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if info.Mode().Perm()&0111 == 0 {
+			return "", fmt.Errorf("CNI plugin %s is not executable", path)
+		}
+		return path, nil
+	}
+	return "", fmt.Errorf("CNI plugin %s is not found in %s", pluginType, binDirs)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCNI(t *testing.T) {
+	writeFile := func(t *testing.T, file, content string, perm os.FileMode) {
+		require.NoError(t, os.WriteFile(file, []byte(content), perm))
+	}
+	newDirs := func(t *testing.T) (string, string) {
+		confDir, binDir := filepath.Join(t.TempDir(), "net.d"), filepath.Join(t.TempDir(), "bin")
+		require.NoError(t, os.MkdirAll(confDir, 0755))
+		require.NoError(t, os.MkdirAll(binDir, 0755))
+		return confDir, binDir
+	}
+
+	t.Run("conflist with the plugins", func(t *testing.T) {
+		confDir, binDir := newDirs(t)
+		writeFile(t, filepath.Join(confDir, "00-invalid.conflist"), "{", 0644)
+		writeFile(t, filepath.Join(confDir, "10-flannel.conflist"),
+			`{"name":"cbr0","plugins":[{"type":"flannel"},{"type":"portmap"}]}`, 0644)
+		writeFile(t, filepath.Join(confDir, "20-bridge.conf"), `{"name":"bridge","type":"bridge"}`, 0644)
+		writeFile(t, filepath.Join(binDir, "flannel"), "", 0755)
+		writeFile(t, filepath.Join(binDir, "portmap"), "", 0755)
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckCNI(buf, confDir, "/not-exist,"+binDir))
+		assert.Contains(t, buf.String(), "skip CNI config "+filepath.Join(confDir, "00-invalid.conflist")+", invalid json")
+		assert.Contains(t, buf.String(), "CNI config "+filepath.Join(confDir, "10-flannel.conflist")+" is used, network: cbr0\n")
+		assert.Contains(t, buf.String(), "CNI plugin flannel: "+filepath.Join(binDir, "flannel")+"\n")
+		assert.Contains(t, buf.String(), "CNI plugin portmap: "+filepath.Join(binDir, "portmap")+"\n")
+		assert.NotContains(t, buf.String(), "bridge")
+	})
+
+	t.Run("plugin is missing or not executable", func(t *testing.T) {
+		confDir, binDir := newDirs(t)
+		writeFile(t, filepath.Join(confDir, "10-bridge.conf"), `{"name":"mynet","type":"bridge"}`, 0644)
+		err := CheckCNI(&bytes.Buffer{}, confDir, binDir)
+		require.ErrorContains(t, err, "CNI plugin bridge is not found in "+binDir)
+
+		writeFile(t, filepath.Join(binDir, "bridge"), "", 0644)
+		err = CheckCNI(&bytes.Buffer{}, confDir, binDir)
+		require.ErrorContains(t, err, "CNI plugin "+filepath.Join(binDir, "bridge")+" is not executable")
+	})
+
+	t.Run("no valid config", func(t *testing.T) {
+		confDir, binDir := newDirs(t)
+		writeFile(t, filepath.Join(confDir, "10-bridge.conf"), `{"type":"bridge"}`, 0644)
+		writeFile(t, filepath.Join(confDir, "README"), "", 0644)
+		buf := &bytes.Buffer{}
+		err := CheckCNI(buf, confDir, binDir)
+		require.ErrorContains(t, err, "no valid CNI config is found in "+confDir)
+		assert.Contains(t, buf.String(), "the network name or the plugin type is missing")
+	})
+
+	t.Run("config dir is not exists", func(t *testing.T) {
+		err := CheckCNI(&bytes.Buffer{}, filepath.Join(t.TempDir(), "not-exist"), "")
+		require.ErrorContains(t, err, "read CNI config dir")
+	})
+}
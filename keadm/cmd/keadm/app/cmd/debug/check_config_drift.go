@@ -0,0 +1,155 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/kubeedge/api/apis/common/constants"
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+)
+
+// edgecoreProcess is a running edgecore process, its config flag and its start time
+type edgecoreProcess struct {
+	Pid     int32
+	Config  string
+	Started time.Time
+}
+
+// CheckConfigDrift checks the running edgecore uses the edge config configPath as it is now,
+// it warns if edgecore runs with another config file, or if the config is modified after edgecore started,
+// the cloudhub server, the transport and the node name in the file are not applied until edgecore is restarted.
+func CheckConfigDrift(w io.Writer, configPath string, edgeconfig *v1alpha2.EdgeCoreConfig) error {
+	if configPath == util.EdgecoreConfigStdin {
+		fmt.Fprintf(w, "edge config is read from stdin, it is not compared with the config edgecore runs with\n")
+		return nil
+	}
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return warningError(fmt.Errorf("failed to stat edge config %s: %v", configPath, err))
+	}
+	procs, err := edgecoreProcesses()
+	if err != nil {
+		return warningError(fmt.Errorf("failed to get the edgecore processes: %v", err))
+	}
+	if drifts := configDrifts(configPath, info.ModTime(), procs, edgeconfig); len(drifts) > 0 {
+		return warningError(fmt.Errorf("%s", strings.Join(drifts, "\n")))
+	}
+	fmt.Fprintf(w, "edgecore is running with edge config %s as it is now\n", configPath)
+	return nil
+}
+
+// configDrifts returns the reasons why the edgecore processes procs may not run with the edge config configPath
+// which is last modified at modTime
+func configDrifts(configPath string, modTime time.Time, procs []edgecoreProcess, edgeconfig *v1alpha2.EdgeCoreConfig) []string {
+	var drifts []string
+	for _, proc := range procs {
+		if !sameFile(proc.Config, configPath) {
+			drifts = append(drifts, fmt.Sprintf("edgecore %d is running with edge config %s instead of %s, diagnose it with --config %s",
+				proc.Pid, proc.Config, configPath, proc.Config))
+			continue
+		}
+		if modTime.After(proc.Started) {
+			drifts = append(drifts, fmt.Sprintf("edge config %s is modified at %s after edgecore %d started at %s, "+
+				"%s are not applied until edgecore is restarted", configPath, modTime.Format(time.RFC3339), proc.Pid,
+				proc.Started.Format(time.RFC3339), configKeyValues(edgeconfig)))
+		}
+	}
+	return drifts
+}
+
+// configKeyValues describes the cloudhub server, the transport and the node name of the edge config
+func configKeyValues(c *v1alpha2.EdgeCoreConfig) string {
+	server, transport := "", "none"
+	if eh := c.Modules.EdgeHub; eh != nil {
+		switch {
+		case eh.WebSocket != nil && eh.WebSocket.Enable:
+			server, transport = eh.WebSocket.Server, "websocket"
+		case eh.Quic != nil && eh.Quic.Enable:
+			server, transport = eh.Quic.Server, "quic"
+		}
+	}
+	var nodeName string
+	if edged := c.Modules.Edged; edged != nil {
+		nodeName = edged.HostnameOverride
+	}
+	return fmt.Sprintf("the cloudhub server %q, the transport %s and the node name %q of it", server, transport, nodeName)
+}
+
+// edgecoreProcesses returns the running edgecore processes with the config flags they are started with
+func edgecoreProcesses() ([]edgecoreProcess, error) {
+	pids, err := processPids(constants.KubeEdgeBinaryName)
+	if err != nil {
+		return nil, err
+	}
+	procs := make([]edgecoreProcess, 0, len(pids))
+	for _, pid := range pids {
+		proc, err := process.NewProcess(pid)
+		if err != nil {
+			// the process exits after it is listed
+			continue
+		}
+		args, err := proc.CmdlineSlice()
+		if err != nil {
+			return nil, fmt.Errorf("get the command line of edgecore %d failed: %v", pid, err)
+		}
+		created, err := proc.CreateTime()
+		if err != nil {
+			return nil, fmt.Errorf("get the start time of edgecore %d failed: %v", pid, err)
+		}
+		procs = append(procs, edgecoreProcess{Pid: pid, Config: configFlag(args), Started: time.UnixMilli(created)})
+	}
+	return procs, nil
+}
+
+// configFlag returns the value of the --config flag in the edgecore command line args,
+// edgecore reads the default edge config if the flag is not set
+func configFlag(args []string) string {
+	for i, arg := range args {
+		switch {
+		case arg == "--config" && i+1 < len(args):
+			return args[i+1]
+		case strings.HasPrefix(arg, "--config="):
+			return strings.TrimPrefix(arg, "--config=")
+		}
+	}
+	return constants.EdgecoreConfigPath
+}
+
+// sameFile returns whether the paths a and b refer to the same file, the symlinks are resolved
+func sameFile(a, b string) bool {
+	if filepath.Clean(a) == filepath.Clean(b) {
+		return true
+	}
+	ia, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	ib, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(ia, ib)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/common/constants"
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+)
+
+func TestConfigFlag(t *testing.T) {
+	assert.Equal(t, "/tmp/edgecore.yaml", configFlag([]string{"edgecore", "--config", "/tmp/edgecore.yaml"}))
+	assert.Equal(t, "/tmp/edgecore.yaml", configFlag([]string{"edgecore", "--config=/tmp/edgecore.yaml", "--v=4"}))
+	assert.Equal(t, constants.EdgecoreConfigPath, configFlag([]string{"edgecore"}))
+}
+
+func TestConfigDrifts(t *testing.T) {
+	edgeconfig := &v1alpha2.EdgeCoreConfig{Modules: &v1alpha2.Modules{
+		EdgeHub: &v1alpha2.EdgeHub{WebSocket: &v1alpha2.EdgeHubWebSocket{Enable: true, Server: "10.0.0.1:10000"}},
+		Edged:   &v1alpha2.Edged{TailoredKubeletFlag: v1alpha2.TailoredKubeletFlag{HostnameOverride: "edge-1"}},
+	}}
+	started := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	drifts := configDrifts("/etc/kubeedge/config/edgecore.yaml", started.Add(-time.Hour),
+		[]edgecoreProcess{{Pid: 1, Config: "/etc/kubeedge/config/edgecore.yaml", Started: started}}, edgeconfig)
+	assert.Empty(t, drifts)
+
+	drifts = configDrifts("/etc/kubeedge/config/edgecore.yaml", started.Add(time.Hour),
+		[]edgecoreProcess{{Pid: 1, Config: "/etc/kubeedge/config/edgecore.yaml", Started: started}}, edgeconfig)
+	require.Len(t, drifts, 1)
+	assert.Contains(t, drifts[0], "after edgecore 1 started")
+	assert.Contains(t, drifts[0], `the cloudhub server "10.0.0.1:10000", the transport websocket and the node name "edge-1"`)
+
+	drifts = configDrifts("/etc/kubeedge/config/edgecore.yaml", started.Add(time.Hour),
+		[]edgecoreProcess{{Pid: 2, Config: "/opt/edgecore.yaml", Started: started}}, edgeconfig)
+	require.Len(t, drifts, 1)
+	assert.Contains(t, drifts[0], "edgecore 2 is running with edge config /opt/edgecore.yaml instead of /etc/kubeedge/config/edgecore.yaml")
+}
+
+func TestCheckConfigDrift(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "edgecore.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("apiVersion: edgecore.config.kubeedge.io/v1alpha2\n"), 0600))
+	edgeconfig := &v1alpha2.EdgeCoreConfig{Modules: &v1alpha2.Modules{}}
+
+	var started time.Time
+	patches := gomonkey.ApplyFunc(edgecoreProcesses, func() ([]edgecoreProcess, error) {
+		return []edgecoreProcess{{Pid: 1, Config: configPath, Started: started}}, nil
+	})
+	defer patches.Reset()
+
+	started = time.Now().Add(time.Hour)
+	buf := &bytes.Buffer{}
+	require.NoError(t, CheckConfigDrift(buf, configPath, edgeconfig))
+	assert.Contains(t, buf.String(), "as it is now")
+
+	started = time.Now().Add(-time.Hour)
+	err := CheckConfigDrift(&bytes.Buffer{}, configPath, edgeconfig)
+	require.Error(t, err)
+	assert.True(t, isDiagnoseWarning(err))
+	assert.Contains(t, err.Error(), "not applied until edgecore is restarted")
+
+	buf.Reset()
+	require.NoError(t, CheckConfigDrift(buf, util.EdgecoreConfigStdin, edgeconfig))
+	assert.Contains(t, buf.String(), "edge config is read from stdin")
+}
+
+func TestSameFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "edgecore.yaml")
+	require.NoError(t, os.WriteFile(file, nil, 0600))
+	link := filepath.Join(dir, "link.yaml")
+	require.NoError(t, os.Symlink(file, link))
+
+	assert.True(t, sameFile(file, filepath.Join(dir, ".", "edgecore.yaml")))
+	assert.True(t, sameFile(link, file))
+	assert.False(t, sameFile(file, filepath.Join(dir, "other.yaml")))
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// conntrackCountKey and conntrackMaxKey are the sysctls of the connections tracked by netfilter and the size of the table
+	conntrackCountKey = "net.netfilter.nf_conntrack_count"
+	conntrackMaxKey   = "net.netfilter.nf_conntrack_max"
+	// conntrackWarnRatio is the ratio of nf_conntrack_max, above which the conntrack table is near full
+	conntrackWarnRatio = 0.8
+)
+
+// CheckConntrack checks the connections tracked by netfilter in sysDir against nf_conntrack_max. Once the table is full
+// the kernel drops the packets of the new connections, including the ones to cloudhub, which shows up as an intermittent
+// sync failure that a reachability probe misses. It warns if the table is near full and fails if it is full,
+// and it is skipped if nf_conntrack is not loaded, so the connections are not tracked.
+func CheckConntrack(w io.Writer, sysDir string) error {
+	if _, err := os.Stat(filepath.Join(sysDir, "net", "netfilter", "nf_conntrack_count")); os.IsNotExist(err) {
+		return skippedError("nf_conntrack is not loaded, the connections are not tracked")
+	}
+	count, err := readSysctlUint(sysDir, conntrackCountKey)
+	if err != nil {
+		return err
+	}
+	limit, err := readSysctlUint(sysDir, conntrackMaxKey)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "conntrack table: %d connections tracked, nf_conntrack_max %d\n", count, limit)
+	if limit == 0 {
+		return errors.New("nf_conntrack_max is 0, no connection can be tracked")
+	}
+	if count >= limit {
+		return fmt.Errorf("conntrack table is full, %d/%d connections are tracked and the new connections are dropped, "+
+			"raise %s by sysctl or lower the connections of the node", count, limit, conntrackMaxKey)
+	}
+	if float64(count) >= float64(limit)*conntrackWarnRatio {
+		return warningError(fmt.Errorf("conntrack table is near full, %d/%d connections are tracked, the new connections "+
+			"including the ones to cloudhub are dropped once it is full, raise %s by sysctl", count, limit, conntrackMaxKey))
+	}
+	return nil
+}
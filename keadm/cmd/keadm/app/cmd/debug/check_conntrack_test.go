@@ -0,0 +1,89 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckConntrack(t *testing.T) {
+	cases := []struct {
+		name           string
+		count          string
+		max            string
+		expectedErr    string
+		expectedWarn   bool
+		expectedOutput string
+	}{
+		{
+			name:           "below the limit",
+			count:          "1024",
+			max:            "262144",
+			expectedOutput: "conntrack table: 1024 connections tracked, nf_conntrack_max 262144\n",
+		},
+		{
+			name:         "near the limit",
+			count:        "60000",
+			max:          "65536",
+			expectedErr:  "conntrack table is near full, 60000/65536 connections are tracked",
+			expectedWarn: true,
+		},
+		{
+			name:        "table is full",
+			count:       "65536",
+			max:         "65536",
+			expectedErr: "conntrack table is full, 65536/65536 connections are tracked and the new connections are dropped",
+		},
+		{
+			name:        "invalid max",
+			count:       "1024",
+			max:         "many",
+			expectedErr: `invalid sysctl net.netfilter.nf_conntrack_max "many"`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := t.TempDir()
+			netfilter := filepath.Join(dir, "net", "netfilter")
+			require.NoError(t, os.MkdirAll(netfilter, 0700))
+			require.NoError(t, os.WriteFile(filepath.Join(netfilter, "nf_conntrack_count"), []byte(c.count+"\n"), 0600))
+			require.NoError(t, os.WriteFile(filepath.Join(netfilter, "nf_conntrack_max"), []byte(c.max+"\n"), 0600))
+
+			buf := &bytes.Buffer{}
+			err := CheckConntrack(buf, dir)
+			if c.expectedErr == "" {
+				require.NoError(t, err)
+				assert.Equal(t, c.expectedOutput, buf.String())
+				return
+			}
+			require.ErrorContains(t, err, c.expectedErr)
+			assert.Equal(t, c.expectedWarn, isDiagnoseWarning(err))
+		})
+	}
+
+	t.Run("nf_conntrack is not loaded", func(t *testing.T) {
+		err := CheckConntrack(&bytes.Buffer{}, t.TempDir())
+		require.EqualError(t, err, "nf_conntrack is not loaded, the connections are not tracked")
+		assert.True(t, isDiagnoseSkipped(err))
+	})
+}
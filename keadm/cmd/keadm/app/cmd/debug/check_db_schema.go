@@ -0,0 +1,169 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/beego/beego/v2/client/orm"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtclient"
+	eventbusdao "github.com/kubeedge/kubeedge/edge/pkg/eventbus/dao"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	metav2 "github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/v2"
+	servicebusdao "github.com/kubeedge/kubeedge/edge/pkg/servicebus/dao"
+	"github.com/kubeedge/kubeedge/pkg/version"
+)
+
+// edgecoreModels are the models edgecore registers, edgecore records no schema version in the database,
+// instead orm.RunSyncdb creates the missing tables and columns of the models when edgecore starts
+var edgecoreModels = []interface{}{
+	new(dao.Meta),
+	new(metav2.MetaV2),
+	new(servicebusdao.TargetUrls),
+	new(eventbusdao.SubTopics),
+	new(dtclient.Device),
+	new(dtclient.DeviceAttr),
+	new(dtclient.DeviceTwin),
+}
+
+// CheckDatabaseSchema checks whether the tables and the columns in the database match the models of this version,
+// the missing ones mean the database is created by an older edgecore which is not restarted since the upgrade,
+// and the queries of the missing columns fail. The extra columns are reported as they are created by a newer edgecore.
+func CheckDatabaseSchema(w io.Writer, o orm.Ormer) error {
+	if o == nil {
+		return warningError(errors.New("the database is not initialized, the schema is not checked"))
+	}
+	actual, err := databaseTableColumns(o)
+	if err != nil {
+		return warningError(fmt.Errorf("failed to read the database schema: %v", err))
+	}
+
+	var missing []string
+	for _, model := range edgecoreModels {
+		table, columns := modelTableColumns(model)
+		actualColumns, ok := actual[table]
+		if !ok {
+			missing = append(missing, "table "+table)
+			continue
+		}
+		expected := make(map[string]bool, len(columns))
+		for _, column := range columns {
+			expected[column] = true
+			if !actualColumns[column] {
+				missing = append(missing, fmt.Sprintf("column %s.%s", table, column))
+			}
+		}
+		for _, column := range sortedKeys(actualColumns) {
+			if !expected[column] {
+				fmt.Fprintf(w, "column %s.%s is not used by version %s, it may be created by a newer edgecore\n",
+					table, column, version.Get().GitVersion)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return warningError(fmt.Errorf("the database schema is older than version %s, %s are missing, "+
+			"the queries of them fail until edgecore of the version is restarted and creates them",
+			version.Get().GitVersion, strings.Join(missing, ", ")))
+	}
+	fmt.Fprintf(w, "database schema matches the %d tables of version %s\n", len(edgecoreModels), version.Get().GitVersion)
+	return nil
+}
+
+// databaseTableColumns returns the columns of the tables in the sqlite database keyed by the table names
+func databaseTableColumns(o orm.Ormer) (map[string]map[string]bool, error) {
+	var tables orm.ParamsList
+	if _, err := o.Raw("SELECT name FROM sqlite_master WHERE type = 'table'").ValuesFlat(&tables); err != nil {
+		return nil, err
+	}
+	columns := make(map[string]map[string]bool, len(tables))
+	for _, t := range tables {
+		table := fmt.Sprint(t)
+		var rows []orm.Params
+		if _, err := o.Raw(fmt.Sprintf("PRAGMA table_info(%q)", table)).Values(&rows); err != nil {
+			return nil, err
+		}
+		columns[table] = make(map[string]bool, len(rows))
+		for _, row := range rows {
+			columns[table][fmt.Sprint(row["name"])] = true
+		}
+	}
+	return columns, nil
+}
+
+// modelTableColumns returns the table and the columns of the model the same as orm,
+// the table is returned by the TableName method or is the snake name of the struct,
+// and a column is set by the column() of the orm tag or is the snake name of the field.
+func modelTableColumns(model interface{}) (string, []string) {
+	val := reflect.ValueOf(model)
+	table := snakeName(reflect.Indirect(val).Type().Name())
+	if fn := val.MethodByName("TableName"); fn.IsValid() {
+		if out := fn.Call(nil); len(out) > 0 && out[0].Kind() == reflect.String {
+			table = out[0].String()
+		}
+	}
+
+	typ := reflect.Indirect(val).Type()
+	var columns []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("orm")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		column := snakeName(field.Name)
+		for _, attr := range strings.Split(tag, ";") {
+			attr = strings.TrimSpace(attr)
+			if strings.HasPrefix(attr, "column(") && strings.HasSuffix(attr, ")") {
+				column = strings.TrimSuffix(strings.TrimPrefix(attr, "column("), ")")
+			}
+		}
+		columns = append(columns, column)
+	}
+	return table, columns
+}
+
+// snakeName converts XxYy to xx_yy the same as the default name strategy of orm
+func snakeName(s string) string {
+	var b strings.Builder
+	word := false
+	for i := 0; i < len(s); i++ {
+		if word && s[i] >= 'A' && s[i] <= 'Z' {
+			b.WriteByte('_')
+		}
+		if s[i] != '_' {
+			word = true
+		}
+		b.WriteByte(s[i])
+	}
+	return strings.ToLower(b.String())
+}
+
+// sortedKeys returns the keys of the set in order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
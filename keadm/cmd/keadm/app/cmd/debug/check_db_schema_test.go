@@ -0,0 +1,98 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/beego/beego/v2/client/orm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtclient"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	metav2 "github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/v2"
+)
+
+// newSchemaTestDB returns an orm of a new sqlite database with the tables created by the statements
+func newSchemaTestDB(t *testing.T, alias string, statements ...string) orm.Ormer {
+	require.NoError(t, orm.RegisterDataBase(alias, "sqlite3", filepath.Join(t.TempDir(), "edgecore.db")))
+	o := orm.NewOrmUsingDB(alias)
+	for _, statement := range statements {
+		_, err := o.Raw(statement).Exec()
+		require.NoError(t, err)
+	}
+	return o
+}
+
+// createTableStatement returns the statement creating the table of the model with its columns
+func createTableStatement(model interface{}, extraColumns ...string) string {
+	table, columns := modelTableColumns(model)
+	return fmt.Sprintf(`CREATE TABLE %s ("%s")`, table, strings.Join(append(columns, extraColumns...), `", "`))
+}
+
+func TestCheckDatabaseSchema(t *testing.T) {
+	t.Run("schema matches", func(t *testing.T) {
+		var statements []string
+		for _, model := range edgecoreModels {
+			statements = append(statements, createTableStatement(model))
+		}
+		o := newSchemaTestDB(t, "schema-matches", statements...)
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckDatabaseSchema(buf, o))
+		assert.Contains(t, buf.String(), "database schema matches the 7 tables of version")
+	})
+
+	t.Run("stale schema", func(t *testing.T) {
+		o := newSchemaTestDB(t, "schema-stale",
+			createTableStatement(new(dao.Meta), "extra"),
+			`CREATE TABLE device ("id", "name")`,
+		)
+		buf := &bytes.Buffer{}
+		err := CheckDatabaseSchema(buf, o)
+		require.Error(t, err)
+		assert.True(t, isDiagnoseWarning(err))
+		assert.Contains(t, err.Error(), "table meta_v2")
+		assert.Contains(t, err.Error(), "column device.description, column device.state, column device.last_online")
+		assert.Contains(t, buf.String(), "column meta.extra is not used by version")
+	})
+
+	t.Run("database is not initialized", func(t *testing.T) {
+		err := CheckDatabaseSchema(&bytes.Buffer{}, nil)
+		assert.True(t, isDiagnoseWarning(err))
+	})
+}
+
+func TestModelTableColumns(t *testing.T) {
+	table, columns := modelTableColumns(new(metav2.MetaV2))
+	assert.Equal(t, "meta_v2", table)
+	assert.Contains(t, columns, "groupversionresource")
+
+	table, columns = modelTableColumns(new(dtclient.DeviceAttr))
+	assert.Equal(t, "device_attr", table)
+	assert.Equal(t, []string{"id", "deviceid", "name", "description", "value", "optional", "attr_type", "metadata"}, columns)
+}
+
+func TestSnakeName(t *testing.T) {
+	assert.Equal(t, "target_urls", snakeName("TargetUrls"))
+	assert.Equal(t, "meta_v2", snakeName("MetaV2"))
+	assert.Equal(t, "_meta", snakeName("_Meta"))
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+// dnsAddon is a well-known DNS addon running as pods on the edge nodes, whose pods are matched by a label
+type dnsAddon struct {
+	Name       string
+	LabelKey   string
+	LabelValue string
+}
+
+// dnsAddons are the DNS addons CheckDNSAddon looks for in the edge database
+var dnsAddons = []dnsAddon{
+	{Name: "edgemesh-agent", LabelKey: "kubeedge", LabelValue: "edgemesh-agent"},
+	{Name: "coredns", LabelKey: "k8s-app", LabelValue: "kube-dns"},
+	{Name: "node-local-dns", LabelKey: "k8s-app", LabelValue: "node-local-dns"},
+}
+
+// dnsAddonNames returns the names of the well-known DNS addons
+func dnsAddonNames() string {
+	names := make([]string, 0, len(dnsAddons))
+	for _, addon := range dnsAddons {
+		names = append(names, addon.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// CheckDNSAddon checks the pods of the well-known DNS addons cached in the edge database, e.g. edgemesh-agent,
+// are Running and Ready, and that the DNS servers they serve resolve the kubernetes service of the cluster domain.
+// The DNS servers of a host network pod are the clusterDNS servers of edged, which the addon listens on,
+// and the DNS server of the other pods is the pod ip. The check passes if none of the addons is on the node.
+func CheckDNSAddon(w io.Writer, kubelet *v1alpha2.TailoredKubeletConfiguration, timeout int) error {
+	pods, err := QueryPodsFromDatabase(metav1.NamespaceAll)
+	if err != nil {
+		return err
+	}
+	clusterDomain := kubelet.ClusterDomain
+	if clusterDomain == "" {
+		clusterDomain = "cluster.local"
+	}
+	testName := "kubernetes.default.svc." + clusterDomain
+
+	var found int
+	var errs []error
+	for _, pod := range pods {
+		addon, ok := podDNSAddon(pod)
+		if !ok {
+			continue
+		}
+		found++
+		ref := pod.Namespace + "/" + pod.Name
+		status, ok, err := cachedPodStatus(pod.Namespace, pod.Name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			status = &pod.Status
+		}
+		cached := pod
+		cached.Status = *status
+		if reason := podFailureReason(&cached); reason != "" || status.Phase != v1.PodRunning {
+			if reason == "" {
+				reason = fmt.Sprintf("phase is %s", status.Phase)
+			}
+			errs = append(errs, fmt.Errorf("%s pod %s is not Ready, %s, the pods resolving with it fail to resolve the services",
+				addon.Name, ref, reason))
+			continue
+		}
+		fmt.Fprintf(w, "%s pod %s is Running and Ready\n", addon.Name, ref)
+
+		servers := kubelet.ClusterDNS
+		if !pod.Spec.HostNetwork {
+			servers = nil
+			if status.PodIP != "" {
+				servers = []string{status.PodIP}
+			}
+		}
+		if len(servers) == 0 {
+			fmt.Fprintf(w, "the DNS server of %s pod %s is unknown, skip resolving %s\n", addon.Name, ref, testName)
+			continue
+		}
+		for _, server := range servers {
+			if err := lookupHostVia(w, server, testName, timeout); err != nil {
+				errs = append(errs, networkError(fmt.Errorf("%s pod %s: %v", addon.Name, ref, err)))
+			}
+		}
+	}
+	if found == 0 {
+		fmt.Fprintf(w, "no pods of the DNS addons %s are on the node\n", dnsAddonNames())
+	}
+	return errors.Join(errs...)
+}
+
+// podDNSAddon returns the well-known DNS addon which the pod belongs to
+func podDNSAddon(pod v1.Pod) (dnsAddon, bool) {
+	for _, addon := range dnsAddons {
+		if pod.Labels[addon.LabelKey] == addon.LabelValue {
+			return addon, true
+		}
+	}
+	return dnsAddon{}, false
+}
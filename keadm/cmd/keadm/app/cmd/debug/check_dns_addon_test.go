@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+func TestCheckDNSAddon(t *testing.T) {
+	readyStatus := v1.PodStatus{
+		Phase:      v1.PodRunning,
+		PodIP:      "10.244.0.5",
+		Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+	}
+	var pods []v1.Pod
+	statuses := map[string]v1.PodStatus{}
+	var lookups []string
+	patches := gomonkey.ApplyFunc(QueryPodsFromDatabase, func(_namespace string) ([]v1.Pod, error) {
+		return pods, nil
+	})
+	patches.ApplyFunc(cachedPodStatus, func(_namespace, podName string) (*v1.PodStatus, bool, error) {
+		status, ok := statuses[podName]
+		return &status, ok, nil
+	})
+	patches.ApplyFunc(lookupHostVia, func(w io.Writer, server, domain string, _timeout int) error {
+		lookups = append(lookups, server+" "+domain)
+		if server == "10.0.0.1" {
+			return fmt.Errorf("dns server %s failed to resolve %s: timeout", server, domain)
+		}
+		fmt.Fprintf(w, "dns server %s resolves %s\n", server, domain)
+		return nil
+	})
+	defer patches.Reset()
+	kubelet := &v1alpha2.TailoredKubeletConfiguration{ClusterDNS: []string{"169.254.96.16"}}
+
+	t.Run("no addon", func(t *testing.T) {
+		pods, lookups = []v1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default"}}}, nil
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckDNSAddon(buf, kubelet, 1))
+		assert.Contains(t, buf.String(), "no pods of the DNS addons edgemesh-agent, coredns, node-local-dns are on the node")
+		assert.Empty(t, lookups)
+	})
+
+	t.Run("host network addon resolves via clusterDNS", func(t *testing.T) {
+		pods, lookups = []v1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{Name: "edgemesh-agent-x", Namespace: "kubeedge", Labels: map[string]string{"kubeedge": "edgemesh-agent"}},
+			Spec:       v1.PodSpec{HostNetwork: true},
+		}}, nil
+		statuses = map[string]v1.PodStatus{"edgemesh-agent-x": readyStatus}
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckDNSAddon(buf, kubelet, 1))
+		assert.Contains(t, buf.String(), "edgemesh-agent pod kubeedge/edgemesh-agent-x is Running and Ready")
+		assert.Equal(t, []string{"169.254.96.16 kubernetes.default.svc.cluster.local"}, lookups)
+	})
+
+	t.Run("pod network addon resolves via pod ip", func(t *testing.T) {
+		pods, lookups = []v1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{Name: "coredns-x", Namespace: "kube-system", Labels: map[string]string{"k8s-app": "kube-dns"}},
+		}}, nil
+		statuses = map[string]v1.PodStatus{"coredns-x": readyStatus}
+		require.NoError(t, CheckDNSAddon(&bytes.Buffer{}, &v1alpha2.TailoredKubeletConfiguration{ClusterDomain: "edge.local"}, 1))
+		assert.Equal(t, []string{"10.244.0.5 kubernetes.default.svc.edge.local"}, lookups)
+	})
+
+	t.Run("addon not ready", func(t *testing.T) {
+		pods, lookups = []v1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{Name: "edgemesh-agent-x", Namespace: "kubeedge", Labels: map[string]string{"kubeedge": "edgemesh-agent"}},
+			Spec:       v1.PodSpec{HostNetwork: true},
+		}}, nil
+		statuses = map[string]v1.PodStatus{"edgemesh-agent-x": {
+			Phase: v1.PodRunning,
+			ContainerStatuses: []v1.ContainerStatus{{Name: "agent",
+				State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}}},
+		}}
+		err := CheckDNSAddon(&bytes.Buffer{}, kubelet, 1)
+		require.ErrorContains(t, err, "edgemesh-agent pod kubeedge/edgemesh-agent-x is not Ready, container agent is waiting, reason: CrashLoopBackOff")
+		assert.Empty(t, lookups)
+	})
+
+	t.Run("resolve failed", func(t *testing.T) {
+		pods, lookups = []v1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodelocaldns-x", Namespace: "kube-system", Labels: map[string]string{"k8s-app": "node-local-dns"}},
+			Spec:       v1.PodSpec{HostNetwork: true},
+			Status:     readyStatus,
+		}}, nil
+		statuses = map[string]v1.PodStatus{}
+		err := CheckDNSAddon(&bytes.Buffer{}, &v1alpha2.TailoredKubeletConfiguration{ClusterDNS: []string{"10.0.0.1"}}, 1)
+		require.ErrorContains(t, err, "node-local-dns pod kube-system/nodelocaldns-x: dns server 10.0.0.1 failed")
+		assert.Equal(t, DiagnoseErrorCodeNetworkUnreachable, diagnoseErrorCode(err))
+	})
+}
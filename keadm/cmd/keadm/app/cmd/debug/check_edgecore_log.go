@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/util/files"
+)
+
+const (
+	// klogTimeLayout is the time of the klog header, e.g. the "1014 12:04:05.000000" of "E1014 12:04:05.000000 1 edged.go:1] msg"
+	klogTimeLayout = "0102 15:04:05.000000"
+	// journalTimeLayout is the time format accepted by the --since of journalctl
+	journalTimeLayout = "2006-01-02 15:04:05"
+	// maxLogLineSize is the max size of a line of the edgecore logs, the rest of a longer line is dropped
+	maxLogLineSize = 1024 * 1024
+)
+
+var (
+	// klogHeaderPattern matches the severity and the time of the klog header
+	klogHeaderPattern = regexp.MustCompile(`^([IWEF])(\d{4} \d{2}:\d{2}:\d{2}\.\d{6})`)
+	// edgecoreCrashPattern matches the go panics, the go runtime fatal errors and the FATAL lines
+	edgecoreCrashPattern = regexp.MustCompile(`^(panic: |fatal error: )|\bFATAL\b`)
+)
+
+// CheckEdgecoreLog checks the edgecore logs within the window for the panics and the fatal errors,
+// which catches the crashes between which edgecore looks healthy. The log file is read if it exists,
+// otherwise the journal of the edgecore unit, since edgecore logs to its stderr by default.
+func CheckEdgecoreLog(w io.Writer, logFile string, window time.Duration) error {
+	now := time.Now()
+	since := now.Add(-window)
+	var crashes []string
+	var err error
+	switch {
+	case files.FileExists(logFile):
+		crashes, err = edgecoreLogFileCrashes(logFile, since, now)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "read the edgecore log file %s\n", logFile)
+	case util.HasSystemd():
+		out, err := util.ExecShellFilter(fmt.Sprintf(common.CmdGetEdgecoreJournal, since.Format(journalTimeLayout)))
+		if err != nil {
+			return fmt.Errorf("read the journal of edgecore.service failed: %v", err)
+		}
+		// the journal is already limited to the window
+		crashes, err = edgecoreLogCrashes(strings.NewReader(out), time.Time{}, now)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "read the journal of edgecore.service\n")
+	default:
+		fmt.Fprintf(w, "edgecore log file %s and systemd are not found, skip edgecore log check\n", logFile)
+		return nil
+	}
+
+	if len(crashes) == 0 {
+		fmt.Fprintf(w, "no panics or fatal errors are found in the edgecore logs of the last %v\n", window)
+		return nil
+	}
+	for _, line := range crashes {
+		fmt.Fprintf(w, "%s\n", line)
+	}
+	return fmt.Errorf("%d panics or fatal errors are found in the edgecore logs of the last %v, edgecore may be crashing, the last one: %s",
+		len(crashes), window, crashes[len(crashes)-1])
+}
+
+// edgecoreLogFileCrashes returns the panic and fatal lines of the log file logged after since
+func edgecoreLogFileCrashes(logFile string, since, now time.Time) ([]string, error) {
+	f, err := os.Open(logFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return edgecoreLogCrashes(f, since, now)
+}
+
+// edgecoreLogCrashes returns the panic and fatal lines logged after since, all the lines are matched if since is zero.
+// A line without the klog header, e.g. the panic of the go runtime, is logged at the time of the last klog header before it,
+// and the lines before any klog header are of unknown time, which are only matched if since is zero.
+func edgecoreLogCrashes(r io.Reader, since, now time.Time) ([]string, error) {
+	var crashes []string
+	var logged time.Time
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLogLineSize)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		header := klogHeaderPattern.FindStringSubmatch(line)
+		if header != nil {
+			if t, ok := parseKlogTime(header[2], now); ok {
+				logged = t
+			}
+		}
+		if !since.IsZero() && (logged.IsZero() || logged.Before(since)) {
+			continue
+		}
+		if (header != nil && header[1] == "F") || edgecoreCrashPattern.MatchString(line) {
+			crashes = append(crashes, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read the edgecore logs failed: %v", err)
+	}
+	return crashes, nil
+}
+
+// parseKlogTime parses the time of the klog header in the local time, which has no year.
+// The year of now is taken, or the last year if the time is later than now, e.g. the logs of Dec read in Jan.
+func parseKlogTime(value string, now time.Time) (time.Time, bool) {
+	t, err := time.ParseInLocation(klogTimeLayout, value, now.Location())
+	if err != nil {
+		return time.Time{}, false
+	}
+	t = t.AddDate(now.Year(), 0, 0)
+	if t.After(now.Add(time.Hour)) {
+		t = t.AddDate(-1, 0, 0)
+	}
+	return t, true
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+)
+
+func klogLine(severity string, t time.Time, msg string) string {
+	return fmt.Sprintf("%s%s    1234 edged.go:100] %s", severity, t.Format(klogTimeLayout), msg)
+}
+
+func TestEdgecoreLogCrashes(t *testing.T) {
+	now := time.Now()
+	logs := strings.Join([]string{
+		"panic: before any klog header",
+		klogLine("I", now.Add(-2*time.Hour), "edgecore started"),
+		"panic: runtime error: invalid memory address or nil pointer dereference",
+		klogLine("F", now.Add(-3*time.Minute), "init edged failed"),
+		klogLine("I", now.Add(-time.Minute), "edgecore started"),
+		"panic: assignment to entry in nil map",
+		"goroutine 1 [running]:",
+		"fatal error: concurrent map writes",
+		klogLine("E", now, "FATAL: sync failed"),
+		klogLine("E", now, "fatal is not matched in lower case"),
+	}, "\n")
+
+	crashes, err := edgecoreLogCrashes(strings.NewReader(logs), now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		klogLine("F", now.Add(-3*time.Minute), "init edged failed"),
+		"panic: assignment to entry in nil map",
+		"fatal error: concurrent map writes",
+		klogLine("E", now, "FATAL: sync failed"),
+	}, crashes)
+
+	// all the lines are matched without since
+	crashes, err = edgecoreLogCrashes(strings.NewReader(logs), time.Time{}, now)
+	require.NoError(t, err)
+	assert.Len(t, crashes, 6)
+}
+
+func TestParseKlogTime(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.Local)
+	got, ok := parseKlogTime("0102 09:30:00.000000", now)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 1, 2, 9, 30, 0, 0, time.Local), got)
+
+	// the logs of the last year
+	got, ok = parseKlogTime("1231 23:00:00.000000", now)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 12, 31, 23, 0, 0, 0, time.Local), got)
+
+	_, ok = parseKlogTime("invalid", now)
+	assert.False(t, ok)
+}
+
+func TestCheckEdgecoreLog(t *testing.T) {
+	now := time.Now()
+
+	t.Run("log file with a panic", func(t *testing.T) {
+		logFile := filepath.Join(t.TempDir(), "edgecore.log")
+		require.NoError(t, os.WriteFile(logFile, []byte(strings.Join([]string{
+			klogLine("I", now.Add(-time.Minute), "edgecore started"),
+			"panic: assignment to entry in nil map",
+		}, "\n")), 0600))
+
+		buf := &bytes.Buffer{}
+		err := CheckEdgecoreLog(buf, logFile, time.Hour)
+		require.EqualError(t, err, "1 panics or fatal errors are found in the edgecore logs of the last 1h0m0s, edgecore may be crashing, the last one: panic: assignment to entry in nil map")
+		assert.Contains(t, buf.String(), "read the edgecore log file "+logFile)
+	})
+
+	t.Run("healthy log file", func(t *testing.T) {
+		logFile := filepath.Join(t.TempDir(), "edgecore.log")
+		require.NoError(t, os.WriteFile(logFile, []byte(klogLine("I", now, "edgecore started")+"\n"), 0600))
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckEdgecoreLog(buf, logFile, time.Hour))
+		assert.Contains(t, buf.String(), "no panics or fatal errors are found in the edgecore logs of the last 1h0m0s")
+	})
+
+	logFile := filepath.Join(t.TempDir(), "not-exist.log")
+	t.Run("journal", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+		patches.ApplyFunc(util.HasSystemd, func() bool {
+			return true
+		})
+		var command string
+		patches.ApplyFunc(util.ExecShellFilter, func(c string) (string, error) {
+			command = c
+			return "panic: runtime error: index out of range\n", nil
+		})
+
+		err := CheckEdgecoreLog(&bytes.Buffer{}, logFile, time.Hour)
+		require.ErrorContains(t, err, "the last one: panic: runtime error: index out of range")
+		assert.Contains(t, command, "journalctl -u edgecore.service")
+	})
+
+	t.Run("journal is not readable", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+		patches.ApplyFunc(util.HasSystemd, func() bool {
+			return true
+		})
+		patches.ApplyFunc(util.ExecShellFilter, func(_c string) (string, error) {
+			return "", errors.New("permission denied")
+		})
+
+		err := CheckEdgecoreLog(&bytes.Buffer{}, logFile, time.Hour)
+		require.EqualError(t, err, "read the journal of edgecore.service failed: permission denied")
+	})
+
+	t.Run("no logs", func(t *testing.T) {
+		patches := gomonkey.ApplyFunc(util.HasSystemd, func() bool {
+			return false
+		})
+		defer patches.Reset()
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckEdgecoreLog(buf, logFile, time.Hour))
+		assert.Contains(t, buf.String(), "skip edgecore log check")
+	})
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/kubelet/eviction"
+	evictionapi "k8s.io/kubernetes/pkg/kubelet/eviction/api"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/pkg/util/files"
+)
+
+const (
+	// cgroupV1MemoryDir is the memory controller of the root cgroup on the cgroup v1 hosts
+	cgroupV1MemoryDir = "/sys/fs/cgroup/memory"
+	// procMeminfo is the memory statistics of the host, which is used on the cgroup v2 hosts
+	procMeminfo = "/proc/meminfo"
+	// evictionNearRatio is the ratio of a threshold, under which the available memory is near the threshold
+	evictionNearRatio = 1.25
+)
+
+// CheckEvictionPressure checks whether the available memory of the node is at or near the memory.available
+// eviction thresholds of edged. The available memory is computed the same as kubelet, i.e. the capacity
+// minus the working set of the root cgroup, where the working set is the usage minus the inactive file cache.
+func CheckEvictionPressure(w io.Writer, kubelet *v1alpha2.TailoredKubeletConfiguration) error {
+	thresholds, err := eviction.ParseThresholdConfig(nil, kubelet.EvictionHard, kubelet.EvictionSoft,
+		kubelet.EvictionSoftGracePeriod, kubelet.EvictionMinimumReclaim)
+	if err != nil {
+		return fmt.Errorf("parse eviction thresholds failed, %v", err)
+	}
+
+	capacity, workingSet, err := nodeMemoryWorkingSet()
+	if err != nil {
+		return err
+	}
+	available := int64(capacity) - int64(workingSet)
+	capacityQuantity := resource.NewQuantity(int64(capacity), resource.BinarySI)
+	fmt.Fprintf(w, "memory capacity: %.2f MB, working set: %.2f MB, available: %.2f MB\n",
+		float64(capacity)/common.MB, float64(workingSet)/common.MB, float64(available)/common.MB)
+
+	var reached []string
+	for _, threshold := range thresholds {
+		if threshold.Signal != evictionapi.SignalMemoryAvailable {
+			continue
+		}
+		kind := "hard"
+		if threshold.GracePeriod > 0 {
+			kind = fmt.Sprintf("soft (grace period %v)", threshold.GracePeriod)
+		}
+		value := evictionapi.GetThresholdQuantity(threshold.Value, capacityQuantity).Value()
+		switch {
+		case available < value:
+			reached = append(reached, fmt.Sprintf("memory.available %.2f MB is below the eviction %s threshold %.2f MB",
+				float64(available)/common.MB, kind, float64(value)/common.MB))
+		case float64(available) < float64(value)*evictionNearRatio:
+			fmt.Fprintf(w, "memory.available %.2f MB is near the eviction %s threshold %.2f MB, pods will be evicted if the usage keeps growing\n",
+				float64(available)/common.MB, kind, float64(value)/common.MB)
+		default:
+			fmt.Fprintf(w, "memory.available is above the eviction %s threshold %.2f MB\n", kind, float64(value)/common.MB)
+		}
+	}
+	if len(reached) > 0 {
+		return fmt.Errorf("%s, edged evicts the pods to reclaim memory", strings.Join(reached, "; "))
+	}
+	return nil
+}
+
+// nodeMemoryWorkingSet returns the memory capacity and the working set of the node in bytes,
+// the working set is read from the root memory cgroup on the cgroup v1 hosts, and from /proc/meminfo otherwise.
+func nodeMemoryWorkingSet() (uint64, uint64, error) {
+	meminfo, err := readKeyValues(procMeminfo, ":")
+	if err != nil {
+		return 0, 0, err
+	}
+	capacity := meminfo["MemTotal"] * 1024
+
+	usageFile := cgroupV1MemoryDir + "/memory.usage_in_bytes"
+	if files.FileExists(usageFile) {
+		data, err := os.ReadFile(usageFile)
+		if err != nil {
+			return 0, 0, err
+		}
+		usage, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid %s: %v", usageFile, err)
+		}
+		stat, err := readKeyValues(cgroupV1MemoryDir+"/memory.stat", " ")
+		if err != nil {
+			return 0, 0, err
+		}
+		return capacity, subtractFloor(usage, stat["total_inactive_file"]), nil
+	}
+
+	// the values in /proc/meminfo are in kB
+	usage := (meminfo["MemTotal"] - meminfo["MemFree"]) * 1024
+	return capacity, subtractFloor(usage, meminfo["Inactive(file)"]*1024), nil
+}
+
+// readKeyValues reads the lines of "<key><sep><value> [unit]" in the file, the unit is ignored
+func readKeyValues(file, sep string) (map[string]uint64, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), sep)
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		if v, err := strconv.ParseUint(fields[0], 10, 64); err == nil {
+			values[strings.TrimSpace(key)] = v
+		}
+	}
+	return values, scanner.Err()
+}
+
+// subtractFloor returns a - b, or 0 if b is greater than a
+func subtractFloor(a, b uint64) uint64 {
+	if b > a {
+		return 0
+	}
+	return a - b
+}
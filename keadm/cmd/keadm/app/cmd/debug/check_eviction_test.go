@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+func TestCheckEvictionPressure(t *testing.T) {
+	const gi = 1024 * 1024 * 1024
+	kubelet := &v1alpha2.TailoredKubeletConfiguration{
+		EvictionHard:            map[string]string{"memory.available": "100Mi", "nodefs.available": "10%"},
+		EvictionSoft:            map[string]string{"memory.available": "10%"},
+		EvictionSoftGracePeriod: map[string]string{"memory.available": "1m"},
+	}
+
+	cases := []struct {
+		name         string
+		workingSet   uint64
+		expectedErr  string
+		expectedOuts []string
+	}{
+		{
+			name:       "enough memory",
+			workingSet: 4 * gi,
+			expectedOuts: []string{
+				"memory capacity: 8192.00 MB, working set: 4096.00 MB, available: 4096.00 MB",
+				"memory.available is above the eviction hard threshold 100.00 MB",
+				"memory.available is above the eviction soft (grace period 1m0s) threshold 819.20 MB",
+			},
+		},
+		{
+			name:       "near the soft threshold",
+			workingSet: 8*gi - 900*1024*1024,
+			expectedOuts: []string{
+				"memory.available 900.00 MB is near the eviction soft (grace period 1m0s) threshold 819.20 MB",
+			},
+		},
+		{
+			name:        "below the soft threshold",
+			workingSet:  8*gi - 500*1024*1024,
+			expectedErr: "memory.available 500.00 MB is below the eviction soft (grace period 1m0s) threshold 819.20 MB, edged evicts the pods",
+		},
+		{
+			name:        "below the hard threshold",
+			workingSet:  8*gi - 50*1024*1024,
+			expectedErr: "memory.available 50.00 MB is below the eviction hard threshold 100.00 MB",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			patches := gomonkey.ApplyFunc(nodeMemoryWorkingSet, func() (uint64, uint64, error) {
+				return 8 * gi, c.workingSet, nil
+			})
+			defer patches.Reset()
+
+			buf := &bytes.Buffer{}
+			err := CheckEvictionPressure(buf, kubelet)
+			if c.expectedErr != "" {
+				require.ErrorContains(t, err, c.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			for _, out := range c.expectedOuts {
+				assert.Contains(t, buf.String(), out)
+			}
+		})
+	}
+
+	t.Run("invalid thresholds", func(t *testing.T) {
+		err := CheckEvictionPressure(&bytes.Buffer{}, &v1alpha2.TailoredKubeletConfiguration{
+			EvictionSoft: map[string]string{"memory.available": "10%"},
+		})
+		require.ErrorContains(t, err, "parse eviction thresholds failed")
+	})
+}
+
+func TestNodeMemoryWorkingSet(t *testing.T) {
+	if _, err := os.Stat(procMeminfo); err != nil {
+		t.Skipf("%s is not available", procMeminfo)
+	}
+	capacity, workingSet, err := nodeMemoryWorkingSet()
+	require.NoError(t, err)
+	assert.Greater(t, capacity, uint64(0))
+	assert.LessOrEqual(t, workingSet, capacity)
+}
+
+func TestReadKeyValues(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "meminfo")
+	require.NoError(t, os.WriteFile(file, []byte("MemTotal:        8000 kB\nMemFree:  100 kB\nInactive(file):  200 kB\ninvalid\n"), 0600))
+
+	values, err := readKeyValues(file, ":")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint64{"MemTotal": 8000, "MemFree": 100, "Inactive(file)": 200}, values)
+
+	_, err = readKeyValues(filepath.Join(t.TempDir(), "not-exist"), ":")
+	assert.Error(t, err)
+}
+
+func TestSubtractFloor(t *testing.T) {
+	assert.Equal(t, uint64(1), subtractFloor(3, 2))
+	assert.Equal(t, uint64(0), subtractFloor(2, 3))
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kubeedge/api/apis/common/constants"
+)
+
+const (
+	// procDir is the root of the processes, the fds of a process are the entries of /proc/<pid>/fd
+	procDir = "/proc"
+	// fileLimitWarnRatio is the ratio of a limit, above which the usage is near the limit
+	fileLimitWarnRatio = 0.8
+)
+
+// fileLimitUsage is the usage of a limit of the files which the host or a process opens
+type fileLimitUsage struct {
+	name  string
+	used  uint64
+	limit uint64
+}
+
+// near returns whether the usage is near the limit, where the unlimited limit is 0
+func (u fileLimitUsage) near() bool {
+	return u.limit > 0 && float64(u.used) >= float64(u.limit)*fileLimitWarnRatio
+}
+
+// CheckFileLimits checks the open files of edgecore against its limit of open files, i.e. ulimit -n of the process,
+// the open files of the host against fs.file-max, and the inotify instances and watches against
+// fs.inotify.max_user_instances and fs.inotify.max_user_watches. Edged watches the pod dirs and the configs by inotify,
+// whose limits, once reached, fail the watches and the pod starts with the errors like "too many open files".
+// The usages near the limits are reported as a warning. The inotify usages are counted over the processes readable
+// by keadm, which are the processes of the user the limits apply to if keadm is run as the user of edgecore.
+func CheckFileLimits(w io.Writer) error {
+	var usages []fileLimitUsage
+	pids, err := processPids(constants.KubeEdgeBinaryName)
+	if err != nil {
+		return err
+	}
+	if len(pids) == 0 {
+		fmt.Fprintf(w, "edgecore is not running, the open files of edgecore are not checked\n")
+	}
+	for _, pid := range pids {
+		usage, err := processFileUsage(procDir, pid)
+		if err != nil {
+			return err
+		}
+		usages = append(usages, usage)
+	}
+
+	var errs []error
+	if usage, err := hostFileUsage(procSysDir); err != nil {
+		errs = append(errs, err)
+	} else {
+		usages = append(usages, usage)
+	}
+	instances, watches := inotifyUsage(procDir)
+	for _, inotify := range []struct {
+		name, key string
+		used      uint64
+	}{
+		{name: "inotify instances", key: "fs.inotify.max_user_instances", used: instances},
+		{name: "inotify watches", key: "fs.inotify.max_user_watches", used: watches},
+	} {
+		limit, err := readSysctlUint(procSysDir, inotify.key)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		usages = append(usages, fileLimitUsage{name: fmt.Sprintf("%s (%s)", inotify.name, inotify.key), used: inotify.used, limit: limit})
+	}
+
+	var near []string
+	for _, u := range usages {
+		limit := "unlimited"
+		if u.limit > 0 {
+			limit = strconv.FormatUint(u.limit, 10)
+		}
+		fmt.Fprintf(w, "%s: %d used, limit %s\n", u.name, u.used, limit)
+		if u.near() {
+			near = append(near, fmt.Sprintf("%s %d/%d", u.name, u.used, u.limit))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if len(near) > 0 {
+		return warningError(fmt.Errorf("the usages are near the limits: %s, the watches and the pod starts of edged fail once "+
+			"the limits are reached, raise the limits by sysctl or LimitNOFILE of edgecore.service", strings.Join(near, "; ")))
+	}
+	return nil
+}
+
+// processFileUsage returns the open fds of the process pid and its soft limit of open files
+func processFileUsage(dir string, pid int32) (fileLimitUsage, error) {
+	usage := fileLimitUsage{name: fmt.Sprintf("open files of edgecore (pid %d)", pid)}
+	fds, err := os.ReadDir(filepath.Join(dir, strconv.Itoa(int(pid)), "fd"))
+	if err != nil {
+		return usage, fmt.Errorf("read the fds of edgecore (pid %d) failed, %v", pid, err)
+	}
+	usage.used = uint64(len(fds))
+
+	limits, err := os.Open(filepath.Join(dir, strconv.Itoa(int(pid)), "limits"))
+	if err != nil {
+		return usage, fmt.Errorf("read the limits of edgecore (pid %d) failed, %v", pid, err)
+	}
+	defer limits.Close()
+	scanner := bufio.NewScanner(limits)
+	for scanner.Scan() {
+		// Max open files            1024                 524288               files
+		rest, ok := strings.CutPrefix(scanner.Text(), "Max open files")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) > 0 && fields[0] != "unlimited" {
+			if usage.limit, err = strconv.ParseUint(fields[0], 10, 64); err != nil {
+				return usage, fmt.Errorf("invalid max open files of edgecore (pid %d): %v", pid, err)
+			}
+		}
+	}
+	return usage, scanner.Err()
+}
+
+// hostFileUsage returns the allocated files of the host and fs.file-max by fs.file-nr,
+// which is of the allocated, the unused and the max files
+func hostFileUsage(sysDir string) (fileLimitUsage, error) {
+	usage := fileLimitUsage{name: "open files of the host (fs.file-max)"}
+	value, err := readSysctl(sysDir, "fs.file-nr")
+	if err != nil {
+		return usage, err
+	}
+	fields := strings.Fields(value)
+	if len(fields) != 3 {
+		return usage, fmt.Errorf("invalid sysctl fs.file-nr %q", value)
+	}
+	if usage.used, err = strconv.ParseUint(fields[0], 10, 64); err != nil {
+		return usage, fmt.Errorf("invalid sysctl fs.file-nr %q: %v", value, err)
+	}
+	if usage.limit, err = strconv.ParseUint(fields[2], 10, 64); err != nil {
+		return usage, fmt.Errorf("invalid sysctl fs.file-nr %q: %v", value, err)
+	}
+	return usage, nil
+}
+
+// readSysctlUint reads the sysctl key of an unsigned integer value
+func readSysctlUint(sysDir, key string) (uint64, error) {
+	value, err := readSysctl(sysDir, key)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sysctl %s %q: %v", key, value, err)
+	}
+	return n, nil
+}
+
+// inotifyUsage returns the inotify instances and watches of the processes in dir, an inotify instance is an fd
+// linking to anon_inode:inotify, and each of its watches is an inotify line in the fdinfo of the fd.
+// The processes which are not readable or exit while counting are skipped.
+func inotifyUsage(dir string) (uint64, uint64) {
+	var instances, watches uint64
+	procs, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, 0
+	}
+	for _, proc := range procs {
+		if _, err := strconv.Atoi(proc.Name()); err != nil {
+			continue
+		}
+		fdDir := filepath.Join(dir, proc.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			if link, err := os.Readlink(filepath.Join(fdDir, fd.Name())); err != nil || link != "anon_inode:inotify" {
+				continue
+			}
+			instances++
+			watches += countInotifyWatches(filepath.Join(dir, proc.Name(), "fdinfo", fd.Name()))
+		}
+	}
+	return instances, watches
+}
+
+// countInotifyWatches counts the inotify lines in the fdinfo file of an inotify fd
+func countInotifyWatches(fdinfo string) uint64 {
+	f, err := os.Open(fdinfo)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	var n uint64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "inotify ") {
+			n++
+		}
+	}
+	return n
+}
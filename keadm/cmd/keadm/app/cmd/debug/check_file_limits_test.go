@@ -0,0 +1,150 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckFileLimits(t *testing.T) {
+	cases := []struct {
+		name         string
+		openFiles    uint64
+		watches      uint64
+		expectedWarn string
+		expectedOuts []string
+	}{
+		{
+			name:      "below the limits",
+			openFiles: 100,
+			watches:   1000,
+			expectedOuts: []string{
+				"open files of edgecore (pid 42): 100 used, limit 1024\n",
+				"open files of the host (fs.file-max): 2048 used, limit 100000\n",
+				"inotify instances (fs.inotify.max_user_instances): 3 used, limit 128\n",
+				"inotify watches (fs.inotify.max_user_watches): 1000 used, limit 8192\n",
+			},
+		},
+		{
+			name:         "near the limits",
+			openFiles:    1000,
+			watches:      8000,
+			expectedWarn: "the usages are near the limits: open files of edgecore (pid 42) 1000/1024; inotify watches (fs.inotify.max_user_watches) 8000/8192",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			patches := gomonkey.NewPatches()
+			defer patches.Reset()
+			patches.ApplyFunc(processPids, func(_name string) ([]int32, error) {
+				return []int32{42}, nil
+			})
+			patches.ApplyFunc(processFileUsage, func(_dir string, pid int32) (fileLimitUsage, error) {
+				return fileLimitUsage{name: "open files of edgecore (pid 42)", used: c.openFiles, limit: 1024}, nil
+			})
+			patches.ApplyFunc(hostFileUsage, func(_sysDir string) (fileLimitUsage, error) {
+				return fileLimitUsage{name: "open files of the host (fs.file-max)", used: 2048, limit: 100000}, nil
+			})
+			patches.ApplyFunc(inotifyUsage, func(_dir string) (uint64, uint64) {
+				return 3, c.watches
+			})
+			patches.ApplyFunc(readSysctlUint, func(_sysDir, key string) (uint64, error) {
+				if key == "fs.inotify.max_user_instances" {
+					return 128, nil
+				}
+				return 8192, nil
+			})
+
+			buf := &bytes.Buffer{}
+			err := CheckFileLimits(buf)
+			if c.expectedWarn != "" {
+				require.ErrorContains(t, err, c.expectedWarn)
+				assert.True(t, isDiagnoseWarning(err))
+				return
+			}
+			require.NoError(t, err)
+			for _, out := range c.expectedOuts {
+				assert.Contains(t, buf.String(), out)
+			}
+		})
+	}
+}
+
+func TestProcessFileUsage(t *testing.T) {
+	dir := t.TempDir()
+	fdDir := filepath.Join(dir, "42", "fd")
+	require.NoError(t, os.MkdirAll(fdDir, 0700))
+	for _, fd := range []string{"0", "1", "2"} {
+		require.NoError(t, os.WriteFile(filepath.Join(fdDir, fd), nil, 0600))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "42", "limits"), []byte(
+		"Limit                     Soft Limit           Hard Limit           Units\n"+
+			"Max open files            1024                 524288               files\n"), 0600))
+
+	usage, err := processFileUsage(dir, 42)
+	require.NoError(t, err)
+	assert.Equal(t, fileLimitUsage{name: "open files of edgecore (pid 42)", used: 3, limit: 1024}, usage)
+
+	_, err = processFileUsage(dir, 43)
+	assert.ErrorContains(t, err, "read the fds of edgecore (pid 43) failed")
+}
+
+func TestHostFileUsage(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "fs"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fs", "file-nr"), []byte("2048\t0\t100000\n"), 0600))
+	usage, err := hostFileUsage(dir)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2048), usage.used)
+	assert.Equal(t, uint64(100000), usage.limit)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fs", "file-nr"), []byte("2048\n"), 0600))
+	_, err = hostFileUsage(dir)
+	assert.ErrorContains(t, err, "invalid sysctl fs.file-nr")
+}
+
+func TestInotifyUsage(t *testing.T) {
+	dir := t.TempDir()
+	for _, pid := range []string{"1", "2"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, pid, "fd"), 0700))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, pid, "fdinfo"), 0700))
+	}
+	require.NoError(t, os.Symlink("anon_inode:inotify", filepath.Join(dir, "1", "fd", "3")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "1", "fdinfo", "3"), []byte("pos:\t0\nflags:\t02004000\n"+
+		"inotify wd:2 ino:1 sdev:800001 mask:fc6 ignored_mask:0\ninotify wd:1 ino:2 sdev:800001 mask:fc6 ignored_mask:0\n"), 0600))
+	require.NoError(t, os.Symlink("/dev/null", filepath.Join(dir, "1", "fd", "4")))
+	require.NoError(t, os.Symlink("anon_inode:inotify", filepath.Join(dir, "2", "fd", "5")))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "self"), 0700))
+
+	instances, watches := inotifyUsage(dir)
+	assert.Equal(t, uint64(2), instances)
+	assert.Equal(t, uint64(2), watches)
+}
+
+func TestFileLimitUsageNear(t *testing.T) {
+	assert.True(t, fileLimitUsage{used: 80, limit: 100}.near())
+	assert.False(t, fileLimitUsage{used: 79, limit: 100}.near())
+	assert.False(t, fileLimitUsage{used: 80}.near())
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// hostPathVolume is a hostPath volume of a cached pod
+type hostPathVolume struct {
+	pod      string
+	volume   string
+	path     string
+	pathType v1.HostPathType
+}
+
+func (v hostPathVolume) String() string {
+	return fmt.Sprintf("hostPath %s of volume %s of pod %s", v.path, v.volume, v.pod)
+}
+
+// CheckHostPaths checks the host paths of the hostPath volumes of the active pods cached in the edge database exist
+// and are of the types of the volumes, e.g. a CharDevice for /dev/ttyUSB0, since edged fails to start the pods whose
+// host paths are missing or of another type. The volumes of type DirectoryOrCreate and FileOrCreate are created by
+// edged and only the type of an existing path is checked. A missing path of a volume without a type is a warning,
+// the container runtime creates an empty dir in its place, which hides a missing device from the pod.
+// The database must be initialized before.
+func CheckHostPaths(w io.Writer) error {
+	pods, err := QueryPodsFromDatabase(metav1.NamespaceAll)
+	if err != nil {
+		return err
+	}
+	var volumes []hostPathVolume
+	for _, pod := range pods {
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.HostPath == nil {
+				continue
+			}
+			v := hostPathVolume{pod: pod.Namespace + "/" + pod.Name, volume: volume.Name, path: volume.HostPath.Path}
+			if volume.HostPath.Type != nil {
+				v.pathType = *volume.HostPath.Type
+			}
+			volumes = append(volumes, v)
+		}
+	}
+	if len(volumes) == 0 {
+		fmt.Fprintf(w, "no hostPath volumes in the %d pods in the edge database\n", len(pods))
+		return nil
+	}
+
+	var failed, missing []string
+	for _, v := range volumes {
+		info, err := os.Stat(v.path)
+		switch {
+		case os.IsNotExist(err):
+			switch v.pathType {
+			case v1.HostPathDirectoryOrCreate, v1.HostPathFileOrCreate:
+				// edged creates the path before starting the pod
+			case v1.HostPathUnset:
+				fmt.Fprintf(w, "WARNING: %s is not exists, the container runtime creates an empty dir in its place\n", v)
+				missing = append(missing, v.path)
+			default:
+				fmt.Fprintf(w, "FAILED: %s is not exists, the pod fails to start with type %s\n", v, v.pathType)
+				failed = append(failed, v.path)
+			}
+		case err != nil:
+			fmt.Fprintf(w, "FAILED: stat %s failed: %v\n", v, err)
+			failed = append(failed, v.path)
+		case !hostPathTypeMatches(v.pathType, info.Mode()):
+			fmt.Fprintf(w, "FAILED: %s is a %s, not a %s\n", v, fileModeType(info.Mode()), v.pathType)
+			failed = append(failed, v.path)
+		}
+	}
+	fmt.Fprintf(w, "%d/%d hostPath volumes of the pods in the edge database are ok\n", len(volumes)-len(failed)-len(missing), len(volumes))
+
+	if len(failed) > 0 {
+		return fmt.Errorf("host paths %s of the hostPath volumes are missing or of another type, the pods mounting them fail to start",
+			strings.Join(failed, ","))
+	}
+	if len(missing) > 0 {
+		return warningError(fmt.Errorf("host paths %s of the hostPath volumes without a type are missing, "+
+			"the pods mount empty dirs instead", strings.Join(missing, ",")))
+	}
+	return nil
+}
+
+// hostPathTypeMatches returns whether the file of mode is of the hostPath type, a volume without a type matches any
+func hostPathTypeMatches(pathType v1.HostPathType, mode os.FileMode) bool {
+	switch pathType {
+	case v1.HostPathDirectory, v1.HostPathDirectoryOrCreate:
+		return mode.IsDir()
+	case v1.HostPathFile, v1.HostPathFileOrCreate:
+		return mode.IsRegular()
+	case v1.HostPathSocket:
+		return mode&os.ModeSocket != 0
+	case v1.HostPathCharDev:
+		return mode&os.ModeCharDevice != 0
+	case v1.HostPathBlockDev:
+		return mode&os.ModeDevice != 0 && mode&os.ModeCharDevice == 0
+	}
+	return true
+}
+
+// fileModeType describes the type of the file of mode by the names of the hostPath types
+func fileModeType(mode os.FileMode) string {
+	switch {
+	case mode.IsDir():
+		return string(v1.HostPathDirectory)
+	case mode.IsRegular():
+		return string(v1.HostPathFile)
+	case mode&os.ModeSocket != 0:
+		return string(v1.HostPathSocket)
+	case mode&os.ModeCharDevice != 0:
+		return string(v1.HostPathCharDev)
+	case mode&os.ModeDevice != 0:
+		return string(v1.HostPathBlockDev)
+	}
+	return "special file"
+}
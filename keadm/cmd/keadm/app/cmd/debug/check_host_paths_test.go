@@ -0,0 +1,114 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newHostPathPod(name string, phase v1.PodPhase, paths map[string]v1.HostPathType) v1.Pod {
+	pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}
+	pod.Status.Phase = phase
+	for path, pathType := range paths {
+		pathType := pathType
+		pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+			Name:         filepath.Base(path),
+			VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: path, Type: &pathType}},
+		})
+	}
+	return pod
+}
+
+func TestCheckHostPaths(t *testing.T) {
+	var pods []v1.Pod
+	patches := gomonkey.ApplyFunc(QueryPodsFromDatabase, func(_namespace string) ([]v1.Pod, error) {
+		return pods, nil
+	})
+	defer patches.Reset()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("key: value\n"), 0600))
+	missing := filepath.Join(dir, "missing")
+
+	t.Run("no hostPath volumes", func(t *testing.T) {
+		pods = []v1.Pod{{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"}}}
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckHostPaths(buf))
+		assert.Equal(t, "no hostPath volumes in the 1 pods in the edge database\n", buf.String())
+	})
+
+	t.Run("host paths exist", func(t *testing.T) {
+		pods = []v1.Pod{
+			newHostPathPod("modbus", v1.PodRunning, map[string]v1.HostPathType{
+				dir:         v1.HostPathDirectory,
+				file:        v1.HostPathFile,
+				"/dev/null": v1.HostPathCharDev,
+				missing:     v1.HostPathDirectoryOrCreate,
+			}),
+			// the host paths of the finished pods are not checked
+			newHostPathPod("job", v1.PodSucceeded, map[string]v1.HostPathType{missing: v1.HostPathFile}),
+		}
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckHostPaths(buf))
+		assert.Equal(t, "4/4 hostPath volumes of the pods in the edge database are ok\n", buf.String())
+	})
+
+	t.Run("host path is missing", func(t *testing.T) {
+		pods = []v1.Pod{newHostPathPod("modbus", v1.PodPending, map[string]v1.HostPathType{missing: v1.HostPathCharDev})}
+		buf := &bytes.Buffer{}
+		err := CheckHostPaths(buf)
+		require.EqualError(t, err, "host paths "+missing+" of the hostPath volumes are missing or of another type, the pods mounting them fail to start")
+		assert.False(t, isDiagnoseWarning(err))
+		assert.Contains(t, buf.String(), "FAILED: hostPath "+missing+" of volume missing of pod default/modbus is not exists, the pod fails to start with type CharDevice\n")
+	})
+
+	t.Run("host path is of another type", func(t *testing.T) {
+		pods = []v1.Pod{newHostPathPod("modbus", v1.PodPending, map[string]v1.HostPathType{file: v1.HostPathCharDev})}
+		buf := &bytes.Buffer{}
+		require.Error(t, CheckHostPaths(buf))
+		assert.Contains(t, buf.String(), "FAILED: hostPath "+file+" of volume config.yaml of pod default/modbus is a File, not a CharDevice\n")
+	})
+
+	t.Run("host path without a type is missing", func(t *testing.T) {
+		pods = []v1.Pod{newHostPathPod("modbus", v1.PodRunning, map[string]v1.HostPathType{missing: v1.HostPathUnset})}
+		err := CheckHostPaths(&bytes.Buffer{})
+		require.Error(t, err)
+		assert.True(t, isDiagnoseWarning(err))
+		assert.Contains(t, err.Error(), "host paths "+missing+" of the hostPath volumes without a type are missing")
+	})
+}
+
+func TestHostPathTypeMatches(t *testing.T) {
+	assert.True(t, hostPathTypeMatches(v1.HostPathDirectory, os.ModeDir))
+	assert.False(t, hostPathTypeMatches(v1.HostPathFile, os.ModeDir))
+	assert.True(t, hostPathTypeMatches(v1.HostPathSocket, os.ModeSocket))
+	assert.True(t, hostPathTypeMatches(v1.HostPathCharDev, os.ModeDevice|os.ModeCharDevice))
+	assert.False(t, hostPathTypeMatches(v1.HostPathBlockDev, os.ModeDevice|os.ModeCharDevice))
+	assert.True(t, hostPathTypeMatches(v1.HostPathBlockDev, os.ModeDevice))
+	assert.True(t, hostPathTypeMatches(v1.HostPathUnset, os.ModeSocket))
+	assert.Equal(t, "BlockDevice", fileModeType(os.ModeDevice))
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+// ipFamilyUsage is the usage of the --ip-family flag of the network checks
+const ipFamilyUsage = "the ip family the network checks resolve and dial the servers with, one of ipv4, ipv6 and dual"
+
+// validateIPFamily returns an error if family is not one of ipv4, ipv6 and dual, the empty family is dual
+func validateIPFamily(family string) error {
+	switch family {
+	case "", common.IPFamilyIPv4, common.IPFamilyIPv6, common.IPFamilyDual:
+		return nil
+	}
+	return fmt.Errorf("invalid ip family %s, must be one of %s, %s and %s",
+		family, common.IPFamilyIPv4, common.IPFamilyIPv6, common.IPFamilyDual)
+}
+
+// ipFamilyNetwork returns the network restricted to the ip family, e.g. tcp6 for tcp and ipv6,
+// the network is unchanged for dual
+func ipFamilyNetwork(network, family string) string {
+	switch family {
+	case common.IPFamilyIPv4:
+		return network + "4"
+	case common.IPFamilyIPv6:
+		return network + "6"
+	}
+	return network
+}
+
+// dialContext returns the dial function of the connections of the checks, which only dials the addresses of opts.IPFamily
+func (opts HTTPCheckOptions) dialContext() func(ctx context.Context, network, address string) (net.Conn, error) {
+	d := &net.Dialer{}
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		return d.DialContext(ctx, ipFamilyNetwork(network, opts.IPFamily), address)
+	}
+}
+
+// addrIPFamily returns the ip family of the address of a connection
+func addrIPFamily(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		host = addr.String()
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+		return common.IPFamilyIPv6
+	}
+	return common.IPFamilyIPv4
+}
+
+// splitIPFamilies splits the ips into the ipv4 and the ipv6 addresses
+func splitIPFamilies(ips []net.IP) ([]string, []string) {
+	var v4, v6 []string
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			v4 = append(v4, ip.String())
+		} else {
+			v6 = append(v6, ip.String())
+		}
+	}
+	return v4, v6
+}
+
+// reportOtherIPFamily reports whether the host also resolves to the addresses of the ip family other than used,
+// which tells whether the node can fall back to the other family. Nothing is reported if the host is an ip address.
+func reportOtherIPFamily(w io.Writer, host, used string) {
+	if net.ParseIP(host) != nil {
+		return
+	}
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		fmt.Fprintf(w, "WARNING: dns resolution of %s failed, %v\n", host, err)
+		return
+	}
+	v4, v6 := splitIPFamilies(ips)
+	other, addrs := common.IPFamilyIPv6, v6
+	if used == common.IPFamilyIPv6 {
+		other, addrs = common.IPFamilyIPv4, v4
+	}
+	if len(addrs) == 0 {
+		fmt.Fprintf(w, "%s does not resolve to %s addresses\n", host, other)
+		return
+	}
+	fmt.Fprintf(w, "%s also resolves to %s addresses [%s]\n", host, other, strings.Join(addrs, ","))
+}
+
+// ipFamilyNameserver returns the first nameserver of the ip family in the resolv.conf file,
+// or "" if there is none. The nameserver of either family is returned for dual.
+func ipFamilyNameserver(path, family string) (string, error) {
+	nameservers, err := resolvConfNameservers(path)
+	if err != nil {
+		return "", err
+	}
+	for _, ns := range nameservers {
+		ip := net.ParseIP(ns)
+		if ip == nil {
+			continue
+		}
+		if family == common.IPFamilyIPv4 && ip.To4() == nil || family == common.IPFamilyIPv6 && ip.To4() != nil {
+			continue
+		}
+		return ns, nil
+	}
+	return "", nil
+}
+
+// resolveUDPAddr resolves the udp address to an address of the ip family, and returns the host of the address
+// as the tls server name. The address is returned unchanged for dual.
+func resolveUDPAddr(address, family string) (string, string, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", "", fmt.Errorf(" invalid address %s: %v", address, err)
+	}
+	if family == "" || family == common.IPFamilyDual {
+		return address, host, nil
+	}
+	addr, err := net.ResolveUDPAddr(ipFamilyNetwork("udp", family), address)
+	if err != nil {
+		return "", "", fmt.Errorf(" resolve %s to an %s address failed: %v", address, family, err)
+	}
+	return addr.String(), host, nil
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+)
+
+func TestValidateIPFamily(t *testing.T) {
+	for _, family := range []string{"", common.IPFamilyIPv4, common.IPFamilyIPv6, common.IPFamilyDual} {
+		assert.NoError(t, validateIPFamily(family))
+	}
+	assert.EqualError(t, validateIPFamily("ipv5"), "invalid ip family ipv5, must be one of ipv4, ipv6 and dual")
+}
+
+func TestIPFamilyNetwork(t *testing.T) {
+	assert.Equal(t, "tcp4", ipFamilyNetwork("tcp", common.IPFamilyIPv4))
+	assert.Equal(t, "udp6", ipFamilyNetwork("udp", common.IPFamilyIPv6))
+	assert.Equal(t, "tcp", ipFamilyNetwork("tcp", common.IPFamilyDual))
+	assert.Equal(t, "tcp", ipFamilyNetwork("tcp", ""))
+}
+
+func TestAddrIPFamily(t *testing.T) {
+	assert.Equal(t, common.IPFamilyIPv4, addrIPFamily(&net.TCPAddr{IP: net.ParseIP("192.168.1.10"), Port: 10000}))
+	assert.Equal(t, common.IPFamilyIPv6, addrIPFamily(&net.TCPAddr{IP: net.ParseIP("fd00::10"), Port: 10000}))
+	assert.Equal(t, common.IPFamilyIPv6, addrIPFamily(&net.IPAddr{IP: net.ParseIP("::1")}))
+}
+
+func TestReportOtherIPFamily(t *testing.T) {
+	patches := gomonkey.ApplyFunc(net.LookupIP, func(host string) ([]net.IP, error) {
+		switch host {
+		case "dual.example.com":
+			return []net.IP{net.ParseIP("192.168.1.10"), net.ParseIP("fd00::10")}, nil
+		case "v4.example.com":
+			return []net.IP{net.ParseIP("192.168.1.10")}, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	})
+	defer patches.Reset()
+
+	buf := &bytes.Buffer{}
+	reportOtherIPFamily(buf, "dual.example.com", common.IPFamilyIPv4)
+	assert.Equal(t, "dual.example.com also resolves to ipv6 addresses [fd00::10]\n", buf.String())
+
+	buf.Reset()
+	reportOtherIPFamily(buf, "dual.example.com", common.IPFamilyIPv6)
+	assert.Equal(t, "dual.example.com also resolves to ipv4 addresses [192.168.1.10]\n", buf.String())
+
+	buf.Reset()
+	reportOtherIPFamily(buf, "v4.example.com", common.IPFamilyIPv4)
+	assert.Equal(t, "v4.example.com does not resolve to ipv6 addresses\n", buf.String())
+
+	buf.Reset()
+	reportOtherIPFamily(buf, "invalid.example.com", common.IPFamilyIPv4)
+	assert.Contains(t, buf.String(), "WARNING: dns resolution of invalid.example.com failed")
+
+	buf.Reset()
+	reportOtherIPFamily(buf, "192.168.1.10", common.IPFamilyIPv4)
+	assert.Empty(t, buf.String())
+}
+
+func TestIPFamilyNameserver(t *testing.T) {
+	resolvConf := filepath.Join(t.TempDir(), "resolv.conf")
+	require.NoError(t, os.WriteFile(resolvConf, []byte("# comment\nnameserver 10.0.0.10\nnameserver fd00::53\n"), 0600))
+
+	ns, err := ipFamilyNameserver(resolvConf, common.IPFamilyIPv6)
+	require.NoError(t, err)
+	assert.Equal(t, "fd00::53", ns)
+
+	ns, err = ipFamilyNameserver(resolvConf, common.IPFamilyIPv4)
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.10", ns)
+
+	ns, err = ipFamilyNameserver(resolvConf, common.IPFamilyDual)
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.10", ns)
+
+	require.NoError(t, os.WriteFile(resolvConf, []byte("nameserver 10.0.0.10\n"), 0600))
+	ns, err = ipFamilyNameserver(resolvConf, common.IPFamilyIPv6)
+	require.NoError(t, err)
+	assert.Empty(t, ns)
+
+	_, err = ipFamilyNameserver(filepath.Join(t.TempDir(), "not-exist"), common.IPFamilyIPv6)
+	assert.Error(t, err)
+}
+
+func TestResolveUDPAddr(t *testing.T) {
+	address, host, err := resolveUDPAddr("cloudcore.example.com:10001", common.IPFamilyDual)
+	require.NoError(t, err)
+	assert.Equal(t, "cloudcore.example.com:10001", address)
+	assert.Equal(t, "cloudcore.example.com", host)
+
+	address, host, err = resolveUDPAddr("127.0.0.1:10001", common.IPFamilyIPv4)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:10001", address)
+	assert.Equal(t, "127.0.0.1", host)
+
+	_, _, err = resolveUDPAddr("127.0.0.1:10001", common.IPFamilyIPv6)
+	assert.ErrorContains(t, err, "resolve 127.0.0.1:10001 to an ipv6 address failed")
+
+	_, _, err = resolveUDPAddr("cloudcore", common.IPFamilyIPv4)
+	assert.ErrorContains(t, err, "invalid address cloudcore")
+}
+
+func TestCheckDNSIPFamily(t *testing.T) {
+	patches := gomonkey.ApplyFunc(net.LookupIP, func(host string) ([]net.IP, error) {
+		if host == "v4.example.com" {
+			return []net.IP{net.ParseIP("192.168.1.10")}, nil
+		}
+		return []net.IP{net.ParseIP("192.168.1.10"), net.ParseIP("fd00::10")}, nil
+	})
+	defer patches.Reset()
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, CheckDNS(buf, "dual.example.com", common.IPFamilyDual))
+	assert.Contains(t, buf.String(), "dns resolution success, domain: dual.example.com ip: 192.168.1.10 (ipv4)")
+	assert.Contains(t, buf.String(), "domain dual.example.com also resolves to ipv6 addresses [fd00::10]")
+
+	buf.Reset()
+	require.NoError(t, CheckDNS(buf, "dual.example.com", common.IPFamilyIPv6))
+	assert.Contains(t, buf.String(), "dns resolution success, domain: dual.example.com ip: fd00::10 (ipv6)")
+	assert.Contains(t, buf.String(), "domain dual.example.com also resolves to ipv4 addresses [192.168.1.10]")
+
+	buf.Reset()
+	require.NoError(t, CheckDNS(buf, "v4.example.com", common.IPFamilyDual))
+	assert.Contains(t, buf.String(), "domain v4.example.com does not resolve to ipv6 addresses")
+
+	err := CheckDNS(&bytes.Buffer{}, "v4.example.com", common.IPFamilyIPv6)
+	assert.EqualError(t, err, "dns resolution failed, domain: v4.example.com has no ipv6 addresses, it resolves to ipv4 addresses [192.168.1.10]")
+}
+
+func TestCheckHTTPConnIPFamily(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+
+	res, err := checkHTTPConn(server.URL, HTTPCheckOptions{Timeout: 1, IPFamily: common.IPFamilyIPv4})
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimPrefix(server.URL, "http://"), res.remote.String())
+
+	_, err = checkHTTPConn(server.URL, HTTPCheckOptions{Timeout: 1, IPFamily: common.IPFamilyIPv6})
+	assert.Error(t, err)
+}
+
+func TestCheckNetWorkIPFamily(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+	cloudhubServer := strings.TrimPrefix(server.URL, "https://")
+	edgecore := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer edgecore.Close()
+	edgecoreServer := strings.TrimPrefix(edgecore.URL, "http://")
+
+	patches := gomonkey.ApplyFunc(util.ExecShellFilter, func(_c string) (string, error) {
+		return "0%", nil
+	})
+	defer patches.Reset()
+
+	buf := &bytes.Buffer{}
+	opts := HTTPCheckOptions{Timeout: 1, InsecureSkipTLSVerify: true, IPFamily: common.IPFamilyIPv4}
+	require.NoError(t, CheckNetWork(buf, "127.0.0.1", opts, cloudhubServer, edgecoreServer, ""))
+	assert.Contains(t, buf.String(), "cloudhubServer "+cloudhubServer+" is connected over ipv4 at "+cloudhubServer)
+	assert.Contains(t, buf.String(), "check edgecoreServer "+edgecoreServer+" success")
+
+	opts.IPFamily = common.IPFamilyIPv6
+	err := CheckNetWork(&bytes.Buffer{}, "127.0.0.1", opts, cloudhubServer, edgecoreServer, "")
+	assert.ErrorContains(t, err, "check cloudhubServer "+cloudhubServer+" failed")
+
+	opts.IPFamily = "ipv5"
+	err = CheckNetWork(&bytes.Buffer{}, "127.0.0.1", opts, cloudhubServer, edgecoreServer, "")
+	assert.EqualError(t, err, "invalid ip family ipv5, must be one of ipv4, ipv6 and dual")
+}
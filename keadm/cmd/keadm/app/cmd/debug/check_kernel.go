@@ -0,0 +1,149 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kubeedge/kubeedge/pkg/util/files"
+)
+
+const (
+	// procModules lists the loadable kernel modules loaded in the kernel
+	procModules = "/proc/modules"
+	// sysModuleDir has a dir of each loaded module, and of the built-in modules with parameters
+	sysModuleDir = "/sys/module"
+	// libModulesDir has the modules.builtin file listing the built-in modules of each kernel release
+	libModulesDir = "/lib/modules"
+	// procSysDir is the root of the sysctls, net.ipv4.ip_forward is read from /proc/sys/net/ipv4/ip_forward
+	procSysDir = "/proc/sys"
+	// procOSRelease is the release of the running kernel
+	procOSRelease = "/proc/sys/kernel/osrelease"
+)
+
+// defaultKernelModules are the kernel modules the pod networks and the overlay snapshotter of the runtime require
+var defaultKernelModules = []string{"br_netfilter", "overlay"}
+
+// defaultKernelSysctls are the sysctls the pod networks require, the bridge sysctls exist once br_netfilter is loaded
+var defaultKernelSysctls = []string{"net.ipv4.ip_forward=1", "net.bridge.bridge-nf-call-iptables=1"}
+
+// CheckKernel checks whether the kernel modules are loaded or built into the kernel, and whether the sysctls,
+// in the form of key=value, have the expected values. Every module and sysctl is reported, and all of the failures are returned.
+func CheckKernel(w io.Writer, modules, sysctls []string) error {
+	var errs []error
+	builtin := builtinKernelModules(libModulesDir, procOSRelease)
+	for _, module := range modules {
+		loaded, err := kernelModuleLoaded(procModules, sysModuleDir, module)
+		switch {
+		case err != nil:
+			errs = append(errs, err)
+		case loaded:
+			fmt.Fprintf(w, "kernel module %s is loaded\n", module)
+		case builtin[strings.ReplaceAll(module, "-", "_")]:
+			fmt.Fprintf(w, "kernel module %s is built into the kernel\n", module)
+		default:
+			errs = append(errs, fmt.Errorf("kernel module %s is not loaded, load it with modprobe %s "+
+				"and add it to /etc/modules-load.d to load it at boot", module, module))
+		}
+	}
+
+	for _, sysctl := range sysctls {
+		key, expected, ok := strings.Cut(sysctl, "=")
+		key, expected = strings.TrimSpace(key), strings.TrimSpace(expected)
+		if !ok || key == "" {
+			errs = append(errs, fmt.Errorf("invalid sysctl %s, must be key=value", sysctl))
+			continue
+		}
+		value, err := readSysctl(procSysDir, key)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if value != expected {
+			errs = append(errs, fmt.Errorf("sysctl %s is %s, expected %s, set it with sysctl -w %s=%s "+
+				"and add it to /etc/sysctl.d to persist it", key, value, expected, key, expected))
+			continue
+		}
+		fmt.Fprintf(w, "sysctl %s is %s\n", key, value)
+	}
+	return errors.Join(errs...)
+}
+
+// kernelModuleLoaded returns whether the module is in the modules file, or has a dir in the sys module dir.
+// The names of the modules are compared with the dashes replaced by underscores, the same as the kernel does.
+func kernelModuleLoaded(modulesFile, sysDir, module string) (bool, error) {
+	name := strings.ReplaceAll(module, "-", "_")
+	if files.FileExists(filepath.Join(sysDir, name)) {
+		return true, nil
+	}
+	f, err := os.Open(modulesFile)
+	if err != nil {
+		return false, fmt.Errorf("read the loaded kernel modules failed, %v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 && fields[0] == name {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// builtinKernelModules returns the modules built into the running kernel, which are listed in the modules.builtin file
+// of the kernel release as the paths of the modules, e.g. kernel/net/bridge/br_netfilter.ko. No module is returned if it is not readable.
+func builtinKernelModules(modulesDir, osReleaseFile string) map[string]bool {
+	builtin := make(map[string]bool)
+	release, err := os.ReadFile(osReleaseFile)
+	if err != nil {
+		return builtin
+	}
+	f, err := os.Open(filepath.Join(modulesDir, strings.TrimSpace(string(release)), "modules.builtin"))
+	if err != nil {
+		return builtin
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		name := strings.TrimSuffix(filepath.Base(strings.TrimSpace(scanner.Text())), ".ko")
+		if name != "" && name != "." {
+			builtin[strings.ReplaceAll(name, "-", "_")] = true
+		}
+	}
+	return builtin
+}
+
+// readSysctl reads the value of the sysctl key from the sysctl dir, the whitespaces of the multi-value sysctls are collapsed
+func readSysctl(sysDir, key string) (string, error) {
+	path := filepath.Join(sysDir, strings.ReplaceAll(key, ".", "/"))
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("sysctl %s is not exists, the kernel module providing it is not loaded", key)
+	}
+	if err != nil {
+		return "", fmt.Errorf("read sysctl %s failed, %v", key, err)
+	}
+	return strings.Join(strings.Fields(string(data)), " "), nil
+}
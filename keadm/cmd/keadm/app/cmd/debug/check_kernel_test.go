@@ -0,0 +1,106 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckKernel(t *testing.T) {
+	patches := gomonkey.NewPatches()
+	defer patches.Reset()
+	patches.ApplyFunc(kernelModuleLoaded, func(_modulesFile, _sysDir, module string) (bool, error) {
+		return module == "br_netfilter", nil
+	})
+	patches.ApplyFunc(builtinKernelModules, func(_modulesDir, _osReleaseFile string) map[string]bool {
+		return map[string]bool{"overlay": true}
+	})
+	patches.ApplyFunc(readSysctl, func(_sysDir, key string) (string, error) {
+		switch key {
+		case "net.ipv4.ip_forward":
+			return "1", nil
+		case "net.ipv4.conf.all.rp_filter":
+			return "2", nil
+		}
+		return "", os.ErrNotExist
+	})
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, CheckKernel(buf, []string{"br_netfilter", "overlay"}, []string{"net.ipv4.ip_forward=1"}))
+	assert.Contains(t, buf.String(), "kernel module br_netfilter is loaded")
+	assert.Contains(t, buf.String(), "kernel module overlay is built into the kernel")
+	assert.Contains(t, buf.String(), "sysctl net.ipv4.ip_forward is 1")
+
+	err := CheckKernel(&bytes.Buffer{}, []string{"ip_vs"}, []string{"net.ipv4.conf.all.rp_filter=0", "invalid"})
+	require.ErrorContains(t, err, "kernel module ip_vs is not loaded, load it with modprobe ip_vs")
+	require.ErrorContains(t, err, "sysctl net.ipv4.conf.all.rp_filter is 2, expected 0")
+	require.ErrorContains(t, err, "invalid sysctl invalid, must be key=value")
+}
+
+func TestKernelModuleLoaded(t *testing.T) {
+	dir := t.TempDir()
+	modulesFile := filepath.Join(dir, "modules")
+	sysDir := filepath.Join(dir, "module")
+	require.NoError(t, os.WriteFile(modulesFile, []byte("br_netfilter 32768 0 - Live 0x0000000000000000\n"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(sysDir, "nf_conntrack"), 0700))
+
+	for module, expected := range map[string]bool{"br_netfilter": true, "br-netfilter": true, "nf_conntrack": true, "overlay": false} {
+		loaded, err := kernelModuleLoaded(modulesFile, sysDir, module)
+		require.NoError(t, err)
+		assert.Equal(t, expected, loaded, module)
+	}
+
+	_, err := kernelModuleLoaded(filepath.Join(dir, "not-exist"), sysDir, "overlay")
+	assert.ErrorContains(t, err, "read the loaded kernel modules failed")
+}
+
+func TestBuiltinKernelModules(t *testing.T) {
+	dir := t.TempDir()
+	osRelease := filepath.Join(dir, "osrelease")
+	require.NoError(t, os.WriteFile(osRelease, []byte("6.1.0-test\n"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "6.1.0-test"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "6.1.0-test", "modules.builtin"),
+		[]byte("kernel/fs/overlayfs/overlay.ko\nkernel/net/bridge/br_netfilter.ko\nkernel/drivers/md/dm-mod.ko\n"), 0600))
+
+	assert.Equal(t, map[string]bool{"overlay": true, "br_netfilter": true, "dm_mod": true}, builtinKernelModules(dir, osRelease))
+	assert.Empty(t, builtinKernelModules(dir, filepath.Join(dir, "not-exist")))
+}
+
+func TestReadSysctl(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "net", "ipv4"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "net", "ipv4", "ip_forward"), []byte("1\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "net", "ipv4", "ip_local_port_range"), []byte("32768\t60999\n"), 0600))
+
+	value, err := readSysctl(dir, "net.ipv4.ip_forward")
+	require.NoError(t, err)
+	assert.Equal(t, "1", value)
+
+	value, err = readSysctl(dir, "net.ipv4.ip_local_port_range")
+	require.NoError(t, err)
+	assert.Equal(t, "32768 60999", value)
+
+	_, err = readSysctl(dir, "net.bridge.bridge-nf-call-iptables")
+	assert.EqualError(t, err, "sysctl net.bridge.bridge-nf-call-iptables is not exists, the kernel module providing it is not loaded")
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/kubeedge/api/apis/common/constants"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+)
+
+// kubeletProcessName is the process name of a standalone kubelet
+const kubeletProcessName = "kubelet"
+
+// CheckKubeletConflict warns if a standalone kubelet runs alongside edgecore or is enabled to start on boot.
+// Both the kubelet and edged manage the pods of the node and fight over the container runtime,
+// while each of them looks healthy on its own.
+func CheckKubeletConflict(w io.Writer) error {
+	kubeletPids, err := processPids(kubeletProcessName)
+	if err != nil {
+		return err
+	}
+	unit := kubeletUnit()
+
+	if len(kubeletPids) > 0 {
+		edgePids, err := processPids(constants.KubeEdgeBinaryName)
+		if err != nil {
+			return err
+		}
+		return warningError(fmt.Errorf("a standalone kubelet (pid %s) is running alongside edgecore (pid %s), "+
+			"both of them manage the pods and fight over the container runtime, stop and disable the kubelet",
+			joinPids(kubeletPids), joinPids(edgePids)))
+	}
+	if unit["UnitFileState"] == "enabled" {
+		return warningError(errors.New("kubelet.service is enabled, the kubelet runs alongside edgecore after the node reboots, disable it"))
+	}
+	fmt.Fprintf(w, "no standalone kubelet is running alongside edgecore\n")
+	return nil
+}
+
+// kubeletUnit returns the properties of the kubelet systemd unit, it is empty if systemd is not found
+func kubeletUnit() map[string]string {
+	if !util.HasSystemd() {
+		return map[string]string{}
+	}
+	out, err := util.ExecShellFilter(common.CmdShowKubeletUnit)
+	if err != nil {
+		return map[string]string{}
+	}
+	return parseSystemdProperties(out)
+}
+
+// processPids returns the pids of the processes named name, which are named with executableSuffix on windows
+func processPids(name string) ([]int32, error) {
+	procs, err := process.Processes()
+	if err != nil {
+		return nil, fmt.Errorf("list processes failed: %v", err)
+	}
+	var pids []int32
+	for _, proc := range procs {
+		// the processes exiting while listing have no name, skip them
+		if procName, err := proc.Name(); err == nil && procName == name+executableSuffix {
+			pids = append(pids, proc.Pid)
+		}
+	}
+	return pids, nil
+}
+
+// joinPids returns the comma-separated pids
+func joinPids(pids []int32) string {
+	strs := make([]string, 0, len(pids))
+	for _, pid := range pids {
+		strs = append(strs, fmt.Sprint(pid))
+	}
+	if len(strs) == 0 {
+		return "unknown"
+	}
+	return strings.Join(strs, ", ")
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckKubeletConflict(t *testing.T) {
+	cases := []struct {
+		name            string
+		pids            map[string][]int32
+		unit            map[string]string
+		expectedOut     string
+		expectedWarning string
+	}{
+		{
+			name:        "no kubelet",
+			pids:        map[string][]int32{"edgecore": {100}},
+			unit:        map[string]string{"LoadState": "not-found"},
+			expectedOut: "no standalone kubelet is running alongside edgecore",
+		},
+		{
+			name:            "kubelet is running",
+			pids:            map[string][]int32{"edgecore": {100}, "kubelet": {200, 201}},
+			unit:            map[string]string{"ActiveState": "active", "UnitFileState": "enabled"},
+			expectedWarning: "a standalone kubelet (pid 200, 201) is running alongside edgecore (pid 100)",
+		},
+		{
+			name:            "kubelet is enabled",
+			pids:            map[string][]int32{"edgecore": {100}},
+			unit:            map[string]string{"ActiveState": "inactive", "UnitFileState": "enabled"},
+			expectedWarning: "kubelet.service is enabled, the kubelet runs alongside edgecore after the node reboots",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			patches := gomonkey.ApplyFunc(processPids, func(name string) ([]int32, error) {
+				return c.pids[name], nil
+			})
+			defer patches.Reset()
+			patches.ApplyFunc(kubeletUnit, func() map[string]string {
+				return c.unit
+			})
+
+			buf := &bytes.Buffer{}
+			err := CheckKubeletConflict(buf)
+			if c.expectedWarning != "" {
+				require.ErrorContains(t, err, c.expectedWarning)
+				assert.True(t, isDiagnoseWarning(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, buf.String(), c.expectedOut)
+		})
+	}
+}
+
+func TestProcessPids(t *testing.T) {
+	pids, err := processPids("not-exist-process")
+	require.NoError(t, err)
+	assert.Empty(t, pids)
+}
+
+func TestJoinPids(t *testing.T) {
+	assert.Equal(t, "1, 2", joinPids([]int32{1, 2}))
+	assert.Equal(t, "unknown", joinPids(nil))
+}
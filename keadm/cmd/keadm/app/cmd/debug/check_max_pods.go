@@ -0,0 +1,67 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"fmt"
+	"io"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+const (
+	// defaultMaxPods is the maxPods of edged if it is not set in the edge config
+	defaultMaxPods = 110
+	// maxPodsNearRatio is the ratio of maxPods, over which the active pods are near the limit
+	maxPodsNearRatio = 0.9
+)
+
+// CheckMaxPods checks whether the active pods cached in the edge database are within the maxPods of edged,
+// the pods are not scheduled to the node once the limit is reached, which is neither a taint nor a resource issue.
+// The succeeded and failed pods do not count against the limit, the same as kubelet.
+func CheckMaxPods(w io.Writer, kubelet *v1alpha2.TailoredKubeletConfiguration) error {
+	maxPods := kubelet.MaxPods
+	if maxPods <= 0 {
+		maxPods = defaultMaxPods
+	}
+	pods, err := QueryPodsFromDatabase(metav1.NamespaceAll)
+	if err != nil {
+		return err
+	}
+	var active, containers int
+	for _, pod := range pods {
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		active++
+		containers += len(pod.Spec.Containers)
+	}
+	fmt.Fprintf(w, "pods: %d/%d of maxPods, containers: %d\n", active, maxPods, containers)
+
+	switch {
+	case active >= int(maxPods):
+		return fmt.Errorf("%d active pods reach maxPods %d of edged, new pods are not scheduled to the node "+
+			"until some are deleted or maxPods is raised", active, maxPods)
+	case float64(active) >= float64(maxPods)*maxPodsNearRatio:
+		return warningError(fmt.Errorf("%d active pods are near maxPods %d of edged, new pods are not scheduled to the node "+
+			"once the limit is reached", active, maxPods))
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+func TestCheckMaxPods(t *testing.T) {
+	var pods []v1.Pod
+	patches := gomonkey.ApplyFunc(QueryPodsFromDatabase, func(_namespace string) ([]v1.Pod, error) {
+		return pods, nil
+	})
+	defer patches.Reset()
+	newPods := func(n int, phase v1.PodPhase) []v1.Pod {
+		pods := make([]v1.Pod, n)
+		for i := range pods {
+			pods[i].Spec.Containers = []v1.Container{{Name: "app"}, {Name: "sidecar"}}
+			pods[i].Status.Phase = phase
+		}
+		return pods
+	}
+
+	t.Run("within the limit", func(t *testing.T) {
+		pods = append(newPods(3, v1.PodRunning), newPods(5, v1.PodSucceeded)...)
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckMaxPods(buf, &v1alpha2.TailoredKubeletConfiguration{MaxPods: 8}))
+		assert.Equal(t, "pods: 3/8 of maxPods, containers: 6\n", buf.String())
+	})
+
+	t.Run("near the limit", func(t *testing.T) {
+		pods = newPods(9, v1.PodRunning)
+		err := CheckMaxPods(&bytes.Buffer{}, &v1alpha2.TailoredKubeletConfiguration{MaxPods: 10})
+		require.Error(t, err)
+		assert.True(t, isDiagnoseWarning(err))
+		assert.Contains(t, err.Error(), "9 active pods are near maxPods 10")
+	})
+
+	t.Run("limit reached", func(t *testing.T) {
+		pods = newPods(10, v1.PodPending)
+		err := CheckMaxPods(&bytes.Buffer{}, &v1alpha2.TailoredKubeletConfiguration{MaxPods: 10})
+		require.Error(t, err)
+		assert.False(t, isDiagnoseWarning(err))
+		assert.Contains(t, err.Error(), "10 active pods reach maxPods 10")
+	})
+
+	t.Run("default maxPods", func(t *testing.T) {
+		pods = newPods(1, v1.PodRunning)
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckMaxPods(buf, &v1alpha2.TailoredKubeletConfiguration{}))
+		assert.Contains(t, buf.String(), "pods: 1/110 of maxPods")
+	})
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/kubeedge/api/apis/common/constants"
+)
+
+const (
+	// ipv4HeaderLen and ipv6HeaderLen are the ip headers plus the tcp header added to the mss of a tcp connection
+	ipv4HeaderLen = 40
+	ipv6HeaderLen = 60
+	// vxlanOverhead is the encapsulation overhead of the vxlan overlays, the path MTU must be larger by it than the MTU of the pods
+	vxlanOverhead = 50
+)
+
+// CheckPathMTU measures the path MTU of a tcp connection to the cloudhub server, the smaller of the MTU of the path
+// known to the kernel and the MTU implied by the mss negotiated with the server, which the VPNs and the overlays clamp.
+// The websocket connection establishes over a small MTU, but the large messages fragment and stall, so it warns if
+// the path MTU is below minMTU, or below the MTU of the CNI config in cniConfDir plus the vxlan overhead.
+func CheckPathMTU(w io.Writer, server string, timeout, minMTU int, cniConfDir string) error {
+	conn, err := net.DialTimeout("tcp", server, time.Duration(timeout)*time.Second)
+	if err != nil {
+		return warningError(fmt.Errorf("failed to measure the path MTU to %s: %v", server, err))
+	}
+	defer conn.Close()
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return warningError(fmt.Errorf("failed to measure the path MTU to %s: not a tcp connection", server))
+	}
+	routeMTU, mss, err := tcpPathMTU(tcpConn)
+	if err != nil {
+		return warningError(fmt.Errorf("failed to measure the path MTU to %s: %v", server, err))
+	}
+	headerLen := ipv4HeaderLen
+	if addr, ok := tcpConn.RemoteAddr().(*net.TCPAddr); ok && addr.IP.To4() == nil {
+		headerLen = ipv6HeaderLen
+	}
+	mtu := min(routeMTU, mss+headerLen)
+	fmt.Fprintf(w, "path MTU to %s: %d (route MTU %d, tcp mss %d), required >= %d\n", server, mtu, routeMTU, mss, minMTU)
+
+	if mtu < minMTU {
+		return warningError(fmt.Errorf("path MTU %d to %s is below %d, the large messages of cloudhub fragment and the sync may stall, "+
+			"check the MTU of the VPN or the tunnel to cloudcore", mtu, server, minMTU))
+	}
+	if podMTU, file := cniMTU(cniConfDir); podMTU > 0 {
+		if required := podMTU + vxlanOverhead; mtu < required {
+			return warningError(fmt.Errorf("path MTU %d to %s is below %d required by the MTU %d of the CNI config %s plus the overlay overhead, "+
+				"lower the MTU of the CNI network", mtu, server, required, podMTU, file))
+		}
+		fmt.Fprintf(w, "MTU of the CNI config %s: %d, the overlay overhead fits in the path MTU\n", file, podMTU)
+	}
+	return nil
+}
+
+// cniMTU returns the MTU of the first CNI config in confDir which sets one, in the config or in one of its plugins.
+// It returns 0 if no config sets the MTU, the CNI plugins then use the MTU of the host interface.
+func cniMTU(confDir string) (int, string) {
+	if confDir == "" {
+		confDir = constants.DefaultCNIConfDir
+	}
+	entries, err := os.ReadDir(confDir)
+	if err != nil {
+		return 0, ""
+	}
+	var names []string
+	for _, e := range entries {
+		switch filepath.Ext(e.Name()) {
+		case ".conflist", ".conf", ".json":
+			if !e.IsDir() {
+				names = append(names, e.Name())
+			}
+		}
+	}
+	sort.Strings(names)
+
+	type mtuConfig struct {
+		MTU     int `json:"mtu"`
+		Plugins []struct {
+			MTU int `json:"mtu"`
+		} `json:"plugins"`
+	}
+	for _, name := range names {
+		file := filepath.Join(confDir, name)
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var conf mtuConfig
+		if err := json.Unmarshal(data, &conf); err != nil {
+			continue
+		}
+		if conf.MTU > 0 {
+			return conf.MTU, file
+		}
+		for _, p := range conf.Plugins {
+			if p.MTU > 0 {
+				return p.MTU, file
+			}
+		}
+	}
+	return 0, ""
+}
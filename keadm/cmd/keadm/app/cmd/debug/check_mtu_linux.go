@@ -0,0 +1,48 @@
+//go:build linux
+
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// tcpPathMTU returns the MTU of the path of the tcp connection known to the kernel and the mss of the connection
+func tcpPathMTU(conn *net.TCPConn) (int, int, error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return 0, 0, err
+	}
+	level, opt := unix.IPPROTO_IP, unix.IP_MTU
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok && addr.IP.To4() == nil {
+		level, opt = unix.IPPROTO_IPV6, unix.IPV6_MTU
+	}
+	var mtu, mss int
+	var sockErr error
+	if err := raw.Control(func(fd uintptr) {
+		if mtu, sockErr = unix.GetsockoptInt(int(fd), level, opt); sockErr != nil {
+			return
+		}
+		mss, sockErr = unix.GetsockoptInt(int(fd), unix.IPPROTO_TCP, unix.TCP_MAXSEG)
+	}); err != nil {
+		return 0, 0, err
+	}
+	return mtu, mss, sockErr
+}
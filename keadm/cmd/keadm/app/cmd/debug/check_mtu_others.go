@@ -0,0 +1,31 @@
+//go:build !linux
+
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+)
+
+// tcpPathMTU returns the MTU of the path of the tcp connection known to the kernel and the mss of the connection,
+// which is only supported on linux
+func tcpPathMTU(_ *net.TCPConn) (int, int, error) {
+	return 0, 0, fmt.Errorf("measuring the path MTU is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPathMTU(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	var routeMTU, mss int
+	patches := gomonkey.ApplyFunc(tcpPathMTU, func(_ *net.TCPConn) (int, int, error) {
+		return routeMTU, mss, nil
+	})
+	defer patches.Reset()
+
+	t.Run("large enough", func(t *testing.T) {
+		routeMTU, mss = 1500, 1460
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckPathMTU(buf, ln.Addr().String(), 1, 1280, t.TempDir()))
+		assert.Contains(t, buf.String(), "path MTU to "+ln.Addr().String()+": 1500 (route MTU 1500, tcp mss 1460), required >= 1280")
+	})
+
+	t.Run("mss clamped below the min", func(t *testing.T) {
+		routeMTU, mss = 1500, 1160
+		err := CheckPathMTU(&bytes.Buffer{}, ln.Addr().String(), 1, 1280, t.TempDir())
+		require.Error(t, err)
+		assert.True(t, isDiagnoseWarning(err))
+		assert.Contains(t, err.Error(), "path MTU 1200")
+	})
+
+	t.Run("below the MTU of the overlay", func(t *testing.T) {
+		routeMTU, mss = 1450, 1410
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "10-flannel.conflist"),
+			[]byte(`{"name":"cbr0","plugins":[{"type":"flannel","mtu":1450},{"type":"portmap"}]}`), 0600))
+		err := CheckPathMTU(&bytes.Buffer{}, ln.Addr().String(), 1, 1280, dir)
+		require.Error(t, err)
+		assert.True(t, isDiagnoseWarning(err))
+		assert.Contains(t, err.Error(), "below 1500 required by the MTU 1450 of the CNI config")
+	})
+
+	t.Run("server unreachable", func(t *testing.T) {
+		closed, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := closed.Addr().String()
+		closed.Close()
+		err = CheckPathMTU(&bytes.Buffer{}, addr, 1, 1280, "")
+		require.Error(t, err)
+		assert.True(t, isDiagnoseWarning(err))
+	})
+}
+
+func TestTCPPathMTU(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the path MTU is only measured on linux")
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	mtu, mss, err := tcpPathMTU(conn.(*net.TCPConn))
+	require.NoError(t, err)
+	assert.Greater(t, mtu, 0)
+	assert.Greater(t, mss, 0)
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/api/apis/common/constants"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/util/files"
+)
+
+// CheckCachedNode checks whether the node cached by metamanager reports the Ready condition as True.
+// The edgecore process may be running while the node status stops being updated, which is caught by
+// the status and the last heartbeat time of the Ready condition.
+func CheckCachedNode(w io.Writer, nodeName string) error {
+	node, err := QueryNodeFromDatabase(metav1.NamespaceDefault, nodeName)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return fmt.Errorf("node %s is not cached in the database, edgecore has not registered the node", nodeName)
+	}
+
+	for _, condition := range node.Status.Conditions {
+		if condition.Type != v1.NodeReady {
+			continue
+		}
+		heartbeat := "never"
+		if !condition.LastHeartbeatTime.IsZero() {
+			heartbeat = fmt.Sprintf("%s (%v ago)", condition.LastHeartbeatTime.Format(time.RFC3339),
+				time.Since(condition.LastHeartbeatTime.Time).Round(time.Second))
+		}
+		if condition.Status != v1.ConditionTrue {
+			return fmt.Errorf("node %s Ready condition is %s, reason: %s, message: %s, last heartbeat: %s",
+				nodeName, condition.Status, condition.Reason, condition.Message, heartbeat)
+		}
+		fmt.Fprintf(w, "node %s is Ready, last heartbeat: %s\n", nodeName, heartbeat)
+		return nil
+	}
+	return fmt.Errorf("node %s has no Ready condition in the database", nodeName)
+}
+
+// CheckNodeLabelsTaints checks that the cached node has the labels and the taints which edged registers the node with.
+// edged only applies them when it registers the node, so the later changes of the config, or of the node from the cloud,
+// drift from the config, which is reported as a warning. The taints of the node which are not in the config are printed,
+// since they keep the pods without the tolerations off the node.
+func CheckNodeLabelsTaints(w io.Writer, nodeName string, labels map[string]string, taints []v1.Taint) error {
+	node, err := QueryNodeFromDatabase(metav1.NamespaceDefault, nodeName)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return fmt.Errorf("node %s is not cached in the database, edgecore has not registered the node", nodeName)
+	}
+
+	var drifts []string
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, ok := node.Labels[key]
+		switch {
+		case !ok:
+			drifts = append(drifts, fmt.Sprintf("label %s=%s is not on the node", key, labels[key]))
+		case value != labels[key]:
+			drifts = append(drifts, fmt.Sprintf("label %s is %s on the node, but %s in the config", key, value, labels[key]))
+		default:
+			fmt.Fprintf(w, "label %s=%s is on the node\n", key, value)
+		}
+	}
+
+	configured := make(map[string]bool, len(taints))
+	for _, taint := range taints {
+		configured[taint.Key+":"+string(taint.Effect)] = true
+		found := false
+		for _, nodeTaint := range node.Spec.Taints {
+			if !nodeTaint.MatchTaint(&taint) {
+				continue
+			}
+			found = true
+			if nodeTaint.Value != taint.Value {
+				drifts = append(drifts, fmt.Sprintf("taint %s is %s on the node", taint.ToString(), nodeTaint.ToString()))
+			} else {
+				fmt.Fprintf(w, "taint %s is on the node\n", taint.ToString())
+			}
+		}
+		if !found {
+			drifts = append(drifts, fmt.Sprintf("taint %s is not on the node", taint.ToString()))
+		}
+	}
+	for _, nodeTaint := range node.Spec.Taints {
+		if !configured[nodeTaint.Key+":"+string(nodeTaint.Effect)] {
+			fmt.Fprintf(w, "taint %s is on the node but not in the config, the pods without its toleration are not scheduled to the node\n",
+				nodeTaint.ToString())
+		}
+	}
+
+	if len(drifts) > 0 {
+		return warningError(fmt.Errorf("node %s drifts from the labels and taints of the config, which edged only applies when it registers the node: %s",
+			nodeName, strings.Join(drifts, "; ")))
+	}
+	fmt.Fprintf(w, "node %s has the %d labels and %d taints of the config\n", nodeName, len(labels), len(taints))
+	return nil
+}
+
+// productUUIDFile is the system uuid of the host, which kubelet reports in the node info
+const productUUIDFile = "/sys/class/dmi/id/product_uuid"
+
+// machineIDFiles are the files of the machine id of the host in the same order as cadvisor reads them
+var machineIDFiles = []string{"/etc/machine-id", "/var/lib/dbus/machine-id"}
+
+// nodeIdentity is the identity of a host which edged reports in the node status
+type nodeIdentity struct {
+	machineID  string
+	systemUUID string
+	addresses  map[string]bool
+}
+
+// CheckNodeIdentity checks whether the node is reported by this host, by comparing the machine id, the system uuid
+// and the internal addresses of the node status with the host. Another edgecore configured with the same node name
+// reports the node status of its own host, and the two fight over the node lease, which is reported as a warning.
+// The node in the apiserver of kubeConfig is compared, which is the node status cloudcore has received; the node
+// cached in the edge database is compared if kubeConfig is not exists, which lags behind it.
+func CheckNodeIdentity(w io.Writer, nodeName, kubeConfig string) error {
+	fmt.Fprintf(w, "the configured node name is %s\n", nodeName)
+	node, source, err := identityNode(w, nodeName, kubeConfig)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "comparing node %s in %s with this host\n", nodeName, source)
+
+	local := localNodeIdentity()
+	info := node.Status.NodeInfo
+	var conflicts []string
+	if info.MachineID != "" && local.machineID != "" && info.MachineID != local.machineID {
+		conflicts = append(conflicts, fmt.Sprintf("the machine id of the node is %s, but %s on this host", info.MachineID, local.machineID))
+	}
+	if info.SystemUUID != "" && local.systemUUID != "" && !strings.EqualFold(info.SystemUUID, local.systemUUID) {
+		conflicts = append(conflicts, fmt.Sprintf("the system uuid of the node is %s, but %s on this host", info.SystemUUID, local.systemUUID))
+	}
+	var internalIPs []string
+	var found bool
+	for _, address := range node.Status.Addresses {
+		if address.Type != v1.NodeInternalIP {
+			continue
+		}
+		internalIPs = append(internalIPs, address.Address)
+		found = found || local.addresses[address.Address]
+	}
+	if len(internalIPs) > 0 && !found {
+		conflicts = append(conflicts, fmt.Sprintf("the internal ip %s of the node is not an address of this host", strings.Join(internalIPs, ", ")))
+	}
+
+	if len(conflicts) > 0 {
+		return warningError(fmt.Errorf("node name %s in %s may be used by another edgecore, which reports the status of the node "+
+			"and fights over the node lease with this host: %s; set a unique node name by modules.edged.hostnameOverride of the config",
+			nodeName, source, strings.Join(conflicts, "; ")))
+	}
+	fmt.Fprintf(w, "node %s is reported by this host\n", nodeName)
+	return nil
+}
+
+// identityNode returns the node CheckNodeIdentity compares and where it is read from, the node in the apiserver
+// of kubeConfig is returned if kubeConfig exists, otherwise the node cached in the edge database
+func identityNode(w io.Writer, nodeName, kubeConfig string) (*v1.Node, string, error) {
+	if files.FileExists(kubeConfig) {
+		cli, err := util.KubeClient(kubeConfig)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create KubeClient, error: %v", err)
+		}
+		node, err := cli.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("get node %s from the apiserver failed: %v", nodeName, err)
+		}
+		return node, "the apiserver", nil
+	}
+	fmt.Fprintf(w, "kubeconfig %s is not exists, the node cached in the edge database is compared, "+
+		"which only shows another edgecore once the node status is synced back to this node\n", kubeConfig)
+	node, err := QueryNodeFromDatabase(metav1.NamespaceDefault, nodeName)
+	if err != nil {
+		return nil, "", err
+	}
+	if node == nil {
+		return nil, "", fmt.Errorf("node %s is not cached in the database, edgecore has not registered the node", nodeName)
+	}
+	return node, "the edge database", nil
+}
+
+// localNodeIdentity returns the identity of this host, the values which can not be read are left empty
+func localNodeIdentity() nodeIdentity {
+	identity := nodeIdentity{addresses: make(map[string]bool)}
+	for _, file := range machineIDFiles {
+		if data, err := os.ReadFile(file); err == nil && strings.TrimSpace(string(data)) != "" {
+			identity.machineID = strings.TrimSpace(string(data))
+			break
+		}
+	}
+	if data, err := os.ReadFile(productUUIDFile); err == nil {
+		identity.systemUUID = strings.TrimSpace(string(data))
+	}
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok {
+				identity.addresses[ipNet.IP.String()] = true
+			}
+		}
+	}
+	return identity
+}
+
+// QueryNodeFromDatabase returns the node cached in the database, it returns nil if it is not cached
+func QueryNodeFromDatabase(namespace, name string) (*v1.Node, error) {
+	key := fmt.Sprintf("%v/%v/%v", namespace, model.ResourceTypeNode, name)
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+	result, err := queryMeta(ctx, "key", key)
+	if err != nil {
+		return nil, fmt.Errorf("read database fail: %s", err.Error())
+	}
+	if len(*result) == 0 {
+		return nil, nil
+	}
+
+	node := &v1.Node{}
+	if err := json.Unmarshal([]byte((*result)[0]), node); err != nil {
+		return nil, fmt.Errorf("unmarshal %s failed: %v", key, err)
+	}
+	return node, nil
+}
+
+// CheckEdgecoreUser checks whether edgecore runs as edgecoreUser, edgecore of another user is reported running,
+// but it can not read the config and the database or manage the containers and the pod directories.
+func CheckEdgecoreUser(w io.Writer, ex Executor) error {
+	users, err := ex.ProcessUsers(constants.KubeEdgeBinaryName)
+	if err != nil {
+		return warningError(fmt.Errorf("failed to get the user of edgecore: %v", err))
+	}
+	for _, user := range users {
+		if user != edgecoreUser {
+			return warningError(fmt.Errorf("edgecore is running as user %s instead of %s, it may have no permission to "+
+				"access the config, the database and the container runtime socket", user, edgecoreUser))
+		}
+	}
+	fmt.Fprintf(w, "edgecore is running as user %s\n", edgecoreUser)
+	return nil
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+)
+
+func TestCheckCachedNode(t *testing.T) {
+	heartbeat := metav1.NewTime(time.Now().Add(-time.Minute))
+	nodeWithReady := func(status v1.ConditionStatus) string {
+		node := v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "edge-node"},
+			Status: v1.NodeStatus{Conditions: []v1.NodeCondition{{
+				Type:              v1.NodeReady,
+				Status:            status,
+				Reason:            "EdgeReady",
+				LastHeartbeatTime: heartbeat,
+			}}},
+		}
+		data, err := json.Marshal(node)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	cases := []struct {
+		name        string
+		cached      []string
+		expectedErr string
+		expectedOut string
+	}{
+		{
+			name:        "node is ready",
+			cached:      []string{nodeWithReady(v1.ConditionTrue)},
+			expectedOut: "node edge-node is Ready, last heartbeat: " + heartbeat.Format(time.RFC3339),
+		},
+		{
+			name:        "node is not ready",
+			cached:      []string{nodeWithReady(v1.ConditionUnknown)},
+			expectedErr: "node edge-node Ready condition is Unknown, reason: EdgeReady",
+		},
+		{
+			name:        "node has no ready condition",
+			cached:      []string{`{"metadata":{"name":"edge-node"}}`},
+			expectedErr: "node edge-node has no Ready condition in the database",
+		},
+		{
+			name:        "node is not cached",
+			cached:      []string{},
+			expectedErr: "node edge-node is not cached in the database",
+		},
+		{
+			name:        "invalid node",
+			cached:      []string{"invalid"},
+			expectedErr: "unmarshal default/node/edge-node failed",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			patches := gomonkey.ApplyFunc(dao.QueryMetaWithContext, func(_ctx context.Context, _key, condition string) (*[]string, error) {
+				assert.Equal(t, "default/node/edge-node", condition)
+				return &c.cached, nil
+			})
+			defer patches.Reset()
+
+			buf := &bytes.Buffer{}
+			err := CheckCachedNode(buf, "edge-node")
+			if c.expectedErr != "" {
+				require.ErrorContains(t, err, c.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, buf.String(), c.expectedOut)
+		})
+	}
+}
+
+func TestCheckNodeLabelsTaints(t *testing.T) {
+	node := v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "edge-node", Labels: map[string]string{"zone": "east", "role": "edge", "gpu": "false"}},
+		Spec: v1.NodeSpec{Taints: []v1.Taint{
+			{Key: "dedicated", Value: "edge", Effect: v1.TaintEffectNoSchedule},
+			{Key: "maintenance", Effect: v1.TaintEffectNoExecute},
+		}},
+	}
+	data, err := json.Marshal(node)
+	require.NoError(t, err)
+	cached := []string{string(data)}
+	patches := gomonkey.ApplyFunc(dao.QueryMetaWithContext, func(_ctx context.Context, _key, _condition string) (*[]string, error) {
+		return &cached, nil
+	})
+	defer patches.Reset()
+
+	t.Run("matched", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckNodeLabelsTaints(buf, "edge-node", map[string]string{"zone": "east"},
+			[]v1.Taint{{Key: "dedicated", Value: "edge", Effect: v1.TaintEffectNoSchedule}}))
+		assert.Contains(t, buf.String(), "label zone=east is on the node\n")
+		assert.Contains(t, buf.String(), "taint dedicated=edge:NoSchedule is on the node\n")
+		assert.Contains(t, buf.String(), "taint maintenance:NoExecute is on the node but not in the config")
+		assert.Contains(t, buf.String(), "node edge-node has the 1 labels and 1 taints of the config")
+	})
+
+	t.Run("drifted", func(t *testing.T) {
+		err := CheckNodeLabelsTaints(&bytes.Buffer{}, "edge-node", map[string]string{"zone": "west", "disk": "ssd", "role": "edge"},
+			[]v1.Taint{
+				{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule},
+				{Key: "edge", Effect: v1.TaintEffectPreferNoSchedule},
+			})
+		require.EqualError(t, err, "node edge-node drifts from the labels and taints of the config, which edged only applies when it registers the node: "+
+			"label disk=ssd is not on the node; label zone is east on the node, but west in the config; "+
+			"taint dedicated=gpu:NoSchedule is dedicated=edge:NoSchedule on the node; taint edge:PreferNoSchedule is not on the node")
+		assert.True(t, isDiagnoseWarning(err))
+	})
+
+	t.Run("node is not cached", func(t *testing.T) {
+		cached = []string{}
+		err := CheckNodeLabelsTaints(&bytes.Buffer{}, "edge-node", nil, nil)
+		require.ErrorContains(t, err, "node edge-node is not cached in the database")
+	})
+}
+
+func TestCheckNodeIdentity(t *testing.T) {
+	node := v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "edge-node"},
+		Status: v1.NodeStatus{
+			Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "192.168.1.10"}, {Type: v1.NodeHostName, Address: "edge-node"}},
+			NodeInfo:  v1.NodeSystemInfo{MachineID: "machine-1", SystemUUID: "UUID-1"},
+		},
+	}
+	data, err := json.Marshal(node)
+	require.NoError(t, err)
+	cached := []string{string(data)}
+	patches := gomonkey.ApplyFunc(dao.QueryMetaWithContext, func(_ctx context.Context, _key, _condition string) (*[]string, error) {
+		return &cached, nil
+	})
+	defer patches.Reset()
+	noKubeConfig := filepath.Join(t.TempDir(), "not-exists")
+
+	t.Run("reported by this host", func(t *testing.T) {
+		patches := gomonkey.ApplyFunc(localNodeIdentity, func() nodeIdentity {
+			return nodeIdentity{machineID: "machine-1", systemUUID: "uuid-1", addresses: map[string]bool{"192.168.1.10": true}}
+		})
+		defer patches.Reset()
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckNodeIdentity(buf, "edge-node", noKubeConfig))
+		assert.Contains(t, buf.String(), "the configured node name is edge-node\n")
+		assert.Contains(t, buf.String(), "comparing node edge-node in the edge database with this host\n")
+		assert.Contains(t, buf.String(), "node edge-node is reported by this host\n")
+	})
+
+	t.Run("reported by another host", func(t *testing.T) {
+		patches := gomonkey.ApplyFunc(localNodeIdentity, func() nodeIdentity {
+			return nodeIdentity{machineID: "machine-2", addresses: map[string]bool{"192.168.1.20": true}}
+		})
+		defer patches.Reset()
+
+		err := CheckNodeIdentity(&bytes.Buffer{}, "edge-node", noKubeConfig)
+		require.EqualError(t, err, "node name edge-node in the edge database may be used by another edgecore, which reports the status of the node "+
+			"and fights over the node lease with this host: the machine id of the node is machine-1, but machine-2 on this host; "+
+			"the internal ip 192.168.1.10 of the node is not an address of this host; "+
+			"set a unique node name by modules.edged.hostnameOverride of the config")
+		assert.True(t, isDiagnoseWarning(err))
+	})
+
+	t.Run("reported by another host in the apiserver", func(t *testing.T) {
+		patches := gomonkey.ApplyFunc(localNodeIdentity, func() nodeIdentity {
+			return nodeIdentity{machineID: "machine-1", systemUUID: "uuid-1", addresses: map[string]bool{"192.168.1.10": true}}
+		})
+		defer patches.Reset()
+
+		// the node in the apiserver is reported by another host, while the cached node is not synced yet
+		apiNode := node
+		apiNode.Status.NodeInfo.MachineID = "machine-2"
+		apiserver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v1/nodes/edge-node" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(apiNode)
+		}))
+		defer apiserver.Close()
+		kubeConfig := filepath.Join(t.TempDir(), "config")
+		require.NoError(t, os.WriteFile(kubeConfig, []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: %s
+contexts:
+- name: test
+  context:
+    cluster: test
+current-context: test
+`, apiserver.URL)), 0600))
+
+		buf := &bytes.Buffer{}
+		err := CheckNodeIdentity(buf, "edge-node", kubeConfig)
+		require.ErrorContains(t, err, "node name edge-node in the apiserver may be used by another edgecore")
+		require.ErrorContains(t, err, "the machine id of the node is machine-2, but machine-1 on this host")
+		assert.True(t, isDiagnoseWarning(err))
+		assert.Contains(t, buf.String(), "comparing node edge-node in the apiserver with this host\n")
+	})
+
+	t.Run("node is not cached", func(t *testing.T) {
+		cached = []string{}
+		err := CheckNodeIdentity(&bytes.Buffer{}, "edge-node", noKubeConfig)
+		require.ErrorContains(t, err, "node edge-node is not cached in the database")
+	})
+}
+
+func TestLocalNodeIdentity(t *testing.T) {
+	identity := localNodeIdentity()
+	assert.NotNil(t, identity.addresses)
+}
+
+// fakeUserExecutor is the local executor whose processes are running as the users
+type fakeUserExecutor struct {
+	localExecutor
+	users []string
+	err   error
+}
+
+func (e fakeUserExecutor) ProcessUsers(_proc string) ([]string, error) {
+	return e.users, e.err
+}
+
+func TestCheckEdgecoreUser(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, CheckEdgecoreUser(buf, fakeUserExecutor{users: []string{"root"}}))
+	assert.Equal(t, "edgecore is running as user root\n", buf.String())
+
+	err := CheckEdgecoreUser(&bytes.Buffer{}, fakeUserExecutor{users: []string{"root", "edge"}})
+	require.EqualError(t, err, "edgecore is running as user edge instead of root, it may have no permission to "+
+		"access the config, the database and the container runtime socket")
+	assert.True(t, isDiagnoseWarning(err))
+
+	err = CheckEdgecoreUser(&bytes.Buffer{}, fakeUserExecutor{err: errors.New("list processes failed")})
+	assert.True(t, isDiagnoseWarning(err))
+}
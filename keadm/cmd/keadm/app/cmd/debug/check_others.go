@@ -0,0 +1,72 @@
+//go:build !windows
+
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/shirou/gopsutil/disk"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+)
+
+const (
+	// edgecoreUser is the user which edgecore is expected to run as
+	edgecoreUser = "root"
+	// executableSuffix is the suffix of the names of the executables, which the processes are named after
+	executableSuffix = ""
+)
+
+// platformUnsupportedChecks are the checks which are not supported on the platform, all the checks are supported on linux
+var platformUnsupportedChecks map[string]bool
+
+// systemDiskPath returns the mount point of the first disk partition
+func systemDiskPath() (string, error) {
+	parts, err := disk.Partitions(false)
+	if err != nil {
+		return "", err
+	}
+	if len(parts) == 0 {
+		return "", errors.New("no disk partition is found")
+	}
+	return parts[0].Mountpoint, nil
+}
+
+func CheckPid(w io.Writer) error {
+	rMax, err := util.ExecShellFilter(common.CmdGetMaxProcessNum)
+	if err != nil {
+		return err
+	}
+	r, err := util.ExecShellFilter(common.CmdGetProcessNum)
+	if err != nil {
+		return err
+	}
+	vMax, err := strconv.ParseFloat(rMax, 32)
+	v, err := strconv.ParseFloat(r, 32)
+	rate := (1 - v/vMax)
+	if rate > common.AllowedValuePIDRate {
+		fmt.Fprintf(w, "Maximum PIDs: %s; Running processes: %s\n", rMax, r)
+		return nil
+	}
+	return fmt.Errorf("Maximum PIDs: %s; Running processes: %s", rMax, r)
+}
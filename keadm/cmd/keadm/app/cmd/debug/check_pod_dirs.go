@@ -0,0 +1,139 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/api/apis/common/constants"
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+// procMounts is the mount table of the host, the volumes of the pods are mounted under <rootDir>/pods/<uid>/volumes
+const procMounts = "/proc/mounts"
+
+// orphanedPodDir is a pod dir of edged whose pod is not cached in the edge database
+type orphanedPodDir struct {
+	path   string
+	inodes int
+	mounts []string
+}
+
+// CheckOrphanedPodDirs warns about the pod dirs under <rootDir>/pods which edged leaves behind for the pods
+// that are no longer cached in the edge database, the dirs are named by the pod uids. The inodes the files of
+// the dirs take and the volumes still mounted under them are reported, the mounted volumes must be unmounted
+// before the dirs are removed. The database must be initialized before.
+func CheckOrphanedPodDirs(w io.Writer, rootDir string) error {
+	podsDir := filepath.Join(rootDir, "pods")
+	entries, err := os.ReadDir(podsDir)
+	if os.IsNotExist(err) {
+		fmt.Fprintf(w, "pod dir %s is not exists, skip\n", podsDir)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read pod dir %s failed: %v", podsDir, err)
+	}
+
+	pods, err := QueryPodsFromDatabase(metav1.NamespaceAll)
+	if err != nil {
+		return err
+	}
+	uids := make(map[string]bool, len(pods))
+	for _, pod := range pods {
+		uids[string(pod.UID)] = true
+	}
+	mounts, err := mountPoints()
+	if err != nil {
+		return err
+	}
+
+	var orphans []orphanedPodDir
+	for _, e := range entries {
+		if !e.IsDir() || uids[e.Name()] {
+			continue
+		}
+		dir := orphanedPodDir{path: filepath.Join(podsDir, e.Name())}
+		// the unreadable files are not counted, e.g. in the volumes of other users
+		_ = filepath.WalkDir(dir.path, func(_ string, _ fs.DirEntry, err error) error {
+			if err == nil {
+				dir.inodes++
+			}
+			return nil
+		})
+		for _, mount := range mounts {
+			if strings.HasPrefix(mount, dir.path+string(filepath.Separator)) {
+				dir.mounts = append(dir.mounts, mount)
+			}
+		}
+		orphans = append(orphans, dir)
+	}
+
+	if len(orphans) == 0 {
+		fmt.Fprintf(w, "all the %d pod dirs under %s belong to the pods in the edge database\n", len(entries), podsDir)
+		return nil
+	}
+	var inodes int
+	for _, dir := range orphans {
+		inodes += dir.inodes
+		fmt.Fprintf(w, "WARNING: pod dir %s is orphaned, it takes %d inodes\n", dir.path, dir.inodes)
+		for _, mount := range dir.mounts {
+			fmt.Fprintf(w, "WARNING: volume %s of the orphaned pod dir is still mounted, unmount it before removing the dir\n", mount)
+		}
+	}
+	return warningError(fmt.Errorf("%d/%d pod dirs under %s are orphaned and take %d inodes, remove them once the pods are confirmed deleted",
+		len(orphans), len(entries), podsDir, inodes))
+}
+
+// mountPoints returns the sorted mount points in the mount table of the host
+func mountPoints() ([]string, error) {
+	f, err := os.Open(procMounts)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read mount table %s failed: %v", procMounts, err)
+	}
+	defer f.Close()
+
+	var mounts []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) >= 2 {
+			// the spaces in the mount points are escaped as \040
+			mounts = append(mounts, strings.ReplaceAll(fields[1], `\040`, " "))
+		}
+	}
+	sort.Strings(mounts)
+	return mounts, scanner.Err()
+}
+
+// edgedRootDir returns the root dir of edged, or the default one if it is not set
+func edgedRootDir(edged *v1alpha2.Edged) string {
+	if edged.RootDirectory == "" {
+		return constants.DefaultRootDir
+	}
+	return edged.RootDirectory
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubeedge/api/apis/common/constants"
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+func TestCheckOrphanedPodDirs(t *testing.T) {
+	rootDir := t.TempDir()
+	podsDir := filepath.Join(rootDir, "pods")
+	cachedVolume := filepath.Join(podsDir, "uid-cached", "volumes", "kubernetes.io~configmap", "config")
+	orphanVolume := filepath.Join(podsDir, "uid-orphan", "volumes", "kubernetes.io~empty-dir", "data")
+	require.NoError(t, os.MkdirAll(cachedVolume, 0700))
+	require.NoError(t, os.MkdirAll(orphanVolume, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(orphanVolume, "file"), []byte("data"), 0600))
+
+	patches := gomonkey.NewPatches()
+	defer patches.Reset()
+	patches.ApplyFunc(QueryPodsFromDatabase, func(_namespace string) ([]v1.Pod, error) {
+		return []v1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "nginx", UID: types.UID("uid-cached")}}}, nil
+	})
+	patches.ApplyFunc(mountPoints, func() ([]string, error) {
+		return []string{"/", orphanVolume, filepath.Join(podsDir, "uid-cached-other")}, nil
+	})
+
+	t.Run("orphaned pod dir", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := CheckOrphanedPodDirs(buf, rootDir)
+		require.ErrorContains(t, err, fmt.Sprintf("1/2 pod dirs under %s are orphaned and take 5 inodes", podsDir))
+		assert.True(t, isDiagnoseWarning(err))
+		// the pod dir, volumes, the plugin dir, the volume dir and the file
+		assert.Contains(t, buf.String(), fmt.Sprintf("WARNING: pod dir %s is orphaned, it takes 5 inodes", filepath.Join(podsDir, "uid-orphan")))
+		assert.Contains(t, buf.String(), fmt.Sprintf("WARNING: volume %s of the orphaned pod dir is still mounted", orphanVolume))
+		assert.NotContains(t, buf.String(), "uid-cached")
+	})
+
+	t.Run("no orphaned pod dirs", func(t *testing.T) {
+		require.NoError(t, os.RemoveAll(filepath.Join(podsDir, "uid-orphan")))
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckOrphanedPodDirs(buf, rootDir))
+		assert.Contains(t, buf.String(), fmt.Sprintf("all the 1 pod dirs under %s belong to the pods in the edge database", podsDir))
+	})
+
+	t.Run("pod dir is not exists", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckOrphanedPodDirs(buf, filepath.Join(rootDir, "not-exists")))
+		assert.Contains(t, buf.String(), "is not exists, skip")
+	})
+}
+
+func TestEdgedRootDir(t *testing.T) {
+	edged := &v1alpha2.Edged{}
+	assert.Equal(t, constants.DefaultRootDir, edgedRootDir(edged))
+	edged.RootDirectory = "/data/edged"
+	assert.Equal(t, "/data/edged", edgedRootDir(edged))
+}
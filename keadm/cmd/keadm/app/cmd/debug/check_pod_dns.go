@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+// defaultPodDNSDomain is the domain the nameservers of resolvConf resolve if no domain is specified
+const defaultPodDNSDomain = "kubeedge.io"
+
+// CheckPodDNS checks the DNS servers which edged passes to the containers, which differ from the DNS of the node:
+// the cluster DNS servers resolve the kubernetes service of the cluster domain, and the nameservers of resolvConf,
+// which the pods inherit, resolve domain, or defaultPodDNSDomain if it is empty. A loopback nameserver, e.g. the
+// stub resolver of systemd-resolved, fails the check since it is unreachable from the network namespace of the pods.
+func CheckPodDNS(w io.Writer, kubelet *v1alpha2.TailoredKubeletConfiguration, domain string, timeout int) error {
+	if domain == "" {
+		domain = defaultPodDNSDomain
+	}
+	var errs []error
+
+	var resolvConf string
+	if kubelet.ResolverConfig != nil {
+		resolvConf = *kubelet.ResolverConfig
+	}
+	if resolvConf == "" {
+		fmt.Fprintf(w, "resolvConf is empty, the pods do not inherit the DNS of the node\n")
+	} else {
+		nameservers, err := resolvConfNameservers(resolvConf)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("read resolvConf %s of edged failed: %v, the pods fail to start", resolvConf, err))
+		} else {
+			fmt.Fprintf(w, "resolvConf: %s, nameservers: %s\n", resolvConf, strings.Join(nameservers, ","))
+			for _, ns := range nameservers {
+				if ip := net.ParseIP(ns); ip != nil && ip.IsLoopback() {
+					errs = append(errs, fmt.Errorf("nameserver %s of resolvConf %s is a loopback address, which is unreachable from the pods,"+
+						" set resolvConf to the resolv.conf of the upstream servers, e.g. /run/systemd/resolve/resolv.conf", ns, resolvConf))
+					continue
+				}
+				errs = append(errs, lookupHostVia(w, ns, domain, timeout))
+			}
+		}
+	}
+
+	if len(kubelet.ClusterDNS) == 0 {
+		fmt.Fprintf(w, "clusterDNS is not set, the pods of dnsPolicy ClusterFirst use the nameservers of resolvConf\n")
+		return errors.Join(errs...)
+	}
+	fmt.Fprintf(w, "clusterDNS: %s\n", strings.Join(kubelet.ClusterDNS, ","))
+	clusterDomain := kubelet.ClusterDomain
+	if clusterDomain == "" {
+		clusterDomain = "cluster.local"
+	}
+	for _, server := range kubelet.ClusterDNS {
+		errs = append(errs, lookupHostVia(w, server, "kubernetes.default.svc."+clusterDomain, timeout))
+	}
+	return errors.Join(errs...)
+}
+
+// lookupHostVia resolves domain with the DNS server directly, bypassing the resolv.conf of the node
+func lookupHostVia(w io.Writer, server, domain string, timeout int) error {
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			d := net.Dialer{Timeout: time.Duration(timeout) * time.Second}
+			return d.DialContext(ctx, network, net.JoinHostPort(server, "53"))
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	// the trailing dot stops the search domains of the node from being appended
+	ips, err := resolver.LookupHost(ctx, strings.TrimSuffix(domain, ".")+".")
+	if err != nil {
+		return fmt.Errorf("dns server %s failed to resolve %s: %v", server, domain, err)
+	}
+	fmt.Fprintf(w, "dns server %s resolves %s: %s\n", server, domain, strings.Join(ips, ","))
+	return nil
+}
+
+// resolvConfNameservers returns the nameservers in the resolv.conf file
+func resolvConfNameservers(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var nameservers []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			nameservers = append(nameservers, fields[1])
+		}
+	}
+	return nameservers, scanner.Err()
+}
+
+// podDNSTarget returns the resolvConf and the cluster DNS servers CheckPodDNS checks
+func podDNSTarget(kubelet *v1alpha2.TailoredKubeletConfiguration) string {
+	resolvConf := "<empty>"
+	if kubelet.ResolverConfig != nil && *kubelet.ResolverConfig != "" {
+		resolvConf = *kubelet.ResolverConfig
+	}
+	clusterDNS := "<not set>"
+	if len(kubelet.ClusterDNS) > 0 {
+		clusterDNS = strings.Join(kubelet.ClusterDNS, ",")
+	}
+	return fmt.Sprintf("resolvConf %s, clusterDNS %s", resolvConf, clusterDNS)
+}
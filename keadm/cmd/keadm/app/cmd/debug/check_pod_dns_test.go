@@ -0,0 +1,133 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+func TestCheckPodDNS(t *testing.T) {
+	dir := t.TempDir()
+	resolvConf := filepath.Join(dir, "resolv.conf")
+	stubResolvConf := filepath.Join(dir, "stub-resolv.conf")
+	require.NoError(t, os.WriteFile(resolvConf, []byte("# upstream\nnameserver 10.0.0.2\nsearch example.com\n"), 0600))
+	require.NoError(t, os.WriteFile(stubResolvConf, []byte("nameserver 127.0.0.53\noptions edns0\n"), 0600))
+
+	var lookups []string
+	patches := gomonkey.ApplyFunc(lookupHostVia, func(w io.Writer, server, domain string, _timeout int) error {
+		lookups = append(lookups, server+" "+domain)
+		if server == "10.96.0.10" {
+			return fmt.Errorf("dns server %s failed to resolve %s: i/o timeout", server, domain)
+		}
+		fmt.Fprintf(w, "dns server %s resolves %s: 1.2.3.4\n", server, domain)
+		return nil
+	})
+	defer patches.Reset()
+
+	cases := []struct {
+		name            string
+		resolvConf      *string
+		clusterDNS      []string
+		domain          string
+		expectedLookups []string
+		expectedErr     string
+		expectedOut     string
+	}{
+		{
+			name:            "resolvConf without clusterDNS",
+			resolvConf:      &resolvConf,
+			expectedLookups: []string{"10.0.0.2 " + defaultPodDNSDomain},
+			expectedOut:     fmt.Sprintf("resolvConf: %s, nameservers: 10.0.0.2", resolvConf),
+		},
+		{
+			name:            "resolvConf and clusterDNS with the specified domain",
+			resolvConf:      &resolvConf,
+			clusterDNS:      []string{"169.254.96.16"},
+			domain:          "example.com",
+			expectedLookups: []string{"10.0.0.2 example.com", "169.254.96.16 kubernetes.default.svc.cluster.local"},
+			expectedOut:     "clusterDNS: 169.254.96.16",
+		},
+		{
+			name:            "clusterDNS does not resolve",
+			resolvConf:      &resolvConf,
+			clusterDNS:      []string{"10.96.0.10"},
+			expectedLookups: []string{"10.0.0.2 " + defaultPodDNSDomain, "10.96.0.10 kubernetes.default.svc.cluster.local"},
+			expectedErr:     "dns server 10.96.0.10 failed to resolve kubernetes.default.svc.cluster.local",
+		},
+		{
+			name:        "loopback nameserver",
+			resolvConf:  &stubResolvConf,
+			expectedErr: "nameserver 127.0.0.53 of resolvConf " + stubResolvConf + " is a loopback address",
+		},
+		{
+			name:        "resolvConf is not exists",
+			resolvConf:  ptrString(filepath.Join(dir, "not-exists")),
+			expectedErr: "the pods fail to start",
+		},
+		{
+			name:        "resolvConf is empty",
+			resolvConf:  ptrString(""),
+			expectedOut: "resolvConf is empty, the pods do not inherit the DNS of the node",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			lookups = nil
+			kubelet := &v1alpha2.TailoredKubeletConfiguration{ResolverConfig: c.resolvConf, ClusterDNS: c.clusterDNS}
+			buf := &bytes.Buffer{}
+			err := CheckPodDNS(buf, kubelet, c.domain, 1)
+			if c.expectedErr != "" {
+				require.ErrorContains(t, err, c.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, c.expectedLookups, lookups)
+			assert.Contains(t, buf.String(), c.expectedOut)
+		})
+	}
+}
+
+func ptrString(s string) *string {
+	return &s
+}
+
+func TestResolvConfNameservers(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "resolv.conf")
+	require.NoError(t, os.WriteFile(file, []byte("nameserver 10.0.0.2\n#nameserver 10.0.0.3\nnameserver  10.0.0.4 \nnameserver\n"), 0600))
+	nameservers, err := resolvConfNameservers(file)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.2", "10.0.0.4"}, nameservers)
+}
+
+func TestPodDNSTarget(t *testing.T) {
+	assert.Equal(t, "resolvConf <empty>, clusterDNS <not set>", podDNSTarget(&v1alpha2.TailoredKubeletConfiguration{}))
+	assert.Equal(t, "resolvConf /etc/resolv.conf, clusterDNS 10.96.0.10,10.96.0.11", podDNSTarget(&v1alpha2.TailoredKubeletConfiguration{
+		ResolverConfig: ptrString("/etc/resolv.conf"), ClusterDNS: []string{"10.96.0.10", "10.96.0.11"},
+	}))
+}
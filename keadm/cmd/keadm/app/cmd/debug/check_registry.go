@@ -0,0 +1,285 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+)
+
+const (
+	// defaultRegistry is the registry of the images without a registry host, e.g. nginx:latest
+	defaultRegistry = "docker.io"
+	// defaultRegistryEndpoint is the host serving the registry api of defaultRegistry
+	defaultRegistryEndpoint = "registry-1.docker.io"
+)
+
+// authParamPattern matches the key="value" params of the WWW-Authenticate challenge
+var authParamPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// registryCredential is the credential of a registry in a pull secret
+type registryCredential struct {
+	// secret is the namespace/name of the pull secret
+	secret   string
+	username string
+	password string
+}
+
+// CheckRegistry checks the image registries are reachable over the /v2/ api anonymously, which is what an image pull
+// starts with, and validates the auth handshake of the pull secrets of the registries which require authentication.
+// A registry is either a host like registry.example.com:5000, which is reached over https, or an http:// url.
+func CheckRegistry(w io.Writer, registries []string, credentials map[string][]registryCredential, opts HTTPCheckOptions) error {
+	if len(registries) == 0 {
+		fmt.Fprintf(w, "no registries are specified or referenced by the cached pods, skip registry check\n")
+		return nil
+	}
+	var errs []error
+	for _, registry := range registries {
+		if err := checkRegistry(w, registry, credentials[normalizeRegistry(registry)], opts); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkRegistry checks the /v2/ api of the registry, and the auth handshake of the credentials if the registry requires it
+func checkRegistry(w io.Writer, registry string, credentials []registryCredential, opts HTTPCheckOptions) error {
+	endpoint := registryEndpoint(registry)
+	client, err := opts.newHTTPClient()
+	if err != nil {
+		return err
+	}
+	response, err := client.Get(endpoint)
+	if err != nil {
+		return networkError(fmt.Errorf("registry %s%s is not reachable,%v, the images of it can not be pulled",
+			registry, opts.viaProxy(endpoint), connectError(err, opts.timeout())))
+	}
+	response.Body.Close()
+
+	var challenge string
+	switch response.StatusCode {
+	case http.StatusOK:
+		fmt.Fprintf(w, "registry %s is reachable, %s answered with status %s, anonymous pulls are allowed\n", registry, endpoint, response.Status)
+		return nil
+	case http.StatusUnauthorized:
+		challenge = response.Header.Get("WWW-Authenticate")
+		fmt.Fprintf(w, "registry %s is reachable, %s answered with status %s, it requires authentication\n", registry, endpoint, response.Status)
+	default:
+		return fmt.Errorf("registry %s answered %s with status %s, which is not the docker registry v2 api", registry, endpoint, response.Status)
+	}
+
+	if len(credentials) == 0 {
+		fmt.Fprintf(w, "no pull secrets of registry %s are cached, skip the auth check\n", registry)
+		return nil
+	}
+	var errs []error
+	for _, credential := range credentials {
+		if err := checkRegistryAuth(client, endpoint, challenge, credential, opts.timeout()); err != nil {
+			errs = append(errs, fmt.Errorf("pull secret %s of registry %s is rejected, %v", credential.secret, registry, err))
+			continue
+		}
+		fmt.Fprintf(w, "pull secret %s is authenticated by registry %s\n", credential.secret, registry)
+	}
+	return errors.Join(errs...)
+}
+
+// checkRegistryAuth runs the auth handshake of the challenge with the credential the same as docker login,
+// i.e. a token is requested from the realm of a Bearer challenge, or the api is requested again with a Basic challenge.
+func checkRegistryAuth(client *http.Client, endpoint, challenge string, credential registryCredential, timeout int) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	target := endpoint
+	switch {
+	case strings.EqualFold(scheme, "Bearer"):
+		values := make(map[string]string)
+		for _, match := range authParamPattern.FindAllStringSubmatch(params, -1) {
+			values[strings.ToLower(match[1])] = match[2]
+		}
+		if values["realm"] == "" {
+			return fmt.Errorf("the challenge %q has no realm", challenge)
+		}
+		u, err := url.Parse(values["realm"])
+		if err != nil {
+			return fmt.Errorf("invalid realm %s: %v", values["realm"], err)
+		}
+		if service := values["service"]; service != "" {
+			query := u.Query()
+			query.Set("service", service)
+			u.RawQuery = query.Encode()
+		}
+		target = u.String()
+	case strings.EqualFold(scheme, "Basic"):
+	default:
+		return fmt.Errorf("unsupported auth challenge %q", challenge)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(credential.username, credential.password)
+	response, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request %s failed,%v", target, connectError(err, timeout))
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s answered with status %s", target, response.Status)
+	}
+	return nil
+}
+
+// registryEndpoint returns the url of the /v2/ api of the registry
+func registryEndpoint(registry string) string {
+	if strings.HasPrefix(registry, "http://") || strings.HasPrefix(registry, "https://") {
+		return strings.TrimSuffix(registry, "/") + "/v2/"
+	}
+	if registry == defaultRegistry {
+		registry = defaultRegistryEndpoint
+	}
+	return "https://" + registry + "/v2/"
+}
+
+// imageRegistry returns the registry host of the image, which is defaultRegistry for the images without a registry host
+func imageRegistry(image string) string {
+	host, _, ok := strings.Cut(image, "/")
+	if !ok || (!strings.ContainsAny(host, ".:") && host != "localhost") {
+		return defaultRegistry
+	}
+	return normalizeRegistry(host)
+}
+
+// normalizeRegistry returns the registry host of the key of a docker config, e.g. https://index.docker.io/v1/ is docker.io
+func normalizeRegistry(key string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(key, "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+	switch host {
+	case "index.docker.io", defaultRegistryEndpoint:
+		return defaultRegistry
+	}
+	return host
+}
+
+// cachedPodRegistries returns the registries of the images of the pods cached in the edge database,
+// and the credentials of the registries in the cached pull secrets of the pods.
+func cachedPodRegistries() ([]string, map[string][]registryCredential, error) {
+	pods, err := QueryPodsFromDatabase(metav1.NamespaceAll)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seen := make(map[string]bool)
+	var registries []string
+	credentials := make(map[string][]registryCredential)
+	secrets := make(map[string]bool)
+	for _, pod := range pods {
+		containers := append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+		for _, container := range containers {
+			if registry := imageRegistry(container.Image); !seen[registry] {
+				seen[registry] = true
+				registries = append(registries, registry)
+			}
+		}
+		for _, ref := range pod.Spec.ImagePullSecrets {
+			key := pod.Namespace + "/" + ref.Name
+			if secrets[key] {
+				continue
+			}
+			secrets[key] = true
+			creds, err := cachedPullSecret(pod.Namespace, ref.Name)
+			if err != nil {
+				return nil, nil, err
+			}
+			for registry, credential := range creds {
+				credentials[registry] = append(credentials[registry], credential)
+			}
+		}
+	}
+	sort.Strings(registries)
+	return registries, credentials, nil
+}
+
+// cachedPullSecret returns the credentials of the registries in the pull secret cached in the edge database,
+// it returns nil if the secret is not cached.
+func cachedPullSecret(namespace, name string) (map[string]registryCredential, error) {
+	key := fmt.Sprintf("%s/%s/%s", namespace, model.ResourceTypeSecret, name)
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+	result, err := queryMeta(ctx, "key", key)
+	if err != nil {
+		return nil, fmt.Errorf("read database fail: %s", err.Error())
+	}
+	if len(*result) == 0 {
+		return nil, nil
+	}
+	secret := &v1.Secret{}
+	if err := json.Unmarshal([]byte((*result)[0]), secret); err != nil {
+		return nil, fmt.Errorf("unmarshal %s failed: %v", key, err)
+	}
+
+	// the .dockercfg of the legacy secrets is the auths of .dockerconfigjson
+	auths := make(map[string]dockerConfigAuth)
+	if data, ok := secret.Data[v1.DockerConfigJsonKey]; ok {
+		config := struct {
+			Auths map[string]dockerConfigAuth `json:"auths"`
+		}{}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("unmarshal %s of %s failed: %v", v1.DockerConfigJsonKey, key, err)
+		}
+		auths = config.Auths
+	} else if data, ok := secret.Data[v1.DockerConfigKey]; ok {
+		if err := json.Unmarshal(data, &auths); err != nil {
+			return nil, fmt.Errorf("unmarshal %s of %s failed: %v", v1.DockerConfigKey, key, err)
+		}
+	}
+
+	credentials := make(map[string]registryCredential, len(auths))
+	for registry, auth := range auths {
+		username, password := auth.Username, auth.Password
+		if auth.Auth != "" {
+			if decoded, err := base64.StdEncoding.DecodeString(auth.Auth); err == nil {
+				username, password, _ = strings.Cut(string(decoded), ":")
+			}
+		}
+		credentials[normalizeRegistry(registry)] = registryCredential{
+			secret:   namespace + "/" + name,
+			username: username,
+			password: password,
+		}
+	}
+	return credentials, nil
+}
+
+// dockerConfigAuth is the credential of a registry in a docker config
+type dockerConfigAuth struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
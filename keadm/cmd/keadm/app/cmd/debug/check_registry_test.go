@@ -0,0 +1,195 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+)
+
+func TestCheckRegistry(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "robot" || password != "secret" || r.URL.Query().Get("service") != "registry.test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"token":"abc"}`)
+	}))
+	defer tokenServer.Close()
+	bearer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry.test"`, tokenServer.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer bearer.Close()
+	basic := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, _, ok := r.BasicAuth(); ok && username == "robot" {
+			return
+		}
+		w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer basic.Close()
+	anonymous := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/", r.URL.Path)
+	}))
+	defer anonymous.Close()
+	notRegistry := httptest.NewServer(http.NotFoundHandler())
+	defer notRegistry.Close()
+
+	opts := HTTPCheckOptions{Timeout: 1}
+	valid := registryCredential{secret: "default/regcred", username: "robot", password: "secret"}
+	invalid := registryCredential{secret: "default/stale", username: "robot", password: "expired"}
+
+	t.Run("anonymous", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckRegistry(buf, []string{anonymous.URL}, nil, opts))
+		assert.Contains(t, buf.String(), "answered with status 200 OK, anonymous pulls are allowed")
+	})
+
+	t.Run("bearer auth", func(t *testing.T) {
+		registry := normalizeRegistry(bearer.URL)
+		buf := &bytes.Buffer{}
+		err := CheckRegistry(buf, []string{bearer.URL}, map[string][]registryCredential{registry: {valid, invalid}}, opts)
+		require.EqualError(t, err, fmt.Sprintf("pull secret default/stale of registry %s is rejected, %s/token?service=registry.test answered with status 401 Unauthorized",
+			bearer.URL, tokenServer.URL))
+		assert.Contains(t, buf.String(), "it requires authentication")
+		assert.Contains(t, buf.String(), "pull secret default/regcred is authenticated by registry "+bearer.URL)
+	})
+
+	t.Run("basic auth", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := CheckRegistry(buf, []string{basic.URL}, map[string][]registryCredential{normalizeRegistry(basic.URL): {valid}}, opts)
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "pull secret default/regcred is authenticated")
+	})
+
+	t.Run("no pull secrets", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckRegistry(buf, []string{basic.URL}, nil, opts))
+		assert.Contains(t, buf.String(), "no pull secrets of registry "+basic.URL+" are cached, skip the auth check")
+	})
+
+	t.Run("not a registry", func(t *testing.T) {
+		err := CheckRegistry(&bytes.Buffer{}, []string{notRegistry.URL}, nil, opts)
+		require.ErrorContains(t, err, "with status 404 Not Found, which is not the docker registry v2 api")
+	})
+
+	t.Run("not reachable", func(t *testing.T) {
+		err := CheckRegistry(&bytes.Buffer{}, []string{"http://127.0.0.1:1"}, nil, opts)
+		require.ErrorContains(t, err, "registry http://127.0.0.1:1 is not reachable, connect fail")
+		var exitErr *DiagnoseExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, DiagnoseExitCodeNetwork, exitErr.ExitCode())
+	})
+
+	t.Run("no registries", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckRegistry(buf, nil, nil, opts))
+		assert.Contains(t, buf.String(), "skip registry check")
+	})
+}
+
+func TestImageRegistry(t *testing.T) {
+	cases := map[string]string{
+		"nginx":                                  defaultRegistry,
+		"library/nginx:1.25":                     defaultRegistry,
+		"docker.io/library/nginx":                defaultRegistry,
+		"registry.example.com/app/web:v1":        "registry.example.com",
+		"10.0.0.1:5000/web@sha256:0123456789abc": "10.0.0.1:5000",
+		"localhost/web":                          "localhost",
+	}
+	for image, expected := range cases {
+		assert.Equal(t, expected, imageRegistry(image), image)
+	}
+
+	assert.Equal(t, "https://registry-1.docker.io/v2/", registryEndpoint(defaultRegistry))
+	assert.Equal(t, "https://registry.example.com/v2/", registryEndpoint("registry.example.com"))
+	assert.Equal(t, "http://10.0.0.1:5000/v2/", registryEndpoint("http://10.0.0.1:5000/"))
+	assert.Equal(t, defaultRegistry, normalizeRegistry("https://index.docker.io/v1/"))
+}
+
+func TestCachedPodRegistries(t *testing.T) {
+	dockerConfig, err := json.Marshal(map[string]interface{}{
+		"auths": map[string]interface{}{
+			"registry.example.com":        map[string]string{"auth": base64.StdEncoding.EncodeToString([]byte("robot:secret"))},
+			"https://index.docker.io/v1/": map[string]string{"username": "hub", "password": "token"},
+		},
+	})
+	require.NoError(t, err)
+	secret, err := json.Marshal(v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "regcred", Namespace: "default"},
+		Type:       v1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{v1.DockerConfigJsonKey: dockerConfig},
+	})
+	require.NoError(t, err)
+
+	patches := gomonkey.NewPatches()
+	defer patches.Reset()
+	patches.ApplyFunc(QueryPodsFromDatabase, func(_namespace string) ([]v1.Pod, error) {
+		pod := func(name string, images ...string) v1.Pod {
+			p := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+			for _, image := range images {
+				p.Spec.Containers = append(p.Spec.Containers, v1.Container{Image: image})
+			}
+			p.Spec.ImagePullSecrets = []v1.LocalObjectReference{{Name: "regcred"}, {Name: "missing"}}
+			return p
+		}
+		web := pod("web", "registry.example.com/app/web:v1", "nginx")
+		web.Spec.InitContainers = []v1.Container{{Image: "10.0.0.1:5000/init"}}
+		return []v1.Pod{web, pod("web-2", "registry.example.com/app/web:v1")}, nil
+	})
+	queried := 0
+	patches.ApplyFunc(dao.QueryMetaWithContext, func(_ctx context.Context, _key, condition string) (*[]string, error) {
+		queried++
+		if condition == "default/secret/regcred" {
+			return &[]string{string(secret)}, nil
+		}
+		return &[]string{}, nil
+	})
+
+	registries, credentials, err := cachedPodRegistries()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1:5000", defaultRegistry, "registry.example.com"}, registries)
+	assert.Equal(t, map[string][]registryCredential{
+		"registry.example.com": {{secret: "default/regcred", username: "robot", password: "secret"}},
+		defaultRegistry:        {{secret: "default/regcred", username: "hub", password: "token"}},
+	}, credentials)
+	// the pull secrets shared by the pods are queried once
+	assert.Equal(t, 2, queried)
+
+	patches.ApplyFunc(QueryPodsFromDatabase, func(_namespace string) ([]v1.Pod, error) {
+		return nil, errors.New("read database fail")
+	})
+	_, _, err = cachedPodRegistries()
+	assert.EqualError(t, err, "read database fail")
+}
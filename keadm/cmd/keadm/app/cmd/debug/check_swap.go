@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+// procSwaps is the swap devices of the host
+const procSwaps = "/proc/swaps"
+
+// swapDevice is a line of /proc/swaps, the sizes are in kB
+type swapDevice struct {
+	name string
+	size uint64
+	used uint64
+}
+
+// CheckSwap checks whether swap is active on the host while edged fails to start on it by failSwapOn.
+// The swap usage is printed either way, edged tolerates swap unless failSwapOn is set, where the active swap is
+// reported as a warning since edged refuses to start on the node.
+func CheckSwap(w io.Writer, failSwapOn bool) error {
+	devices, err := readSwaps(procSwaps)
+	if os.IsNotExist(err) {
+		fmt.Fprintf(w, "%s is not exists, the swap is not checked\n", procSwaps)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(devices) == 0 {
+		fmt.Fprintf(w, "swap is off\n")
+		return nil
+	}
+
+	var size, used uint64
+	for _, device := range devices {
+		size += device.size
+		used += device.used
+		fmt.Fprintf(w, "swap %s: size %.2f MB, used %.2f MB\n", device.name,
+			float64(device.size)*1024/common.MB, float64(device.used)*1024/common.MB)
+	}
+	if failSwapOn {
+		return warningError(fmt.Errorf("swap of %.2f MB is on with %.2f MB used, edged fails to start by failSwapOn, "+
+			"turn swap off by swapoff -a or set modules.edged.tailoredKubeletConfig.failSwapOn to false",
+			float64(size)*1024/common.MB, float64(used)*1024/common.MB))
+	}
+	fmt.Fprintf(w, "swap of %.2f MB is on with %.2f MB used, edged tolerates it as failSwapOn is false\n",
+		float64(size)*1024/common.MB, float64(used)*1024/common.MB)
+	return nil
+}
+
+// edgedFailSwapOn returns failSwapOn of edged in the edgecore config, the default is used if the config is nil
+func edgedFailSwapOn(edgeconfig *v1alpha2.EdgeCoreConfig) bool {
+	if edgeconfig == nil {
+		edgeconfig = v1alpha2.NewDefaultEdgeCoreConfig()
+	}
+	if edged := edgeconfig.Modules.Edged; edged != nil && edged.TailoredKubeletConfig != nil &&
+		edged.TailoredKubeletConfig.FailSwapOn != nil {
+		return *edged.TailoredKubeletConfig.FailSwapOn
+	}
+	return false
+}
+
+// readSwaps reads the swap devices in the file of the /proc/swaps format, the header line is skipped
+func readSwaps(file string) ([]swapDevice, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var devices []swapDevice
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[0] == "Filename" {
+			continue
+		}
+		size, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size of swap %s in %s: %v", fields[0], file, err)
+		}
+		used, err := strconv.ParseUint(fields[3], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid used of swap %s in %s: %v", fields[0], file, err)
+		}
+		devices = append(devices, swapDevice{name: fields[0], size: size, used: used})
+	}
+	return devices, scanner.Err()
+}
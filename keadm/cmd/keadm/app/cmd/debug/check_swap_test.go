@@ -0,0 +1,98 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/pointer"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+func TestCheckSwap(t *testing.T) {
+	swaps := []swapDevice{{name: "/dev/sda2", size: 2097152, used: 102400}, {name: "/swapfile", size: 1048576}}
+	patches := gomonkey.ApplyFunc(readSwaps, func(_file string) ([]swapDevice, error) {
+		return swaps, nil
+	})
+	defer patches.Reset()
+
+	t.Run("swap is tolerated", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckSwap(buf, false))
+		assert.Contains(t, buf.String(), "swap /dev/sda2: size 2048.00 MB, used 100.00 MB\n")
+		assert.Contains(t, buf.String(), "swap of 3072.00 MB is on with 100.00 MB used, edged tolerates it as failSwapOn is false\n")
+	})
+
+	t.Run("swap fails edged", func(t *testing.T) {
+		err := CheckSwap(&bytes.Buffer{}, true)
+		require.ErrorContains(t, err, "swap of 3072.00 MB is on with 100.00 MB used, edged fails to start by failSwapOn")
+		assert.True(t, isDiagnoseWarning(err))
+	})
+
+	t.Run("swap is off", func(t *testing.T) {
+		swaps = nil
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckSwap(buf, true))
+		assert.Equal(t, "swap is off\n", buf.String())
+	})
+
+	t.Run("swaps is not exists", func(t *testing.T) {
+		patches := gomonkey.ApplyFunc(readSwaps, func(_file string) ([]swapDevice, error) {
+			return nil, os.ErrNotExist
+		})
+		defer patches.Reset()
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckSwap(buf, true))
+		assert.Contains(t, buf.String(), "the swap is not checked")
+	})
+
+	t.Run("invalid swaps", func(t *testing.T) {
+		patches := gomonkey.ApplyFunc(readSwaps, func(_file string) ([]swapDevice, error) {
+			return nil, errors.New("invalid size of swap")
+		})
+		defer patches.Reset()
+		require.ErrorContains(t, CheckSwap(&bytes.Buffer{}, true), "invalid size of swap")
+	})
+}
+
+func TestReadSwaps(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "swaps")
+	require.NoError(t, os.WriteFile(file, []byte("Filename\t\t\t\tType\t\tSize\t\tUsed\t\tPriority\n"+
+		"/dev/sda2                               partition\t8388604\t\t1024\t\t-2\n"), 0600))
+	devices, err := readSwaps(file)
+	require.NoError(t, err)
+	assert.Equal(t, []swapDevice{{name: "/dev/sda2", size: 8388604, used: 1024}}, devices)
+
+	require.NoError(t, os.WriteFile(file, []byte("/dev/sda2 partition big 0 -2\n"), 0600))
+	_, err = readSwaps(file)
+	assert.ErrorContains(t, err, "invalid size of swap /dev/sda2")
+}
+
+func TestEdgedFailSwapOn(t *testing.T) {
+	assert.False(t, edgedFailSwapOn(nil))
+	cfg := v1alpha2.NewDefaultEdgeCoreConfig()
+	cfg.Modules.Edged.TailoredKubeletConfig.FailSwapOn = pointer.Bool(true)
+	assert.True(t, edgedFailSwapOn(cfg))
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// hostEtcDir is the dir of the localtime and timezone files of the host, which set the time zone edgecore logs in
+const hostEtcDir = "/etc"
+
+// utcTimeZones are the names of the time zones which are the same as UTC
+var utcTimeZones = map[string]bool{
+	"UTC": true, "Etc/UTC": true, "UCT": true, "Etc/UCT": true, "Universal": true, "Etc/Universal": true,
+	"Zulu": true, "Etc/Zulu": true, "GMT": true, "Etc/GMT": true, "GMT0": true, "Etc/GMT0": true,
+}
+
+// CheckTimeZone reports the time zone of the node set by the localtime and timezone files in etcDir, and warns if it
+// is not the expected one, e.g. UTC, since the timestamps of the logs of the nodes in different time zones are hard
+// to correlate with each other and with the cloud. The time zone is only reported if expected is empty.
+func CheckTimeZone(w io.Writer, etcDir, expected string) error {
+	name, loc, err := nodeTimeZone(etcDir)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	abbr, offset := now.In(loc).Zone()
+	display := name
+	if display == "" {
+		display = "unknown"
+	}
+	fmt.Fprintf(w, "time zone of the node: %s (%s, UTC%s)\n", display, abbr, formatUTCOffset(offset))
+	if expected == "" {
+		return nil
+	}
+
+	expectedLoc, err := time.LoadLocation(expected)
+	if err != nil {
+		return fmt.Errorf("invalid expected time zone %s: %v", expected, err)
+	}
+	_, expectedOffset := now.In(expectedLoc).Zone()
+	matched := canonicalTimeZone(name) == canonicalTimeZone(expected)
+	// the name of a localtime file copied from the zoneinfo is unknown, which is compared by the offset instead
+	if name == "" {
+		matched = offset == expectedOffset
+	}
+	if matched {
+		return nil
+	}
+	return warningError(fmt.Errorf("time zone of the node is %s (UTC%s), not the expected %s (UTC%s), the log timestamps of "+
+		"the node are off from the nodes in %s, set it with timedatectl set-timezone %s", display, formatUTCOffset(offset),
+		expected, formatUTCOffset(expectedOffset), expected, expected))
+}
+
+// nodeTimeZone returns the name and the location of the time zone of the localtime file in etcDir. The name is the
+// zoneinfo path the localtime file links to, or the content of the timezone file of the debian based distros,
+// and it is empty if neither is found. The node is in UTC if the localtime file is not exists, the same as glibc.
+func nodeTimeZone(etcDir string) (string, *time.Location, error) {
+	localtime := filepath.Join(etcDir, "localtime")
+	data, err := os.ReadFile(localtime)
+	if os.IsNotExist(err) {
+		return "UTC", time.UTC, nil
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("read %s failed: %v", localtime, err)
+	}
+
+	var name string
+	if target, err := os.Readlink(localtime); err == nil {
+		if i := strings.LastIndex(target, "zoneinfo/"); i >= 0 {
+			name = target[i+len("zoneinfo/"):]
+		}
+	}
+	if name == "" {
+		if data, err := os.ReadFile(filepath.Join(etcDir, "timezone")); err == nil {
+			name = strings.TrimSpace(string(data))
+		}
+	}
+	loc, err := time.LoadLocationFromTZData(name, data)
+	if err != nil {
+		return "", nil, fmt.Errorf("parse %s failed: %v", localtime, err)
+	}
+	return name, loc, nil
+}
+
+// canonicalTimeZone returns UTC for the names of utcTimeZones and the name otherwise
+func canonicalTimeZone(name string) string {
+	if utcTimeZones[name] {
+		return "UTC"
+	}
+	return name
+}
+
+// formatUTCOffset formats the offset in seconds east of UTC, e.g. +08:00
+func formatUTCOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	return fmt.Sprintf("%s%02d:%02d", sign, offset/3600, offset%3600/60)
+}
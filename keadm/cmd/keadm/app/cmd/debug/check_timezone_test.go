@@ -0,0 +1,98 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTimeZone(t *testing.T) {
+	shanghai, err := os.ReadFile("/usr/share/zoneinfo/Asia/Shanghai")
+	if err != nil {
+		t.Skipf("zoneinfo is not installed: %v", err)
+	}
+	// newEtcDir returns an etc dir whose localtime links to a zoneinfo file of Asia/Shanghai, or is a copy of it
+	newEtcDir := func(t *testing.T, link bool) string {
+		dir := t.TempDir()
+		zoneinfo := filepath.Join(dir, "zoneinfo", "Asia", "Shanghai")
+		require.NoError(t, os.MkdirAll(filepath.Dir(zoneinfo), 0700))
+		require.NoError(t, os.WriteFile(zoneinfo, shanghai, 0600))
+		etcDir := filepath.Join(dir, "etc")
+		require.NoError(t, os.Mkdir(etcDir, 0700))
+		if link {
+			require.NoError(t, os.Symlink(zoneinfo, filepath.Join(etcDir, "localtime")))
+		} else {
+			require.NoError(t, os.WriteFile(filepath.Join(etcDir, "localtime"), shanghai, 0600))
+		}
+		return etcDir
+	}
+
+	t.Run("localtime is not exists", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckTimeZone(buf, t.TempDir(), ""))
+		assert.Equal(t, "time zone of the node: UTC (UTC, UTC+00:00)\n", buf.String())
+		require.NoError(t, CheckTimeZone(&bytes.Buffer{}, t.TempDir(), "Etc/UTC"))
+	})
+
+	t.Run("expected time zone", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckTimeZone(buf, newEtcDir(t, true), "Asia/Shanghai"))
+		assert.Equal(t, "time zone of the node: Asia/Shanghai (CST, UTC+08:00)\n", buf.String())
+	})
+
+	t.Run("unexpected time zone", func(t *testing.T) {
+		err := CheckTimeZone(&bytes.Buffer{}, newEtcDir(t, true), "UTC")
+		require.Error(t, err)
+		assert.True(t, isDiagnoseWarning(err))
+		assert.Contains(t, err.Error(), "time zone of the node is Asia/Shanghai (UTC+08:00), not the expected UTC (UTC+00:00)")
+	})
+
+	t.Run("name of the timezone file", func(t *testing.T) {
+		etcDir := newEtcDir(t, false)
+		require.NoError(t, os.WriteFile(filepath.Join(etcDir, "timezone"), []byte("Asia/Shanghai\n"), 0600))
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckTimeZone(buf, etcDir, "Asia/Shanghai"))
+		assert.Equal(t, "time zone of the node: Asia/Shanghai (CST, UTC+08:00)\n", buf.String())
+	})
+
+	t.Run("unknown name is compared by the offset", func(t *testing.T) {
+		etcDir := newEtcDir(t, false)
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckTimeZone(buf, etcDir, "Asia/Shanghai"))
+		assert.Equal(t, "time zone of the node: unknown (CST, UTC+08:00)\n", buf.String())
+		err := CheckTimeZone(&bytes.Buffer{}, etcDir, "UTC")
+		assert.True(t, isDiagnoseWarning(err))
+	})
+
+	t.Run("invalid expected time zone", func(t *testing.T) {
+		err := CheckTimeZone(&bytes.Buffer{}, t.TempDir(), "Mars/Olympus")
+		require.ErrorContains(t, err, "invalid expected time zone Mars/Olympus")
+		assert.False(t, isDiagnoseWarning(err))
+	})
+}
+
+func TestFormatUTCOffset(t *testing.T) {
+	assert.Equal(t, "+00:00", formatUTCOffset(0))
+	assert.Equal(t, "+05:30", formatUTCOffset(19800))
+	assert.Equal(t, "-04:30", formatUTCOffset(-16200))
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/util/files"
+)
+
+const (
+	// cloudcoreDeployment is the name of the deployment and the container of cloudcore installed by keadm
+	cloudcoreDeployment = "cloudcore"
+	// maxVersionMinorSkew is the max difference of the minor versions of edgecore and cloudcore
+	maxVersionMinorSkew = 1
+)
+
+// CheckVersionSkew reports the versions of edgecore and cloudcore, and warns if the minor versions differ by more than
+// the supported skew. The cloudcore version is inferred from the image of the cloudcore deployment through kubeConfig,
+// it is reported unknown if kubeConfig is not exists. A version skew is a warning, the check never fails.
+func CheckVersionSkew(w io.Writer, kubeConfig string) error {
+	edgeVersion, err := edgecoreVersion()
+	if err != nil {
+		fmt.Fprintf(w, "edgecore version: unknown, %v\n", err)
+	} else {
+		fmt.Fprintf(w, "edgecore version: %s\n", edgeVersion)
+	}
+
+	if !files.FileExists(kubeConfig) {
+		fmt.Fprintf(w, "cloudcore version: unknown, kubeconfig %s is not exists, skip the version skew check\n", kubeConfig)
+		return nil
+	}
+	cli, err := util.KubeClient(kubeConfig)
+	if err != nil {
+		fmt.Fprintf(w, "cloudcore version: unknown, failed to create KubeClient, error: %v\n", err)
+		return nil
+	}
+	cloudVersion, err := cloudcoreVersion(cli)
+	if err != nil {
+		fmt.Fprintf(w, "cloudcore version: unknown, %v\n", err)
+		return nil
+	}
+	fmt.Fprintf(w, "cloudcore version: %s\n", cloudVersion)
+
+	if edgeVersion != "" {
+		warnVersionSkew(w, edgeVersion, cloudVersion)
+	}
+	return nil
+}
+
+// edgecoreVersion returns the version of the edgecore binary, e.g. v1.20.0
+func edgecoreVersion() (string, error) {
+	out, err := util.ExecShellFilter(common.CmdGetEdgecoreVersion)
+	if err != nil {
+		return "", fmt.Errorf("get edgecore version failed: %v", err)
+	}
+	return strings.TrimPrefix(strings.TrimSpace(out), "KubeEdge "), nil
+}
+
+// cloudcoreVersion returns the tag of the cloudcore image of the cloudcore deployment
+func cloudcoreVersion(cli kubernetes.Interface) (string, error) {
+	deploy, err := cli.AppsV1().Deployments(constants.SystemNamespace).Get(context.TODO(), cloudcoreDeployment, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("get deployment %s/%s failed: %v", constants.SystemNamespace, cloudcoreDeployment, err)
+	}
+	for _, c := range deploy.Spec.Template.Spec.Containers {
+		if c.Name != cloudcoreDeployment {
+			continue
+		}
+		// the tag follows the last colon after the registry and the repository, a digest has no version
+		name := c.Image[strings.LastIndex(c.Image, "/")+1:]
+		if i := strings.LastIndex(name, ":"); i >= 0 && !strings.Contains(name, "@") {
+			return name[i+1:], nil
+		}
+		return "", fmt.Errorf("image %s of cloudcore has no version tag", c.Image)
+	}
+	return "", fmt.Errorf("container %s is not found in deployment %s/%s", cloudcoreDeployment,
+		constants.SystemNamespace, cloudcoreDeployment)
+}
+
+// warnVersionSkew warns if the minor versions of edgecore and cloudcore differ by more than maxVersionMinorSkew
+func warnVersionSkew(w io.Writer, edgeVersion, cloudVersion string) {
+	ev, err := versionutil.ParseGeneric(edgeVersion)
+	if err != nil {
+		fmt.Fprintf(w, "invalid edgecore version %s, skip the version skew check\n", edgeVersion)
+		return
+	}
+	cv, err := versionutil.ParseGeneric(cloudVersion)
+	if err != nil {
+		fmt.Fprintf(w, "invalid cloudcore version %s, skip the version skew check\n", cloudVersion)
+		return
+	}
+	skew := int(ev.Minor()) - int(cv.Minor())
+	if skew < 0 {
+		skew = -skew
+	}
+	if ev.Major() != cv.Major() || skew > maxVersionMinorSkew {
+		fmt.Fprintf(w, "WARNING: edgecore %s and cloudcore %s differ by more than %d minor version, which is not supported "+
+			"and may cause protocol problems, upgrade edgecore to the version of cloudcore\n", edgeVersion, cloudVersion, maxVersionMinorSkew)
+		return
+	}
+	fmt.Fprintf(w, "edgecore %s and cloudcore %s are within the supported version skew\n", edgeVersion, cloudVersion)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+)
+
+func newCloudcoreDeployment(image string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloudcore", Namespace: "kubeedge"},
+		Spec: appsv1.DeploymentSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "cloudcore", Image: image}},
+		}}},
+	}
+}
+
+func TestCheckVersionSkew(t *testing.T) {
+	patches := gomonkey.ApplyFunc(util.ExecShellFilter, func(c string) (string, error) {
+		assert.Equal(t, common.CmdGetEdgecoreVersion, c)
+		return "KubeEdge v1.20.0\n", nil
+	})
+	defer patches.Reset()
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, CheckVersionSkew(buf, filepath.Join(t.TempDir(), "config")))
+	assert.Contains(t, buf.String(), "edgecore version: v1.20.0\n")
+	assert.Contains(t, buf.String(), "cloudcore version: unknown, kubeconfig")
+}
+
+func TestEdgecoreVersionFailed(t *testing.T) {
+	patches := gomonkey.ApplyFunc(util.ExecShellFilter, func(_c string) (string, error) {
+		return "", errors.New("command not found")
+	})
+	defer patches.Reset()
+
+	_, err := edgecoreVersion()
+	assert.EqualError(t, err, "get edgecore version failed: command not found")
+}
+
+func TestCloudcoreVersion(t *testing.T) {
+	version, err := cloudcoreVersion(fake.NewSimpleClientset(newCloudcoreDeployment("registry:5000/kubeedge/cloudcore:v1.19.1")))
+	require.NoError(t, err)
+	assert.Equal(t, "v1.19.1", version)
+
+	_, err = cloudcoreVersion(fake.NewSimpleClientset(newCloudcoreDeployment("registry:5000/kubeedge/cloudcore")))
+	assert.ErrorContains(t, err, "has no version tag")
+
+	_, err = cloudcoreVersion(fake.NewSimpleClientset(newCloudcoreDeployment("kubeedge/cloudcore@sha256:abc")))
+	assert.ErrorContains(t, err, "has no version tag")
+
+	_, err = cloudcoreVersion(fake.NewSimpleClientset())
+	assert.ErrorContains(t, err, "get deployment kubeedge/cloudcore failed")
+}
+
+func TestWarnVersionSkew(t *testing.T) {
+	cases := []struct {
+		edge, cloud string
+		expected    string
+	}{
+		{edge: "v1.20.0", cloud: "v1.20.3", expected: "edgecore v1.20.0 and cloudcore v1.20.3 are within the supported version skew\n"},
+		{edge: "v1.19.0", cloud: "v1.20.0", expected: "edgecore v1.19.0 and cloudcore v1.20.0 are within the supported version skew\n"},
+		{edge: "v1.17.0", cloud: "v1.20.0", expected: "WARNING: edgecore v1.17.0 and cloudcore v1.20.0 differ by more than 1 minor version"},
+		{edge: "v1.20.0", cloud: "v2.20.0", expected: "WARNING: edgecore v1.20.0 and cloudcore v2.20.0 differ"},
+		{edge: "dev", cloud: "v1.20.0", expected: "invalid edgecore version dev, skip the version skew check\n"},
+		{edge: "v1.20.0", cloud: "latest", expected: "invalid cloudcore version latest, skip the version skew check\n"},
+	}
+	for _, c := range cases {
+		buf := &bytes.Buffer{}
+		warnVersionSkew(buf, c.edge, c.cloud)
+		assert.Contains(t, buf.String(), c.expected)
+	}
+}
@@ -0,0 +1,71 @@
+//go:build windows
+
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+)
+
+const (
+	// edgecoreUser is the user which edgecore is expected to run as, the nssm service runs as the LocalSystem account
+	edgecoreUser = `NT AUTHORITY\SYSTEM`
+	// executableSuffix is the suffix of the names of the executables, which the processes are named after
+	executableSuffix = ".exe"
+)
+
+// platformUnsupportedChecks are the checks which read the procfs, the sysfs, the cgroups, systemd or the journal of linux,
+// they are recorded as skipped on windows instead of failing
+var platformUnsupportedChecks = map[string]bool{
+	"kernel":        true,
+	"swap":          true,
+	"timesync":      true,
+	"timezone":      true,
+	"systemd":       true,
+	"edgecore-log":  true,
+	"file-limits":   true,
+	"conntrack":     true,
+	"cgroup-driver": true,
+	"eviction":      true,
+	"pod-dirs":      true,
+	"mtu":           true,
+}
+
+// systemDiskPath returns the root of the system drive, e.g. C:\
+func systemDiskPath() (string, error) {
+	drive := os.Getenv("SystemDrive")
+	if drive == "" {
+		drive = "C:"
+	}
+	return drive + `\`, nil
+}
+
+// CheckPid counts the running processes by tasklist, windows has no limit of the pids like kernel.pid_max of linux
+func CheckPid(w io.Writer) error {
+	out, err := util.ExecShellFilter(common.CmdGetProcessList)
+	if err != nil {
+		return fmt.Errorf("list the processes by tasklist failed: %v", err)
+	}
+	fmt.Fprintf(w, "Running processes: %d\n", tasklistProcessCount(out))
+	return nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+)
+
+// writeProbePattern is the pattern of the file written to probe whether a dir is writable
+const writeProbePattern = ".keadm-write-probe-*"
+
+// CheckWritableDirs checks the dirs edged and the edge database write to are writable by writing a small file
+// to each of them, the file is removed afterwards. The stat of a dir succeeds on a filesystem remounted read-only
+// after disk errors, only an actual write catches it. A dir which does not exist yet is skipped.
+func CheckWritableDirs(w io.Writer, dirs []string) error {
+	var errs []error
+	for _, dir := range dirs {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			fmt.Fprintf(w, "dir %s is not exists, skip\n", dir)
+			continue
+		}
+		if err := probeWrite(dir); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fmt.Fprintf(w, "dir %s is writable\n", dir)
+	}
+	return errors.Join(errs...)
+}
+
+// probeWrite writes a small file to dir and syncs it to the disk, then removes it
+func probeWrite(dir string) error {
+	f, err := os.CreateTemp(dir, writeProbePattern)
+	if err != nil {
+		return writeProbeError(dir, err)
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write([]byte("keadm write probe\n"))
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return writeProbeError(dir, err)
+	}
+	return nil
+}
+
+// writeProbeError returns why the write probe of dir failed, the read-only filesystem and the missing permission
+// of the user running the diagnose are told from the other errors, e.g. the disk is full
+func writeProbeError(dir string, err error) error {
+	switch {
+	case errors.Is(err, syscall.EROFS):
+		return fmt.Errorf("dir %s is on a read-only filesystem, it may be remounted read-only after disk errors, check dmesg: %v", dir, err)
+	case errors.Is(err, os.ErrPermission):
+		return newDiagnoseExitError(DiagnoseExitCodePermissionDenied,
+			fmt.Errorf("dir %s is not writable by the user running the diagnose, run it as root or the user edgecore runs as: %v", dir, err))
+	}
+	return fmt.Errorf("write to dir %s failed: %v", dir, err)
+}
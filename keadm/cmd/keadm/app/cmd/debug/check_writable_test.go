@@ -0,0 +1,65 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckWritableDirs(t *testing.T) {
+	t.Run("writable", func(t *testing.T) {
+		dir := t.TempDir()
+		missing := filepath.Join(dir, "edged")
+		buf := &bytes.Buffer{}
+		require.NoError(t, CheckWritableDirs(buf, []string{dir, missing}))
+		assert.Equal(t, "dir "+dir+" is writable\ndir "+missing+" is not exists, skip\n", buf.String())
+
+		// the probe file is removed
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("read-only filesystem", func(t *testing.T) {
+		patches := gomonkey.ApplyFunc(os.CreateTemp, func(dir, pattern string) (*os.File, error) {
+			return nil, &fs.PathError{Op: "open", Path: filepath.Join(dir, pattern), Err: syscall.EROFS}
+		})
+		defer patches.Reset()
+
+		dir := t.TempDir()
+		err := CheckWritableDirs(&bytes.Buffer{}, []string{dir})
+		require.ErrorContains(t, err, "dir "+dir+" is on a read-only filesystem")
+	})
+}
+
+func TestWriteProbeError(t *testing.T) {
+	err := writeProbeError("/var/lib/edged", &fs.PathError{Op: "open", Path: "/var/lib/edged", Err: syscall.EACCES})
+	assert.Equal(t, DiagnoseErrorCodePermissionDenied, diagnoseErrorCode(err))
+	assert.ErrorContains(t, err, "dir /var/lib/edged is not writable by the user running the diagnose")
+
+	err = writeProbeError("/var/lib/edged", &fs.PathError{Op: "write", Path: "/var/lib/edged", Err: syscall.ENOSPC})
+	assert.EqualError(t, err, "write to dir /var/lib/edged failed: write /var/lib/edged: no space left on device")
+}
@@ -0,0 +1,360 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/kubeedge/api/apis/common/constants"
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+)
+
+// Checker is a check of the install diagnose, a new check is added by registering it in installCheckers.
+// The details of the check are written to w, and the returned error fails the check.
+type Checker interface {
+	// Name is the name of the check in the results, which --only and --skip select the check by
+	Name() string
+	Run(ctx context.Context, opts *common.CheckOptions, w io.Writer) error
+}
+
+// conditionalChecker is a Checker which only runs if the options apply to it, e.g. a flag of it is set
+type conditionalChecker interface {
+	Checker
+	Applies(opts *common.CheckOptions) bool
+}
+
+// installCheckers are the checks of DiagnoseInstall in the order their results are recorded
+var installCheckers = []Checker{
+	cpuChecker{},
+	memoryChecker{},
+	diskChecker{},
+	diskPathsChecker{},
+	kernelChecker{},
+	swapChecker{},
+	dnsChecker{},
+	networkChecker{},
+	timeSyncChecker{},
+	timeZoneChecker{},
+	clockChecker{},
+	pidChecker{},
+	certChecker{},
+}
+
+// checkHints are the remediation hints of the failed checks printed in --explain mode, keyed by the check names
+// of the install checkers and the other diagnoses. A new check registers its hint here along with its definition.
+var checkHints = map[string]string{
+	common.ArgCheckCPU:    "the node has too few cores or the cpu is overloaded, stop the busy processes or lower the floor with --min-cpu",
+	common.ArgCheckMemory: "the node has too little free memory, stop the memory hungry processes or lower the floor with --min-memory",
+	common.ArgCheckDisk:   "the disk is almost full, remove the unused images with crictl rmi --prune and clean up the old logs",
+	"disk-paths":          "free the filesystems holding the edgecore database and logs, or move them to a larger disk",
+	"kernel":              "load the missing kernel modules with modprobe and set the sysctls in /etc/sysctl.d, then run sysctl --system",
+	"swap":                "disable swap with swapoff -a and remove the swap entries of /etc/fstab",
+	common.ArgCheckDNS:    "check the nameservers of /etc/resolv.conf, or pass a reachable dns server with --dns-ip",
+	common.ArgCheckNetwork: "check the firewall and the routes between the node and cloudcore allow the cloudhub ports, " +
+		"e.g. 10000 for the websocket and 10002 for the https server",
+	"timesync":         "enable a time sync daemon, e.g. systemctl enable --now chronyd",
+	"timezone":         "set the time zone of the node with timedatectl set-timezone and restart edgecore to log in it",
+	"clock":            "sync the clock of the node with ntp, the certificates of cloudcore are rejected if the clock skews",
+	common.ArgCheckPID: "too many processes are running, stop the leaking processes or raise kernel.pid_max",
+	common.ArgCheckCert: "the edge certificates are expired, remove them from /etc/kubeedge/certs and restart edgecore " +
+		"to request new ones from cloudcore, run keadm gettoken on the cloud side if the token expired as well",
+
+	"edgecore":        "start edgecore with systemctl start edgecore, and check journalctl -u edgecore if it exits",
+	"config":          "generate the edge config with keadm join or edgecore --defaultconfig, or pass it with --config",
+	"config-drift":    "restart edgecore with systemctl restart edgecore to apply the edge config",
+	"database":        "start edgecore once to create the database at the dataSource of the edge config",
+	"database-schema": "the database is created by another version of edgecore, upgrade edgecore or remove the database and restart it",
+	"systemd":         "edgecore keeps restarting, check journalctl -u edgecore for the error it exits with",
+	"edgecore-log":    "edgecore panicked or exited recently, check the logs around the error and report it with the diagnose report",
+	"file-limits":     "raise LimitNOFILE of edgecore.service and the fs.inotify.max_user_instances and fs.inotify.max_user_watches sysctls",
+	"node":            "the node is not Ready, check the conditions of the node with kubectl describe node on the cloud side",
+	"writable-dirs":   "the filesystem is remounted read-only after disk errors, check dmesg and the disk, then fsck it and remount it read-write",
+	"runtime":         "start the container runtime, e.g. systemctl start containerd, and check the remoteRuntimeEndpoint of the edge config",
+	"kubelet":         "kubelet conflicts with edged, stop and disable it with systemctl disable --now kubelet",
+	"cgroup-driver":   "set the cgroupDriver of edged to the cgroup driver of the container runtime and restart edgecore",
+	"cni":             "install the CNI plugins and a CNI config of the network plugin, or pass the dirs with --cni-conf-dir and --cni-bin-dir",
+	"dns-addon":       "check the logs of the DNS addon pod with kubectl logs on the cloud side, and the clusterDNS of edged is the address it listens on",
+	"edged":           "edged is not serving, check the logs of edgecore for the errors of edged",
+	"eviction":        "the node is under memory pressure and edged evicts the pods, free memory or lower the eviction thresholds",
+	"max-pods":        "the node runs maxPods pods, delete the unused pods or raise maxPods of edged and restart edgecore",
+	"host-paths":      "create the missing host paths or plug in the devices, or fix the hostPath volumes of the pods and recreate them",
+	"registry":        "check the node can reach the image registries, and configure the mirrors or the proxy of the container runtime",
+	"mqtt":            "start the mqtt broker, e.g. mosquitto, or fix the mqttServerExternal of eventbus in the edge config",
+	"servicebus":      "servicebus is not listening, check the port of servicebus is not used by another process and restart edgecore",
+	"cloudhub-dns":    "the node can not resolve the cloudhub server, use its ip in the edge config or fix the nameservers of the node",
+	"cloudhub": "check cloudcore is running with kubectl -n kubeedge get pod on the cloud side, " +
+		"and the firewall between the node and cloudcore allows the cloudhub port",
+	"cloudhub-quic": "check cloudcore is started with quic enabled and the firewall allows the udp port of quic",
+	"node-identity": "the node is reported by another host, give the node a unique name with hostnameOverride and rejoin it, " +
+		"check the node with kubectl get node -o wide on the cloud side if only the cached node is compared",
+	"edgestream": "enable cloudstream in cloudcore and check the tunnel port of it is reachable from the node",
+	"mtu":        "lower the MTU of the VPN or the tunnel interface to cloudcore consistently, or clamp the tcp mss on the gateway",
+	"version":    "upgrade edgecore with keadm upgrade edge to a version supported by cloudcore",
+	"certs":      "the certificates of cloudcore are expired, delete the secrets casecret and cloudcoresecret and restart cloudcore to recreate them",
+
+	"conntrack": "raise net.netfilter.nf_conntrack_max by sysctl and persist it in /etc/sysctl.d, or lower the connections of the node",
+
+	"token":          "get a new join token with keadm gettoken on the cloud side, and pass it as is with --token",
+	"token-ca":       "the token is issued by another cloudcore, get the token from the cloudcore of --cloudcore-ipport with keadm gettoken",
+	"token-accepted": "cloudcore rejects the token, e.g. its CA key is regenerated, get a new token with keadm gettoken on the cloud side",
+}
+
+// diagnoseErrorHints are the remediation hints of the error codes of the failed checks without a hint of their own
+var diagnoseErrorHints = map[string]string{
+	DiagnoseErrorCodeNetworkUnreachable: "check the firewall and the routes between the node and the endpoint of the check",
+	DiagnoseErrorCodeConfigNotFound:     "pass the path of the file with the flag of it, e.g. --config",
+	DiagnoseErrorCodeCorruptData:        "the cached data is corrupted, remove the database and restart edgecore to resync it from cloudcore",
+	DiagnoseErrorCodePermissionDenied:   "run the diagnose as root or as the user edgecore runs as",
+}
+
+// checkHint returns the remediation hint of the failed check with the error code, the permission errors are hinted
+// by their code since the hint of the check does not apply to them. It returns empty if the check has no hint.
+func checkHint(check, code string) string {
+	if code == DiagnoseErrorCodePermissionDenied {
+		return diagnoseErrorHints[code]
+	}
+	if hint, ok := checkHints[check]; ok {
+		return hint
+	}
+	return diagnoseErrorHints[code]
+}
+
+type cpuChecker struct{}
+
+func (cpuChecker) Name() string { return common.ArgCheckCPU }
+
+func (cpuChecker) Run(_ context.Context, opts *common.CheckOptions, w io.Writer) error {
+	return CheckCPU(w, opts.MinCPU)
+}
+
+type memoryChecker struct{}
+
+func (memoryChecker) Name() string { return common.ArgCheckMemory }
+
+func (memoryChecker) Run(_ context.Context, opts *common.CheckOptions, w io.Writer) error {
+	return CheckMemory(w, opts.MinMemory)
+}
+
+type diskChecker struct{}
+
+func (diskChecker) Name() string { return common.ArgCheckDisk }
+
+func (diskChecker) Run(_ context.Context, opts *common.CheckOptions, w io.Writer) error {
+	return CheckDisk(w, opts.MinDisk)
+}
+
+// diskPathsChecker checks the disks of the edgecore database and logs, the default paths are checked
+// if the edgecore config is not installed yet
+type diskPathsChecker struct{}
+
+func (diskPathsChecker) Name() string { return "disk-paths" }
+
+func (diskPathsChecker) Run(_ context.Context, opts *common.CheckOptions, w io.Writer) error {
+	var edgeconfig *v1alpha2.EdgeCoreConfig
+	if opts.Config != "" && util.EdgecoreConfigExists(opts.Config) {
+		edgeconfig, _ = util.ParseEdgecoreConfig(opts.Config)
+	}
+	return CheckPathDisk(w, edgecoreDiskPaths(edgeconfig), opts.MinDiskFree)
+}
+
+// kernelChecker checks the kernel modules and the sysctls the pod networks require
+type kernelChecker struct{}
+
+func (kernelChecker) Name() string { return "kernel" }
+
+func (kernelChecker) Run(_ context.Context, opts *common.CheckOptions, w io.Writer) error {
+	return CheckKernel(w, opts.KernelModules, opts.Sysctls)
+}
+
+// swapChecker checks the swap of the host against failSwapOn of edged, the default config is checked
+// if the edgecore config is not installed yet
+type swapChecker struct{}
+
+func (swapChecker) Name() string { return "swap" }
+
+func (swapChecker) Run(_ context.Context, opts *common.CheckOptions, w io.Writer) error {
+	var edgeconfig *v1alpha2.EdgeCoreConfig
+	if opts.Config != "" && util.EdgecoreConfigExists(opts.Config) {
+		edgeconfig, _ = util.ParseEdgecoreConfig(opts.Config)
+	}
+	return CheckSwap(w, edgedFailSwapOn(edgeconfig))
+}
+
+// dnsChecker resolves the domain of --domain, it only runs if the domain is specified
+type dnsChecker struct{}
+
+func (dnsChecker) Name() string { return common.ArgCheckDNS }
+
+func (dnsChecker) Applies(opts *common.CheckOptions) bool { return opts.Domain != "" }
+
+func (dnsChecker) Run(_ context.Context, opts *common.CheckOptions, w io.Writer) error {
+	return networkError(CheckDNSSpecify(w, opts.Domain, opts.DNSIP, opts.IPFamily))
+}
+
+type networkChecker struct{}
+
+func (networkChecker) Name() string { return common.ArgCheckNetwork }
+
+func (networkChecker) Run(_ context.Context, opts *common.CheckOptions, w io.Writer) error {
+	return networkError(CheckNetWork(w, opts.IP, NewHTTPCheckOptions(opts), opts.CloudHubServer,
+		opts.EdgecoreServer, opts.Config))
+}
+
+type timeSyncChecker struct{}
+
+func (timeSyncChecker) Name() string { return "timesync" }
+
+func (timeSyncChecker) Run(_ context.Context, _ *common.CheckOptions, w io.Writer) error {
+	return CheckTimeSync(w)
+}
+
+type timeZoneChecker struct{}
+
+func (timeZoneChecker) Name() string { return "timezone" }
+
+func (timeZoneChecker) Run(_ context.Context, opts *common.CheckOptions, w io.Writer) error {
+	return CheckTimeZone(w, hostEtcDir, opts.ExpectedTZ)
+}
+
+type clockChecker struct{}
+
+func (clockChecker) Name() string { return "clock" }
+
+func (clockChecker) Run(_ context.Context, opts *common.CheckOptions, w io.Writer) error {
+	server, chOpts := clockSkewCheckOptions(opts)
+	return CheckClockSkew(w, server, chOpts, opts.MaxClockSkew)
+}
+
+type pidChecker struct{}
+
+func (pidChecker) Name() string { return common.ArgCheckPID }
+
+func (pidChecker) Run(_ context.Context, _ *common.CheckOptions, w io.Writer) error {
+	return CheckPid(w)
+}
+
+// certChecker checks the certificates of the edgecore config, the default config is checked if it is not specified
+type certChecker struct{}
+
+func (certChecker) Name() string { return common.ArgCheckCert }
+
+func (certChecker) Run(_ context.Context, opts *common.CheckOptions, w io.Writer) error {
+	config := opts.Config
+	if config == "" {
+		config = constants.EdgecoreConfigPath
+	}
+	return CheckCertExpiry(w, config, opts.CertWarnDays)
+}
+
+// checkerNames returns the names of the checkers in order
+func checkerNames(checkers []Checker) []string {
+	names := make([]string, 0, len(checkers))
+	for _, c := range checkers {
+		names = append(names, c.Name())
+	}
+	return names
+}
+
+// checkerAliases are the other names of the checks accepted by --only and --skip
+var checkerAliases = map[string]string{
+	"memory": common.ArgCheckMemory,
+}
+
+// normalizeCheckNames trims the names of the checks and resolves the aliases of them
+func normalizeCheckNames(names []string) []string {
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if alias, ok := checkerAliases[name]; ok {
+			name = alias
+		}
+		normalized = append(normalized, name)
+	}
+	return normalized
+}
+
+// validateCheckSelection normalizes the names of --only and --skip, and validates them against the names of the checkers
+func validateCheckSelection(checkers []Checker, opts *common.CheckOptions) error {
+	if len(opts.Only) > 0 && len(opts.Skip) > 0 {
+		return errors.New("--only and --skip are mutually exclusive")
+	}
+	opts.Only, opts.Skip = normalizeCheckNames(opts.Only), normalizeCheckNames(opts.Skip)
+	names := checkerNames(checkers)
+	for _, name := range append(slices.Clone(opts.Only), opts.Skip...) {
+		if !slices.Contains(names, name) {
+			return fmt.Errorf("unknown check %s, the checks are: %s", name, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
+// selectCheckers returns the checkers selected by the names of only, or all the checkers except the names of skip,
+// the order of the checkers is kept. The checkers which the options do not apply to are not selected.
+func selectCheckers(checkers []Checker, opts *common.CheckOptions) ([]Checker, error) {
+	if err := validateCheckSelection(checkers, opts); err != nil {
+		return nil, err
+	}
+
+	var selected []Checker
+	for _, c := range checkers {
+		if checkerSkipReason(c, opts) == "" {
+			selected = append(selected, c)
+		}
+	}
+	return selected, nil
+}
+
+// checkerSkipReason returns why the checker is not selected by the options, or "" if it is selected
+func checkerSkipReason(c Checker, opts *common.CheckOptions) string {
+	switch {
+	case len(opts.Only) > 0 && !slices.Contains(opts.Only, c.Name()):
+		return "not selected by --only"
+	case slices.Contains(opts.Skip, c.Name()):
+		return "deselected by --skip"
+	}
+	if cc, ok := c.(conditionalChecker); ok && !cc.Applies(opts) {
+		return "the options of the check are not set"
+	}
+	return ""
+}
+
+// recordSkippedCheckers records the checkers which are not selected by the options as skipped with the reasons
+func recordSkippedCheckers(r *DiagnoseReport, checkers []Checker, opts *common.CheckOptions) {
+	for _, c := range checkers {
+		if reason := checkerSkipReason(c, opts); reason != "" {
+			r.Skipf(c.Name(), "%s", reason)
+		}
+	}
+}
+
+// runCheckers runs the checkers concurrently with at most limit checkers at a time, and records their results in order
+func runCheckers(ctx context.Context, r *DiagnoseReport, limit int, opts *common.CheckOptions, checkers []Checker) error {
+	checks := make([]ReportCheck, 0, len(checkers))
+	for _, c := range checkers {
+		checks = append(checks, ReportCheck{Name: c.Name(), Fn: func(w io.Writer) error {
+			return c.Run(ctx, opts, w)
+		}})
+	}
+	return r.RunAll(limit, checks)
+}
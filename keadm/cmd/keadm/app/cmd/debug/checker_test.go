@@ -0,0 +1,168 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+// fakeChecker is a Checker which returns err
+type fakeChecker struct {
+	name string
+	err  error
+}
+
+func (c fakeChecker) Name() string { return c.name }
+
+func (c fakeChecker) Run(_ context.Context, _ *common.CheckOptions, w io.Writer) error {
+	_, _ = io.WriteString(w, c.name+" is checked\n")
+	return c.err
+}
+
+func TestInstallCheckers(t *testing.T) {
+	assert.Equal(t, []string{common.ArgCheckCPU, common.ArgCheckMemory, common.ArgCheckDisk, "disk-paths", "kernel", "swap",
+		common.ArgCheckDNS, common.ArgCheckNetwork, "timesync", "timezone", "clock", common.ArgCheckPID, common.ArgCheckCert},
+		checkerNames(installCheckers))
+}
+
+func TestCheckHint(t *testing.T) {
+	for _, c := range installCheckers {
+		assert.NotEmptyf(t, checkHints[c.Name()], "install check %s has no hint", c.Name())
+	}
+	assert.Equal(t, checkHints["cloudhub"], checkHint("cloudhub", DiagnoseErrorCodeNetworkUnreachable))
+	assert.Equal(t, diagnoseErrorHints[DiagnoseErrorCodeNetworkUnreachable], checkHint("pod", DiagnoseErrorCodeNetworkUnreachable))
+	assert.Equal(t, diagnoseErrorHints[DiagnoseErrorCodePermissionDenied], checkHint("config", DiagnoseErrorCodePermissionDenied))
+	assert.Empty(t, checkHint("pod", DiagnoseErrorCodeCheckFailed))
+}
+
+func TestSelectCheckers(t *testing.T) {
+	cases := []struct {
+		name        string
+		opts        *common.CheckOptions
+		expected    []string
+		expectedErr string
+	}{
+		{
+			name:     "default checks",
+			opts:     &common.CheckOptions{},
+			expected: []string{"cpu", "mem", "disk", "disk-paths", "kernel", "swap", "network", "timesync", "timezone", "clock", "pid", "cert"},
+		},
+		{
+			name:     "dns check applies with the domain",
+			opts:     &common.CheckOptions{Domain: "example.com"},
+			expected: []string{"cpu", "mem", "disk", "disk-paths", "kernel", "swap", "dns", "network", "timesync", "timezone", "clock", "pid", "cert"},
+		},
+		{
+			name:     "only keeps the order of the checks",
+			opts:     &common.CheckOptions{Only: []string{"pid", "cpu"}},
+			expected: []string{"cpu", "pid"},
+		},
+		{
+			name:     "aliases and spaces",
+			opts:     &common.CheckOptions{Only: []string{"cpu", " memory", "disk"}},
+			expected: []string{"cpu", "mem", "disk"},
+		},
+		{
+			name:     "skip",
+			opts:     &common.CheckOptions{Skip: []string{"network", "clock"}},
+			expected: []string{"cpu", "mem", "disk", "disk-paths", "kernel", "swap", "timesync", "timezone", "pid", "cert"},
+		},
+		{
+			name:        "unknown check",
+			opts:        &common.CheckOptions{Only: []string{"gpu"}},
+			expectedErr: "unknown check gpu, the checks are: cpu, mem, disk, disk-paths, kernel, swap, dns, network, timesync, timezone, clock, pid, cert",
+		},
+		{
+			name:        "only and skip",
+			opts:        &common.CheckOptions{Only: []string{"cpu"}, Skip: []string{"pid"}},
+			expectedErr: "--only and --skip are mutually exclusive",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			checkers, err := selectCheckers(installCheckers, c.opts)
+			if c.expectedErr != "" {
+				require.EqualError(t, err, c.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, checkerNames(checkers))
+		})
+	}
+}
+
+func TestRecordSkippedCheckers(t *testing.T) {
+	t.Run("skip and the checks without options", func(t *testing.T) {
+		r := newTestReport()
+		recordSkippedCheckers(r, installCheckers, &common.CheckOptions{Skip: []string{"network"}})
+		assert.Equal(t, []common.DiagnoseResult{
+			{Check: "dns", Status: common.DiagnoseStatusSkip, Message: "the options of the check are not set"},
+			{Check: "network", Status: common.DiagnoseStatusSkip, Message: "deselected by --skip"},
+		}, r.Results)
+	})
+
+	t.Run("only", func(t *testing.T) {
+		r := newTestReport()
+		recordSkippedCheckers(r, installCheckers, &common.CheckOptions{Only: []string{"cpu"}, Domain: "example.com"})
+		require.Len(t, r.Results, len(installCheckers)-1)
+		assert.Equal(t, common.DiagnoseResult{Check: "mem", Status: common.DiagnoseStatusSkip, Message: "not selected by --only"}, r.Results[0])
+	})
+}
+
+func TestRunCheckers(t *testing.T) {
+	r := newTestReport()
+	err := runCheckers(context.Background(), r, 2, &common.CheckOptions{}, []Checker{
+		fakeChecker{name: "first"},
+		fakeChecker{name: "second", err: errors.New("second failed")},
+		fakeChecker{name: "third"},
+	})
+	require.EqualError(t, err, "second failed")
+
+	require.Len(t, r.Results, 3)
+	assert.Equal(t, common.DiagnoseResult{Check: "first", Status: common.DiagnoseStatusPass, Detail: "first is checked"}, r.Results[0])
+	assert.Equal(t, common.DiagnoseStatusFail, r.Results[1].Status)
+	assert.Equal(t, "third", r.Results[2].Check)
+}
+
+// resolvingChecker resolves localhost with the default resolver, as the network and clock checks do
+type resolvingChecker struct{}
+
+func (resolvingChecker) Name() string { return "resolving" }
+
+func (resolvingChecker) Run(ctx context.Context, _ *common.CheckOptions, _ io.Writer) error {
+	_, err := net.DefaultResolver.LookupHost(ctx, "localhost")
+	return err
+}
+
+func TestRunCheckersWithDNSIP(t *testing.T) {
+	resolver := net.DefaultResolver
+	opts := &common.CheckOptions{Domain: "example.invalid", DNSIP: "127.0.0.1"}
+	checkers := []Checker{dnsChecker{}, resolvingChecker{}, dnsChecker{}, resolvingChecker{}}
+
+	_ = runCheckers(context.Background(), newTestReport(), len(checkers), opts, checkers)
+	assert.Same(t, resolver, net.DefaultResolver)
+}
@@ -1,15 +1,33 @@
 package debug
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/kubeedge/api/apis/common/constants"
 	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2/validation"
+	"github.com/kubeedge/beehive/pkg/core/model"
 	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
@@ -28,165 +46,1603 @@ keadm debug diagnose node
 # Diagnose whether the pod is normal
 keadm debug diagnose pod nginx-xxx -n test
 
+# Diagnose whether the pods are normal, the node is diagnosed once for all the pods
+keadm debug diagnose pod nginx-xxx redis-xxx -n test
+
+# Diagnose whether the pod is normal and print the last 50 lines of the logs of its containers which are not ready
+keadm debug diagnose pod nginx-xxx -n test --logs --tail 50
+
+# Diagnose the pod in an edgecore database copied from the node
+keadm debug diagnose pod nginx-xxx -n test --db-path ./edgecore.db
+
+# Diagnose whether the pods matching the label selector are normal
+keadm debug diagnose pod -l app=nginx -n test
+
+# Diagnose whether the pods of the deployment are normal
+keadm debug diagnose deployment nginx -n test
+
+# Diagnose whether the pods of the daemonset are normal and whether the node has its pod
+keadm debug diagnose workload daemonset kube-proxy -n kube-system
+
+# Diagnose whether the node is normal and print one line of check=status per check
+keadm debug diagnose node -o go-template --template '{{range .results}}{{.check}}={{.status}}{{"\n"}}{{end}}'
+
+# Diagnose the edge nodes and their pods of the cluster from the cloud side
+keadm debug diagnose cluster --kube-config $HOME/.kube/config
+
+# Diagnose the join token of a node which fails to join, and whether the cloudcore still accepts it
+keadm debug diagnose join --token <token> --cloudcore-ipport 10.0.0.1:10000
+
+# Diagnose whether the twins of the device are synced
+keadm debug diagnose device sensor-1
+
 # Diagnose node installation conditions
 keadm debug diagnose install
 
-# Diagnose node installation conditions and specify the detected ip
-keadm debug diagnose install -i 192.168.1.2
-`
-)
+# Diagnose node installation conditions and specify the detected ip
+keadm debug diagnose install -i 192.168.1.2
+
+# Diagnose node installation conditions except the network and dns checks, e.g. on an air-gapped node
+keadm debug diagnose install --skip network,dns
+
+# Run only the resource checks of the node installation conditions
+keadm debug diagnose install --only cpu,memory,disk
+
+# Check that the kernel modules are loaded and the sysctls have the values
+keadm debug diagnose install --only kernel --kernel-modules br_netfilter,ip_vs --sysctls net.ipv4.ip_forward=1
+
+# Diagnose the eventbus and edgehub modules of edgecore
+keadm debug diagnose module eventbus edgehub
+
+# Diagnose install, node and all the pods, and continue past failed diagnoses
+keadm debug diagnose all
+
+# Diagnose install, node and the pods of the prod namespace
+keadm debug diagnose all -n prod
+
+# Diagnose whether the pods matching the label selector in all the namespaces are normal
+keadm debug diagnose pod -l app=nginx -A
+
+# Print the checks of the node diagnose and their targets without running them
+keadm debug diagnose node --dry-run
+
+# Print the network probes of the install diagnose without running them
+keadm debug diagnose install --dry-run --cloud-hub-server 10.0.0.1:10000
+
+# Diagnose the node and fail if edgecore panicked within the last 24 hours
+keadm debug diagnose node --log-window 24h
+
+# Diagnose the node and check the private registry of the images is reachable
+keadm debug diagnose node --registry registry.example.com:5000
+
+# Diagnose edgecore running in the container edgecore instead of a host process
+keadm debug diagnose node --container edgecore
+
+# Watch the node diagnose every 10 seconds until interrupted
+keadm debug diagnose node --watch --interval 10s
+
+# Stream a json line per check result of the node and pods diagnose as soon as each check finishes
+keadm debug diagnose all -o jsonl
+
+# Print the effective edgecore config with the defaults applied
+keadm debug diagnose config
+
+# Print the values of the edgecore config which come from the defaults
+keadm debug diagnose config --diff
+
+# Diagnose the node with the edgecore config piped from stdin, e.g. in a sidecar container
+cat edgecore.yaml | keadm debug diagnose node -c -
+
+# List the available diagnose targets in json format
+keadm debug diagnose list -o json
+
+# Diagnose node installation conditions with the thresholds of ~/.keadm/diagnose.yaml, e.g.
+#   timeout: 5
+#   min-disk-free: 2048
+#   cert-warn-days: 14
+keadm debug diagnose install
+
+# Diagnose the node and print a remediation hint for each failed check
+keadm debug diagnose node --explain
+
+# Diagnose the node in json format with the healthScore weighting the cloudhub check higher and ignoring swap
+keadm debug diagnose node -o json --score-weights cloudhub=10,swap=0
+
+# Diagnose whether the node is normal and print the results in json format
+keadm debug diagnose node -o json
+
+# Diagnose everything and write a support bundle in json format
+keadm debug diagnose all -o json --report /tmp/diagnose-report.json
+
+# Show the checks which changed status after a fix, e.g. cloudhub went from fail to pass
+keadm debug diagnose node -o json --report /tmp/before.json
+keadm debug diagnose node -o json --report /tmp/after.json
+keadm debug diagnose diff /tmp/before.json /tmp/after.json
+
+# Diagnose the node and write the results for the textfile collector of node-exporter
+keadm debug diagnose node --metrics-file /var/lib/node-exporter/kubeedge.prom
+`
+)
+
+const (
+	// DiagnoseExitCodeGeneric is the exit code of a failed diagnose in general
+	DiagnoseExitCodeGeneric = 1
+	// DiagnoseExitCodeNetwork is the exit code of a diagnose failed by network problems
+	DiagnoseExitCodeNetwork = 2
+	// DiagnoseExitCodeConfigNotFound is the exit code of a diagnose failed by a missing edgecore config
+	DiagnoseExitCodeConfigNotFound = 3
+	// DiagnoseExitCodePermissionDenied is the exit code of a diagnose failed by the files the user running it can not read
+	DiagnoseExitCodePermissionDenied = 4
+)
+
+// The codes of the diagnose errors, which the wrappers of keadm can branch on
+const (
+	// DiagnoseErrorCodeCheckFailed is the code of a failed check in general
+	DiagnoseErrorCodeCheckFailed = "CHECK_FAILED"
+	// DiagnoseErrorCodeNetworkUnreachable is the code of a check failed by network problems
+	DiagnoseErrorCodeNetworkUnreachable = "NETWORK_UNREACHABLE"
+	// DiagnoseErrorCodeConfigNotFound is the code of a check failed by a missing edgecore config
+	DiagnoseErrorCodeConfigNotFound = "CONFIG_NOT_FOUND"
+	// DiagnoseErrorCodeCorruptData is the code of a check failed by the corrupt data cached in the edge database
+	DiagnoseErrorCodeCorruptData = "CORRUPT_DATA"
+	// DiagnoseErrorCodePermissionDenied is the code of a check failed by a file the user running the diagnose can not read
+	DiagnoseErrorCodePermissionDenied = "PERMISSION_DENIED"
+)
+
+// maxConcurrentInstallChecks is the max number of the checks of DiagnoseInstall running at a time
+const maxConcurrentInstallChecks = 4
+
+type Diagnose common.DiagnoseObject
+
+// DiagnoseExitError is a failed diagnose error carrying the exit code of its failure class
+type DiagnoseExitError struct {
+	Code int
+	Err  error
+}
+
+func newDiagnoseExitError(code int, err error) *DiagnoseExitError {
+	return &DiagnoseExitError{Code: code, Err: err}
+}
+
+func (e *DiagnoseExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DiagnoseExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the process exit code of the error
+func (e *DiagnoseExitError) ExitCode() int {
+	return e.Code
+}
+
+// networkError returns err as a diagnose error failed by network problems, it returns nil if err is nil
+func networkError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return newDiagnoseExitError(DiagnoseExitCodeNetwork, err)
+}
+
+// DiagnoseWarning is a non-fatal finding of a check, which is recorded with the warn status
+type DiagnoseWarning struct {
+	err error
+}
+
+// warningError returns err as a warning of the check, it returns nil if err is nil
+func warningError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &DiagnoseWarning{err: err}
+}
+
+func (w *DiagnoseWarning) Error() string {
+	return w.err.Error()
+}
+
+func (w *DiagnoseWarning) Unwrap() error {
+	return w.err
+}
+
+// isDiagnoseWarning returns whether err is a warning, the joined errors are a warning only if all of them are warnings
+func isDiagnoseWarning(err error) bool {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs := joined.Unwrap()
+		for _, e := range errs {
+			if !isDiagnoseWarning(e) {
+				return false
+			}
+		}
+		return len(errs) > 0
+	}
+	_, ok := err.(*DiagnoseWarning)
+	return ok
+}
+
+// DiagnoseSkipped is returned by a check which does not apply to the node
+type DiagnoseSkipped struct {
+	reason string
+}
+
+// skippedError returns the formatted reason of a check being skipped
+func skippedError(format string, a ...interface{}) error {
+	return &DiagnoseSkipped{reason: fmt.Sprintf(format, a...)}
+}
+
+func (s *DiagnoseSkipped) Error() string {
+	return s.reason
+}
+
+// isDiagnoseSkipped returns whether err is the reason of a check being skipped
+func isDiagnoseSkipped(err error) bool {
+	_, ok := err.(*DiagnoseSkipped)
+	return ok
+}
+
+// DiagnoseError is the error of a failed check carrying the code of its failure class
+type DiagnoseError struct {
+	Code    string `json:"code"`
+	Check   string `json:"check"`
+	Message string `json:"message"`
+
+	err error
+}
+
+// newDiagnoseError wraps err of the check as a *DiagnoseError with the code derived from its exit code
+func newDiagnoseError(check string, err error) error {
+	var diagnoseErr *DiagnoseError
+	if err == nil || errors.As(err, &diagnoseErr) {
+		return err
+	}
+	return &DiagnoseError{Code: diagnoseErrorCode(err), Check: check, Message: err.Error(), err: err}
+}
+
+func (e *DiagnoseError) Error() string {
+	return e.Message
+}
+
+func (e *DiagnoseError) Unwrap() error {
+	return e.err
+}
+
+// CorruptPodDataError is the error of a pod or a pod status cached in the edge database which can not be unmarshalled
+type CorruptPodDataError struct {
+	Key string
+	Err error
+}
+
+func (e *CorruptPodDataError) Error() string {
+	return fmt.Sprintf("cached pod data is corrupt for %s: %v", e.Key, e.Err)
+}
+
+func (e *CorruptPodDataError) Unwrap() error {
+	return e.Err
+}
+
+// unmarshalCachedPod unmarshals the pod data of key cached in the edge database into v
+func unmarshalCachedPod(key, data string, v interface{}) error {
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		return &CorruptPodDataError{Key: key, Err: err}
+	}
+	return nil
+}
+
+// diagnoseErrorCode returns the code of the diagnose error err
+func diagnoseErrorCode(err error) string {
+	var diagnoseErr *DiagnoseError
+	if errors.As(err, &diagnoseErr) {
+		return diagnoseErr.Code
+	}
+	var corruptErr *CorruptPodDataError
+	if errors.As(err, &corruptErr) {
+		return DiagnoseErrorCodeCorruptData
+	}
+	var exitErr *DiagnoseExitError
+	if errors.As(err, &exitErr) {
+		switch exitErr.ExitCode() {
+		case DiagnoseExitCodeNetwork:
+			return DiagnoseErrorCodeNetworkUnreachable
+		case DiagnoseExitCodeConfigNotFound:
+			return DiagnoseErrorCodeConfigNotFound
+		case DiagnoseExitCodePermissionDenied:
+			return DiagnoseErrorCodePermissionDenied
+		}
+	}
+	return DiagnoseErrorCodeCheckFailed
+}
+
+// NewDiagnose returns KubeEdge edge debug Diagnose command.
+func NewDiagnose() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "diagnose",
+		Short:   edgeDiagnoseShortDescription,
+		Long:    edgeDiagnoseLongDescription,
+		Example: edgeDiagnoseExample,
+	}
+	do := NewDiagnoseOptions()
+	cmd.PersistentFlags().StringVarP(&do.Output, common.FlagNameOutput, "o", do.Output,
+		fmt.Sprintf("Indicate the output format. Currently supports formats such as %s, the json lines format streams a line per check result",
+			strings.Join(diagnoseReportOutputs, "|")))
+	cmd.PersistentFlags().StringVar(&do.Template, "template", do.Template,
+		`the go template executed on the json fields of the report in -o go-template, e.g. '{{range .results}}{{.check}}={{.status}}{{"\n"}}{{end}}'`)
+	cmd.PersistentFlags().BoolVar(&do.Explain, "explain", do.Explain,
+		"print a remediation hint for each failed check, the hints are recorded in the results of the json and yaml output as well")
+	cmd.PersistentFlags().StringVar(&do.ReportFile, "report", do.ReportFile,
+		"Write the diagnose report to the file in the output format in addition to stdout, e.g. to attach it to an issue")
+	cmd.PersistentFlags().StringVar(&do.MetricsFile, "metrics-file", do.MetricsFile,
+		"Write the results to the file in the prometheus text format for the textfile collector of node-exporter (e.g. /var/lib/node-exporter/kubeedge.prom)")
+	cmd.PersistentFlags().StringToIntVar(&do.ScoreWeights, "score-weights", do.ScoreWeights,
+		fmt.Sprintf("override the weights of the checks in the healthScore of the report, e.g. cloudhub=10,swap=0, the checks of weight 0 "+
+			"are not scored, the critical checks are weighted %d and the others %d by default", criticalScoreWeight, defaultScoreWeight))
+	defaultsFile := diagnoseDefaultsFile()
+	cmd.PersistentFlags().StringVar(&defaultsFile, "defaults-file", defaultsFile,
+		"the yaml file of the default flag values of the diagnose commands keyed by the flag names (e.g. timeout: 5), the flags on the command line win over the file")
+	cmd.PersistentPreRunE = func(c *cobra.Command, _ []string) error {
+		return applyDiagnoseDefaults(c, defaultsFile, c.Flags().Changed("defaults-file"))
+	}
+	for _, v := range common.DiagnoseObjectMap {
+		cmd.AddCommand(NewSubDiagnose(Diagnose(v), do))
+	}
+	cmd.AddCommand(NewDiagnoseList(do))
+	cmd.AddCommand(NewDiagnoseConfig(do))
+	cmd.AddCommand(NewDiagnoseDiff(do))
+	return cmd
+}
+
+// NewSubDiagnose returns KubeEdge edge diagnose subcommand, do is shared with the parent command.
+func NewSubDiagnose(object Diagnose, do *common.DiagnoseOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Short: object.Desc,
+		Use:   object.Use,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return object.ExecuteDiagnose(object.Use, do, args)
+		},
+		// the diagnose failure is already printed by ExecuteDiagnose
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	switch object.Use {
+	case common.ArgDiagnoseNode:
+		addConfigFlag(cmd, do)
+		cmd.Flags().StringVar(&do.Host, "host", do.Host,
+			"diagnose the remote node over ssh instead of the local node (e.g. user@edge1), the ssh config and keys of the user are used")
+		cmd.Flags().StringVar(&do.Container, "container", do.Container,
+			"diagnose edgecore running in the container of the name instead of a host process (e.g. edgecore), the container is found by the container runtime")
+		cmd.Flags().StringVar(&do.RuntimeEndpoint, "remote-runtime-endpoint", do.RuntimeEndpoint,
+			fmt.Sprintf("the container runtime endpoint the container of --container is found by, default is %s", constants.DefaultRemoteRuntimeEndpoint))
+		cmd.Flags().StringVar(&do.KubeConfig, common.FlagNameKubeConfig, do.KubeConfig,
+			"Use this key to set kube-config path of the apiserver to read the cloudcore version and the node of the identity check, eg: $HOME/.kube/config")
+		cmd.Flags().StringVarP(&do.CheckOptions.Domain, "domain", "d", do.CheckOptions.Domain,
+			fmt.Sprintf("the domain the nameservers of the resolvConf of edged resolve in the pod dns check, default is %s", defaultPodDNSDomain))
+		cmd.Flags().BoolVar(&do.MQTTConnect, "mqtt-connect", do.MQTTConnect,
+			"send a CONNECT packet to the mqtt brokers of eventbus besides the tcp connection check")
+		cmd.Flags().BoolVarP(&do.Watch, "watch", "w", do.Watch,
+			"re-run the diagnose every interval until interrupted, the failures do not stop the watch")
+		cmd.Flags().DurationVar(&do.WatchInterval, "interval", do.WatchInterval, "the interval of the diagnose in watch mode")
+		cmd.Flags().StringVar(&do.CheckOptions.EdgecoreLogFile, "log-file", do.CheckOptions.EdgecoreLogFile,
+			"the log file of edgecore checked for the recent panics and fatal errors, the journal of edgecore.service is checked if it is not exists")
+		cmd.Flags().DurationVar(&do.CheckOptions.LogWindow, "log-window", do.CheckOptions.LogWindow,
+			"fail the edgecore log check if edgecore logged a panic or a fatal error within the duration")
+		cmd.Flags().StringSliceVar(&do.CheckOptions.Registries, "registry", do.CheckOptions.Registries,
+			"the comma-separated image registries checked for reachability (e.g. registry.example.com:5000 or http://10.0.0.1:5000), "+
+				"the registries of the images of the cached pods are checked if not specified")
+		addNetworkFlags(cmd, do)
+		addCloudHubFlags(cmd, do)
+		addClockFlags(cmd, do)
+		addDiskFreeFlag(cmd, do)
+		addCNIFlags(cmd, do)
+		addDryRunFlag(cmd, do)
+	case common.ArgDiagnoseModule:
+		addConfigFlag(cmd, do)
+		addDryRunFlag(cmd, do)
+	case common.ArgDiagnoseAll:
+		addConfigFlag(cmd, do)
+		cmd.Flags().BoolVar(&do.FailFast, "fail-fast", do.FailFast, "stop the diagnose at the first failure")
+		cmd.Flags().StringVarP(&do.Namespace, "namespace", "n", do.Namespace,
+			"diagnose only the pods of the namespace, the pods of all the namespaces are diagnosed if it is not specified")
+		cmd.Flags().BoolVarP(&do.AllNamespaces, "all-namespaces", "A", do.AllNamespaces,
+			"diagnose the pods of all the namespaces in the edge database, which is the default")
+		// the pods of all the namespaces are diagnosed unless a namespace is specified
+		cmd.PreRun = func(cmd *cobra.Command, _ []string) {
+			if !cmd.Flags().Changed("namespace") {
+				do.AllNamespaces = true
+			}
+		}
+		addNetworkFlags(cmd, do)
+		addCloudHubFlags(cmd, do)
+		addClockFlags(cmd, do)
+		addDiskFreeFlag(cmd, do)
+		addCNIFlags(cmd, do)
+		addInstallCheckFlags(cmd, do)
+		addDryRunFlag(cmd, do)
+	case common.ArgDiagnoseDeployment, common.ArgDiagnoseWorkload:
+		addConfigFlag(cmd, do)
+		cmd.Flags().StringVarP(&do.Namespace, "namespace", "n", do.Namespace, "specify namespace")
+		addSinceFlag(cmd, do)
+		addDryRunFlag(cmd, do)
+	case common.ArgDiagnoseDevice:
+		addConfigFlag(cmd, do)
+		addDryRunFlag(cmd, do)
+	case common.ArgDiagnoseCluster:
+		cmd.Flags().StringVar(&do.KubeConfig, common.FlagNameKubeConfig, do.KubeConfig,
+			"Use this key to set kube-config path of the apiserver the edge nodes are read from, eg: $HOME/.kube/config")
+		cmd.Flags().IntVar(&do.CheckOptions.CertWarnDays, "cert-warn-days", do.CheckOptions.CertWarnDays,
+			"warn in the certificate check if a certificate of cloudcore expires within the specified days")
+	case common.ArgDiagnoseJoin:
+		cmd.Flags().StringVarP(&do.Token, common.FlagNameToken, "t", do.Token,
+			"the join token of the node to diagnose, which keadm gettoken prints on the cloud side")
+		cmd.Flags().StringVarP(&do.CloudCoreIPPort, common.FlagNameCloudCoreIPPort, "e", do.CloudCoreIPPort,
+			"IP:Port address of KubeEdge CloudCore the node joins, the token is checked with the cloudcore if it is set")
+		cmd.Flags().StringVarP(&do.CertPort, common.FlagNameCertPort, "s", do.CertPort,
+			fmt.Sprintf("The port where to apply for the edge certificate, default is %s", DefaultJoinCertPort))
+	case common.ArgDiagnosePod:
+		addConfigFlag(cmd, do)
+		cmd.Flags().StringVarP(&do.Namespace, "namespace", "n", do.Namespace, "specify namespace")
+		cmd.Flags().StringVarP(&do.LabelSelector, common.FlagNameLabelSelector, "l", do.LabelSelector,
+			"Selector (label query) to filter the pods to diagnose, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
+		cmd.Flags().StringVar(&do.UID, "uid", do.UID,
+			"diagnose the cached pod of the uid instead of a pod name, e.g. the uid of the exact pod instance in an event or a log line")
+		cmd.Flags().BoolVarP(&do.AllNamespaces, "all-namespaces", "A", do.AllNamespaces,
+			"diagnose the pods matching the label selector or the pod of --uid in all the namespaces in the edge database")
+		cmd.Flags().BoolVar(&do.Live, "live", do.Live,
+			"compare the pods in the edge database with the pods in the apiserver, and report the divergences")
+		cmd.Flags().StringVar(&do.KubeConfig, common.FlagNameKubeConfig, do.KubeConfig,
+			"Use this key to set kube-config path of the apiserver for --live, eg: $HOME/.kube/config")
+		cmd.Flags().StringVar(&do.DBPath, "db-path", do.DBPath,
+			"diagnose the pods in the edgecore database directly, e.g. a database copied from the node, the node diagnose and the config are skipped")
+		cmd.Flags().BoolVar(&do.Logs, "logs", do.Logs,
+			"print the last lines of the logs of the containers which are not ready, which are read from the log files of the containers on the node")
+		cmd.Flags().IntVar(&do.TailLines, "tail", do.TailLines, "the number of the last lines of the container logs printed by --logs")
+		addSinceFlag(cmd, do)
+		addDryRunFlag(cmd, do)
+	case common.ArgDiagnoseInstall:
+		addConfigFlag(cmd, do)
+		cmd.Flags().StringVarP(&do.CheckOptions.DNSIP, "dns-ip", "D", do.CheckOptions.DNSIP, "specify test dns server ip")
+		cmd.Flags().StringVarP(&do.CheckOptions.Domain, "domain", "d", do.CheckOptions.Domain, "specify test domain")
+		cmd.Flags().StringVarP(&do.CheckOptions.IP, "ip", "i", do.CheckOptions.IP, "specify test ip")
+		cmd.Flags().StringVarP(&do.CheckOptions.CloudHubServer, "cloud-hub-server", "s", do.CheckOptions.CloudHubServer, "specify cloudhub server")
+		cmd.Flags().IntVar(&do.CheckOptions.CertWarnDays, "cert-warn-days", do.CheckOptions.CertWarnDays,
+			"warn in the certificate check if a certificate expires within the specified days")
+		addNetworkFlags(cmd, do)
+		addClockFlags(cmd, do)
+		addDiskFreeFlag(cmd, do)
+		addInstallCheckFlags(cmd, do)
+		addDryRunFlag(cmd, do)
+	}
+	cmd.Flags().IntVar(&do.CheckOptions.Timeout, "timeout", do.CheckOptions.Timeout,
+		"specify the timeout in seconds of the network checks")
+	cmd.Flags().BoolVarP(&do.Verbose, "verbose", "v", do.Verbose,
+		"print the extra details of the checks, e.g. the parsed config values and each attempt of the network connectivity checks")
+	cmd.Flags().BoolVarP(&do.Quiet, "quiet", "q", do.Quiet,
+		"print only the final result of the diagnose, the exit code tells whether it fails")
+	cmd.Flags().BoolVar(&do.CheckOptions.InsecureSkipTLSVerify, "insecure-skip-tls-verify", do.CheckOptions.InsecureSkipTLSVerify,
+		"skip the verification of the server certificates in the network checks, which is insecure and only for testing")
+	cmd.Flags().StringVar(&do.CheckOptions.Proxy, "proxy", do.CheckOptions.Proxy,
+		"the proxy of the http checks, which overrides the HTTP_PROXY and HTTPS_PROXY env vars (e.g. http://proxy:3128)")
+	cmd.Flags().StringVar(&do.CheckOptions.IPFamily, "ip-family", do.CheckOptions.IPFamily, ipFamilyUsage)
+	cmd.Flags().BoolVar(&do.CheckOptions.NoFollowRedirects, "no-follow-redirects", do.CheckOptions.NoFollowRedirects, noFollowRedirectsUsage)
+	cmd.Flags().BoolVar(&do.WarningsAsErrors, "warnings-as-errors", do.WarningsAsErrors,
+		"fail the diagnose if any check warns, the warnings do not fail the diagnose by default")
+	return cmd
+}
+
+// addConfigFlag adds the edgecore config flag
+func addConfigFlag(cmd *cobra.Command, do *common.DiagnoseOptions) {
+	cmd.Flags().StringVarP(&do.Config, common.EdgecoreConfig, "c", do.Config,
+		fmt.Sprintf("Specify configuration file, default is %s", constants.EdgecoreConfigPath))
+}
+
+// addDryRunFlag adds the dry run flag
+func addDryRunFlag(cmd *cobra.Command, do *common.DiagnoseOptions) {
+	cmd.Flags().BoolVar(&do.DryRun, "dry-run", do.DryRun,
+		"print the ordered checks and their targets derived from the edgecore config without running them")
+}
+
+// addSinceFlag adds the container restart window flag
+func addSinceFlag(cmd *cobra.Command, do *common.DiagnoseOptions) {
+	cmd.Flags().DurationVar(&do.Since, "since", do.Since,
+		"Only report the container restarts within the duration as current problems, the older ones are reported as stabilized (e.g. 1h)")
+}
+
+// addNetworkFlags adds the flags of the network connectivity checks
+func addNetworkFlags(cmd *cobra.Command, do *common.DiagnoseOptions) {
+	cmd.Flags().IntVar(&do.CheckOptions.Retries, "retries", do.CheckOptions.Retries,
+		"the number of attempts of the network connectivity checks, with exponential backoff between attempts")
+}
+
+// addCloudHubFlags adds the flags of the cloudhub latency and mtu checks
+func addCloudHubFlags(cmd *cobra.Command, do *common.DiagnoseOptions) {
+	cmd.Flags().DurationVar(&do.CheckOptions.MaxLatency, "max-latency", do.CheckOptions.MaxLatency,
+		"fail the cloudhub latency check if the tls handshake time or the first byte latency exceeds the duration, 0 disables it")
+	cmd.Flags().IntVar(&do.CheckOptions.MinMTU, "min-mtu", do.CheckOptions.MinMTU,
+		"warn in the mtu check if the path MTU to the cloudhub server is smaller")
+}
+
+// addClockFlags adds the flags of the clock and time zone checks
+func addClockFlags(cmd *cobra.Command, do *common.DiagnoseOptions) {
+	cmd.Flags().DurationVar(&do.CheckOptions.MaxClockSkew, "max-skew", do.CheckOptions.MaxClockSkew,
+		"fail the clock skew check if the clock of the node differs from cloudcore by more than the duration")
+	cmd.Flags().StringVar(&do.CheckOptions.ExpectedTZ, "expected-tz", do.CheckOptions.ExpectedTZ,
+		"warn in the time zone check if the node is not in the time zone (e.g. UTC or Asia/Shanghai), the time zone is only reported if not specified")
+}
+
+// addDiskFreeFlag adds the free disk space flag
+func addDiskFreeFlag(cmd *cobra.Command, do *common.DiagnoseOptions) {
+	cmd.Flags().IntVar(&do.CheckOptions.MinDiskFree, "min-disk-free", do.CheckOptions.MinDiskFree,
+		"fail the disk check if the filesystem holding the edgecore database or logs has less free space in MB")
+}
+
+// addCNIFlags adds the flags of the CNI checks
+func addCNIFlags(cmd *cobra.Command, do *common.DiagnoseOptions) {
+	cmd.Flags().StringVar(&do.CheckOptions.CNIConfDir, "cni-conf-dir", do.CheckOptions.CNIConfDir,
+		"the CNI config dir of the container runtime")
+	cmd.Flags().StringVar(&do.CheckOptions.CNIBinDir, "cni-bin-dir", do.CheckOptions.CNIBinDir,
+		"the comma-separated CNI plugin dirs of the container runtime")
+}
+
+// addInstallCheckFlags adds the flags of the install checks
+func addInstallCheckFlags(cmd *cobra.Command, do *common.DiagnoseOptions) {
+	cmd.Flags().IntVar(&do.CheckOptions.MinCPU, "min-cpu", do.CheckOptions.MinCPU,
+		"fail the cpu check if the node has less logical cores")
+	cmd.Flags().IntVar(&do.CheckOptions.MinMemory, "min-memory", do.CheckOptions.MinMemory,
+		"fail the memory check if the node has less memory in MB")
+	cmd.Flags().IntVar(&do.CheckOptions.MinDisk, "min-disk", do.CheckOptions.MinDisk,
+		"fail the disk check if the first disk partition of the node is smaller in MB")
+	cmd.Flags().StringSliceVar(&do.CheckOptions.KernelModules, "kernel-modules", do.CheckOptions.KernelModules,
+		"the comma-separated kernel modules the kernel check requires to be loaded or built into the kernel")
+	cmd.Flags().StringSliceVar(&do.CheckOptions.Sysctls, "sysctls", do.CheckOptions.Sysctls,
+		"the comma-separated sysctls the kernel check requires to have the values (e.g. net.ipv4.ip_forward=1)")
+	cmd.Flags().StringSliceVar(&do.CheckOptions.Only, "only", do.CheckOptions.Only,
+		fmt.Sprintf("run only the install checks of the comma-separated names, the checks are: %s", strings.Join(checkerNames(installCheckers), ", ")))
+	cmd.Flags().StringSliceVar(&do.CheckOptions.Skip, "skip", do.CheckOptions.Skip,
+		"skip the install checks of the comma-separated names, e.g. the network checks on an air-gapped node")
+}
+
+// NewDiagnoseOptions returns diagnose options
+func NewDiagnoseOptions() *common.DiagnoseOptions {
+	do := &common.DiagnoseOptions{}
+	do.Namespace = "default"
+	do.Config = constants.EdgecoreConfigPath
+	do.Output = common.DiagnoseOutputText
+	do.WatchInterval = defaultWatchInterval
+	do.TailLines = defaultTailLines
+	do.KubeConfig = common.DefaultKubeConfig
+	do.CheckOptions = &common.CheckOptions{
+		IP:            "",
+		Timeout:       3,
+		Retries:       1,
+		IPFamily:      common.IPFamilyDual,
+		CertWarnDays:  common.DefaultCertWarnDays,
+		MaxClockSkew:  common.DefaultMaxClockSkew,
+		MaxLatency:    common.DefaultMaxLatency,
+		MinMTU:        common.DefaultMinMTU,
+		MinDiskFree:   common.DefaultMinDiskFree,
+		MinCPU:        common.DefaultMinCPU,
+		MinMemory:     common.DefaultMinMemory,
+		MinDisk:       common.DefaultMinDisk,
+		LogWindow:     common.DefaultLogWindow,
+		KernelModules: slices.Clone(defaultKernelModules),
+		Sysctls:       slices.Clone(defaultKernelSysctls),
+		CNIConfDir:    constants.DefaultCNIConfDir,
+		CNIBinDir:     constants.DefaultCNIBinDir,
+		// edgecore logs to the journal by default, the file is written if edgecore is started with the log file flags
+		EdgecoreLogFile: filepath.Join(common.KubeEdgeLogPath, "edgecore.log"),
+	}
+	return do
+}
+
+// ExecuteDiagnose runs the diagnose object use and prints the results
+func (da Diagnose) ExecuteDiagnose(use string, ops *common.DiagnoseOptions, args []string) error {
+	if err := completeDiagnoseOptions(use, ops); err != nil {
+		return failDiagnoseOptions(use, ops.Output, err)
+	}
+	if ops.DryRun {
+		if err := DryRunDiagnose(use, ops, args, os.Stdout); err != nil {
+			return failDiagnoseOptions(use, ops.Output, err)
+		}
+		return nil
+	}
+	if use == common.ArgDiagnoseNode && ops.Watch {
+		if ops.ReportFile != "" {
+			err := errors.New("--report is not supported together with --watch")
+			return failDiagnoseOptions(use, ops.Output, err)
+		}
+		// the failed diagnoses do not stop the watch, it stops when the user cancels it
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return toDiagnoseExitError(watchDiagnoseNode(ctx, ops, os.Stdout))
+	}
+
+	r := NewDiagnoseReport(use, ops.Output, os.Stdout)
+	r.SetVerbosity(diagnoseVerbosity(ops))
+	r.SetExplain(ops.Explain)
+	r.SetScoreWeights(ops.ScoreWeights)
+	if err := r.SetTemplate(ops.Template); err != nil {
+		return failDiagnoseOptions(use, ops.Output, err)
+	}
+	if ops.ReportFile != "" {
+		f, err := os.Create(ops.ReportFile)
+		if err != nil {
+			err = fmt.Errorf("failed to create report file %s: %v", ops.ReportFile, err)
+			return failDiagnoseOptions(use, ops.Output, err)
+		}
+		defer f.Close()
+		if err := r.TeeFile(f, NewDiagnoseReportHeader(ops.Config)); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+		}
+	}
+	ops.Object, ops.Args = use, args
+	err := runDiagnostics(context.Background(), ops, r)
+
+	if ops.MetricsFile != "" {
+		if merr := writeMetricsFile(ops.MetricsFile, r, time.Now()); merr != nil {
+			fmt.Fprintln(os.Stderr, merr.Error())
+		}
+	}
+	if !r.IsText() {
+		if perr := r.Print(); perr != nil {
+			fmt.Fprintln(os.Stderr, perr.Error())
+		}
+	} else if err != nil {
+		r.PrintHints()
+		r.PrintSkipped()
+		r.Printf("%v\n", err)
+		util.PrintFail(use, common.StrDiagnose)
+	} else {
+		r.PrintHints()
+		r.PrintSkipped()
+		util.PrintSucceed(use, common.StrDiagnose)
+	}
+	return toDiagnoseExitError(err)
+}
+
+// failDiagnoseOptions reports the error of the options without breaking the structured output
+func failDiagnoseOptions(use, output string, err error) error {
+	fmt.Fprintln(os.Stderr, err.Error())
+	switch output {
+	case common.DiagnoseOutputJSON, common.DiagnoseOutputYAML, common.DiagnoseOutputJSONL:
+		r := NewDiagnoseReport(use, output, os.Stdout)
+		_ = r.Fail("options", err)
+		if perr := r.Print(); perr != nil {
+			fmt.Fprintln(os.Stderr, perr.Error())
+		}
+	}
+	return newDiagnoseExitError(DiagnoseExitCodeGeneric, err)
+}
+
+// completeDiagnoseOptions validates the options before any diagnose is run, and completes the check options from them
+func completeDiagnoseOptions(use string, ops *common.DiagnoseOptions) error {
+	if err := validateDiagnoseReportOutput(ops.Output, ops.Template); err != nil {
+		return err
+	}
+	if ops.Verbose && ops.Quiet {
+		return errors.New("--verbose and --quiet are mutually exclusive")
+	}
+	if ops.CheckOptions != nil {
+		if err := validateIPFamily(ops.CheckOptions.IPFamily); err != nil {
+			return err
+		}
+	}
+	if err := validateScoreWeights(ops.ScoreWeights); err != nil {
+		return err
+	}
+	// the unknown install checks fail before any diagnose is run
+	if use == common.ArgDiagnoseInstall || use == common.ArgDiagnoseAll {
+		if err := validateCheckSelection(installCheckers, ops.CheckOptions); err != nil {
+			return err
+		}
+	}
+	// stdin is read on the local node, the remote node has no stdin to read the config from
+	if ops.Config == util.EdgecoreConfigStdin && ops.Host != "" {
+		return errors.New("--config - is not supported together with --host")
+	}
+	if ops.Container != "" && ops.Host != "" {
+		return errors.New("--container is not supported together with --host")
+	}
+	// the attempts of the network connectivity checks are the details printed in verbose mode
+	if ops.Verbose && ops.CheckOptions != nil {
+		ops.CheckOptions.Verbose = true
+	}
+	return nil
+}
+
+// runDiagnose runs the diagnose object use and records the results in r
+func runDiagnose(use string, ops *common.DiagnoseOptions, args []string, r *DiagnoseReport) error {
+	var err error
+	if run, ok := diagnoseRunners[use]; ok {
+		err = run(ops, args, r)
+	}
+	if err == nil && ops.WarningsAsErrors {
+		if _, summary := r.summarize(); summary.Warn > 0 {
+			err = fmt.Errorf("%d checks warned, which fail the diagnose with --warnings-as-errors", summary.Warn)
+		}
+	}
+	return err
+}
+
+// diagnoseRunner runs the diagnose of an object with the args of the command and records the results in r
+type diagnoseRunner func(ops *common.DiagnoseOptions, args []string, r *DiagnoseReport) error
+
+// diagnoseRunners are the runners of the diagnose objects, a new object is diagnosed by registering its runner
+var diagnoseRunners = map[string]diagnoseRunner{
+	common.ArgDiagnoseNode: func(ops *common.DiagnoseOptions, _ []string, r *DiagnoseReport) error {
+		return DiagnoseNode(ops, r)
+	},
+	common.ArgDiagnosePod:        runDiagnosePod,
+	common.ArgDiagnoseDeployment: runDiagnoseDeployment,
+	common.ArgDiagnoseWorkload:   runDiagnoseWorkload,
+	common.ArgDiagnoseDevice:     runDiagnoseDevice,
+	common.ArgDiagnoseCluster: func(ops *common.DiagnoseOptions, _ []string, r *DiagnoseReport) error {
+		return DiagnoseCluster(ops, r)
+	},
+	common.ArgDiagnoseInstall: func(ops *common.DiagnoseOptions, _ []string, r *DiagnoseReport) error {
+		if ops.CheckOptions.Config == "" {
+			ops.CheckOptions.Config = ops.Config
+		}
+		return DiagnoseInstall(ops.CheckOptions, r)
+	},
+	common.ArgDiagnoseModule: DiagnoseModule,
+	common.ArgDiagnoseJoin: func(ops *common.DiagnoseOptions, _ []string, r *DiagnoseReport) error {
+		return DiagnoseJoin(ops, r)
+	},
+	common.ArgDiagnoseAll: func(ops *common.DiagnoseOptions, _ []string, r *DiagnoseReport) error {
+		return DiagnoseAll(ops, r)
+	},
+}
+
+// runDiagnosePod diagnoses the node and then the pods of args, of the label selector or of the uid
+func runDiagnosePod(ops *common.DiagnoseOptions, args []string, r *DiagnoseReport) error {
+	if len(args) == 0 && ops.LabelSelector == "" && ops.UID == "" {
+		return r.Fail(common.ArgDiagnosePod, errors.New("you must specify a pod name, a label selector or a pod uid"))
+	}
+	if ops.UID != "" && (len(args) > 0 || ops.LabelSelector != "" || ops.Live) {
+		return r.Fail(common.ArgDiagnosePod, errors.New("--uid is not supported together with a pod name, a label selector or --live"))
+	}
+	if ops.AllNamespaces && (len(args) > 0 || ops.Live) {
+		return r.Fail(common.ArgDiagnosePod, errors.New("--all-namespaces only supports the pods of a label selector or a uid without --live"))
+	}
+	// diagnose Pod, first diagnose node unless a captured database is diagnosed offline
+	if ops.DBPath != "" {
+		if !files.FileExists(ops.DBPath) {
+			return r.Fail("database", fmt.Errorf("database %s is not exists", ops.DBPath))
+		}
+		r.Printf("--db-path is set, skip the node diagnose and diagnose the pods in the database %s\n", ops.DBPath)
+	} else if err := DiagnoseNode(ops, r); err != nil {
+		return err
+	}
+	var err error
+	switch {
+	case ops.UID != "":
+		err = DiagnosePodByUID(ops, r)
+	case ops.LabelSelector != "":
+		err = DiagnosePodsBySelector(ops, r)
+	default:
+		err = DiagnosePods(ops, args, r)
+	}
+	// the pods missing in the edge database are reported by the live comparison as well
+	if ops.Live {
+		podNames := args
+		if ops.LabelSelector != "" {
+			podNames = []string{""}
+		}
+		for _, podName := range podNames {
+			err = errors.Join(err, DiagnosePodLive(ops, podName, r))
+		}
+	}
+	return err
+}
+
+// runDiagnoseDeployment diagnoses the node and then the deployment of args
+func runDiagnoseDeployment(ops *common.DiagnoseOptions, args []string, r *DiagnoseReport) error {
+	if len(args) == 0 {
+		return r.Fail(common.ArgDiagnoseDeployment, errors.New("you must specify a deployment name"))
+	}
+	if err := DiagnoseNode(ops, r); err != nil {
+		return err
+	}
+	return DiagnoseDeployment(ops, args[0], r)
+}
+
+// runDiagnoseWorkload diagnoses the node and then the workload of the kind and the name of args
+func runDiagnoseWorkload(ops *common.DiagnoseOptions, args []string, r *DiagnoseReport) error {
+	if len(args) < 2 {
+		return r.Fail(common.ArgDiagnoseWorkload, fmt.Errorf("you must specify a workload kind and name, the kinds are: %s",
+			strings.Join(workloadKinds, ", ")))
+	}
+	if err := DiagnoseNode(ops, r); err != nil {
+		return err
+	}
+	return DiagnoseWorkload(ops, args[0], args[1], r)
+}
+
+// runDiagnoseDevice diagnoses the node and then the device of args
+func runDiagnoseDevice(ops *common.DiagnoseOptions, args []string, r *DiagnoseReport) error {
+	if len(args) == 0 {
+		return r.Fail(common.ArgDiagnoseDevice, errors.New("you must specify a device name"))
+	}
+	if err := DiagnoseNode(ops, r); err != nil {
+		return err
+	}
+	return DiagnoseDevice(ops, args[0], r)
+}
+
+// newPermissionDeniedError returns the error of the file the user running the diagnose has no permission to read
+func newPermissionDeniedError(name, path string, err error) error {
+	return newDiagnoseExitError(DiagnoseExitCodePermissionDenied,
+		fmt.Errorf("%s %s is not readable by the user running the diagnose, run it as root or the user edgecore runs as: %v", name, path, err))
+}
+
+// toDiagnoseExitError returns err as a *DiagnoseExitError, which is of the generic exit code if err carries no exit code
+func toDiagnoseExitError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var exitErr *DiagnoseExitError
+	if errors.As(err, &exitErr) {
+		return exitErr
+	}
+	return newDiagnoseExitError(DiagnoseExitCodeGeneric, err)
+}
+
+// warnInsecureSkipTLSVerify warns that the server certificates are not verified
+func warnInsecureSkipTLSVerify(r *DiagnoseReport) {
+	const warning = "WARNING: --insecure-skip-tls-verify is set, the server certificates are not verified, " +
+		"a passing check does not mean a valid TLS setup"
+	if r.IsText() {
+		r.Printf("%s\n", warning)
+		return
+	}
+	fmt.Fprintln(os.Stderr, warning)
+}
+
+// diagnoseExecutor returns the executor of the container of ops.Container or the node of ops.Host
+func diagnoseExecutor(ops *common.DiagnoseOptions) (Executor, error) {
+	if ops.Container != "" {
+		return NewContainerExecutor(ops.Container, ops.RuntimeEndpoint, ops.CheckOptions.Timeout)
+	}
+	return NewExecutor(ops.Host), nil
+}
+
+// diagnoseNodeName returns the name of the node diagnosed by ops
+func diagnoseNodeName(ops *common.DiagnoseOptions) string {
+	ex, err := diagnoseExecutor(ops)
+	if err != nil {
+		ex = NewExecutor(ops.Host)
+	}
+	return ex.NodeName(ops.Config)
+}
+
+// remoteUnsupportedChecks are the checks of DiagnoseNode skipped on the remote node
+var remoteUnsupportedChecks = []string{
+	"config-drift", "systemd", "edgecore-log", "file-limits", "conntrack", "node", "node-labels", "disk-paths", "writable-dirs",
+	"runtime", "kubelet", "cgroup-driver", "cni", "pod-dns", "dns-addon", "edged", "eviction", "pod-dirs", "max-pods",
+	"host-paths", "registry", "mqtt", "servicebus", "cloudhub", "node-identity", "edgestream", "version", "cloudhub-latency",
+	"mtu", "timesync", "timezone", "clock",
+}
+
+// edgedChecks are the checks of DiagnoseNode which need edged enabled with its kubelet config
+var edgedChecks = []string{
+	"kubelet", "cgroup-driver", "cni", "pod-dns", "dns-addon", "edged", "eviction", "pod-dirs", "max-pods", "host-paths",
+	"registry",
+}
+
+func DiagnoseNode(ops *common.DiagnoseOptions, r *DiagnoseReport) error {
+	ex, err := diagnoseExecutor(ops)
+	if err != nil {
+		return r.Fail("edgecore", fmt.Errorf("find the container of edgecore failed: %v", err))
+	}
+	isEdgeRunning, err := ex.IsProcessRunning(constants.KubeEdgeBinaryName)
+	if err != nil {
+		return r.Fail("edgecore", fmt.Errorf("get edgecore status fail"))
+	}
+
+	if !isEdgeRunning {
+		return r.Fail("edgecore", fmt.Errorf("edgecore is not running, detection mode: %s", ex.DetectionMode()))
+	}
+	r.Pass("edgecore", "edgecore is running, detection mode: %s\n", ex.DetectionMode())
+
+	// check edgecore runs as root, a process of another user can not access the config, the database and the runtime
+	if err := r.Run("edgecore-user", func(w io.Writer) error {
+		return CheckEdgecoreUser(w, ex)
+	}); err != nil {
+		return err
+	}
+
+	if ops.Config == util.EdgecoreConfigStdin {
+		r.Pass("config", "edge config is read from stdin\n")
+	} else {
+		// a file without the read permission is not reported as missing, which os.Stat fails the same for
+		if err := ex.FileReadable(ops.Config); errors.Is(err, os.ErrPermission) {
+			return r.Fail("config", newPermissionDeniedError("edge config", ops.Config, err))
+		}
+		if !ex.FileExists(ops.Config) {
+			return r.Fail("config", newDiagnoseExitError(DiagnoseExitCodeConfigNotFound,
+				fmt.Errorf("edge config is not exists")))
+		}
+		r.Pass("config", "edge config is exists: %v\n", ops.Config)
+	}
+
+	edgeconfig, err := ex.ParseEdgecoreConfig(ops.Config)
+	if err != nil {
+		return r.Fail("config", fmt.Errorf("parse edgecore config failed"))
+	}
+	if errs := ValidateEdgecoreConfig(edgeconfig); len(errs) > 0 {
+		for _, e := range errs {
+			r.Failf("config", "%v\n", e)
+		}
+		return newDiagnoseError("config", fmt.Errorf("edge config %s has %d invalid values, edgecore fails to start with it", ops.Config, len(errs)))
+	}
+	debugEdgecoreConfig(r, edgeconfig)
+
+	// check datebase
+	dataSource := v1alpha2.DataBaseDataSource
+	if edgeconfig.DataBase.DataSource != "" {
+		dataSource = edgeconfig.DataBase.DataSource
+	}
+	ops.DBPath = dataSource
+	if err := ex.FileReadable(dataSource); errors.Is(err, os.ErrPermission) {
+		return r.Fail("database", newPermissionDeniedError("dataSource", dataSource, err))
+	}
+	if !ex.FileExists(dataSource) {
+		return r.Fail("database", fmt.Errorf("dataSource is not exists"))
+	}
+	r.Pass("database", "dataSource is exists: %v\n", dataSource)
+
+	// the other checks access the local disks, sockets and network, which are not reachable through ssh
+	if ops.Host != "" {
+		r.SkipAll(remoteUnsupportedChecks, "not supported on the remote node %s", ops.Host)
+		return nil
+	}
+
+	// the files edgecore reads in the container are reached through the root of the container on the host,
+	// and edgecore in the container is not managed by systemd
+	if ce, ok := ex.(containerExecutor); ok {
+		ce.hostEdgecoreConfig(edgeconfig)
+		dataSource = edgeconfig.DataBase.DataSource
+		ops.DBPath = dataSource
+		r.SkipAll([]string{"config-drift", "systemd", "edgecore-log"}, "not supported in %s", ex.DetectionMode())
+	} else {
+		// check edgecore is not running with another config or a config modified after it started
+		if err := r.Run("config-drift", func(w io.Writer) error {
+			return CheckConfigDrift(w, ops.Config, edgeconfig)
+		}); err != nil {
+			return err
+		}
+
+		// check the edgecore systemd unit is not flapping
+		if err := r.Run("systemd", CheckSystemd); err != nil {
+			return err
+		}
+
+		// check the edgecore logs for the recent crashes, which the process check misses between them
+		if err := r.Run("edgecore-log", func(w io.Writer) error {
+			return CheckEdgecoreLog(w, ops.CheckOptions.EdgecoreLogFile, ops.CheckOptions.LogWindow)
+		}); err != nil {
+			return err
+		}
+	}
+
+	// check edgecore and the host are not running out of the fds and the inotify watches
+	if err := r.Run("file-limits", CheckFileLimits); err != nil {
+		return err
+	}
+
+	// check the conntrack table is not full, the new connections are dropped once it is
+	if err := r.Run("conntrack", func(w io.Writer) error {
+		return CheckConntrack(w, procSysDir)
+	}); err != nil {
+		return err
+	}
+
+	// check the node status reported by edged is still Ready
+	if err := r.Run("node", func(w io.Writer) error {
+		if err := InitDB(v1alpha2.DataBaseDriverName, v1alpha2.DataBaseAliasName, dataSource); err != nil {
+			return fmt.Errorf("failed to initialize database: %v ", err)
+		}
+		return CheckCachedNode(w, ex.NodeName(ops.Config))
+	}); err != nil {
+		return err
+	}
+
+	// check the labels and taints edged registered the node with have not drifted from the config
+	if edged := edgeconfig.Modules.Edged; edged != nil && edged.Enable {
+		var taints []v1.Taint
+		if edged.TailoredKubeletConfig != nil {
+			taints = edged.TailoredKubeletConfig.RegisterWithTaints
+		}
+		if err := r.Run("node-labels", func(w io.Writer) error {
+			return CheckNodeLabelsTaints(w, ex.NodeName(ops.Config), edged.NodeLabels, taints)
+		}); err != nil {
+			return err
+		}
+	} else {
+		r.Skipf("node-labels", "edged is disabled")
+	}
+
+	// check the disk of the database and logs
+	if err := r.Run("disk-paths", func(w io.Writer) error {
+		return CheckPathDisk(w, edgecoreDiskPaths(edgeconfig), ops.CheckOptions.MinDiskFree)
+	}); err != nil {
+		return err
+	}
+
+	// check the dirs of edged and the database are writable, a filesystem remounted read-only still passes a stat
+	if err := r.Run("writable-dirs", func(w io.Writer) error {
+		return CheckWritableDirs(w, edgecoreWritableDirs(edgeconfig))
+	}); err != nil {
+		return err
+	}
+
+	// check container runtime
+	var endpoint string
+	if edged := edgeconfig.Modules.Edged; edged != nil && edged.TailoredKubeletConfig != nil {
+		endpoint = edged.TailoredKubeletConfig.ContainerRuntimeEndpoint
+	}
+	if err := r.Run("runtime", func(w io.Writer) error {
+		return CheckContainerRuntime(w, endpoint, ops.CheckOptions.Timeout)
+	}); err != nil {
+		return err
+	}
+
+	// check the CNI, the read-only port and the eviction thresholds of edged
+	if edged := edgeconfig.Modules.Edged; edged != nil && edged.Enable && edged.TailoredKubeletConfig != nil {
+		kubelet := edged.TailoredKubeletConfig
+		// check no standalone kubelet manages the pods besides edged
+		if err := r.Run("kubelet", CheckKubeletConflict); err != nil {
+			return err
+		}
+		// check edged and the container runtime manipulate the cgroups by the same driver
+		if err := r.Run("cgroup-driver", func(w io.Writer) error {
+			return CheckCgroupDriver(w, endpoint, kubelet.CgroupDriver, ops.CheckOptions.Timeout)
+		}); err != nil {
+			return err
+		}
+		// check the CNI config and plugins the container runtime sets up the pod network with,
+		// the dirs are owned by the container runtime and not in the edgecore config
+		if err := r.Run("cni", func(w io.Writer) error {
+			return CheckCNI(w, ops.CheckOptions.CNIConfDir, ops.CheckOptions.CNIBinDir)
+		}); err != nil {
+			return err
+		}
+		// check the DNS servers the pods resolve with, the DNS of the node does not guarantee them
+		if err := r.Run("pod-dns", func(w io.Writer) error {
+			return networkError(CheckPodDNS(w, kubelet, ops.CheckOptions.Domain, ops.CheckOptions.Timeout))
+		}); err != nil {
+			return err
+		}
+		// check the DNS addon pods on the node, e.g. edgemesh-agent, serve the DNS the pods resolve the services with
+		if err := r.Run("dns-addon", func(w io.Writer) error {
+			return CheckDNSAddon(w, kubelet, ops.CheckOptions.Timeout)
+		}); err != nil {
+			return err
+		}
+		if err := r.Run("edged", func(w io.Writer) error {
+			return CheckEdgedPort(w, kubelet.Address, kubelet.ReadOnlyPort, ops.CheckOptions.Timeout)
+		}); err != nil {
+			return err
+		}
+		// check the memory is not at the eviction thresholds of edged
+		if err := r.Run("eviction", func(w io.Writer) error {
+			return CheckEvictionPressure(w, kubelet)
+		}); err != nil {
+			return err
+		}
+		// check edged does not leak the dirs of the deleted pods, which eat the inodes of the disk
+		if err := r.Run("pod-dirs", func(w io.Writer) error {
+			return CheckOrphanedPodDirs(w, edgedRootDir(edged))
+		}); err != nil {
+			return err
+		}
+		// check the pods are within maxPods of edged, which the scheduler stops placing pods to the node at
+		if err := r.Run("max-pods", func(w io.Writer) error {
+			return CheckMaxPods(w, kubelet)
+		}); err != nil {
+			return err
+		}
+		// check the host paths of the hostPath volumes of the pods exist, the pods mounting the missing ones fail to start
+		if err := r.Run("host-paths", CheckHostPaths); err != nil {
+			return err
+		}
+		// check the image registries are reachable and the pull secrets are accepted, which ImagePullBackOff comes from
+		if err := r.Run("registry", func(w io.Writer) error {
+			registries, credentials, err := cachedPodRegistries()
+			if err != nil {
+				return err
+			}
+			if len(ops.CheckOptions.Registries) > 0 {
+				registries = ops.CheckOptions.Registries
+			}
+			return CheckRegistry(w, registries, credentials, NewHTTPCheckOptions(ops.CheckOptions))
+		}); err != nil {
+			return err
+		}
+	} else {
+		r.SkipAll(edgedChecks, "edged is disabled or has no kubelet config")
+	}
+
+	// check mqtt brokers of eventbus
+	if err := r.Run("mqtt", func(w io.Writer) error {
+		return networkError(CheckMQTT(w, edgeconfig.Modules.EventBus, ops.CheckOptions.Timeout, ops.MQTTConnect))
+	}); err != nil {
+		return err
+	}
 
-type Diagnose common.DiagnoseObject
+	// check servicebus is listening on the port the edge apps send the edge-to-edge http requests to
+	if sb := edgeconfig.Modules.ServiceBus; sb != nil && sb.Enable {
+		if err := r.Run("servicebus", func(w io.Writer) error {
+			return CheckServiceBusPort(w, sb, ops.CheckOptions.Timeout)
+		}); err != nil {
+			return err
+		}
+	} else {
+		r.Skipf("servicebus", "servicebus is disabled")
+	}
 
-// NewDiagnose returns KubeEdge edge debug Diagnose command.
-func NewDiagnose() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:     "diagnose",
-		Short:   edgeDiagnoseShortDescription,
-		Long:    edgeDiagnoseLongDescription,
-		Example: edgeDiagnoseExample,
+	//CheckNetWork
+	eh := edgeconfig.Modules.EdgeHub
+	if eh == nil {
+		r.SkipAll([]string{"cloudhub-dns", "cloudhub", "cloudhub-quic", "node-identity", "edgestream", "version",
+			"cloudhub-latency", "mtu", "clock"}, "edgehub is not configured")
+		if err := r.Run("timesync", CheckTimeSync); err != nil {
+			return err
+		}
+		return r.Run("timezone", func(w io.Writer) error {
+			return CheckTimeZone(w, hostEtcDir, ops.CheckOptions.ExpectedTZ)
+		})
 	}
-	for _, v := range common.DiagnoseObjectMap {
-		cmd.AddCommand(NewSubDiagnose(Diagnose(v)))
+	wsEnabled := eh.WebSocket != nil && eh.WebSocket.Enable
+	quicEnabled := eh.Quic != nil && eh.Quic.Enable
+	if !wsEnabled && !quicEnabled {
+		return r.Fail("cloudhub", fmt.Errorf("edgehub is not enable"))
 	}
-	return cmd
-}
 
-func NewSubDiagnose(object Diagnose) *cobra.Command {
-	do := NewDiagnoseOptions()
-	cmd := &cobra.Command{
-		Short: object.Desc,
-		Use:   object.Use,
-		Run: func(cmd *cobra.Command, args []string) {
-			object.ExecuteDiagnose(object.Use, do, args)
-		},
+	if ops.CheckOptions.InsecureSkipTLSVerify {
+		warnInsecureSkipTLSVerify(r)
 	}
-	switch object.Use {
-	case common.ArgDiagnoseNode:
-		cmd.Flags().StringVarP(&do.Config, common.EdgecoreConfig, "c", do.Config,
-			fmt.Sprintf("Specify configuration file, default is %s", constants.EdgecoreConfigPath))
-	case common.ArgDiagnosePod:
-		cmd.Flags().StringVarP(&do.Namespace, "namespace", "n", do.Namespace, "specify namespace")
-	case common.ArgDiagnoseInstall:
-		cmd.Flags().StringVarP(&do.CheckOptions.DNSIP, "dns-ip", "D", do.CheckOptions.DNSIP, "specify test dns server ip")
-		cmd.Flags().StringVarP(&do.CheckOptions.Domain, "domain", "d", do.CheckOptions.Domain, "specify test domain")
-		cmd.Flags().StringVarP(&do.CheckOptions.IP, "ip", "i", do.CheckOptions.IP, "specify test ip")
-		cmd.Flags().StringVarP(&do.CheckOptions.CloudHubServer, "cloud-hub-server", "s", do.CheckOptions.CloudHubServer, "specify cloudhub server")
+	chOpts := CloudHubCheckOptions{
+		HTTPCheckOptions: NewHTTPCheckOptions(ops.CheckOptions),
+		CertFile:         eh.TLSCertFile,
+		KeyFile:          eh.TLSPrivateKeyFile,
 	}
-	return cmd
+	chOpts.CAFile = eh.TLSCAFile
+	// resolve the cloudhub server before the connection checks, so a dns problem is not reported as a connection failure
+	var dnsServer string
+	if wsEnabled {
+		dnsServer = eh.WebSocket.Server
+	} else {
+		dnsServer = eh.Quic.Server
+	}
+	if err := r.Run("cloudhub-dns", func(w io.Writer) error {
+		if via := chOpts.viaProxy("https://" + dnsServer); via != "" {
+			fmt.Fprintf(w, "cloudhub server %s is connected%s, skip dns resolution\n", dnsServer, via)
+			return nil
+		}
+		return networkError(CheckCloudHubDNS(w, dnsServer, ops.CheckOptions.IPFamily))
+	}); err != nil {
+		return err
+	}
+
+	// websocket and quic are checked independently if both are enabled,
+	// the results are recorded after both checks, since the failures are warnings in edge autonomy
+	var conns []cloudHubConnection
+	if wsEnabled {
+		wsURL := edgeHubWebSocketURL(edgeconfig)
+		via := chOpts.viaProxy("https://" + eh.WebSocket.Server)
+		conn := cloudHubConnection{check: "cloudhub", message: fmt.Sprintf("cloudcore websocket connection success%s\n", via)}
+		if err := chOpts.retry(r.DebugWriter(), "cloudcore websocket connection", func() error {
+			return CheckWebSocket(wsURL, chOpts)
+		}); err != nil {
+			conn.err = fmt.Errorf("cloudcore websocket connection%s failed,%v", via, err)
+		}
+		conns = append(conns, conn)
+	}
+	if quicEnabled {
+		conn := cloudHubConnection{check: "cloudhub-quic", message: "cloudcore quic connection success\n"}
+		if err := chOpts.retry(r.DebugWriter(), "cloudcore quic connection", func() error {
+			return CheckQUIC(eh.Quic.Server, chOpts)
+		}); err != nil {
+			conn.err = fmt.Errorf("cloudcore quic connection failed,%v", err)
+		}
+		conns = append(conns, conn)
+	}
+	// edgecore keeps the cached pods running if cloudhub is unreachable, which is reported as a warning
+	if diagnoseEdgeAutonomy(r, conns) {
+		r.SkipAll([]string{"node-identity", "edgestream", "version", "cloudhub-latency", "mtu", "clock"}, "cloudcore is unreachable")
+		if err := r.Run("timesync", CheckTimeSync); err != nil {
+			return err
+		}
+		return r.Run("timezone", func(w io.Writer) error {
+			return CheckTimeZone(w, hostEtcDir, ops.CheckOptions.ExpectedTZ)
+		})
+	}
+	var errs []error
+	for _, conn := range conns {
+		if conn.err != nil {
+			errs = append(errs, r.Fail(conn.check, networkError(conn.err)))
+		} else {
+			r.Pass(conn.check, "%s", conn.message)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	// check no other edgecore with the same node name reports the status of the node
+	if err := r.Run("node-identity", func(w io.Writer) error {
+		return CheckNodeIdentity(w, ex.NodeName(ops.Config), ops.KubeConfig)
+	}); err != nil {
+		return err
+	}
+
+	// check the tunnel of edgestream which kubectl logs and exec go through
+	if es := edgeconfig.Modules.EdgeStream; es != nil && es.Enable {
+		if err := r.Run("edgestream", func(w io.Writer) error {
+			return networkError(CheckEdgeStream(w, es, chOpts.HTTPCheckOptions))
+		}); err != nil {
+			return err
+		}
+	} else {
+		r.Skipf("edgestream", "edgestream is disabled")
+	}
+
+	// check the version skew between edgecore and cloudcore
+	if err := r.Run("version", func(w io.Writer) error {
+		return CheckVersionSkew(w, ops.KubeConfig)
+	}); err != nil {
+		return err
+	}
+
+	var server string
+	if wsEnabled {
+		server = eh.WebSocket.Server
+	}
+	// check the link to cloudcore is fast enough, the websocket server serves https
+	if server != "" {
+		if err := r.Run("cloudhub-latency", func(w io.Writer) error {
+			return networkError(CheckCloudHubLatency(w, server, chOpts, ops.CheckOptions.MaxLatency))
+		}); err != nil {
+			return err
+		}
+		// check the path MTU to cloudcore, the connection establishes over a small MTU but the large messages stall
+		if err := r.Run("mtu", func(w io.Writer) error {
+			return CheckPathMTU(w, server, ops.CheckOptions.Timeout, ops.CheckOptions.MinMTU, ops.CheckOptions.CNIConfDir)
+		}); err != nil {
+			return err
+		}
+	} else {
+		r.SkipAll([]string{"cloudhub-latency", "mtu"}, "websocket is disabled")
+	}
+
+	// check the clock is kept synchronized and the time zone the logs are stamped in, then the clock skew with cloudcore
+	if err := r.Run("timesync", CheckTimeSync); err != nil {
+		return err
+	}
+	if err := r.Run("timezone", func(w io.Writer) error {
+		return CheckTimeZone(w, hostEtcDir, ops.CheckOptions.ExpectedTZ)
+	}); err != nil {
+		return err
+	}
+	return r.Run("clock", func(w io.Writer) error {
+		return CheckClockSkew(w, server, chOpts, ops.CheckOptions.MaxClockSkew)
+	})
 }
 
-// NewDiagnoseOptions returns diagnose options
-func NewDiagnoseOptions() *common.DiagnoseOptions {
-	do := &common.DiagnoseOptions{}
-	do.Namespace = "default"
-	do.Config = constants.EdgecoreConfigPath
-	do.CheckOptions = &common.CheckOptions{
-		IP:      "",
-		Timeout: 3,
+// edgeHubWebSocketURL returns the same websocket url of cloudhub as the one edgehub connects to
+func edgeHubWebSocketURL(c *v1alpha2.EdgeCoreConfig) string {
+	var nodeName string
+	if edged := c.Modules.Edged; edged != nil {
+		nodeName = edged.HostnameOverride
 	}
-	return do
+	eh := c.Modules.EdgeHub
+	return strings.Join([]string{"wss:/", eh.WebSocket.Server, eh.ProjectID, nodeName, "events"}, "/")
 }
 
-func (da Diagnose) ExecuteDiagnose(use string, ops *common.DiagnoseOptions, args []string) {
-	var err error
-	switch use {
-	case common.ArgDiagnoseNode:
-		err = DiagnoseNode(ops)
-	case common.ArgDiagnosePod:
-		if len(args) == 0 {
-			fmt.Println("error: You must specify a pod name")
-			return
+// debugEdgecoreConfig prints the parsed edgecore config values the checks depend on in verbose mode
+func debugEdgecoreConfig(r *DiagnoseReport, c *v1alpha2.EdgeCoreConfig) {
+	r.Debugf("database: %s\n", c.DataBase.DataSource)
+	if edged := c.Modules.Edged; edged != nil {
+		r.Debugf("edged: enable=%v, hostnameOverride=%s\n", edged.Enable, edged.HostnameOverride)
+		if kubelet := edged.TailoredKubeletConfig; kubelet != nil {
+			r.Debugf("edged: containerRuntimeEndpoint=%s, address=%s, readOnlyPort=%d\n",
+				kubelet.ContainerRuntimeEndpoint, kubelet.Address, kubelet.ReadOnlyPort)
 		}
-		// diagnose Pod, first diagnose node
-		err = DiagnoseNode(ops)
-		if err == nil {
-			err = DiagnosePod(ops, args[0])
+	}
+	if eh := c.Modules.EdgeHub; eh != nil {
+		if eh.WebSocket != nil {
+			r.Debugf("edgehub: websocket enable=%v, server=%s\n", eh.WebSocket.Enable, eh.WebSocket.Server)
 		}
-	case common.ArgDiagnoseInstall:
-		err = DiagnoseInstall(ops.CheckOptions)
+		if eh.Quic != nil {
+			r.Debugf("edgehub: quic enable=%v, server=%s\n", eh.Quic.Enable, eh.Quic.Server)
+		}
+		r.Debugf("edgehub: httpServer=%s, tlsCAFile=%s, tlsCertFile=%s\n", eh.HTTPServer, eh.TLSCAFile, eh.TLSCertFile)
+	}
+	if eb := c.Modules.EventBus; eb != nil {
+		r.Debugf("eventbus: enable=%v, mqttMode=%s, brokers=%s\n", eb.Enable, mqttModeName(eb.MqttMode),
+			strings.Join(MQTTBrokers(eb), ","))
 	}
+}
 
-	if err != nil {
-		fmt.Println(err.Error())
-		util.PrintFail(use, common.StrDiagnose)
-	} else {
-		util.PrintSucceed(use, common.StrDiagnose)
+// ValidateEdgecoreConfig validates the edgecore config as edgecore does at startup
+func ValidateEdgecoreConfig(c *v1alpha2.EdgeCoreConfig) field.ErrorList {
+	allErrs := validation.ValidateEdgeCoreConfiguration(c)
+
+	eh := c.Modules.EdgeHub
+	if eh == nil || !eh.Enable {
+		return allErrs
+	}
+	if eh.WebSocket != nil && eh.WebSocket.Enable && eh.WebSocket.Server == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("modules", "edgeHub", "websocket", "server"),
+			"the cloudhub websocket server must be set if websocket is enabled"))
+	}
+	if eh.Quic != nil && eh.Quic.Enable && eh.Quic.Server == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("modules", "edgeHub", "quic", "server"),
+			"the cloudhub quic server must be set if quic is enabled"))
 	}
+	if eh.HTTPServer == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("modules", "edgeHub", "httpServer"),
+			"the cloudhub https server is required to apply for the edge certificate"))
+	}
+	if eh.Heartbeat <= 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("modules", "edgeHub", "heartbeat"), eh.Heartbeat,
+			"heartbeat must be a positive number of seconds"))
+	}
+	return allErrs
+}
+
+func DiagnosePod(ops *common.DiagnoseOptions, podName string, r *DiagnoseReport) error {
+	if err := initPodDatabase(ops, r); err != nil {
+		return err
+	}
+	return diagnoseCachedPod(ops, ops.Namespace, podName, r)
 }
 
-func DiagnoseNode(ops *common.DiagnoseOptions) error {
-	osType := util.GetOSInterface()
-	isEdgeRunning, err := osType.IsKubeEdgeProcessRunning(constants.KubeEdgeBinaryName)
+// DiagnosePodByUID diagnoses the cached pod whose metadata.uid is ops.UID
+func DiagnosePodByUID(ops *common.DiagnoseOptions, r *DiagnoseReport) error {
+	if err := initPodDatabase(ops, r); err != nil {
+		return err
+	}
+	pods, err := QueryPodsFromDatabase(podsNamespace(ops))
 	if err != nil {
-		return fmt.Errorf("get edgecore status fail")
+		return r.Fail("pod", err)
 	}
+	var matched []podRef
+	for _, pod := range pods {
+		if string(pod.UID) == ops.UID {
+			matched = append(matched, podRef{namespace: pod.Namespace, name: pod.Name})
+		}
+	}
+	if len(matched) == 0 {
+		return r.Fail("pod", fmt.Errorf("not find pod of uid %s in %s, the pod may be deleted or recreated with another uid",
+			ops.UID, namespaceScope(ops)))
+	}
+	if len(matched) > 1 {
+		names := make([]string, 0, len(matched))
+		for _, ref := range matched {
+			names = append(names, ref.String())
+		}
+		return r.Fail("pod", fmt.Errorf("%d pods of uid %s are found in %s: %s", len(matched), ops.UID,
+			namespaceScope(ops), strings.Join(names, ",")))
+	}
+	r.Printf("Pod of uid %s is %s\n", ops.UID, matched[0])
+	return diagnoseCachedPod(ops, matched[0].namespace, matched[0].name, r)
+}
 
-	if !isEdgeRunning {
-		return fmt.Errorf("edgecore is not running")
+// diagnoseCachedPod diagnoses the status of the pod in the database
+func diagnoseCachedPod(ops *common.DiagnoseOptions, namespace, podName string, r *DiagnoseReport) error {
+	err := diagnosePodStatus(namespace, podName, ops.Since, r)
+	printPodEvents(namespace, podName, r)
+	if ops.Logs {
+		printNotReadyContainerLogs(namespace, podName, ops.TailLines, r)
 	}
-	fmt.Println("edgecore is running")
+	return err
+}
 
-	isFileExists := files.FileExists(ops.Config)
-	if !isFileExists {
-		return fmt.Errorf("edge config is not exists")
+// DiagnosePods diagnoses the pods of the namespace by name
+func DiagnosePods(ops *common.DiagnoseOptions, podNames []string, r *DiagnoseReport) error {
+	if len(podNames) == 1 {
+		return DiagnosePod(ops, podNames[0], r)
+	}
+	if err := initPodDatabase(ops, r); err != nil {
+		return err
 	}
-	fmt.Printf("edge config is exists: %v\n", ops.Config)
 
-	edgeconfig, err := util.ParseEdgecoreConfig(ops.Config)
+	notReady, err := diagnosePodList(ops.Namespace, podNames, ops.Since, r)
 	if err != nil {
-		return fmt.Errorf("parse edgecore config failed")
+		return err
 	}
 
-	// check datebase
-	dataSource := v1alpha2.DataBaseDataSource
-	if edgeconfig.DataBase.DataSource != "" {
-		dataSource = edgeconfig.DataBase.DataSource
+	const summary = "%d/%d pods are Ready\n"
+	if len(notReady) > 0 {
+		r.Failf("pods", summary, len(podNames)-len(notReady), len(podNames))
+		return newDiagnoseError("pods", fmt.Errorf("pod %s is not Ready", strings.Join(notReady, ",")))
 	}
-	ops.DBPath = dataSource
-	isFileExists = files.FileExists(dataSource)
-	if !isFileExists {
-		return fmt.Errorf("dataSource is not exists")
+	r.Pass("pods", summary, len(podNames), len(podNames))
+	return nil
+}
+
+// DiagnosePodsBySelector diagnoses the pods matching the label selector
+func DiagnosePodsBySelector(ops *common.DiagnoseOptions, r *DiagnoseReport) error {
+	selector, err := labels.Parse(ops.LabelSelector)
+	if err != nil {
+		return r.Fail("selector", fmt.Errorf("invalid label selector %s: %v", ops.LabelSelector, err))
+	}
+	if err := initPodDatabase(ops, r); err != nil {
+		return err
 	}
-	fmt.Printf("dataSource is exists: %v\n", dataSource)
 
-	//CheckNetWork
-	if !edgeconfig.Modules.EdgeHub.WebSocket.Enable {
-		return fmt.Errorf("edgehub is not enable")
+	pods, err := QueryPodsFromDatabase(podsNamespace(ops))
+	if err != nil {
+		return r.Fail("pod", err)
+	}
+	var refs []podRef
+	for _, pod := range pods {
+		if !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		ref := podRef{namespace: ops.Namespace, name: pod.Name}
+		if ops.AllNamespaces {
+			ref.namespace = pod.Namespace
+		}
+		refs = append(refs, ref)
+	}
+	if len(refs) == 0 {
+		return r.Fail("pod", fmt.Errorf("not find pods matching %s in %s", ops.LabelSelector, namespaceScope(ops)))
+	}
+	if ops.AllNamespaces {
+		r.Printf("Pods matching %s by namespace: %s\n", ops.LabelSelector, namespacePodCounts(refs))
 	}
 
-	cloudURL := edgeconfig.Modules.EdgeHub.WebSocket.Server
-	err = CheckHTTP("https://" + cloudURL)
+	notReadyRefs, err := diagnosePodRefs(refs, ops.Since, r)
 	if err != nil {
-		return fmt.Errorf("cloudcore websocket connection failed")
+		return err
 	}
-	fmt.Printf("cloudcore websocket connection success")
 
+	const summary = "%d/%d pods matching %s are Ready\n"
+	if len(notReadyRefs) > 0 {
+		notReady := make([]string, 0, len(notReadyRefs))
+		for _, ref := range notReadyRefs {
+			if ops.AllNamespaces {
+				notReady = append(notReady, ref.String())
+			} else {
+				notReady = append(notReady, ref.name)
+			}
+		}
+		r.Failf("pods", summary, len(refs)-len(notReadyRefs), len(refs), ops.LabelSelector)
+		return newDiagnoseError("pods", fmt.Errorf("pod %s is not Ready", strings.Join(notReady, ",")))
+	}
+	r.Pass("pods", summary, len(refs), len(refs), ops.LabelSelector)
 	return nil
 }
 
-func DiagnosePod(ops *common.DiagnoseOptions, podName string) error {
-	var ready bool
+// podRef is the namespace and the name of a pod in the edge database
+type podRef struct {
+	namespace string
+	name      string
+}
+
+func (p podRef) String() string {
+	return p.namespace + "/" + p.name
+}
+
+// podsNamespace returns the namespace the pods are enumerated from the edge database in
+func podsNamespace(ops *common.DiagnoseOptions) string {
+	if ops.AllNamespaces {
+		return metav1.NamespaceAll
+	}
+	return ops.Namespace
+}
+
+// namespaceScope describes the namespaces the pods are enumerated in for the messages
+func namespaceScope(ops *common.DiagnoseOptions) string {
+	if ns := podsNamespace(ops); ns != metav1.NamespaceAll {
+		return "namespace " + ns
+	}
+	return "all namespaces"
+}
+
+// namespacePodCounts returns the numbers of the pods by namespace, e.g. "default: 2, prod: 1"
+func namespacePodCounts(refs []podRef) string {
+	counts := make(map[string]int)
+	var namespaces []string
+	for _, ref := range refs {
+		if counts[ref.namespace] == 0 {
+			namespaces = append(namespaces, ref.namespace)
+		}
+		counts[ref.namespace]++
+	}
+	sort.Strings(namespaces)
+	parts := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		parts = append(parts, fmt.Sprintf("%s: %d", ns, counts[ns]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// diagnosePodList diagnoses the pods of the namespace and returns the names of the pods not Ready
+func diagnosePodList(namespace string, podNames []string, since time.Duration, r *DiagnoseReport) ([]string, error) {
+	refs := make([]podRef, 0, len(podNames))
+	for _, name := range podNames {
+		refs = append(refs, podRef{namespace: namespace, name: name})
+	}
+	notReadyRefs, err := diagnosePodRefs(refs, since, r)
+	var notReady []string
+	for _, ref := range notReadyRefs {
+		notReady = append(notReady, ref.name)
+	}
+	return notReady, err
+}
+
+// diagnosePodRefs diagnoses the pods and returns the pods not Ready
+func diagnosePodRefs(refs []podRef, since time.Duration, r *DiagnoseReport) ([]podRef, error) {
+	var notReady []podRef
+	podErrs := make(map[podRef]error, len(refs))
+	for _, ref := range refs {
+		err := diagnosePodStatus(ref.namespace, ref.name, since, r)
+		if err != nil {
+			notReady = append(notReady, ref)
+			r.Printf("%v\n", err)
+		} else {
+			r.Printf("\n")
+		}
+		podErrs[ref] = err
+	}
+
+	r.Printf("\n")
+	tw := tabwriter.NewWriter(r.TextWriter(), 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tPOD\tREADY\tMESSAGE")
+	for _, ref := range refs {
+		ready, msg := "True", ""
+		if podErrs[ref] != nil {
+			ready, msg = "False", podErrs[ref].Error()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", ref.namespace, ref.name, ready, msg)
+	}
+	return notReady, tw.Flush()
+}
+
+func initPodDatabase(ops *common.DiagnoseOptions, r *DiagnoseReport) error {
 	if ops.DBPath == "" {
 		ops.DBPath = v1alpha2.DataBaseDataSource
+		// the database may be configured in a non-default location
+		if util.EdgecoreConfigExists(ops.Config) {
+			if edgeconfig, err := util.ParseEdgecoreConfig(ops.Config); err == nil && edgeconfig.DataBase.DataSource != "" {
+				ops.DBPath = edgeconfig.DataBase.DataSource
+			}
+		}
 	}
 	err := InitDB(v1alpha2.DataBaseDriverName, v1alpha2.DataBaseAliasName, ops.DBPath)
 	if err != nil {
-		return fmt.Errorf("failed to initialize database: %v ", err)
+		return r.Fail("database", fmt.Errorf("failed to initialize database: %v ", err))
 	}
-	fmt.Printf("Database %s is exist \n", v1alpha2.DataBaseDataSource)
-	podStatus, err := QueryPodFromDatabase(ops.Namespace, podName)
+	r.Pass("database", "Database %s is exist \n", ops.DBPath)
+	// a stale schema is only a warning, it explains the failed queries of the checks below but does not stop them
+	return r.Run("database-schema", func(w io.Writer) error {
+		return CheckDatabaseSchema(w, dbm.DBAccess)
+	})
+}
+
+// diagnosePodStatus diagnoses the status of the pod
+func diagnosePodStatus(namespace, podName string, since time.Duration, r *DiagnoseReport) error {
+	var ready bool
+	podStatus, err := QueryPodFromDatabase(namespace, podName, r)
 	if err != nil {
-		return err
+		return r.Fail("pod", err)
 	}
 
-	fmt.Printf("pod %v phase is %v \n", podName, podStatus.Phase)
+	r.Debugf("pod %v status: phase=%v, hostIP=%v, podIP=%v, qosClass=%v\n", podName, podStatus.Phase,
+		podStatus.HostIP, podStatus.PodIP, podStatus.QOSClass)
 	if podStatus.Phase != "Running" {
 		ready = false
+		r.Failf("phase", "pod %v phase is %v \n", podName, podStatus.Phase)
+	} else {
+		r.Pass("phase", "pod %v phase is %v \n", podName, podStatus.Phase)
 	}
 
 	conditions := podStatus.Conditions
 	containerConditions := podStatus.ContainerStatuses
+	initContainerConditions := podStatus.InitContainerStatuses
 
 	// check conditions
 	for _, v := range conditions {
@@ -194,97 +1650,275 @@ func DiagnosePod(ops *common.DiagnoseOptions, podName string) error {
 			ready = true
 		}
 		if v.Status != "True" {
-			fmt.Printf("conditions is not true, type: %v ,message: %v ,reason: %v \n",
+			r.Failf("condition", "conditions is not true, type: %v ,message: %v ,reason: %v \n",
 				v.Type, v.Message, v.Reason)
 		}
 	}
+	// check initContainerConditions, the init containers must run to completion before the containers start
+	for _, v := range initContainerConditions {
+		switch {
+		case v.State.Terminated != nil && v.State.Terminated.ExitCode == 0:
+			r.Pass("init-container", "initContainerConditions %v Completed\n", v.Name)
+		case v.State.Terminated != nil:
+			r.Failf("init-container", "initContainerConditions %v Terminated, message: %v, reason: %v, ExitCode: %v, RestartCount: %v \n", v.Name,
+				v.State.Terminated.Message, v.State.Terminated.Reason, v.State.Terminated.ExitCode, v.RestartCount)
+		case v.State.Waiting != nil:
+			r.Failf("init-container", "initContainerConditions %v Waiting, message: %v, reason: %v, RestartCount: %v \n", v.Name,
+				v.State.Waiting.Message, v.State.Waiting.Reason, v.RestartCount)
+		default:
+			r.Failf("init-container", "initContainerConditions %v is running, RestartCount: %v \n", v.Name, v.RestartCount)
+		}
+	}
 	// check containerConditions
 	for _, v := range containerConditions {
 		if !v.Ready {
 			if v.State.Waiting != nil {
-				fmt.Printf("containerConditions %v Waiting, message: %v, reason: %v, RestartCount: %v \n", v.Name,
+				r.Failf("container", "containerConditions %v Waiting, message: %v, reason: %v, RestartCount: %v \n", v.Name,
 					v.State.Waiting.Message, v.State.Waiting.Reason, v.RestartCount)
+			} else if v.State.Terminated != nil && stabilized(v.State.Terminated.FinishedAt.Time, since) {
+				r.Pass("container", "containerConditions %v Terminated at %v, stabilized for more than %v, RestartCount: %v \n", v.Name,
+					v.State.Terminated.FinishedAt.Format(time.RFC3339), since, v.RestartCount)
 			} else if v.State.Terminated != nil {
-				fmt.Printf("containerConditions %v Terminated, message: %v, reason: %v, RestartCount: %v \n", v.Name,
+				r.Failf("container", "containerConditions %v Terminated, message: %v, reason: %v, RestartCount: %v \n", v.Name,
 					v.State.Terminated.Message, v.State.Terminated.Reason, v.RestartCount)
 			} else {
-				fmt.Printf("containerConditions %v is not ready\n", v.Name)
+				r.Failf("container", "containerConditions %v is not ready\n", v.Name)
 			}
+		} else if restartedAt, ok := lastRestartTime(v); ok && since > 0 && v.RestartCount > 0 {
+			if stabilized(restartedAt, since) {
+				r.Pass("container", "containerConditions %v is ready, restarts stabilized, last restart at %v, RestartCount: %v\n",
+					v.Name, restartedAt.Format(time.RFC3339), v.RestartCount)
+			} else {
+				r.Failf("container", "containerConditions %v is ready, but restarted at %v within %v, RestartCount: %v\n",
+					v.Name, restartedAt.Format(time.RFC3339), since, v.RestartCount)
+			}
+		} else {
+			r.Pass("container", "containerConditions %v is ready\n", v.Name)
+		}
+	}
+	if len(initContainerConditions) > 0 {
+		summary, done := containerStatusSummary(initContainerConditions, true)
+		if done {
+			r.Pass("init-containers", "%s\n", summary)
+		} else {
+			r.Failf("init-containers", "%s\n", summary)
+		}
+	}
+	if len(containerConditions) > 0 {
+		summary, done := containerStatusSummary(containerConditions, false)
+		if done {
+			r.Pass("containers", "%s\n", summary)
 		} else {
-			fmt.Printf("containerConditions %v is ready\n", v.Name)
+			r.Failf("containers", "%s\n", summary)
 		}
 	}
 	if ready {
-		fmt.Printf("Pod %s is Ready", podName)
+		r.Pass("ready", "Pod %s is Ready\n", podName)
 	} else {
-		return fmt.Errorf("pod %s is not Ready", podName)
+		return r.Fail("ready", fmt.Errorf("pod %s is not Ready", podName))
 	}
 
 	return nil
 }
 
-func QueryPodFromDatabase(resNamePaces string, podName string) (*v1.PodStatus, error) {
+// containerStatusSummary returns a rollup of the container statuses and whether they are all ready
+func containerStatusSummary(statuses []v1.ContainerStatus, init bool) (string, bool) {
+	var done int
+	states := []string{"waiting", "running", "terminated"}
+	counts := make(map[string]int, len(states))
+	reasons := make(map[string][]string, len(states))
+	for _, cs := range statuses {
+		var state, reason string
+		switch {
+		case init && cs.State.Terminated != nil && cs.State.Terminated.ExitCode == 0,
+			!init && cs.Ready:
+			done++
+			continue
+		case cs.State.Waiting != nil:
+			state, reason = "waiting", cs.State.Waiting.Reason
+		case cs.State.Terminated != nil:
+			state, reason = "terminated", cs.State.Terminated.Reason
+		default:
+			state = "running"
+		}
+		counts[state]++
+		if reason != "" && !slices.Contains(reasons[state], reason) {
+			reasons[state] = append(reasons[state], reason)
+		}
+	}
+
+	summary := fmt.Sprintf("%d/%d containers ready", done, len(statuses))
+	if init {
+		summary = fmt.Sprintf("%d/%d init containers completed", done, len(statuses))
+	}
+	for _, state := range states {
+		if counts[state] == 0 {
+			continue
+		}
+		summary += fmt.Sprintf(", %d %s", counts[state], state)
+		if len(reasons[state]) > 0 {
+			summary += fmt.Sprintf(" (%s)", strings.Join(reasons[state], ","))
+		}
+	}
+	return summary, done == len(statuses)
+}
+
+// stabilized returns whether t is older than the window since, it is always false if since is not positive
+func stabilized(t time.Time, since time.Duration) bool {
+	return since > 0 && !t.IsZero() && time.Since(t) > since
+}
+
+// lastRestartTime returns the time of the last restart of the container
+func lastRestartTime(cs v1.ContainerStatus) (time.Time, bool) {
+	if cs.LastTerminationState.Terminated != nil {
+		return cs.LastTerminationState.Terminated.FinishedAt.Time, true
+	}
+	if cs.State.Running != nil {
+		return cs.State.Running.StartedAt.Time, true
+	}
+	return time.Time{}, false
+}
+
+// QueryPodsFromDatabase returns all the pods of the namespace in the database
+func QueryPodsFromDatabase(namespace string) ([]v1.Pod, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+	var resultPods *[]dao.Meta
+	var err error
+	if namespace == metav1.NamespaceAll {
+		resultPods, err = queryAllMeta(ctx, "type", model.ResourceTypePod)
+	} else {
+		resultPods, err = queryAllMeta(ctx, "key__startswith", fmt.Sprintf("%v/pod/", namespace))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read database fail: %s", err.Error())
+	}
+
+	pods := make([]v1.Pod, 0, len(*resultPods))
+	for _, meta := range *resultPods {
+		pod := v1.Pod{}
+		if err := unmarshalCachedPod(meta.Key, meta.Value, &pod); err != nil {
+			return nil, err
+		}
+		// the key of a pod is namespace/pod/name
+		if keys := strings.SplitN(meta.Key, "/", 3); len(keys) == 3 {
+			if pod.Namespace == "" {
+				pod.Namespace = keys[0]
+			}
+			if pod.Name == "" {
+				pod.Name = keys[2]
+			}
+		}
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+func QueryPodFromDatabase(resNamePaces string, podName string, r *DiagnoseReport) (*v1.PodStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+
 	conditionsPod := fmt.Sprintf("%v/pod/%v",
 		resNamePaces,
 		podName)
-	resultPod, err := dao.QueryMeta("key", conditionsPod)
+	resultPod, err := queryMeta(ctx, "key", conditionsPod)
 	if err != nil {
 		return nil, fmt.Errorf("read database fail: %s", err.Error())
 	}
 	if len(*resultPod) == 0 {
 		return nil, fmt.Errorf("not find %v in datebase", conditionsPod)
 	}
-	fmt.Printf("Pod %s is exist \n", podName)
+	r.Pass("pod", "Pod %s is exist \n", podName)
 
 	conditionsStatus := fmt.Sprintf("%v/podstatus/%v",
 		resNamePaces,
 		podName)
-	resultStatus, err := dao.QueryMeta("key", conditionsStatus)
+	resultStatus, err := queryMeta(ctx, "key", conditionsStatus)
 	if err != nil {
 		return nil, fmt.Errorf("read database fail: %s", err.Error())
 	}
 	if len(*resultStatus) == 0 {
-		fmt.Printf("not find %v in datebase\n", conditionsStatus)
-		r := *resultPod
+		r.Failf("podstatus", "not find %v in datebase\n", conditionsStatus)
 		pod := &v1.Pod{}
-		err = json.Unmarshal([]byte(r[0]), pod)
-		if err != nil {
-			return &pod.Status, err
+		if err := unmarshalCachedPod(conditionsPod, (*resultPod)[0], pod); err != nil {
+			return nil, err
 		}
 		return &pod.Status, nil
 	}
-	fmt.Printf("PodStatus %s is exist \n", podName)
+	r.Pass("podstatus", "PodStatus %s is exist \n", podName)
 
-	r := *resultStatus
 	podStatus := &types.PodStatusRequest{}
-	err = json.Unmarshal([]byte(r[0]), podStatus)
-	if err != nil {
-		return &podStatus.Status, err
+	if err := unmarshalCachedPod(conditionsStatus, (*resultStatus)[0], podStatus); err != nil {
+		return nil, err
 	}
 	return &podStatus.Status, nil
 }
 
-func DiagnoseInstall(ob *common.CheckOptions) error {
-	if err := CheckCPU(); err != nil {
-		return err
+func DiagnoseInstall(ob *common.CheckOptions, r *DiagnoseReport) error {
+	checkers, err := selectCheckers(installCheckers, ob)
+	if err != nil {
+		return r.Fail(common.ArgDiagnoseInstall, err)
 	}
-	if err := CheckMemory(); err != nil {
-		return err
+	if ob.InsecureSkipTLSVerify {
+		warnInsecureSkipTLSVerify(r)
 	}
-	if err := CheckDisk(); err != nil {
-		return err
+	// the checks are independent, run them concurrently so that the slow network checks overlap
+	err = runCheckers(context.Background(), r, maxConcurrentInstallChecks, ob, checkers)
+	recordSkippedCheckers(r, installCheckers, ob)
+	return err
+}
+
+// clockSkewCheckOptions returns the cloudhub server and the options of the clock skew check
+func clockSkewCheckOptions(ob *common.CheckOptions) (string, CloudHubCheckOptions) {
+	opts := CloudHubCheckOptions{HTTPCheckOptions: NewHTTPCheckOptions(ob)}
+	server := ob.CloudHubServer
+	if ob.Config == "" || !util.EdgecoreConfigExists(ob.Config) {
+		return server, opts
 	}
-	if ob.Domain != "" {
-		if err := CheckDNSSpecify(ob.Domain, ob.DNSIP); err != nil {
-			return err
+	edgeConfig, err := util.ParseEdgecoreConfig(ob.Config)
+	if err != nil {
+		return server, opts
+	}
+	eh := edgeConfig.Modules.EdgeHub
+	if eh == nil {
+		return server, opts
+	}
+	if server == "" && eh.WebSocket != nil {
+		server = eh.WebSocket.Server
+	}
+	opts.CAFile, opts.CertFile, opts.KeyFile = eh.TLSCAFile, eh.TLSCertFile, eh.TLSPrivateKeyFile
+	return server, opts
+}
+
+// edgecoreDiskPaths returns the paths of the edgecore database, the edgecore logs and the pod logs
+func edgecoreDiskPaths(edgeconfig *v1alpha2.EdgeCoreConfig) []string {
+	dataSource, podLogsDir := v1alpha2.DataBaseDataSource, ""
+	if edgeconfig != nil {
+		if edgeconfig.DataBase != nil && edgeconfig.DataBase.DataSource != "" {
+			dataSource = edgeconfig.DataBase.DataSource
+		}
+		if edged := edgeconfig.Modules.Edged; edged != nil && edged.TailoredKubeletConfig != nil {
+			podLogsDir = edged.TailoredKubeletConfig.PodLogsDir
 		}
 	}
-	if err := CheckNetWork(ob.IP, ob.Timeout, ob.CloudHubServer,
-		ob.EdgecoreServer, ob.Config); err != nil {
-		return err
+	paths := []string{dataSource, common.KubeEdgeLogPath}
+	if podLogsDir != "" {
+		paths = append(paths, podLogsDir)
 	}
-	if err := CheckPid(); err != nil {
-		return err
+	return paths
+}
+
+// edgecoreWritableDirs returns the dirs edgecore writes the database and the pod dirs to
+func edgecoreWritableDirs(edgeconfig *v1alpha2.EdgeCoreConfig) []string {
+	dataSource := v1alpha2.DataBaseDataSource
+	if edgeconfig.DataBase != nil && edgeconfig.DataBase.DataSource != "" {
+		dataSource = edgeconfig.DataBase.DataSource
 	}
-	return nil
+	dirs := []string{filepath.Dir(dataSource)}
+	if edged := edgeconfig.Modules.Edged; edged != nil && edged.Enable {
+		if rootDir := edgedRootDir(edged); rootDir != dirs[0] {
+			dirs = append(dirs, rootDir)
+		}
+	}
+	return dirs
 }
@@ -0,0 +1,124 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+// diagnoseAllSummary counts the diagnoses run by DiagnoseAll
+type diagnoseAllSummary struct {
+	passed   int
+	failed   []string
+	firstErr error
+}
+
+// run runs the diagnose fn named name, and prints its error if it fails
+func (s *diagnoseAllSummary) run(name string, r *DiagnoseReport, fn func() error) error {
+	r.Printf("--- diagnose %s ---\n", name)
+	err := fn()
+	if err != nil {
+		r.Printf("%v\n", err)
+		s.failed = append(s.failed, name)
+		if s.firstErr == nil {
+			s.firstErr = err
+		}
+	} else {
+		r.Printf("\n")
+		s.passed++
+	}
+	return err
+}
+
+// DiagnoseAll runs the install and node diagnoses, then diagnoses every pod in the database
+// of ops.Namespace, or of all the namespaces if ops.AllNamespaces is set.
+// A failed diagnose does not stop the following ones unless ops.FailFast is set,
+// and a summary of all the diagnoses is recorded at the end.
+func DiagnoseAll(ops *common.DiagnoseOptions, r *DiagnoseReport) error {
+	if ops.CheckOptions.Config == "" {
+		ops.CheckOptions.Config = ops.Config
+	}
+
+	s := &diagnoseAllSummary{}
+	err := s.run(common.ArgDiagnoseInstall, r, func() error {
+		return DiagnoseInstall(ops.CheckOptions, r)
+	})
+	if err != nil && ops.FailFast {
+		return err
+	}
+
+	err = s.run(common.ArgDiagnoseNode, r, func() error {
+		return DiagnoseNode(ops, r)
+	})
+	if err != nil && ops.FailFast {
+		return err
+	}
+
+	var pods []string
+	err = s.run("pods", r, func() error {
+		if err := initPodDatabase(ops, r); err != nil {
+			return err
+		}
+		podList, err := QueryPodsFromDatabase(podsNamespace(ops))
+		if err != nil {
+			return r.Fail("pod", err)
+		}
+		refs := make([]podRef, 0, len(podList))
+		for _, pod := range podList {
+			refs = append(refs, podRef{namespace: pod.Namespace, name: pod.Name})
+			pods = append(pods, pod.Namespace+"/"+pod.Name)
+		}
+		if len(refs) == 0 {
+			r.Pass("pods", "find 0 pods in %s of database\n", namespaceScope(ops))
+			return nil
+		}
+		r.Pass("pods", "find %d pods in %s of database, %s\n", len(pods), namespaceScope(ops), namespacePodCounts(refs))
+		return nil
+	})
+	if err != nil && ops.FailFast {
+		return err
+	}
+
+	for _, pod := range pods {
+		namespace, name, _ := strings.Cut(pod, "/")
+		err = s.run("pod "+pod, r, func() error {
+			return diagnosePodStatus(namespace, name, ops.Since, r)
+		})
+		if err != nil && ops.FailFast {
+			return err
+		}
+	}
+
+	total := s.passed + len(s.failed)
+	if len(s.failed) == 0 {
+		r.Pass("summary", "%d/%d diagnoses passed\n", s.passed, total)
+		return nil
+	}
+	r.Failf("summary", "%d/%d diagnoses passed, %d failed: %s\n",
+		s.passed, total, len(s.failed), strings.Join(s.failed, ","))
+
+	err = fmt.Errorf("diagnose %s failed", strings.Join(s.failed, ","))
+	var exitErr *DiagnoseExitError
+	if errors.As(s.firstErr, &exitErr) {
+		err = newDiagnoseExitError(exitErr.ExitCode(), err)
+	}
+	return newDiagnoseError("summary", err)
+}
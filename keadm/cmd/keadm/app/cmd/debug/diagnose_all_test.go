@@ -0,0 +1,164 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func TestDiagnoseAll(t *testing.T) {
+	var nodeErr error
+	var diagnosedPods []string
+
+	patches := gomonkey.NewPatches()
+	defer patches.Reset()
+	patches.ApplyFunc(DiagnoseInstall, func(_ob *common.CheckOptions, _r *DiagnoseReport) error {
+		return nil
+	})
+	patches.ApplyFunc(DiagnoseNode, func(_ops *common.DiagnoseOptions, _r *DiagnoseReport) error {
+		return nodeErr
+	})
+	patches.ApplyFunc(initPodDatabase, func(_ops *common.DiagnoseOptions, _r *DiagnoseReport) error {
+		return nil
+	})
+	var queriedNamespace string
+	patches.ApplyFunc(QueryPodsFromDatabase, func(namespace string) ([]v1.Pod, error) {
+		queriedNamespace = namespace
+		return []v1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "broken"}},
+		}, nil
+	})
+	patches.ApplyFunc(diagnosePodStatus, func(namespace, podName string, _since time.Duration, _r *DiagnoseReport) error {
+		diagnosedPods = append(diagnosedPods, namespace+"/"+podName)
+		if podName == "broken" {
+			return errors.New("pod broken is not Ready")
+		}
+		return nil
+	})
+
+	t.Run("continue past failures", func(t *testing.T) {
+		nodeErr = newDiagnoseExitError(DiagnoseExitCodeNetwork, errors.New("cloudcore websocket connection failed"))
+		diagnosedPods = nil
+		defer func() {
+			nodeErr = nil
+		}()
+
+		r := newTestReport()
+		err := DiagnoseAll(NewDiagnoseOptions(), r)
+		require.EqualError(t, err, "diagnose node,pod test/broken failed")
+		var exitErr *DiagnoseExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, DiagnoseExitCodeNetwork, exitErr.ExitCode())
+		assert.Equal(t, []string{"default/nginx", "test/broken"}, diagnosedPods)
+
+		summary := r.Results[len(r.Results)-1]
+		assert.Equal(t, "summary", summary.Check)
+		assert.Equal(t, common.DiagnoseStatusFail, summary.Status)
+		assert.Equal(t, "3/5 diagnoses passed, 2 failed: node,pod test/broken", summary.Message)
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		nodeErr = errors.New("edgecore is not running")
+		diagnosedPods = nil
+		defer func() {
+			nodeErr = nil
+		}()
+
+		ops := NewDiagnoseOptions()
+		ops.FailFast = true
+		err := DiagnoseAll(ops, newTestReport())
+		require.EqualError(t, err, "edgecore is not running")
+		assert.Empty(t, diagnosedPods)
+	})
+
+	t.Run("diagnose all successful", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+		patches.ApplyFunc(diagnosePodStatus, func(_namespace, _podName string, _since time.Duration, _r *DiagnoseReport) error {
+			return nil
+		})
+
+		r := newTestReport()
+		err := DiagnoseAll(NewDiagnoseOptions(), r)
+		require.NoError(t, err)
+		summary := r.Results[len(r.Results)-1]
+		assert.Equal(t, common.DiagnoseStatusPass, summary.Status)
+		assert.Equal(t, "5/5 diagnoses passed", summary.Message)
+	})
+
+	t.Run("pods of all namespaces", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+		patches.ApplyFunc(diagnosePodStatus, func(_namespace, _podName string, _since time.Duration, _r *DiagnoseReport) error {
+			return nil
+		})
+
+		ops := NewDiagnoseOptions()
+		ops.AllNamespaces = true
+		r := newTestReport()
+		require.NoError(t, DiagnoseAll(ops, r))
+		assert.Equal(t, metav1.NamespaceAll, queriedNamespace)
+		assert.Contains(t, r.Results, common.DiagnoseResult{
+			Check:   "pods",
+			Status:  common.DiagnoseStatusPass,
+			Message: "find 2 pods in all namespaces of database, default: 1, test: 1",
+		})
+	})
+
+	t.Run("pods of the namespace", func(t *testing.T) {
+		ops := NewDiagnoseOptions()
+		ops.Namespace = "prod"
+		require.EqualError(t, DiagnoseAll(ops, newTestReport()), "diagnose pod test/broken failed")
+		assert.Equal(t, "prod", queriedNamespace)
+	})
+}
+
+func TestDiagnoseAllNamespaceFlags(t *testing.T) {
+	patches := gomonkey.ApplyMethod(Diagnose{}, "ExecuteDiagnose", func(_da Diagnose, _use string, _ops *common.DiagnoseOptions, _args []string) error {
+		return nil
+	})
+	defer patches.Reset()
+
+	cases := []struct {
+		args          []string
+		expectedAllNs bool
+		expectedNs    string
+	}{
+		{args: nil, expectedAllNs: true, expectedNs: "default"},
+		{args: []string{"-n", "prod"}, expectedAllNs: false, expectedNs: "prod"},
+		{args: []string{"-A"}, expectedAllNs: true, expectedNs: "default"},
+	}
+	for _, c := range cases {
+		ops := NewDiagnoseOptions()
+		cmd := NewSubDiagnose(Diagnose{Use: common.ArgDiagnoseAll}, ops)
+		cmd.SetArgs(c.args)
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, c.expectedAllNs, ops.AllNamespaces, c.args)
+		assert.Equal(t, c.expectedNs, ops.Namespace, c.args)
+	}
+}
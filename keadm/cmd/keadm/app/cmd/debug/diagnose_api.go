@@ -0,0 +1,86 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+// RunDiagnostics runs the diagnose of ops.Object with ops.Args the same as `keadm debug diagnose <object> [args]`,
+// and returns the recorded results instead of printing them, so the diagnose can be embedded in other programs.
+// ops is usually created by NewDiagnoseOptions, its Output, Template, Verbose, Quiet, ReportFile and MetricsFile which
+// only shape the output of the command are ignored. The checks which are not started yet are skipped once ctx is done.
+// The remediation hints of the failed checks are recorded in the results if ops.Explain is set.
+// The results are returned even if the diagnose fails, and the error is a *DiagnoseExitError carrying the exit code
+// of the command, e.g. DiagnoseExitCodeNetwork if cloudcore is unreachable.
+func RunDiagnostics(ctx context.Context, ops common.DiagnoseOptions) ([]common.DiagnoseResult, error) {
+	ops = copyDiagnoseOptions(ops)
+	// the output options do not apply, the results are recorded without printing anything
+	ops.Output, ops.Template, ops.Verbose, ops.Quiet = common.DiagnoseOutputJSON, "", false, false
+	if err := completeDiagnoseOptions(ops.Object, &ops); err != nil {
+		return nil, newDiagnoseExitError(DiagnoseExitCodeGeneric, err)
+	}
+
+	r := NewDiagnoseReport(ops.Object, ops.Output, io.Discard)
+	r.SetExplain(ops.Explain)
+	err := runDiagnostics(ctx, &ops, r)
+	return r.Results, err
+}
+
+// runDiagnostics runs the diagnose of ops.Object with ops.Args completed by the caller and records the results in r,
+// both RunDiagnostics and the diagnose commands run the diagnoses with it
+func runDiagnostics(ctx context.Context, ops *common.DiagnoseOptions, r *DiagnoseReport) error {
+	if _, ok := diagnoseRunners[ops.Object]; !ok {
+		return newDiagnoseExitError(DiagnoseExitCodeGeneric, fmt.Errorf("unsupported diagnose object %q", ops.Object))
+	}
+	if ops.Watch || ops.DryRun {
+		return newDiagnoseExitError(DiagnoseExitCodeGeneric, errors.New("watch and dry run are not supported by RunDiagnostics"))
+	}
+	if err := ctx.Err(); err != nil {
+		return newDiagnoseExitError(DiagnoseExitCodeGeneric, err)
+	}
+
+	r.SetContext(ctx)
+	// the cluster is diagnosed from the cloud side, which is not a node
+	if ops.Object != common.ArgDiagnoseCluster {
+		r.SetNodeName(diagnoseNodeName(ops))
+	}
+	return toDiagnoseExitError(runDiagnose(ops.Object, ops, ops.Args, r))
+}
+
+// copyDiagnoseOptions returns a deep copy of ops, so the diagnose does not change the options of the caller
+func copyDiagnoseOptions(ops common.DiagnoseOptions) common.DiagnoseOptions {
+	ops.ScoreWeights = maps.Clone(ops.ScoreWeights)
+	ops.Args = slices.Clone(ops.Args)
+	if ops.CheckOptions != nil {
+		checkOptions := *ops.CheckOptions
+		checkOptions.Registries = slices.Clone(checkOptions.Registries)
+		checkOptions.KernelModules = slices.Clone(checkOptions.KernelModules)
+		checkOptions.Sysctls = slices.Clone(checkOptions.Sysctls)
+		checkOptions.Only = slices.Clone(checkOptions.Only)
+		checkOptions.Skip = slices.Clone(checkOptions.Skip)
+		ops.CheckOptions = &checkOptions
+	}
+	return ops
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func TestRunDiagnostics(t *testing.T) {
+	patches := gomonkey.ApplyFunc(DiagnoseNode, func(_ops *common.DiagnoseOptions, r *DiagnoseReport) error {
+		r.Pass("edgecore", "edgecore is running\n")
+		return r.Run("cloudhub", func(w io.Writer) error {
+			return networkError(errors.New("cloudcore websocket connection failed"))
+		})
+	})
+	defer patches.Reset()
+
+	t.Run("results of the node", func(t *testing.T) {
+		ops := *NewDiagnoseOptions()
+		ops.Object = common.ArgDiagnoseNode
+		ops.Output = common.DiagnoseOutputText
+		results, err := RunDiagnostics(context.Background(), ops)
+		var exitErr *DiagnoseExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, DiagnoseExitCodeNetwork, exitErr.ExitCode())
+		require.Len(t, results, 2)
+		assert.Equal(t, common.DiagnoseResult{Check: "edgecore", Status: common.DiagnoseStatusPass, Message: "edgecore is running"}, results[0])
+		assert.Equal(t, "cloudhub", results[1].Check)
+		assert.Equal(t, common.DiagnoseStatusFail, results[1].Status)
+	})
+
+	t.Run("the options of the caller are not changed", func(t *testing.T) {
+		ops := *NewDiagnoseOptions()
+		ops.Object = common.ArgDiagnoseInstall
+		ops.Verbose = true
+		ops.CheckOptions.Skip = []string{"network"}
+		installPatches := gomonkey.ApplyFunc(DiagnoseInstall, func(opts *common.CheckOptions, _ *DiagnoseReport) error {
+			opts.Skip[0] = "dns"
+			return nil
+		})
+		defer installPatches.Reset()
+
+		_, err := RunDiagnostics(context.Background(), ops)
+		require.NoError(t, err)
+		assert.Empty(t, ops.CheckOptions.Config)
+		assert.False(t, ops.CheckOptions.Verbose)
+		assert.Equal(t, []string{"network"}, ops.CheckOptions.Skip)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		ops := *NewDiagnoseOptions()
+		ops.Object = common.ArgDiagnoseNode
+		_, err := RunDiagnostics(ctx, ops)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("unsupported object", func(t *testing.T) {
+		_, err := RunDiagnostics(context.Background(), common.DiagnoseOptions{Object: "fleet"})
+		require.ErrorContains(t, err, `unsupported diagnose object "fleet"`)
+	})
+
+	t.Run("watch", func(t *testing.T) {
+		_, err := RunDiagnostics(context.Background(), common.DiagnoseOptions{Object: common.ArgDiagnoseNode, Watch: true})
+		require.ErrorContains(t, err, "watch and dry run are not supported")
+	})
+}
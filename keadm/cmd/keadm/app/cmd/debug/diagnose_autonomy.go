@@ -0,0 +1,56 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// cloudHubConnection is the result of a connection check of cloudhub, err is nil if it is connected
+type cloudHubConnection struct {
+	check   string
+	message string
+	err     error
+}
+
+// diagnoseEdgeAutonomy reports whether edgecore serves the cached pods autonomously while all the
+// connections to cloudhub fail, i.e. edge autonomy. The failures are recorded as warnings with the
+// explanation in that case, since it is a distinct and sometimes intended state rather than a broken node.
+// It returns false without recording anything if any connection succeeds or the edge database caches no pods.
+func diagnoseEdgeAutonomy(r *DiagnoseReport, conns []cloudHubConnection) bool {
+	for _, conn := range conns {
+		if conn.err == nil {
+			return false
+		}
+	}
+	pods, err := QueryPodsFromDatabase(metav1.NamespaceAll)
+	if err != nil {
+		r.Debugf("query the cached pods for edge autonomy failed: %v\n", err)
+		return false
+	}
+	if len(pods) == 0 {
+		return false
+	}
+
+	for _, conn := range conns {
+		r.Warnf(conn.check, "%v\n", conn.err)
+	}
+	r.Warnf("autonomy", "edgecore is disconnected from cloudcore but serves %d cached pods in edge autonomy mode, "+
+		"the pods keep running from the edge database, while the changes from the cloud are not synced and "+
+		"the status of the node and pods is not reported until cloudhub is reachable again\n", len(pods))
+	return true
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func TestDiagnoseEdgeAutonomy(t *testing.T) {
+	var cachedPods []v1.Pod
+	var queryErr error
+	patches := gomonkey.ApplyFunc(QueryPodsFromDatabase, func(_namespace string) ([]v1.Pod, error) {
+		return cachedPods, queryErr
+	})
+	defer patches.Reset()
+
+	disconnected := []cloudHubConnection{
+		{check: "cloudhub", err: errors.New("cloudcore websocket connection failed, connection refused")},
+		{check: "cloudhub-quic", err: errors.New("cloudcore quic connection failed, timeout")},
+	}
+
+	t.Run("serving the cached pods", func(t *testing.T) {
+		cachedPods = []v1.Pod{*newLivePod("nginx-1", v1.PodRunning, nil)}
+		r := newTestReport()
+		assert.True(t, diagnoseEdgeAutonomy(r, disconnected))
+		assert.Equal(t, []string{"cloudhub", "cloudhub-quic", "autonomy"}, []string{r.Results[0].Check, r.Results[1].Check, r.Results[2].Check})
+		for _, result := range r.Results {
+			assert.Equal(t, common.DiagnoseStatusWarn, result.Status)
+		}
+		assert.Equal(t, "cloudcore websocket connection failed, connection refused", r.Results[0].Message)
+		assert.Contains(t, r.Results[2].Message, "serves 1 cached pods in edge autonomy mode")
+	})
+
+	t.Run("one of the connections succeeds", func(t *testing.T) {
+		r := newTestReport()
+		assert.False(t, diagnoseEdgeAutonomy(r, []cloudHubConnection{disconnected[0], {check: "cloudhub-quic"}}))
+		assert.Empty(t, r.Results)
+	})
+
+	t.Run("no cached pods", func(t *testing.T) {
+		cachedPods = nil
+		r := newTestReport()
+		assert.False(t, diagnoseEdgeAutonomy(r, disconnected))
+		assert.Empty(t, r.Results)
+	})
+
+	t.Run("database is not readable", func(t *testing.T) {
+		cachedPods, queryErr = []v1.Pod{*newLivePod("nginx-1", v1.PodRunning, nil)}, errors.New("read database fail")
+		r := newTestReport()
+		assert.False(t, diagnoseEdgeAutonomy(r, disconnected))
+		assert.Empty(t, r.Results)
+	})
+}
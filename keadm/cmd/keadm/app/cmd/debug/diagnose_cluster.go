@@ -0,0 +1,206 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/util/files"
+)
+
+// cloudCoreCertSecrets are the secrets in the kubeedge namespace which cloudcore stores the DER encoded CA
+// and its certificate in. The edge certificates are issued by the CA, their expiry is not exposed to the cloud.
+var cloudCoreCertSecrets = []struct {
+	name string
+	key  string
+}{
+	{name: "casecret", key: "cadata"},
+	{name: "cloudcoresecret", key: "cloudcoredata"},
+}
+
+// DiagnoseCluster diagnoses the edge nodes of the cluster from the cloud side with the apiserver of ops.KubeConfig,
+// it reports the edge nodes which are NotReady and the pods on the edge nodes which are failing by their status
+// reported to the cloud, and the expiry of the certificates of cloudcore.
+func DiagnoseCluster(ops *common.DiagnoseOptions, r *DiagnoseReport) error {
+	if !files.FileExists(ops.KubeConfig) {
+		return r.Fail("cluster", fmt.Errorf("kubeconfig %s is not exists, the cluster diagnose requires a kubeconfig of the apiserver", ops.KubeConfig))
+	}
+	cli, err := util.KubeClient(ops.KubeConfig)
+	if err != nil {
+		return r.Fail("cluster", fmt.Errorf("failed to create KubeClient, error: %v", err))
+	}
+	return diagnoseCluster(cli, ops.CheckOptions.CertWarnDays, r)
+}
+
+// diagnoseCluster diagnoses the edge nodes and their pods in the apiserver of cli, the failures of the nodes
+// and the pods do not stop the diagnose of the others, and a summary of the edge nodes is recorded at last
+func diagnoseCluster(cli kubernetes.Interface, certWarnDays int, r *DiagnoseReport) error {
+	nodes, err := cli.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{LabelSelector: constants.EdgeNodeRoleKey})
+	if err != nil {
+		return r.Fail("nodes", fmt.Errorf("list edge nodes from the apiserver failed: %v", err))
+	}
+	if len(nodes.Items) == 0 {
+		return r.Fail("nodes", fmt.Errorf("no edge nodes labeled %s are found in the apiserver", constants.EdgeNodeRoleKey))
+	}
+	pods, err := cli.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return r.Fail("pods", fmt.Errorf("list pods from the apiserver failed: %v", err))
+	}
+	podsByNode := make(map[string][]v1.Pod)
+	for _, pod := range pods.Items {
+		podsByNode[pod.Spec.NodeName] = append(podsByNode[pod.Spec.NodeName], pod)
+	}
+
+	sort.Slice(nodes.Items, func(i, j int) bool {
+		return nodes.Items[i].Name < nodes.Items[j].Name
+	})
+	var notReady, failing []string
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if reason := nodeNotReadyReason(node); reason != "" {
+			notReady = append(notReady, node.Name)
+			r.Failf("node", "Node %s is NotReady, %s\n", node.Name, reason)
+		} else {
+			r.Pass("node", "Node %s is Ready\n", node.Name)
+		}
+
+		nodePods := podsByNode[node.Name]
+		sort.Slice(nodePods, func(i, j int) bool {
+			return nodePods[i].Namespace+"/"+nodePods[i].Name < nodePods[j].Namespace+"/"+nodePods[j].Name
+		})
+		var failed int
+		for j := range nodePods {
+			if reason := podFailureReason(&nodePods[j]); reason != "" {
+				failed++
+				r.Failf("pod", "Pod %s/%s on node %s is failing, %s\n", nodePods[j].Namespace, nodePods[j].Name, node.Name, reason)
+			}
+		}
+		if failed > 0 {
+			failing = append(failing, node.Name)
+		}
+		r.Debugf("node %s: %d/%d pods are healthy\n", node.Name, len(nodePods)-failed, len(nodePods))
+	}
+
+	certErr := r.Run("certs", func(w io.Writer) error {
+		return checkCloudCoreCerts(w, cli, certWarnDays)
+	})
+
+	const summary = "%d/%d edge nodes are Ready, %d edge nodes have failing pods\n"
+	if len(notReady) > 0 || len(failing) > 0 {
+		r.Failf("cluster", summary, len(nodes.Items)-len(notReady), len(nodes.Items), len(failing))
+		var errs []string
+		if len(notReady) > 0 {
+			errs = append(errs, fmt.Sprintf("edge nodes %s are NotReady", strings.Join(notReady, ",")))
+		}
+		if len(failing) > 0 {
+			errs = append(errs, fmt.Sprintf("edge nodes %s have failing pods", strings.Join(failing, ",")))
+		}
+		return newDiagnoseError("cluster", errors.New(strings.Join(errs, ", ")))
+	}
+	r.Pass("cluster", summary, len(nodes.Items), len(nodes.Items), 0)
+	return certErr
+}
+
+// nodeNotReadyReason returns why the node is NotReady by its Ready condition, or "" if the node is Ready.
+// The condition is Unknown once cloudcore stops receiving the heartbeats of the edge node.
+func nodeNotReadyReason(node *v1.Node) string {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type != v1.NodeReady {
+			continue
+		}
+		if condition.Status == v1.ConditionTrue {
+			return ""
+		}
+		heartbeat := "never"
+		if !condition.LastHeartbeatTime.IsZero() {
+			heartbeat = fmt.Sprintf("%s (%v ago)", condition.LastHeartbeatTime.Format(time.RFC3339),
+				time.Since(condition.LastHeartbeatTime.Time).Round(time.Second))
+		}
+		return fmt.Sprintf("Ready condition is %s, reason: %s, last heartbeat: %s", condition.Status, condition.Reason, heartbeat)
+	}
+	return "the Ready condition is not reported"
+}
+
+// podFailureReason returns why the pod is failing by its status, or "" if the pod is Ready or Succeeded.
+// The waiting or terminated reason of the first container which is not ready is preferred, e.g. CrashLoopBackOff.
+func podFailureReason(pod *v1.Pod) string {
+	switch pod.Status.Phase {
+	case v1.PodSucceeded:
+		return ""
+	case v1.PodFailed:
+		return fmt.Sprintf("phase is Failed, reason: %s", pod.Status.Reason)
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady && condition.Status == v1.ConditionTrue {
+			return ""
+		}
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Ready {
+			continue
+		}
+		if waiting := status.State.Waiting; waiting != nil {
+			return fmt.Sprintf("container %s is waiting, reason: %s", status.Name, waiting.Reason)
+		}
+		if terminated := status.State.Terminated; terminated != nil {
+			return fmt.Sprintf("container %s is terminated, reason: %s, exit code: %d", status.Name, terminated.Reason, terminated.ExitCode)
+		}
+	}
+	return fmt.Sprintf("phase is %s, not Ready", pod.Status.Phase)
+}
+
+// checkCloudCoreCerts checks the expiry of the CA and the certificate of cloudcore in the secrets of cloudCoreCertSecrets,
+// a missing secret is skipped since cloudcore may be configured with the certificate files instead
+func checkCloudCoreCerts(w io.Writer, cli kubernetes.Interface, warnDays int) error {
+	var errs []error
+	for _, s := range cloudCoreCertSecrets {
+		name := constants.SystemNamespace + "/" + s.name
+		secret, err := cli.CoreV1().Secrets(constants.SystemNamespace).Get(context.TODO(), s.name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			fmt.Fprintf(w, "secret %s is not found, skip its certificate\n", name)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("get secret %s failed: %v", name, err))
+			continue
+		}
+		cert, err := x509.ParseCertificate(secret.Data[s.key])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("parse certificate %s of secret %s failed: %v", s.key, name, err))
+			continue
+		}
+		if err := checkCertNotAfter(w, name, cert, warnDays); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func newClusterNode(name string, ready v1.ConditionStatus) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{constants.EdgeNodeRoleKey: ""}},
+		Status: v1.NodeStatus{Conditions: []v1.NodeCondition{
+			{Type: v1.NodeReady, Status: ready, Reason: "EdgeReady"},
+		}},
+	}
+}
+
+func newClusterPod(name, nodeName string, status v1.PodStatus) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       v1.PodSpec{NodeName: nodeName},
+		Status:     status,
+	}
+}
+
+func readyPodStatus() v1.PodStatus {
+	return v1.PodStatus{
+		Phase:      v1.PodRunning,
+		Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+	}
+}
+
+func newCertSecret(t *testing.T, name, key string, notAfter time.Time) *v1.Secret {
+	cert, _ := writeTestCert(t, filepath.Join(t.TempDir(), name+".crt"), notAfter)
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: constants.SystemNamespace},
+		Data:       map[string][]byte{key: cert.Raw},
+	}
+}
+
+func TestDiagnoseCluster(t *testing.T) {
+	t.Run("healthy cluster", func(t *testing.T) {
+		cli := fake.NewSimpleClientset(
+			newClusterNode("edge-1", v1.ConditionTrue),
+			newClusterPod("nginx", "edge-1", readyPodStatus()),
+			newClusterPod("job", "edge-1", v1.PodStatus{Phase: v1.PodSucceeded}),
+			newCertSecret(t, "casecret", "cadata", time.Now().Add(365*24*time.Hour)),
+		)
+		buf := &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnoseCluster, common.DiagnoseOutputText, buf)
+		require.NoError(t, diagnoseCluster(cli, 30, r))
+		assert.Contains(t, buf.String(), "Node edge-1 is Ready\n")
+		assert.Contains(t, buf.String(), "certificate kubeedge/casecret NotAfter:")
+		assert.Contains(t, buf.String(), "secret kubeedge/cloudcoresecret is not found, skip its certificate")
+		assert.Contains(t, buf.String(), "1/1 edge nodes are Ready, 0 edge nodes have failing pods\n")
+	})
+
+	t.Run("not ready nodes and failing pods", func(t *testing.T) {
+		cli := fake.NewSimpleClientset(
+			newClusterNode("edge-1", v1.ConditionTrue),
+			newClusterNode("edge-2", v1.ConditionUnknown),
+			newClusterPod("nginx", "edge-1", v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{{
+					Name:  "nginx",
+					State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				}},
+			}),
+			newClusterPod("redis", "edge-1", readyPodStatus()),
+			// the pods of the cloud nodes are not diagnosed
+			newClusterPod("coredns", "master", v1.PodStatus{Phase: v1.PodFailed}),
+			&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "master"}},
+		)
+		r := newTestReport()
+		err := diagnoseCluster(cli, 30, r)
+		require.EqualError(t, err, "edge nodes edge-2 are NotReady, edge nodes edge-1 have failing pods")
+
+		assert.Equal(t, []common.DiagnoseResult{
+			{Check: "node", Status: common.DiagnoseStatusPass, Message: "Node edge-1 is Ready"},
+			{Check: "pod", Status: common.DiagnoseStatusFail,
+				Message: "Pod default/nginx on node edge-1 is failing, container nginx is waiting, reason: CrashLoopBackOff"},
+			{Check: "node", Status: common.DiagnoseStatusFail,
+				Message: "Node edge-2 is NotReady, Ready condition is Unknown, reason: EdgeReady, last heartbeat: never"},
+		}, r.Results[:3])
+		last := r.Results[len(r.Results)-1]
+		assert.Equal(t, "cluster", last.Check)
+		assert.Equal(t, "1/2 edge nodes are Ready, 1 edge nodes have failing pods", last.Message)
+	})
+
+	t.Run("expiring certificate", func(t *testing.T) {
+		cli := fake.NewSimpleClientset(
+			newClusterNode("edge-1", v1.ConditionTrue),
+			newCertSecret(t, "cloudcoresecret", "cloudcoredata", time.Now().Add(10*24*time.Hour)),
+		)
+		r := newTestReport()
+		require.NoError(t, diagnoseCluster(cli, 30, r))
+		_, summary := r.summarize()
+		assert.Equal(t, 1, summary.Warn)
+	})
+
+	t.Run("no edge nodes", func(t *testing.T) {
+		cli := fake.NewSimpleClientset(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "master"}})
+		err := diagnoseCluster(cli, 30, newTestReport())
+		require.ErrorContains(t, err, "no edge nodes labeled node-role.kubernetes.io/edge are found")
+	})
+}
+
+func TestPodFailureReason(t *testing.T) {
+	assert.Empty(t, podFailureReason(newClusterPod("nginx", "edge-1", readyPodStatus())))
+	assert.Equal(t, "phase is Failed, reason: Evicted",
+		podFailureReason(newClusterPod("nginx", "edge-1", v1.PodStatus{Phase: v1.PodFailed, Reason: "Evicted"})))
+	assert.Equal(t, "container nginx is terminated, reason: Error, exit code: 1",
+		podFailureReason(newClusterPod("nginx", "edge-1", v1.PodStatus{
+			Phase: v1.PodRunning,
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name:  "nginx",
+				State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+			}},
+		})))
+	assert.Equal(t, "phase is Pending, not Ready", podFailureReason(newClusterPod("nginx", "edge-1", v1.PodStatus{Phase: v1.PodPending})))
+}
+
+func TestDiagnoseClusterWithoutKubeConfig(t *testing.T) {
+	ops := NewDiagnoseOptions()
+	ops.KubeConfig = filepath.Join(t.TempDir(), "config")
+	err := DiagnoseCluster(ops, newTestReport())
+	require.ErrorContains(t, err, "the cluster diagnose requires a kubeconfig of the apiserver")
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeedge/api/apis/common/constants"
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+)
+
+// redactedValue replaces the secrets of the edgecore config in the printed config
+const redactedValue = "<redacted>"
+
+// NewDiagnoseConfig returns the command printing the effective edgecore config, do is shared with the parent command.
+func NewDiagnoseConfig(do *common.DiagnoseOptions) *cobra.Command {
+	var diff bool
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Print the effective edgecore config with the defaults applied and the secrets redacted",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return PrintEffectiveConfig(cmd.OutOrStdout(), do.Config, do.Output, diff)
+		},
+	}
+	cmd.Flags().StringVarP(&do.Config, common.EdgecoreConfig, "c", do.Config,
+		fmt.Sprintf("Specify configuration file, default is %s", constants.EdgecoreConfigPath))
+	cmd.Flags().BoolVar(&diff, "diff", diff,
+		"print only the values of the effective config that are not set in the config file, i.e. the values from the defaults")
+	return cmd
+}
+
+// PrintEffectiveConfig prints the edgecore config parsed from the file config with the defaults applied,
+// the secrets are redacted. The config is printed as YAML in text mode. If diff is set,
+// only the values which are not set in the file and come from the defaults are printed.
+func PrintEffectiveConfig(w io.Writer, config, output string, diff bool) error {
+	if err := ValidateDiagnoseOutput(output); err != nil {
+		return err
+	}
+	if !util.EdgecoreConfigExists(config) {
+		return fmt.Errorf("edge config %s is not exists", config)
+	}
+	edgeConfig, err := util.ParseEdgecoreConfig(config)
+	if err != nil {
+		return fmt.Errorf("parse edgecore config %s failed: %v", config, err)
+	}
+	redactEdgecoreConfig(edgeConfig)
+
+	if !diff {
+		return writeConfigValue(w, output, edgeConfig)
+	}
+	data, err := util.ReadEdgecoreConfig(config)
+	if err != nil {
+		return fmt.Errorf("read edgecore config %s failed: %v", config, err)
+	}
+	defaulted, err := defaultedConfigFields(edgeConfig, data)
+	if err != nil {
+		return err
+	}
+	if output != common.DiagnoseOutputText {
+		return writeConfigValue(w, output, defaulted)
+	}
+	fields := make([]string, 0, len(defaulted))
+	for field := range defaulted {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tDEFAULT")
+	for _, field := range fields {
+		value, err := json.Marshal(defaulted[field])
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %v", field, err)
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", field, value)
+	}
+	return tw.Flush()
+}
+
+// redactEdgecoreConfig replaces the tokens and the passwords set in the edgecore config
+func redactEdgecoreConfig(c *v1alpha2.EdgeCoreConfig) {
+	if eh := c.Modules.EdgeHub; eh != nil && eh.Token != "" {
+		eh.Token = redactedValue
+	}
+	if eb := c.Modules.EventBus; eb != nil && eb.MqttPassword != "" {
+		eb.MqttPassword = redactedValue
+	}
+}
+
+// writeConfigValue writes v as YAML in text and yaml mode, and as JSON in json mode
+func writeConfigValue(w io.Writer, output string, v interface{}) error {
+	var data []byte
+	var err error
+	if output == common.DiagnoseOutputJSON {
+		data, err = json.MarshalIndent(v, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(v)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal edgecore config: %v", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// defaultedConfigFields returns the fields of the effective config which are not set in the config file data,
+// the fields are keyed by their dotted paths, e.g. modules.edgeHub.heartbeat. A list is a single field.
+func defaultedConfigFields(effective *v1alpha2.EdgeCoreConfig, data []byte) (map[string]interface{}, error) {
+	effectiveData, err := json.Marshal(effective)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal edgecore config: %v", err)
+	}
+	var effectiveFields map[string]interface{}
+	if err := json.Unmarshal(effectiveData, &effectiveFields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal edgecore config: %v", err)
+	}
+	var fileFields map[string]interface{}
+	if err := yaml.Unmarshal(data, &fileFields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal edgecore config file: %v", err)
+	}
+
+	defaulted := make(map[string]interface{})
+	collectDefaultedFields("", effectiveFields, fileFields, defaulted)
+	return defaulted, nil
+}
+
+// collectDefaultedFields adds the leaf fields of effective which are missing in file to defaulted
+func collectDefaultedFields(prefix string, effective, file map[string]interface{}, defaulted map[string]interface{}) {
+	for key, value := range effective {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		fileValue, ok := file[key]
+		if !ok {
+			flattenConfigFields(path, value, defaulted)
+			continue
+		}
+		nested, isMap := value.(map[string]interface{})
+		fileNested, fileIsMap := fileValue.(map[string]interface{})
+		if isMap && fileIsMap {
+			collectDefaultedFields(path, nested, fileNested, defaulted)
+		}
+	}
+}
+
+// flattenConfigFields adds the leaf fields of value to fields by their dotted paths under path
+func flattenConfigFields(path string, value interface{}, fields map[string]interface{}) {
+	nested, ok := value.(map[string]interface{})
+	if !ok || len(nested) == 0 {
+		fields[path] = value
+		return
+	}
+	for key, v := range nested {
+		flattenConfigFields(path+"."+key, v, fields)
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+)
+
+const testEdgecoreConfig = `modules:
+  edgeHub:
+    token: secret-token
+    heartbeat: 20
+  eventBus:
+    mqttPassword: secret-password
+`
+
+func writeTestEdgecoreConfig(t *testing.T) string {
+	t.Helper()
+	config := filepath.Join(t.TempDir(), "edgecore.yaml")
+	require.NoError(t, os.WriteFile(config, []byte(testEdgecoreConfig), 0600))
+	return config
+}
+
+func TestPrintEffectiveConfig(t *testing.T) {
+	config := writeTestEdgecoreConfig(t)
+
+	t.Run("effective config", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, PrintEffectiveConfig(buf, config, common.DiagnoseOutputText, false))
+		assert.NotContains(t, buf.String(), "secret")
+
+		got := &v1alpha2.EdgeCoreConfig{}
+		require.NoError(t, yaml.Unmarshal(buf.Bytes(), got))
+		assert.Equal(t, redactedValue, got.Modules.EdgeHub.Token)
+		assert.Equal(t, redactedValue, got.Modules.EventBus.MqttPassword)
+		assert.Equal(t, int32(20), got.Modules.EdgeHub.Heartbeat)
+		// the values not set in the file are the defaults
+		defaults := v1alpha2.NewDefaultEdgeCoreConfig()
+		assert.Equal(t, defaults.Modules.EdgeHub.WebSocket.Server, got.Modules.EdgeHub.WebSocket.Server)
+		assert.Equal(t, defaults.DataBase.DataSource, got.DataBase.DataSource)
+	})
+
+	t.Run("effective config in json", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, PrintEffectiveConfig(buf, config, common.DiagnoseOutputJSON, false))
+		got := &v1alpha2.EdgeCoreConfig{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), got))
+		assert.Equal(t, redactedValue, got.Modules.EdgeHub.Token)
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, PrintEffectiveConfig(buf, config, common.DiagnoseOutputText, true))
+		assert.Contains(t, buf.String(), "FIELD")
+		assert.Contains(t, buf.String(), "modules.edgeHub.websocket.server")
+		assert.Contains(t, buf.String(), "database.dataSource")
+		assert.NotContains(t, buf.String(), "modules.edgeHub.heartbeat")
+		assert.NotContains(t, buf.String(), "modules.edgeHub.token")
+	})
+
+	t.Run("diff in yaml", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, PrintEffectiveConfig(buf, config, common.DiagnoseOutputYAML, true))
+		var got map[string]interface{}
+		require.NoError(t, yaml.Unmarshal(buf.Bytes(), &got))
+		assert.Equal(t, v1alpha2.NewDefaultEdgeCoreConfig().DataBase.DataSource, got["database.dataSource"])
+		assert.NotContains(t, got, "modules.edgeHub.heartbeat")
+	})
+
+	t.Run("diff of the config of stdin", func(t *testing.T) {
+		patches := gomonkey.ApplyFunc(util.ReadEdgecoreConfig, func(path string) ([]byte, error) {
+			assert.Equal(t, util.EdgecoreConfigStdin, path)
+			return []byte(testEdgecoreConfig), nil
+		})
+		defer patches.Reset()
+		buf := &bytes.Buffer{}
+		require.NoError(t, PrintEffectiveConfig(buf, util.EdgecoreConfigStdin, common.DiagnoseOutputText, true))
+		assert.Contains(t, buf.String(), "database.dataSource")
+		assert.NotContains(t, buf.String(), "modules.edgeHub.heartbeat")
+	})
+
+	t.Run("edge config is not exists", func(t *testing.T) {
+		err := PrintEffectiveConfig(&bytes.Buffer{}, filepath.Join(t.TempDir(), "not-exists.yaml"), common.DiagnoseOutputText, false)
+		require.ErrorContains(t, err, "is not exists")
+	})
+
+	t.Run("unsupported output format", func(t *testing.T) {
+		err := PrintEffectiveConfig(&bytes.Buffer{}, config, "wide", false)
+		require.ErrorContains(t, err, "unsupported output format")
+	})
+}
+
+func TestCollectDefaultedFields(t *testing.T) {
+	effective := map[string]interface{}{
+		"a": map[string]interface{}{"b": 1.0, "c": map[string]interface{}{"d": "x"}, "e": map[string]interface{}{}},
+		"f": []interface{}{"y"},
+	}
+	file := map[string]interface{}{
+		"a": map[string]interface{}{"b": 2.0},
+	}
+	defaulted := make(map[string]interface{})
+	collectDefaultedFields("", effective, file, defaulted)
+	assert.Equal(t, map[string]interface{}{
+		"a.c.d": "x",
+		"a.e":   map[string]interface{}{},
+		"f":     []interface{}{"y"},
+	}, defaulted)
+}
+
+func TestNewDiagnoseConfig(t *testing.T) {
+	config := writeTestEdgecoreConfig(t)
+	cmd := NewDiagnose()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"config", "-c", config, "--diff"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, buf.String(), "modules.edgeHub.websocket.server")
+}
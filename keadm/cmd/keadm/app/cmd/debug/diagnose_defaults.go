@@ -0,0 +1,138 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// diagnoseDefaultsFile returns ~/.keadm/diagnose.yaml, which is empty if the home dir is unknown
+func diagnoseDefaultsFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".keadm", "diagnose.yaml")
+}
+
+// applyDiagnoseDefaults sets the flags of cmd which are not set on the command line to the values of the defaults file,
+// so the flags always win over the file. The keys of the file are the flag names, e.g. timeout, min-disk-free and
+// cert-warn-days, the lists are either sequences or comma-separated strings, and the maps, e.g. score-weights, are either
+// mappings or comma-separated key=value strings. The file is shared by all the diagnose subcommands, so the flags of
+// the other subcommands are ignored, while the keys which are no diagnose flags are rejected.
+// A missing file is ignored unless required.
+func applyDiagnoseDefaults(cmd *cobra.Command, file string, required bool) error {
+	if file == "" {
+		return nil
+	}
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) && !required {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read diagnose defaults %s failed: %v", file, err)
+	}
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("parse diagnose defaults %s failed: %v", file, err)
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !isDiagnoseFlag(cmd, key) {
+			return fmt.Errorf("unknown option %s in diagnose defaults %s, the options are the flags of the diagnose commands", key, file)
+		}
+		flag := cmd.Flags().Lookup(key)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		value, err := diagnoseDefaultValue(values[key])
+		if err != nil {
+			return fmt.Errorf("invalid %s in diagnose defaults %s: %v", key, file, err)
+		}
+		if err := cmd.Flags().Set(key, value); err != nil {
+			return fmt.Errorf("invalid %s in diagnose defaults %s: %v", key, file, err)
+		}
+	}
+	return nil
+}
+
+// isDiagnoseFlag returns whether name is a flag of cmd or of any other diagnose subcommand
+func isDiagnoseFlag(cmd *cobra.Command, name string) bool {
+	if cmd.Flags().Lookup(name) != nil {
+		return true
+	}
+	if parent := cmd.Parent(); parent != nil {
+		for _, sub := range parent.Commands() {
+			if sub.Flags().Lookup(name) != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// diagnoseDefaultValue returns the value of the defaults file in the format of the flag,
+// the numbers are converted back from float64, the lists are joined with commas and the maps are joined as key=value pairs.
+func diagnoseDefaultValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, err := diagnoseDefaultValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ","), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		items := make([]string, 0, len(v))
+		for _, key := range keys {
+			s, err := diagnoseDefaultValue(v[key])
+			if err != nil {
+				return "", err
+			}
+			items = append(items, key+"="+s)
+		}
+		return strings.Join(items, ","), nil
+	}
+	return "", fmt.Errorf("unsupported value %v", value)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDefaultsTestCommand() (*cobra.Command, *int, *string, *[]string) {
+	var timeout int
+	var server string
+	var dnsIPs []string
+	parent := &cobra.Command{Use: "diagnose"}
+	cmd := &cobra.Command{Use: "node"}
+	cmd.Flags().IntVar(&timeout, "timeout", 3, "")
+	cmd.Flags().StringVar(&server, "cloud-hub-server", "", "")
+	cmd.Flags().StringSliceVar(&dnsIPs, "dns-ip", nil, "")
+	install := &cobra.Command{Use: "install"}
+	install.Flags().Int("min-disk-free", 0, "")
+	parent.AddCommand(cmd, install)
+	return cmd, &timeout, &server, &dnsIPs
+}
+
+func writeDefaultsFile(t *testing.T, content string) string {
+	file := filepath.Join(t.TempDir(), "diagnose.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(content), 0600))
+	return file
+}
+
+func TestApplyDiagnoseDefaults(t *testing.T) {
+	t.Run("defaults of the file", func(t *testing.T) {
+		cmd, timeout, server, dnsIPs := newDefaultsTestCommand()
+		file := writeDefaultsFile(t, "timeout: 5\ncloud-hub-server: 10.0.0.1:10000\ndns-ip: [8.8.8.8, 1.1.1.1]\nmin-disk-free: 2048\n")
+		require.NoError(t, applyDiagnoseDefaults(cmd, file, true))
+		assert.Equal(t, 5, *timeout)
+		assert.Equal(t, "10.0.0.1:10000", *server)
+		assert.Equal(t, []string{"8.8.8.8", "1.1.1.1"}, *dnsIPs)
+	})
+
+	t.Run("flags win over the file", func(t *testing.T) {
+		cmd, timeout, server, _ := newDefaultsTestCommand()
+		require.NoError(t, cmd.Flags().Parse([]string{"--timeout", "10"}))
+		file := writeDefaultsFile(t, "timeout: 5\ncloud-hub-server: 10.0.0.1:10000\n")
+		require.NoError(t, applyDiagnoseDefaults(cmd, file, true))
+		assert.Equal(t, 10, *timeout)
+		assert.Equal(t, "10.0.0.1:10000", *server)
+	})
+
+	t.Run("map of the file", func(t *testing.T) {
+		cmd, _, _, _ := newDefaultsTestCommand()
+		var weights map[string]int
+		cmd.Flags().StringToIntVar(&weights, "score-weights", nil, "")
+		file := writeDefaultsFile(t, "score-weights:\n  cloudhub: 10\n  swap: 0\n")
+		require.NoError(t, applyDiagnoseDefaults(cmd, file, true))
+		assert.Equal(t, map[string]int{"cloudhub": 10, "swap": 0}, weights)
+	})
+
+	t.Run("unknown option", func(t *testing.T) {
+		cmd, _, _, _ := newDefaultsTestCommand()
+		file := writeDefaultsFile(t, "timeuot: 5\n")
+		require.ErrorContains(t, applyDiagnoseDefaults(cmd, file, true), "unknown option timeuot")
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		cmd, _, _, _ := newDefaultsTestCommand()
+		file := writeDefaultsFile(t, "timeout: fast\n")
+		require.ErrorContains(t, applyDiagnoseDefaults(cmd, file, true), "invalid timeout")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		cmd, timeout, _, _ := newDefaultsTestCommand()
+		file := filepath.Join(t.TempDir(), "diagnose.yaml")
+		require.NoError(t, applyDiagnoseDefaults(cmd, file, false))
+		assert.Equal(t, 3, *timeout)
+		require.ErrorContains(t, applyDiagnoseDefaults(cmd, file, true), "read diagnose defaults")
+	})
+}
+
+func TestDiagnoseDefaultValue(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		expected string
+	}{
+		{value: "2s", expected: "2s"},
+		{value: true, expected: "true"},
+		{value: float64(100), expected: "100"},
+		{value: 0.5, expected: "0.5"},
+		{value: []interface{}{"a", float64(1)}, expected: "a,1"},
+		{value: map[string]interface{}{"swap": float64(0), "cloudhub": float64(10)}, expected: "cloudhub=10,swap=0"},
+	}
+	for _, c := range cases {
+		value, err := diagnoseDefaultValue(c.value)
+		require.NoError(t, err)
+		assert.Equal(t, c.expected, value)
+	}
+	_, err := diagnoseDefaultValue(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+// resourceTypeDeployment is the resource type of the deployments in the keys of the edge database
+const resourceTypeDeployment = "deployment"
+
+// DiagnoseDeployment diagnoses the pods of the deployment in the database.
+// The pods are matched by the selector of the deployment if the deployment is cached,
+// otherwise by the name prefix "<name>-", and the desired and ready replicas are summarized at the end.
+func DiagnoseDeployment(ops *common.DiagnoseOptions, name string, r *DiagnoseReport) error {
+	if err := initPodDatabase(ops, r); err != nil {
+		return err
+	}
+
+	deploy, err := QueryDeploymentFromDatabase(ops.Namespace, name)
+	if err != nil {
+		return r.Fail("deployment", err)
+	}
+
+	var selector labels.Selector
+	if deploy != nil {
+		if deploy.Spec.Selector == nil {
+			return r.Fail("deployment", fmt.Errorf("deployment %s has no selector", name))
+		}
+		selector, err = metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+		if err != nil {
+			return r.Fail("deployment", fmt.Errorf("invalid selector of deployment %s: %v", name, err))
+		}
+		r.Pass("deployment", "Deployment %s is exist, match pods by selector %s\n", name, selector)
+	} else {
+		r.Printf("Deployment %s is not in database, match pods by name prefix %s-\n", name, name)
+	}
+
+	pods, err := QueryPodsFromDatabase(ops.Namespace)
+	if err != nil {
+		return r.Fail("pod", err)
+	}
+	var podNames []string
+	for _, pod := range pods {
+		if selector != nil && selector.Matches(labels.Set(pod.Labels)) ||
+			selector == nil && strings.HasPrefix(pod.Name, name+"-") {
+			podNames = append(podNames, pod.Name)
+		}
+	}
+
+	if len(podNames) == 0 && deploy == nil {
+		return r.Fail("pod", fmt.Errorf("not find deployment %s or its pods in namespace %s", name, ops.Namespace))
+	}
+
+	notReady, err := diagnosePodList(ops.Namespace, podNames, ops.Since, r)
+	if err != nil {
+		return err
+	}
+
+	// the desired replicas are unknown if the deployment is not cached, take the pods found as desired
+	desired := len(podNames)
+	if deploy != nil {
+		desired = 1
+		if deploy.Spec.Replicas != nil {
+			desired = int(*deploy.Spec.Replicas)
+		}
+	}
+	ready := len(podNames) - len(notReady)
+
+	const summary = "deployment %s: %d/%d replicas are Ready\n"
+	if ready < desired {
+		r.Failf("replicas", summary, name, ready, desired)
+		if len(notReady) > 0 {
+			return newDiagnoseError("replicas", fmt.Errorf("pod %s is not Ready", strings.Join(notReady, ",")))
+		}
+		return newDiagnoseError("replicas", fmt.Errorf("deployment %s has %d/%d replicas Ready", name, ready, desired))
+	}
+	r.Pass("replicas", summary, name, ready, desired)
+	return nil
+}
+
+// QueryDeploymentFromDatabase returns the deployment cached in the database, it returns nil if it is not cached
+func QueryDeploymentFromDatabase(namespace, name string) (*appsv1.Deployment, error) {
+	key := fmt.Sprintf("%v/%v/%v", namespace, resourceTypeDeployment, name)
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+	result, err := queryMeta(ctx, "key", key)
+	if err != nil {
+		return nil, fmt.Errorf("read database fail: %s", err.Error())
+	}
+	if len(*result) == 0 {
+		return nil, nil
+	}
+
+	deploy := &appsv1.Deployment{}
+	if err := json.Unmarshal([]byte((*result)[0]), deploy); err != nil {
+		return nil, fmt.Errorf("unmarshal %s failed: %v", key, err)
+	}
+	return deploy, nil
+}
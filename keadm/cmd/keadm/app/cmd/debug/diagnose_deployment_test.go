@@ -0,0 +1,150 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func TestDiagnoseDeployment(t *testing.T) {
+	patches := gomonkey.NewPatches()
+	defer patches.Reset()
+
+	patches.ApplyFunc(InitDB, func(_driverName, _dbName, _dataSource string) error {
+		return nil
+	})
+	patches.ApplyFunc(QueryPodsFromDatabase, func(_namespace string) ([]v1.Pod, error) {
+		return []v1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "nginx-5d8f-abcde", Labels: map[string]string{"app": "nginx"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "nginx-5d8f-fghij", Labels: map[string]string{"app": "nginx"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Labels: map[string]string{"app": "nginx"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "redis-1", Labels: map[string]string{"app": "redis"}}},
+		}, nil
+	})
+	var diagnosed []string
+	patches.ApplyFunc(QueryPodFromDatabase, func(_namespace, podName string, _r *DiagnoseReport) (*v1.PodStatus, error) {
+		diagnosed = append(diagnosed, podName)
+		status := v1.ConditionTrue
+		if podName == "nginx-5d8f-fghij" {
+			status = v1.ConditionFalse
+		}
+		return &v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}},
+		}, nil
+	})
+	opts := &common.DiagnoseOptions{Namespace: "default"}
+
+	t.Run("match pods by the selector of the cached deployment", func(t *testing.T) {
+		diagnosed = nil
+		replicas := int32(3)
+		p := gomonkey.ApplyFunc(QueryDeploymentFromDatabase, func(_namespace, _name string) (*appsv1.Deployment, error) {
+			return &appsv1.Deployment{Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}},
+			}}, nil
+		})
+		defer p.Reset()
+
+		buf := &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnoseDeployment, common.DiagnoseOutputText, buf)
+		err := DiagnoseDeployment(opts, "nginx", r)
+		require.EqualError(t, err, "pod nginx-5d8f-fghij is not Ready")
+		assert.Equal(t, []string{"nginx-5d8f-abcde", "nginx-5d8f-fghij", "web-1"}, diagnosed)
+		assert.Contains(t, buf.String(), "match pods by selector app=nginx")
+
+		last := r.Results[len(r.Results)-1]
+		assert.Equal(t, common.DiagnoseResult{
+			Check:   "replicas",
+			Status:  common.DiagnoseStatusFail,
+			Message: "deployment nginx: 2/3 replicas are Ready",
+		}, last)
+	})
+
+	t.Run("match pods by the name prefix", func(t *testing.T) {
+		diagnosed = nil
+		p := gomonkey.ApplyFunc(QueryDeploymentFromDatabase, func(_namespace, _name string) (*appsv1.Deployment, error) {
+			return nil, nil
+		})
+		defer p.Reset()
+
+		buf := &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnoseDeployment, common.DiagnoseOutputText, buf)
+		err := DiagnoseDeployment(opts, "web", r)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"web-1"}, diagnosed)
+		assert.Contains(t, buf.String(), "match pods by name prefix web-")
+		assert.Contains(t, buf.String(), "deployment web: 1/1 replicas are Ready\n")
+	})
+
+	t.Run("no deployment and no pods", func(t *testing.T) {
+		p := gomonkey.ApplyFunc(QueryDeploymentFromDatabase, func(_namespace, _name string) (*appsv1.Deployment, error) {
+			return nil, nil
+		})
+		defer p.Reset()
+
+		err := DiagnoseDeployment(opts, "mysql", newTestReport())
+		require.EqualError(t, err, "not find deployment mysql or its pods in namespace default")
+	})
+
+	t.Run("query deployment failed", func(t *testing.T) {
+		p := gomonkey.ApplyFunc(QueryDeploymentFromDatabase, func(_namespace, _name string) (*appsv1.Deployment, error) {
+			return nil, errors.New("read database fail: test error")
+		})
+		defer p.Reset()
+
+		err := DiagnoseDeployment(opts, "nginx", newTestReport())
+		require.EqualError(t, err, "read database fail: test error")
+	})
+}
+
+func TestQueryDeploymentFromDatabase(t *testing.T) {
+	patches := gomonkey.NewPatches()
+	defer patches.Reset()
+
+	var gotCondition string
+	patches.ApplyFunc(dao.QueryMetaWithContext, func(_ctx context.Context, _key, condition string) (*[]string, error) {
+		gotCondition = condition
+		if condition == "test/deployment/nginx" {
+			return &[]string{`{"metadata":{"name":"nginx"},"spec":{"selector":{"matchLabels":{"app":"nginx"}}}}`}, nil
+		}
+		return &[]string{}, nil
+	})
+
+	deploy, err := QueryDeploymentFromDatabase("test", "nginx")
+	require.NoError(t, err)
+	assert.Equal(t, "test/deployment/nginx", gotCondition)
+	require.NotNil(t, deploy)
+	assert.Equal(t, "nginx", deploy.Spec.Selector.MatchLabels["app"])
+
+	deploy, err = QueryDeploymentFromDatabase("test", "redis")
+	require.NoError(t, err)
+	assert.Nil(t, deploy)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtclient"
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dttype"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+// DiagnoseDevice diagnoses the device twins in the database,
+// a twin whose desired value differs from the reported value indicates that the device is not synced.
+func DiagnoseDevice(ops *common.DiagnoseOptions, name string, r *DiagnoseReport) error {
+	if err := initPodDatabase(ops, r); err != nil {
+		return err
+	}
+
+	device, err := QueryDeviceFromDatabase(name)
+	if err != nil {
+		return r.Fail("device", err)
+	}
+	r.Pass("device", "Device %s is exist, state: %v, last online: %v\n", name, device.State, device.LastOnline)
+
+	twins, err := dtclient.QueryDeviceTwin("deviceid", device.ID)
+	if err != nil {
+		return r.Fail("twin", fmt.Errorf("read database fail: %s", err.Error()))
+	}
+
+	var notSynced []string
+	for _, twin := range *twins {
+		// a twin without desired value is only reported by the device
+		if twin.Expected == "" || twin.Expected == twin.Actual {
+			r.Pass("twin", "twin %v is synced, reported: %v, updated at %v\n",
+				twin.Name, twin.Actual, twinUpdateTime(twin.ActualMeta))
+			continue
+		}
+		notSynced = append(notSynced, twin.Name)
+		r.Failf("twin", "twin %v is not synced, desired: %v, updated at %v, reported: %v, updated at %v\n",
+			twin.Name, twin.Expected, twinUpdateTime(twin.ExpectedMeta), twin.Actual, twinUpdateTime(twin.ActualMeta))
+	}
+
+	const summary = "%d/%d twins of device %s are synced\n"
+	if len(notSynced) > 0 {
+		r.Failf("twins", summary, len(*twins)-len(notSynced), len(*twins), name)
+		return newDiagnoseError("twins", fmt.Errorf("twin %s of device %s is not synced", strings.Join(notSynced, ","), name))
+	}
+	r.Pass("twins", summary, len(*twins), len(*twins), name)
+	return nil
+}
+
+// QueryDeviceFromDatabase returns the device of the id or the name in the database
+func QueryDeviceFromDatabase(name string) (*dtclient.Device, error) {
+	for _, key := range []string{"id", "name"} {
+		devices, err := dtclient.QueryDevice(key, name)
+		if err != nil {
+			return nil, fmt.Errorf("read database fail: %s", err.Error())
+		}
+		if len(*devices) > 0 {
+			return &(*devices)[0], nil
+		}
+	}
+	return nil, fmt.Errorf("not find device %v in datebase", name)
+}
+
+// twinUpdateTime returns the update time recorded in the metadata of a twin value, or "unknown" if it is not recorded
+func twinUpdateTime(meta string) string {
+	var vm dttype.ValueMetadata
+	if meta == "" || json.Unmarshal([]byte(meta), &vm) != nil || vm.Timestamp == 0 {
+		return "unknown"
+	}
+	return time.UnixMilli(vm.Timestamp).Format(time.RFC3339)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtclient"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func TestDiagnoseDevice(t *testing.T) {
+	patches := gomonkey.NewPatches()
+	defer patches.Reset()
+
+	patches.ApplyFunc(InitDB, func(_driverName, _dbName, _dataSource string) error {
+		return nil
+	})
+	patches.ApplyFunc(dtclient.QueryDevice, func(key, condition string) (*[]dtclient.Device, error) {
+		if key == "name" && condition == "sensor" {
+			return &[]dtclient.Device{{ID: "sensor-id", Name: "sensor", State: "online"}}, nil
+		}
+		return &[]dtclient.Device{}, nil
+	})
+	var twins []dtclient.DeviceTwin
+	patches.ApplyFunc(dtclient.QueryDeviceTwin, func(key, condition string) (*[]dtclient.DeviceTwin, error) {
+		assert.Equal(t, "deviceid", key)
+		assert.Equal(t, "sensor-id", condition)
+		return &twins, nil
+	})
+
+	t.Run("device not found", func(t *testing.T) {
+		err := DiagnoseDevice(&common.DiagnoseOptions{}, "actuator", newTestReport())
+		require.EqualError(t, err, "not find device actuator in datebase")
+	})
+
+	t.Run("twins are synced", func(t *testing.T) {
+		twins = []dtclient.DeviceTwin{
+			{Name: "temperature", Actual: "20"},
+			{Name: "switch", Expected: "on", Actual: "on"},
+		}
+		buf := &bytes.Buffer{}
+		err := DiagnoseDevice(&common.DiagnoseOptions{}, "sensor",
+			NewDiagnoseReport(common.ArgDiagnoseDevice, common.DiagnoseOutputText, buf))
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "Device sensor is exist, state: online")
+		assert.Contains(t, buf.String(), "2/2 twins of device sensor are synced\n")
+	})
+
+	t.Run("twin is not synced", func(t *testing.T) {
+		updated := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+		twins = []dtclient.DeviceTwin{
+			{Name: "temperature", Actual: "20"},
+			{
+				Name:         "switch",
+				Expected:     "on",
+				ExpectedMeta: `{"timestamp":1735830245000}`,
+				Actual:       "off",
+			},
+		}
+		r := NewDiagnoseReport(common.ArgDiagnoseDevice, common.DiagnoseOutputText, &bytes.Buffer{})
+		err := DiagnoseDevice(&common.DiagnoseOptions{}, "sensor", r)
+		require.EqualError(t, err, "twin switch of device sensor is not synced")
+
+		var messages []string
+		for _, result := range r.Results {
+			if result.Status == common.DiagnoseStatusFail {
+				messages = append(messages, result.Message)
+			}
+		}
+		assert.Equal(t, []string{
+			"twin switch is not synced, desired: on, updated at " + updated.Local().Format(time.RFC3339) +
+				", reported: off, updated at unknown",
+			"1/2 twins of device sensor are synced",
+		}, messages)
+	})
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+// diagnoseStatusAbsent is the status of a check which is not in one of the compared reports
+const diagnoseStatusAbsent = "absent"
+
+// DiagnoseReportChange is a check whose status differs between two diagnose reports
+type DiagnoseReportChange struct {
+	Check  string `json:"check"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+	// Message is the message of the failed result of the check in either report, the one after is preferred
+	Message string `json:"message,omitempty"`
+}
+
+// DiagnoseReportDiff is the comparison of the check statuses of two diagnose reports
+type DiagnoseReportDiff struct {
+	Before    string                 `json:"before"`
+	After     string                 `json:"after"`
+	Changes   []DiagnoseReportChange `json:"changes"`
+	Fixed     int                    `json:"fixed"`
+	Regressed int                    `json:"regressed"`
+	Unchanged int                    `json:"unchanged"`
+}
+
+// NewDiagnoseDiff returns the command comparing two diagnose reports, do is shared with the parent command.
+func NewDiagnoseDiff(do *common.DiagnoseOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff BEFORE AFTER",
+		Short: "Show the checks whose status changed between two diagnose reports in json or yaml format",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return DiffDiagnoseReports(cmd.OutOrStdout(), do.Output, args[0], args[1])
+		},
+		SilenceUsage: true,
+	}
+}
+
+// DiffDiagnoseReports compares the reports in the files before and after, which are written by --report or -o json|yaml,
+// and prints the checks whose status changed in the output format. It fails if a check regressed, i.e. it fails
+// after but not before, so that the diff can gate a fix in a script.
+func DiffDiagnoseReports(w io.Writer, output, before, after string) error {
+	if err := ValidateDiagnoseOutput(output); err != nil {
+		return err
+	}
+	beforeReport, err := readDiagnoseReport(before)
+	if err != nil {
+		return err
+	}
+	afterReport, err := readDiagnoseReport(after)
+	if err != nil {
+		return err
+	}
+
+	diff := diffDiagnoseReports(beforeReport, afterReport)
+	diff.Before, diff.After = before, after
+	if err := writeDiagnoseReportDiff(w, output, diff); err != nil {
+		return err
+	}
+	if diff.Regressed > 0 {
+		return fmt.Errorf("%d checks regressed between %s and %s", diff.Regressed, before, after)
+	}
+	return nil
+}
+
+// readDiagnoseReport reads the report in the file, which is in either json or yaml format
+func readDiagnoseReport(file string) (*DiagnoseReport, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read diagnose report %s failed: %v", file, err)
+	}
+	report := &DiagnoseReport{}
+	if err := yaml.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("diagnose report %s is not in json or yaml format: %v", file, err)
+	}
+	return report, nil
+}
+
+// reportCheckStatus is the status of a check aggregated over its results
+type reportCheckStatus struct {
+	status  string
+	message string
+}
+
+// reportCheckStatuses returns the statuses of the checks of the report and the checks in the order they first appear,
+// a check with several results takes the worst status of them.
+func reportCheckStatuses(r *DiagnoseReport) (map[string]reportCheckStatus, []string) {
+	statuses := make(map[string]reportCheckStatus)
+	var checks []string
+	for _, result := range r.Results {
+		s, ok := statuses[result.Check]
+		if !ok {
+			checks = append(checks, result.Check)
+			s = reportCheckStatus{status: result.Status}
+		}
+		if statusSeverity(result.Status) > statusSeverity(s.status) || (result.Status != common.DiagnoseStatusPass && s.message == "") {
+			s.status, s.message = result.Status, result.Message
+		}
+		statuses[result.Check] = s
+	}
+	return statuses, checks
+}
+
+// diffDiagnoseReports returns the checks whose status differs between the reports,
+// in the order of the report after followed by the checks only in the report before.
+func diffDiagnoseReports(before, after *DiagnoseReport) *DiagnoseReportDiff {
+	beforeStatuses, beforeChecks := reportCheckStatuses(before)
+	afterStatuses, afterChecks := reportCheckStatuses(after)
+	checks := afterChecks
+	for _, check := range beforeChecks {
+		if _, ok := afterStatuses[check]; !ok {
+			checks = append(checks, check)
+		}
+	}
+
+	diff := &DiagnoseReportDiff{Changes: []DiagnoseReportChange{}}
+	for _, check := range checks {
+		b, inBefore := beforeStatuses[check]
+		a, inAfter := afterStatuses[check]
+		if !inBefore {
+			b.status = diagnoseStatusAbsent
+		}
+		if !inAfter {
+			a.status = diagnoseStatusAbsent
+		}
+		if a.status == b.status {
+			diff.Unchanged++
+			continue
+		}
+		message := a.message
+		if message == "" {
+			message = b.message
+		}
+		switch {
+		case a.status == common.DiagnoseStatusFail:
+			diff.Regressed++
+		case b.status == common.DiagnoseStatusFail && a.status != diagnoseStatusAbsent:
+			diff.Fixed++
+		}
+		diff.Changes = append(diff.Changes, DiagnoseReportChange{Check: check, Before: b.status, After: a.status, Message: message})
+	}
+	return diff
+}
+
+// writeDiagnoseReportDiff writes the diff as a CHECK/BEFORE/AFTER table in text mode, or in json or yaml format
+func writeDiagnoseReportDiff(w io.Writer, output string, diff *DiagnoseReportDiff) error {
+	var data []byte
+	var err error
+	switch output {
+	case common.DiagnoseOutputJSON:
+		data, err = json.MarshalIndent(diff, "", "  ")
+		data = append(data, '\n')
+	case common.DiagnoseOutputYAML:
+		data, err = yaml.Marshal(diff)
+	default:
+		if len(diff.Changes) == 0 {
+			fmt.Fprintf(w, "no checks changed status between %s and %s, %d checks are unchanged\n", diff.Before, diff.After, diff.Unchanged)
+			return nil
+		}
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, "CHECK\tBEFORE\tAFTER\tMESSAGE")
+		for _, c := range diff.Changes {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.Check, c.Before, c.After, c.Message)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%d checks changed: %d fixed, %d regressed, %d checks are unchanged\n",
+			len(diff.Changes), diff.Fixed, diff.Regressed, diff.Unchanged)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal diagnose report diff: %v", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func writeTestDiagnoseReport(t *testing.T, path string, results []common.DiagnoseResult) {
+	t.Helper()
+	report := &DiagnoseReport{NodeName: "edge-node", Diagnose: common.ArgDiagnoseNode, Results: results}
+	var data []byte
+	var err error
+	if filepath.Ext(path) == ".yaml" {
+		data, err = yaml.Marshal(report)
+	} else {
+		data, err = json.Marshal(report)
+	}
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+}
+
+func TestDiffDiagnoseReports(t *testing.T) {
+	dir := t.TempDir()
+	before := filepath.Join(dir, "before.json")
+	after := filepath.Join(dir, "after.yaml")
+	writeTestDiagnoseReport(t, before, []common.DiagnoseResult{
+		{Check: "edgecore", Status: common.DiagnoseStatusPass},
+		{Check: "config", Status: common.DiagnoseStatusPass},
+		{Check: "config", Status: common.DiagnoseStatusPass},
+		{Check: "cloudhub", Status: common.DiagnoseStatusFail, Message: "connection refused"},
+		{Check: "mqtt", Status: common.DiagnoseStatusPass},
+	})
+	writeTestDiagnoseReport(t, after, []common.DiagnoseResult{
+		{Check: "edgecore", Status: common.DiagnoseStatusPass},
+		{Check: "config", Status: common.DiagnoseStatusPass},
+		{Check: "config", Status: common.DiagnoseStatusFail, Message: "invalid server"},
+		{Check: "cloudhub", Status: common.DiagnoseStatusPass},
+		{Check: "clock", Status: common.DiagnoseStatusPass},
+	})
+
+	t.Run("text", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := DiffDiagnoseReports(buf, common.DiagnoseOutputText, before, after)
+		require.EqualError(t, err, "1 checks regressed between "+before+" and "+after)
+		assert.Contains(t, buf.String(), "CHECK     BEFORE  AFTER   MESSAGE\n")
+		assert.Contains(t, buf.String(), "config    pass    fail    invalid server\n")
+		assert.Contains(t, buf.String(), "cloudhub  fail    pass    connection refused\n")
+		assert.Contains(t, buf.String(), "clock     absent  pass    \n")
+		assert.Contains(t, buf.String(), "mqtt      pass    absent  \n")
+		assert.Contains(t, buf.String(), "4 checks changed: 1 fixed, 1 regressed, 1 checks are unchanged\n")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, DiffDiagnoseReports(buf, common.DiagnoseOutputJSON, after, after))
+		diff := &DiagnoseReportDiff{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), diff))
+		assert.Equal(t, &DiagnoseReportDiff{Before: after, After: after, Changes: []DiagnoseReportChange{}, Unchanged: 4}, diff)
+	})
+
+	t.Run("fixed without regressions", func(t *testing.T) {
+		fixed := filepath.Join(dir, "fixed.json")
+		writeTestDiagnoseReport(t, fixed, []common.DiagnoseResult{
+			{Check: "cloudhub", Status: common.DiagnoseStatusPass},
+		})
+		buf := &bytes.Buffer{}
+		require.NoError(t, DiffDiagnoseReports(buf, common.DiagnoseOutputText, before, fixed))
+		assert.Contains(t, buf.String(), "1 fixed, 0 regressed")
+	})
+
+	t.Run("warned", func(t *testing.T) {
+		warned := filepath.Join(dir, "warned.json")
+		writeTestDiagnoseReport(t, warned, []common.DiagnoseResult{
+			{Check: "cloudhub", Status: common.DiagnoseStatusPass},
+			{Check: "cloudhub", Status: common.DiagnoseStatusWarn, Message: "clock skew is near the max skew"},
+		})
+		buf := &bytes.Buffer{}
+		require.NoError(t, DiffDiagnoseReports(buf, common.DiagnoseOutputText, before, warned))
+		assert.Contains(t, buf.String(), "cloudhub  fail    warn    clock skew is near the max skew\n")
+		assert.Contains(t, buf.String(), "1 fixed, 0 regressed")
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, DiffDiagnoseReports(buf, common.DiagnoseOutputText, before, before))
+		assert.Contains(t, buf.String(), "no checks changed status between")
+	})
+
+	t.Run("report is not exists", func(t *testing.T) {
+		err := DiffDiagnoseReports(&bytes.Buffer{}, common.DiagnoseOutputText, filepath.Join(dir, "not-exists.json"), after)
+		require.ErrorContains(t, err, "read diagnose report")
+	})
+
+	t.Run("report is in text format", func(t *testing.T) {
+		text := filepath.Join(dir, "report.txt")
+		require.NoError(t, os.WriteFile(text, []byte("Node: edge-node\n|diagnose node succeed|\n"), 0600))
+		err := DiffDiagnoseReports(&bytes.Buffer{}, common.DiagnoseOutputText, text, after)
+		require.ErrorContains(t, err, "is not in json or yaml format")
+	})
+}
+
+func TestNewDiagnoseDiff(t *testing.T) {
+	report := filepath.Join(t.TempDir(), "report.json")
+	writeTestDiagnoseReport(t, report, []common.DiagnoseResult{{Check: "edgecore", Status: common.DiagnoseStatusPass}})
+
+	cmd := NewDiagnose()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"diff", report, report})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, buf.String(), "no checks changed status")
+
+	cmd.SetArgs([]string{"diff", report})
+	assert.Error(t, cmd.Execute())
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+)
+
+// maxPodEvents is the max number of the recent events of a pod printed by DiagnosePod
+const maxPodEvents = 5
+
+// QueryPodEventsFromDatabase returns the events of the pod cached in the edge database, the most recent first.
+// The events are reported to cloudcore by edged, only the events cached by metamanager are returned.
+func QueryPodEventsFromDatabase(namespace, podName string) ([]v1.Event, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+	metas, err := queryAllMeta(ctx, "key__startswith", fmt.Sprintf("%v/%v/", namespace, model.ResourceTypeEvent))
+	if err != nil {
+		return nil, fmt.Errorf("read database fail: %s", err.Error())
+	}
+
+	var events []v1.Event
+	for _, meta := range *metas {
+		event := v1.Event{}
+		if err := json.Unmarshal([]byte(meta.Value), &event); err != nil {
+			return nil, fmt.Errorf("unmarshal %s failed: %v", meta.Key, err)
+		}
+		if event.InvolvedObject.Kind == "Pod" && event.InvolvedObject.Name == podName {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i]).After(eventTime(events[j]))
+	})
+	return events, nil
+}
+
+// eventTime returns the last time the event occurred
+func eventTime(event v1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// printPodEvents prints the most recent events of the pod cached in the edge database,
+// which explain why the pod is not Ready, e.g. the image of a container fails to be pulled.
+func printPodEvents(namespace, podName string, r *DiagnoseReport) {
+	events, err := QueryPodEventsFromDatabase(namespace, podName)
+	if err != nil {
+		r.Printf("Failed to query the events of pod %s: %v\n", podName, err)
+		return
+	}
+	if len(events) == 0 {
+		r.Printf("No events of pod %s are cached in the edge database, run `kubectl describe pod %s -n %s` for the events\n",
+			podName, podName, namespace)
+		return
+	}
+
+	if len(events) > maxPodEvents {
+		events = events[:maxPodEvents]
+	}
+	r.Printf("Recent events of pod %s:\n", podName)
+	tw := tabwriter.NewWriter(r.TextWriter(), 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "LAST SEEN\tTYPE\tREASON\tCOUNT\tMESSAGE\n")
+	for _, event := range events {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", eventTime(event).Format(time.RFC3339), event.Type,
+			event.Reason, event.Count, event.Message)
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func newEventMeta(t *testing.T, name, podName, reason string, lastSeen time.Time) dao.Meta {
+	event := v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: podName},
+		Type:           v1.EventTypeWarning,
+		Reason:         reason,
+		Message:        reason + " of " + podName,
+		Count:          1,
+		LastTimestamp:  metav1.NewTime(lastSeen),
+	}
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+	return dao.Meta{Key: "default/event/" + name, Type: "event", Value: string(data)}
+}
+
+func TestQueryPodEventsFromDatabase(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	patches := gomonkey.ApplyFunc(dao.QueryAllMetaWithContext, func(_ctx context.Context, key, condition string) (*[]dao.Meta, error) {
+		assert.Equal(t, "key__startswith", key)
+		assert.Equal(t, "default/event/", condition)
+		return &[]dao.Meta{
+			newEventMeta(t, "nginx.1", "nginx", "Scheduled", now.Add(-time.Hour)),
+			newEventMeta(t, "redis.1", "redis", "BackOff", now),
+			newEventMeta(t, "nginx.2", "nginx", "Failed", now),
+		}, nil
+	})
+	defer patches.Reset()
+
+	events, err := QueryPodEventsFromDatabase("default", "nginx")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "Failed", events[0].Reason)
+	assert.Equal(t, "Scheduled", events[1].Reason)
+}
+
+func TestPrintPodEvents(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("the most recent events", func(t *testing.T) {
+		metas := make([]dao.Meta, 0, maxPodEvents+1)
+		for i := 0; i <= maxPodEvents; i++ {
+			metas = append(metas, newEventMeta(t, fmt.Sprintf("nginx.%d", i), "nginx", fmt.Sprintf("Reason%d", i),
+				now.Add(time.Duration(i)*time.Minute)))
+		}
+		patches := gomonkey.ApplyFunc(dao.QueryAllMetaWithContext, func(_ctx context.Context, _key, _condition string) (*[]dao.Meta, error) {
+			return &metas, nil
+		})
+		defer patches.Reset()
+
+		buf := &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnosePod, common.DiagnoseOutputText, buf)
+		printPodEvents("default", "nginx", r)
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, maxPodEvents+2)
+		assert.Equal(t, "Recent events of pod nginx:", lines[0])
+		assert.Contains(t, lines[1], "LAST SEEN")
+		assert.Contains(t, lines[2], "2025-01-01T00:05:00Z")
+		assert.Contains(t, lines[2], "Reason5 of nginx")
+		assert.NotContains(t, buf.String(), "Reason0")
+		assert.Empty(t, r.Results)
+	})
+
+	t.Run("no events are cached", func(t *testing.T) {
+		patches := gomonkey.ApplyFunc(dao.QueryAllMetaWithContext, func(_ctx context.Context, _key, _condition string) (*[]dao.Meta, error) {
+			return &[]dao.Meta{}, nil
+		})
+		defer patches.Reset()
+
+		buf := &bytes.Buffer{}
+		printPodEvents("default", "nginx", NewDiagnoseReport(common.ArgDiagnosePod, common.DiagnoseOutputText, buf))
+		assert.Contains(t, buf.String(), "No events of pod nginx are cached in the edge database, run `kubectl describe pod nginx -n default`")
+	})
+}
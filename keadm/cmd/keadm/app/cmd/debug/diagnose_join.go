@@ -0,0 +1,210 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/pkg/security/token"
+)
+
+const (
+	// DefaultJoinCertPort is the port of cloudcore which the edge nodes apply for the certificates at
+	DefaultJoinCertPort = "10002"
+	// joinTokenParts are the parts of a join token separated by dots, the hash of the CA of cloudcore and the 3 parts of the jwt
+	joinTokenParts = 4
+	// caHashLen is the length of the hex encoded sha256 hash of the CA in a join token
+	caHashLen = 64
+)
+
+// joinToken is a parsed join token, which keadm gettoken prints on the cloud side
+type joinToken struct {
+	caHash string
+	// jwt is the token without the CA hash, which cloudcore verifies
+	jwt       string
+	expiresAt time.Time
+}
+
+// DiagnoseJoin diagnoses the join token of a node which is not joined yet, so edgecore is not running.
+// It checks the format and the expiry of the token, and if the cloudcore is set by ops.CloudCoreIPPort,
+// the token is issued for the CA of the cloudcore and the cloudcore still accepts it.
+func DiagnoseJoin(ops *common.DiagnoseOptions, r *DiagnoseReport) error {
+	if ops.Token == "" {
+		return r.Fail("token", errors.New("--token is required by the join diagnose"))
+	}
+	var tok *joinToken
+	if err := r.Run("token", func(w io.Writer) error {
+		var err error
+		if tok, err = parseJoinToken(ops.Token); err != nil {
+			return err
+		}
+		return checkJoinTokenExpiry(w, tok, time.Now())
+	}); err != nil {
+		return err
+	}
+
+	if ops.CloudCoreIPPort == "" {
+		r.SkipAll([]string{"token-ca", "token-accepted"}, "--cloudcore-ipport is not set")
+		return nil
+	}
+	host, _, err := net.SplitHostPort(ops.CloudCoreIPPort)
+	if err != nil {
+		return r.Fail("token-ca", fmt.Errorf("invalid --cloudcore-ipport %s: %v", ops.CloudCoreIPPort, err))
+	}
+	certPort := ops.CertPort
+	if certPort == "" {
+		certPort = DefaultJoinCertPort
+	}
+	server := "https://" + net.JoinHostPort(host, certPort)
+	opts := NewHTTPCheckOptions(ops.CheckOptions)
+
+	var ca []byte
+	if err := r.Run("token-ca", func(w io.Writer) error {
+		var err error
+		ca, err = checkJoinTokenCA(w, server, tok, opts)
+		return err
+	}); err != nil {
+		return err
+	}
+	return r.Run("token-accepted", func(w io.Writer) error {
+		return checkJoinTokenAccepted(w, server, tok, ca, opts)
+	})
+}
+
+// parseJoinToken parses the join token of the form <caHash>.<jwt>, the signature of the jwt is verified by cloudcore
+func parseJoinToken(s string) (*joinToken, error) {
+	parts := strings.Split(strings.TrimSpace(s), ".")
+	if len(parts) != joinTokenParts {
+		return nil, fmt.Errorf("token is malformed, it has %d parts separated by dots instead of %d: the hash of the CA and the 3 parts of the jwt",
+			len(parts), joinTokenParts)
+	}
+	if _, err := hex.DecodeString(parts[0]); err != nil || len(parts[0]) != caHashLen {
+		return nil, fmt.Errorf("token is malformed, the CA hash %s is not a hex encoded sha256 hash", parts[0])
+	}
+	tok := &joinToken{caHash: parts[0], jwt: strings.Join(parts[1:], ".")}
+	claims := &jwt.RegisteredClaims{}
+	parsed, _, err := jwt.NewParser().ParseUnverified(tok.jwt, claims)
+	if err != nil {
+		return nil, fmt.Errorf("token is malformed, failed to parse the jwt: %v", err)
+	}
+	if _, ok := parsed.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("token is malformed, the jwt is signed with %s instead of HMAC", parsed.Method.Alg())
+	}
+	if claims.ExpiresAt != nil {
+		tok.expiresAt = claims.ExpiresAt.Time
+	}
+	return tok, nil
+}
+
+// checkJoinTokenExpiry fails if the token is expired at now, the token without an expiry is warned
+func checkJoinTokenExpiry(w io.Writer, tok *joinToken, now time.Time) error {
+	if tok.expiresAt.IsZero() {
+		return warningError(errors.New("token has no expiry, it is not created by keadm gettoken"))
+	}
+	if !now.Before(tok.expiresAt) {
+		return fmt.Errorf("token expired at %s, %v ago, get a new token with keadm gettoken on the cloud side",
+			tok.expiresAt.Format(time.RFC3339), now.Sub(tok.expiresAt).Round(time.Second))
+	}
+	fmt.Fprintf(w, "token is well-formed, it expires at %s, in %v\n",
+		tok.expiresAt.Format(time.RFC3339), tok.expiresAt.Sub(now).Round(time.Second))
+	return nil
+}
+
+// checkJoinTokenCA gets the CA of cloudcore from server the same as keadm join does, and checks the token
+// is issued for the CA by its hash. The server is not verified, it is the CA hash that authenticates the CA.
+func checkJoinTokenCA(w io.Writer, server string, tok *joinToken, opts HTTPCheckOptions) ([]byte, error) {
+	client := &http.Client{
+		Transport: &http.Transport{
+			// the CA is not known yet, it is verified by the hash of the token instead
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			Proxy:           opts.proxyFunc(),
+			DialContext:     opts.dialContext(),
+		},
+		Timeout: time.Duration(opts.timeout()) * time.Second,
+	}
+	resp, err := client.Get(server + constants.DefaultCAURL)
+	if err != nil {
+		return nil, networkError(fmt.Errorf("get the CA of cloudcore %s failed,%v", server, connectError(err, opts.timeout())))
+	}
+	defer resp.Body.Close()
+	ca, err := io.ReadAll(io.LimitReader(resp.Body, constants.MaxRespBodyLength))
+	if err != nil {
+		return nil, fmt.Errorf("read the CA of cloudcore %s failed: %v", server, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get the CA of cloudcore %s failed, status code: %d", server, resp.StatusCode)
+	}
+	if _, err := token.VerifyCAAndGetRealToken(tok.caHash+"."+tok.jwt, ca); err != nil {
+		return nil, fmt.Errorf("token is not issued by cloudcore %s, %v", server, err)
+	}
+	fmt.Fprintf(w, "token is issued for the CA of cloudcore %s\n", server)
+	return ca, nil
+}
+
+// checkJoinTokenAccepted applies for the certificate at server with the token and no CSR, cloudcore verifies
+// the token before it signs the CSR, so the token is rejected as unauthorized or accepted and fails the signing
+// of the empty CSR. No certificate is issued by the check.
+func checkJoinTokenAccepted(w io.Writer, server string, tok *joinToken, ca []byte, opts HTTPCheckOptions) error {
+	caCert, err := x509.ParseCertificate(ca)
+	if err != nil {
+		return fmt.Errorf("parse the CA of cloudcore %s failed: %v", server, err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: roots, InsecureSkipVerify: opts.InsecureSkipTLSVerify},
+			Proxy:           opts.proxyFunc(),
+			DialContext:     opts.dialContext(),
+		},
+		Timeout: time.Duration(opts.timeout()) * time.Second,
+	}
+	req, err := http.NewRequest(http.MethodPost, server+constants.DefaultCertURL, http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(types.HeaderAuthorization, "Bearer "+tok.jwt)
+	resp, err := client.Do(req)
+	if err != nil {
+		return networkError(fmt.Errorf("apply for the certificate at cloudcore %s failed,%v", server, connectError(err, opts.timeout())))
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, constants.MaxRespBodyLength))
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return fmt.Errorf("cloudcore %s rejects the token: %s", server, strings.TrimSpace(string(body)))
+	case http.StatusOK, http.StatusInternalServerError:
+		fmt.Fprintf(w, "cloudcore %s accepts the token\n", server)
+		return nil
+	}
+	return fmt.Errorf("apply for the certificate at cloudcore %s failed, status code: %d, %s", server, resp.StatusCode, strings.TrimSpace(string(body)))
+}
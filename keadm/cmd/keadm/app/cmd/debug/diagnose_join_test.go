@@ -0,0 +1,150 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/pkg/security/token"
+)
+
+// newJoinToken returns a join token for the CA signed by key which expires at expiresAt
+func newJoinToken(t *testing.T, ca, key []byte, expiresAt time.Time) string {
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}).SignedString(key)
+	require.NoError(t, err)
+	digest := sha256.Sum256(ca)
+	return hex.EncodeToString(digest[:]) + "." + signed
+}
+
+// serveFakeCertServer serves the CA and verifies the tokens signed by key the same as the certificate server of cloudcore
+func serveFakeCertServer(t *testing.T, key []byte) (*httptest.Server, string) {
+	mux := http.NewServeMux()
+	srv := httptest.NewUnstartedServer(mux)
+	mux.HandleFunc("/ca.crt", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(srv.Certificate().Raw)
+	})
+	mux.HandleFunc("/edge.crt", func(w http.ResponseWriter, r *http.Request) {
+		bearer := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if valid, err := token.Verify(bearer, key); err != nil || !valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("token validation failure"))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to sign certs for edgenode"))
+	})
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	_, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+	return srv, port
+}
+
+func TestParseJoinToken(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	tok, err := parseJoinToken(newJoinToken(t, []byte("ca"), []byte("key"), expiresAt) + "\n")
+	require.NoError(t, err)
+	assert.Len(t, tok.caHash, caHashLen)
+	assert.Equal(t, 2, strings.Count(tok.jwt, "."))
+	assert.True(t, expiresAt.Equal(tok.expiresAt))
+
+	_, err = parseJoinToken("abc.def")
+	assert.EqualError(t, err, "token is malformed, it has 2 parts separated by dots instead of 4: the hash of the CA and the 3 parts of the jwt")
+	_, err = parseJoinToken("nothex.a.b.c")
+	assert.EqualError(t, err, "token is malformed, the CA hash nothex is not a hex encoded sha256 hash")
+	_, err = parseJoinToken(strings.Repeat("a", caHashLen) + ".a.b.c")
+	assert.ErrorContains(t, err, "token is malformed, failed to parse the jwt")
+}
+
+func TestCheckJoinTokenExpiry(t *testing.T) {
+	now := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	buf := &strings.Builder{}
+	require.NoError(t, checkJoinTokenExpiry(buf, &joinToken{expiresAt: now.Add(2 * time.Hour)}, now))
+	assert.Equal(t, "token is well-formed, it expires at 2025-01-02T02:00:00Z, in 2h0m0s\n", buf.String())
+
+	err := checkJoinTokenExpiry(buf, &joinToken{expiresAt: now.Add(-time.Hour)}, now)
+	assert.EqualError(t, err, "token expired at 2025-01-01T23:00:00Z, 1h0m0s ago, get a new token with keadm gettoken on the cloud side")
+
+	err = checkJoinTokenExpiry(buf, &joinToken{}, now)
+	assert.True(t, isDiagnoseWarning(err))
+}
+
+func TestDiagnoseJoin(t *testing.T) {
+	key := []byte("ca-key")
+	srv, port := serveFakeCertServer(t, key)
+	ca := srv.Certificate().Raw
+	newOps := func(tok string) *common.DiagnoseOptions {
+		ops := NewDiagnoseOptions()
+		ops.Token, ops.CloudCoreIPPort, ops.CertPort = tok, "127.0.0.1:10000", port
+		return ops
+	}
+
+	t.Run("accepted", func(t *testing.T) {
+		r := newTestReport()
+		require.NoError(t, DiagnoseJoin(newOps(newJoinToken(t, ca, key, time.Now().Add(time.Hour))), r))
+		var checks []string
+		for _, result := range r.Results {
+			checks = append(checks, result.Check)
+			assert.Equal(t, common.DiagnoseStatusPass, result.Status)
+		}
+		assert.Equal(t, []string{"token", "token-ca", "token-accepted"}, checks)
+	})
+
+	t.Run("token is expired", func(t *testing.T) {
+		err := DiagnoseJoin(newOps(newJoinToken(t, ca, key, time.Now().Add(-time.Hour))), newTestReport())
+		require.ErrorContains(t, err, "token expired at")
+	})
+
+	t.Run("token of another CA", func(t *testing.T) {
+		err := DiagnoseJoin(newOps(newJoinToken(t, []byte("another ca"), key, time.Now().Add(time.Hour))), newTestReport())
+		require.ErrorContains(t, err, "token is not issued by cloudcore")
+	})
+
+	t.Run("token is rejected", func(t *testing.T) {
+		err := DiagnoseJoin(newOps(newJoinToken(t, ca, []byte("another key"), time.Now().Add(time.Hour))), newTestReport())
+		require.ErrorContains(t, err, "rejects the token: token validation failure")
+	})
+
+	t.Run("cloudcore is not set", func(t *testing.T) {
+		ops := newOps(newJoinToken(t, ca, key, time.Now().Add(time.Hour)))
+		ops.CloudCoreIPPort = ""
+		r := newTestReport()
+		require.NoError(t, DiagnoseJoin(ops, r))
+		require.Len(t, r.Results, 3)
+		assert.Equal(t, common.DiagnoseResult{Check: "token-ca", Status: common.DiagnoseStatusSkip, Message: "--cloudcore-ipport is not set"}, r.Results[1])
+		assert.Equal(t, common.DiagnoseStatusSkip, r.Results[2].Status)
+	})
+
+	t.Run("token is not set", func(t *testing.T) {
+		err := DiagnoseJoin(newOps(""), newTestReport())
+		require.EqualError(t, err, "--token is required by the join diagnose")
+	})
+}
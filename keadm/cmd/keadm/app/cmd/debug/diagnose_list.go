@@ -0,0 +1,70 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+// NewDiagnoseList returns the command listing the available diagnose targets, do is shared with the parent command.
+func NewDiagnoseList(do *common.DiagnoseOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the available diagnose targets",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ListDiagnoseObjects(cmd.OutOrStdout(), do.Output)
+		},
+	}
+}
+
+// ListDiagnoseObjects prints the diagnose targets in common.DiagnoseObjectMap in the output format
+func ListDiagnoseObjects(w io.Writer, output string) error {
+	if err := ValidateDiagnoseOutput(output); err != nil {
+		return err
+	}
+
+	var data []byte
+	var err error
+	switch output {
+	case common.DiagnoseOutputJSON:
+		data, err = json.MarshalIndent(common.DiagnoseObjectMap, "", "  ")
+		data = append(data, '\n')
+	case common.DiagnoseOutputYAML:
+		data, err = yaml.Marshal(common.DiagnoseObjectMap)
+	default:
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, "TARGET\tDESCRIPTION")
+		for _, v := range common.DiagnoseObjectMap {
+			fmt.Fprintf(tw, "%s\t%s\n", v.Use, v.Desc)
+		}
+		return tw.Flush()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal diagnose targets: %v", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
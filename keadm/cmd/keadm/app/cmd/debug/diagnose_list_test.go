@@ -0,0 +1,61 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func TestListDiagnoseObjects(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, ListDiagnoseObjects(buf, common.DiagnoseOutputText))
+		assert.Contains(t, buf.String(), "TARGET      DESCRIPTION\n")
+		assert.Contains(t, buf.String(), "node        "+common.DescDiagnoseNode+"\n")
+		assert.Contains(t, buf.String(), "deployment  "+common.DescDiagnoseDeployment+"\n")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, ListDiagnoseObjects(buf, common.DiagnoseOutputJSON))
+		var got []common.DiagnoseObject
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		assert.Equal(t, common.DiagnoseObjectMap, got)
+		assert.Contains(t, buf.String(), `"use": "node"`)
+	})
+
+	t.Run("unsupported output format", func(t *testing.T) {
+		err := ListDiagnoseObjects(&bytes.Buffer{}, "wide")
+		require.ErrorContains(t, err, "unsupported output format")
+	})
+}
+
+func TestNewDiagnoseList(t *testing.T) {
+	cmd := NewDiagnose()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"list", "-o", "yaml"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, buf.String(), "- desc: "+common.DescDiagnoseNode+"\n  use: node\n")
+}
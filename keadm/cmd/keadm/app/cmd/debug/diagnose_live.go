@@ -0,0 +1,205 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/util/files"
+)
+
+// DiagnosePodLive compares the pods cached in the edge database with the pods in the apiserver,
+// and reports the pods missing on either side and the pods whose phases diverge.
+// The pod podName is compared if it is set, otherwise the pods of the node matching ops.LabelSelector.
+func DiagnosePodLive(ops *common.DiagnoseOptions, podName string, r *DiagnoseReport) error {
+	if !files.FileExists(ops.KubeConfig) {
+		return r.Fail("live", fmt.Errorf("kubeconfig %s is not exists, --live requires a kubeconfig of the apiserver", ops.KubeConfig))
+	}
+	cli, err := util.KubeClient(ops.KubeConfig)
+	if err != nil {
+		return r.Fail("live", fmt.Errorf("failed to create KubeClient, error: %v", err))
+	}
+	return comparePodsLive(cli, ops, podName, r)
+}
+
+// comparePodsLive compares the phases of the pods in the apiserver with the phases cached in the edge database
+func comparePodsLive(cli kubernetes.Interface, ops *common.DiagnoseOptions, podName string, r *DiagnoseReport) error {
+	live, err := livePodPhases(cli, ops, podName, r.NodeName)
+	if err != nil {
+		return r.Fail("live", err)
+	}
+	cached, err := cachedPodPhases(ops, podName)
+	if err != nil {
+		return r.Fail("live", err)
+	}
+
+	names := make([]string, 0, len(live))
+	for name := range live {
+		names = append(names, name)
+	}
+	for name := range cached {
+		if _, ok := live[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var diverged int
+	for _, name := range names {
+		livePhase, inLive := live[name]
+		cachedPhase, inCache := cached[name]
+		switch {
+		case !inCache:
+			diverged++
+			r.Failf("live", "Pod %s is %s in the apiserver, but not in the edge database\n", name, livePhase)
+		case !inLive:
+			diverged++
+			r.Failf("live", "Pod %s is %s in the edge database, but not in the apiserver\n", name, cachedPhase)
+		case livePhase != cachedPhase:
+			diverged++
+			r.Failf("live", "Pod %s is %s in the apiserver, but %s in the edge database\n", name, livePhase, cachedPhase)
+		default:
+			r.Pass("live", "Pod %s is %s in both the apiserver and the edge database\n", name, livePhase)
+		}
+	}
+	if len(names) == 0 {
+		r.Printf("No pods are found in the apiserver or the edge database\n")
+	}
+	if diverged > 0 {
+		return newDiagnoseError("live", fmt.Errorf("%d/%d pods of the edge database diverge from the apiserver", diverged, len(names)))
+	}
+	return nil
+}
+
+// livePodPhases returns the phases of the pods in the apiserver,
+// the pods are listed by the label selector and the node name if podName is empty.
+func livePodPhases(cli kubernetes.Interface, ops *common.DiagnoseOptions, podName, nodeName string) (map[string]v1.PodPhase, error) {
+	phases := make(map[string]v1.PodPhase)
+	if podName != "" {
+		pod, err := cli.CoreV1().Pods(ops.Namespace).Get(context.TODO(), podName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return phases, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get pod %s from the apiserver failed: %v", podName, err)
+		}
+		phases[pod.Name] = pod.Status.Phase
+		return phases, nil
+	}
+
+	listOptions := metav1.ListOptions{LabelSelector: ops.LabelSelector}
+	if nodeName != "" {
+		listOptions.FieldSelector = "spec.nodeName=" + nodeName
+	}
+	pods, err := cli.CoreV1().Pods(ops.Namespace).List(context.TODO(), listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("list pods from the apiserver failed: %v", err)
+	}
+	for _, pod := range pods.Items {
+		phases[pod.Name] = pod.Status.Phase
+	}
+	return phases, nil
+}
+
+// cachedPodPhases returns the phases of the pods cached in the edge database,
+// the pods are matched by the label selector if podName is empty.
+func cachedPodPhases(ops *common.DiagnoseOptions, podName string) (map[string]v1.PodPhase, error) {
+	phases := make(map[string]v1.PodPhase)
+	var podNames []string
+	if podName != "" {
+		podNames = []string{podName}
+	} else {
+		selector, err := labels.Parse(ops.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector %s: %v", ops.LabelSelector, err)
+		}
+		pods, err := QueryPodsFromDatabase(ops.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range pods {
+			if selector.Matches(labels.Set(pod.Labels)) {
+				podNames = append(podNames, pod.Name)
+			}
+		}
+	}
+
+	for _, name := range podNames {
+		phase, ok, err := cachedPodPhase(ops.Namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			phases[name] = phase
+		}
+	}
+	return phases, nil
+}
+
+// cachedPodPhase returns the phase of the pod in the edge database, the pod status reported by edged
+// is preferred to the status in the pod. It returns false if the pod is not cached.
+func cachedPodPhase(namespace, podName string) (v1.PodPhase, bool, error) {
+	status, ok, err := cachedPodStatus(namespace, podName)
+	if err != nil || !ok {
+		return "", ok, err
+	}
+	return status.Phase, true, nil
+}
+
+// cachedPodStatus returns the status of the pod in the edge database without recording any result,
+// the pod status reported by edged is preferred to the status in the pod. It returns false if the pod is not cached.
+func cachedPodStatus(namespace, podName string) (*v1.PodStatus, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbQueryTimeout)
+	defer cancel()
+	statusKey := fmt.Sprintf("%v/podstatus/%v", namespace, podName)
+	resultStatus, err := queryMeta(ctx, "key", statusKey)
+	if err != nil {
+		return nil, false, fmt.Errorf("read database fail: %s", err.Error())
+	}
+	if len(*resultStatus) > 0 {
+		podStatus := &types.PodStatusRequest{}
+		if err := unmarshalCachedPod(statusKey, (*resultStatus)[0], podStatus); err != nil {
+			return nil, false, err
+		}
+		return &podStatus.Status, true, nil
+	}
+
+	podKey := fmt.Sprintf("%v/pod/%v", namespace, podName)
+	resultPod, err := queryMeta(ctx, "key", podKey)
+	if err != nil {
+		return nil, false, fmt.Errorf("read database fail: %s", err.Error())
+	}
+	if len(*resultPod) == 0 {
+		return nil, false, nil
+	}
+	pod := &v1.Pod{}
+	if err := unmarshalCachedPod(podKey, (*resultPod)[0], pod); err != nil {
+		return nil, false, err
+	}
+	return &pod.Status, true, nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func newLivePod(name string, phase v1.PodPhase, podLabels map[string]string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: podLabels},
+		Status:     v1.PodStatus{Phase: phase},
+	}
+}
+
+func TestComparePodsLive(t *testing.T) {
+	cli := fake.NewSimpleClientset(
+		newLivePod("nginx-1", v1.PodRunning, map[string]string{"app": "nginx"}),
+		newLivePod("nginx-2", v1.PodRunning, map[string]string{"app": "nginx"}),
+		newLivePod("nginx-3", v1.PodRunning, map[string]string{"app": "nginx"}),
+	)
+
+	patches := gomonkey.NewPatches()
+	defer patches.Reset()
+	patches.ApplyFunc(QueryPodsFromDatabase, func(_namespace string) ([]v1.Pod, error) {
+		return []v1.Pod{
+			*newLivePod("nginx-1", "", map[string]string{"app": "nginx"}),
+			*newLivePod("nginx-2", "", map[string]string{"app": "nginx"}),
+			*newLivePod("nginx-4", "", map[string]string{"app": "nginx"}),
+			*newLivePod("redis-1", "", map[string]string{"app": "redis"}),
+		}, nil
+	})
+	patches.ApplyFunc(cachedPodPhase, func(_namespace, podName string) (v1.PodPhase, bool, error) {
+		switch podName {
+		case "nginx-1":
+			return v1.PodRunning, true, nil
+		case "nginx-2", "nginx-4":
+			return v1.PodPending, true, nil
+		}
+		return "", false, nil
+	})
+
+	t.Run("pods by selector", func(t *testing.T) {
+		opts := &common.DiagnoseOptions{Namespace: "default", LabelSelector: "app=nginx"}
+		buf := &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnosePod, common.DiagnoseOutputText, buf)
+		err := comparePodsLive(cli, opts, "", r)
+		require.EqualError(t, err, "3/4 pods of the edge database diverge from the apiserver")
+
+		assert.Equal(t, []common.DiagnoseResult{
+			{Check: "live", Status: common.DiagnoseStatusPass, Message: "Pod nginx-1 is Running in both the apiserver and the edge database"},
+			{Check: "live", Status: common.DiagnoseStatusFail, Message: "Pod nginx-2 is Running in the apiserver, but Pending in the edge database"},
+			{Check: "live", Status: common.DiagnoseStatusFail, Message: "Pod nginx-3 is Running in the apiserver, but not in the edge database"},
+			{Check: "live", Status: common.DiagnoseStatusFail, Message: "Pod nginx-4 is Pending in the edge database, but not in the apiserver"},
+		}, r.Results)
+	})
+
+	t.Run("consistent pod by name", func(t *testing.T) {
+		opts := &common.DiagnoseOptions{Namespace: "default"}
+		r := newTestReport()
+		require.NoError(t, comparePodsLive(cli, opts, "nginx-1", r))
+		require.Len(t, r.Results, 1)
+		assert.Equal(t, common.DiagnoseStatusPass, r.Results[0].Status)
+	})
+
+	t.Run("pod not in the apiserver", func(t *testing.T) {
+		opts := &common.DiagnoseOptions{Namespace: "default"}
+		r := newTestReport()
+		err := comparePodsLive(cli, opts, "nginx-4", r)
+		require.EqualError(t, err, "1/1 pods of the edge database diverge from the apiserver")
+	})
+}
+
+func TestDiagnosePodLiveWithoutKubeConfig(t *testing.T) {
+	kubeConfig := filepath.Join(t.TempDir(), "config")
+	opts := &common.DiagnoseOptions{Namespace: "default", KubeConfig: kubeConfig}
+	err := DiagnosePodLive(opts, "nginx-1", newTestReport())
+	require.ErrorContains(t, err, "--live requires a kubeconfig of the apiserver")
+}
+
+func TestCachedPodPhase(t *testing.T) {
+	patches := gomonkey.NewPatches()
+	defer patches.Reset()
+	patches.ApplyFunc(dao.QueryMetaWithContext, func(_ctx context.Context, _key, condition string) (*[]string, error) {
+		switch condition {
+		case "default/podstatus/nginx-1":
+			return &[]string{`{"uid":"1","name":"nginx-1","Status":{"phase":"Running"}}`}, nil
+		case "default/pod/nginx-2":
+			return &[]string{`{"metadata":{"name":"nginx-2"},"status":{"phase":"Pending"}}`}, nil
+		}
+		return &[]string{}, nil
+	})
+
+	phase, ok, err := cachedPodPhase("default", "nginx-1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, v1.PodRunning, phase)
+
+	phase, ok, err = cachedPodPhase("default", "nginx-2")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, v1.PodPending, phase)
+
+	_, ok, err = cachedPodPhase("default", "nginx-3")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
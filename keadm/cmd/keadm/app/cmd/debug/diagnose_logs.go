@@ -0,0 +1,206 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// podLogsRootDir is the dir edged writes the container logs to, the logs of a container are in
+	// <namespace>_<pod>_<uid>/<container>/<restartCount>.log in the CRI logging format
+	podLogsRootDir = "/var/log/pods"
+	// defaultTailLines is the default number of the last lines of the container logs printed by --logs
+	defaultTailLines = 20
+	// maxTailBytes is the max size of the end of a log file read for the last lines
+	maxTailBytes = 256 * 1024
+)
+
+// printNotReadyContainerLogs prints the last tail lines of the logs of the init containers and the containers
+// of the pod which are not ready, so the crash reason of a container in CrashLoopBackOff is shown inline.
+func printNotReadyContainerLogs(namespace, podName string, tail int, r *DiagnoseReport) {
+	status, ok, err := cachedPodStatus(namespace, podName)
+	if err != nil || !ok {
+		return
+	}
+	var containers []string
+	for _, v := range status.InitContainerStatuses {
+		if v.State.Terminated == nil || v.State.Terminated.ExitCode != 0 {
+			containers = append(containers, v.Name)
+		}
+	}
+	for _, v := range status.ContainerStatuses {
+		if !v.Ready {
+			containers = append(containers, v.Name)
+		}
+	}
+
+	for _, container := range containers {
+		file, lines, err := tailContainerLog(podLogsRootDir, namespace, podName, container, tail)
+		if err != nil {
+			r.Printf("Failed to read the logs of container %s of pod %s: %v\n", container, podName, err)
+			continue
+		}
+		r.Printf("Last %d lines of the logs of container %s in %s:\n", len(lines), container, file)
+		for _, line := range lines {
+			r.Printf("  %s\n", line)
+		}
+	}
+}
+
+// tailContainerLog returns the log file of the container and its last tail lines, the messages are stripped
+// of the timestamp, stream and tag of the CRI logging format. The log of the latest run of the container is read,
+// or the run before if the latest one has not written any log yet, e.g. it is waiting to be restarted.
+func tailContainerLog(rootDir, namespace, podName, container string, tail int) (string, []string, error) {
+	dir, err := containerLogDir(rootDir, namespace, podName, container)
+	if err != nil {
+		return "", nil, err
+	}
+	logs, err := containerLogFiles(dir)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(logs) == 0 {
+		return "", nil, fmt.Errorf("no log files in %s", dir)
+	}
+
+	var file string
+	var lines []string
+	for i := len(logs) - 1; i >= 0 && i >= len(logs)-2; i-- {
+		file = logs[i]
+		lines, err = tailLines(file, tail)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(lines) > 0 {
+			break
+		}
+	}
+	for i, line := range lines {
+		lines[i] = criLogMessage(line)
+	}
+	return file, lines, nil
+}
+
+// containerLogDir returns the log dir of the container, the dir of the pod is the latest one
+// if a pod of the same name was recreated with another uid
+func containerLogDir(rootDir, namespace, podName, container string) (string, error) {
+	dirs, err := filepath.Glob(filepath.Join(rootDir, fmt.Sprintf("%s_%s_*", namespace, podName), container))
+	if err != nil {
+		return "", err
+	}
+	if len(dirs) == 0 {
+		return "", fmt.Errorf("log dir of the container is not found in %s", rootDir)
+	}
+	var latest string
+	var latestModTime int64
+	for _, dir := range dirs {
+		info, err := os.Stat(dir)
+		if err != nil {
+			continue
+		}
+		if latest == "" || info.ModTime().UnixNano() > latestModTime {
+			latest, latestModTime = dir, info.ModTime().UnixNano()
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("log dir of the container is not found in %s", rootDir)
+	}
+	return latest, nil
+}
+
+// containerLogFiles returns the <restartCount>.log files in dir ordered by the restart count,
+// the rotated and compressed logs are ignored
+func containerLogFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	var logs []string
+	for _, e := range entries {
+		count, err := strconv.Atoi(strings.TrimSuffix(e.Name(), ".log"))
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".log") || err != nil {
+			continue
+		}
+		file := filepath.Join(dir, e.Name())
+		counts[file] = count
+		logs = append(logs, file)
+	}
+	sort.Slice(logs, func(i, j int) bool { return counts[logs[i]] < counts[logs[j]] })
+	return logs, nil
+}
+
+// tailLines returns the last n lines of the file, only the last maxTailBytes of the file are read
+func tailLines(file string, n int) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := info.Size() - maxTailBytes
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	// the first line is cut by the offset
+	if offset > 0 {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+	data = bytes.TrimRight(data, "\n")
+	if len(data) == 0 || n <= 0 {
+		return nil, nil
+	}
+	lines := strings.Split(string(data), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
+}
+
+// criLogMessage returns the message of a line in the CRI logging format "<timestamp> <stream> <tag> <message>",
+// the line is returned as is if it is not in the format
+func criLogMessage(line string) string {
+	fields := strings.SplitN(line, " ", 4)
+	if len(fields) < 3 || (fields[1] != "stdout" && fields[1] != "stderr") {
+		return line
+	}
+	if len(fields) == 3 {
+		return ""
+	}
+	return fields[3]
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func writeTestContainerLog(t *testing.T, dir, name string, lines ...string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0700))
+	var data string
+	for _, line := range lines {
+		data += "2025-01-01T00:00:00.000000000Z stderr F " + line + "\n"
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0600))
+}
+
+func TestTailContainerLog(t *testing.T) {
+	rootDir := t.TempDir()
+	oldDir := filepath.Join(rootDir, "test_nginx_uid-1", "nginx")
+	dir := filepath.Join(rootDir, "test_nginx_uid-2", "nginx")
+	writeTestContainerLog(t, oldDir, "0.log", "old pod")
+	writeTestContainerLog(t, dir, "1.log", "starting", "panic: config not found")
+	writeTestContainerLog(t, dir, "10.log")
+	writeTestContainerLog(t, dir, "2.log", "line 1", "line 2", "line 3")
+	writeTestContainerLog(t, dir, "2.log.20250101-000000.gz", "rotated")
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(rootDir, "test_nginx_uid-1", "nginx"), past, past))
+
+	t.Run("the latest run has not written logs", func(t *testing.T) {
+		file, lines, err := tailContainerLog(rootDir, "test", "nginx", "nginx", 2)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "2.log"), file)
+		assert.Equal(t, []string{"line 2", "line 3"}, lines)
+	})
+
+	t.Run("the latest run", func(t *testing.T) {
+		writeTestContainerLog(t, dir, "10.log", "restarted")
+		file, lines, err := tailContainerLog(rootDir, "test", "nginx", "nginx", 20)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "10.log"), file)
+		assert.Equal(t, []string{"restarted"}, lines)
+	})
+
+	t.Run("log dir is not found", func(t *testing.T) {
+		_, _, err := tailContainerLog(rootDir, "test", "nginx", "sidecar", 20)
+		require.ErrorContains(t, err, "log dir of the container is not found")
+	})
+}
+
+func TestTailLines(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "0.log")
+	var lines []string
+	for i := 0; i < maxTailBytes/10; i++ {
+		lines = append(lines, fmt.Sprintf("line %05d", i))
+	}
+	require.NoError(t, os.WriteFile(file, []byte(strings.Join(lines, "\n")+"\n"), 0600))
+
+	got, err := tailLines(file, 3)
+	require.NoError(t, err)
+	assert.Equal(t, lines[len(lines)-3:], got)
+
+	// only the end of a large file is read, and the cut first line is dropped
+	got, err = tailLines(file, len(lines))
+	require.NoError(t, err)
+	assert.Less(t, len(got), len(lines))
+	assert.Equal(t, lines[len(lines)-len(got):], got)
+
+	got, err = tailLines(file, 0)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestCRILogMessage(t *testing.T) {
+	assert.Equal(t, "panic: boom", criLogMessage("2025-01-01T00:00:00.000000000Z stderr F panic: boom"))
+	assert.Equal(t, "", criLogMessage("2025-01-01T00:00:00.000000000Z stdout F"))
+	assert.Equal(t, "plain log line", criLogMessage("plain log line"))
+}
+
+func TestPrintNotReadyContainerLogs(t *testing.T) {
+	patches := gomonkey.ApplyFunc(cachedPodStatus, func(_namespace, _podName string) (*v1.PodStatus, bool, error) {
+		return &v1.PodStatus{
+			InitContainerStatuses: []v1.ContainerStatus{
+				{Name: "init", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 0}}},
+			},
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "nginx", Ready: true},
+				{Name: "sidecar", Ready: false},
+			},
+		}, true, nil
+	})
+	defer patches.Reset()
+	patches.ApplyFunc(tailContainerLog, func(_rootDir, _namespace, _podName, container string, tail int) (string, []string, error) {
+		return "/var/log/pods/test_nginx_uid/" + container + "/0.log", []string{"panic: boom"}, nil
+	})
+
+	buf := &bytes.Buffer{}
+	r := NewDiagnoseReport(common.ArgDiagnosePod, common.DiagnoseOutputText, buf)
+	printNotReadyContainerLogs("test", "nginx", 20, r)
+	assert.Equal(t, "Last 1 lines of the logs of container sidecar in /var/log/pods/test_nginx_uid/sidecar/0.log:\n  panic: boom\n", buf.String())
+	assert.Empty(t, r.Results)
+}
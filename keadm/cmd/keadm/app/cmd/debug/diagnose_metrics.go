@@ -0,0 +1,100 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+// metricLabelEscaper escapes a label value of the prometheus text format
+var metricLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteMetrics writes the results of the report in the prometheus text format, which the textfile collector of
+// node-exporter scrapes. Every check is reported by a pass, a warn, a fail and a skip sample, the one of the check status is 1.
+// A check recorded more than once takes the worst status of its results.
+func WriteMetrics(w io.Writer, r *DiagnoseReport, now time.Time) error {
+	diagnose := metricLabelEscaper.Replace(r.Diagnose)
+	node := metricLabelEscaper.Replace(r.NodeName)
+	checks, statuses := r.checkStatuses()
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "# HELP kubeedge_diagnose_check Whether the check of the last diagnose run is of the status.\n")
+	fmt.Fprintf(buf, "# TYPE kubeedge_diagnose_check gauge\n")
+	for _, check := range checks {
+		for _, status := range []string{common.DiagnoseStatusPass, common.DiagnoseStatusWarn, common.DiagnoseStatusFail, common.DiagnoseStatusSkip} {
+			var value int
+			if statuses[check] == status {
+				value = 1
+			}
+			fmt.Fprintf(buf, "kubeedge_diagnose_check{diagnose=\"%s\",node=\"%s\",check=\"%s\",status=\"%s\"} %d\n",
+				diagnose, node, metricLabelEscaper.Replace(check), status, value)
+		}
+	}
+
+	success := 1
+	for _, status := range statuses {
+		if status == common.DiagnoseStatusFail {
+			success = 0
+		}
+	}
+	fmt.Fprintf(buf, "# HELP kubeedge_diagnose_success Whether all the checks of the last diagnose run passed.\n")
+	fmt.Fprintf(buf, "# TYPE kubeedge_diagnose_success gauge\n")
+	fmt.Fprintf(buf, "kubeedge_diagnose_success{diagnose=\"%s\",node=\"%s\"} %d\n", diagnose, node, success)
+	fmt.Fprintf(buf, "# HELP kubeedge_diagnose_health_score The health score from 0 to 100 of the weighted statuses of the checks of the last diagnose run.\n")
+	fmt.Fprintf(buf, "# TYPE kubeedge_diagnose_health_score gauge\n")
+	fmt.Fprintf(buf, "kubeedge_diagnose_health_score{diagnose=\"%s\",node=\"%s\"} %d\n", diagnose, node, healthScore(checks, statuses, r.scoreWeights))
+	fmt.Fprintf(buf, "# HELP kubeedge_diagnose_last_run_timestamp_seconds The unix time of the last diagnose run.\n")
+	fmt.Fprintf(buf, "# TYPE kubeedge_diagnose_last_run_timestamp_seconds gauge\n")
+	fmt.Fprintf(buf, "kubeedge_diagnose_last_run_timestamp_seconds{diagnose=\"%s\",node=\"%s\"} %d\n", diagnose, node, now.Unix())
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeMetricsFile writes the metrics of the report to file. The metrics are written to a temporary file
+// in the same directory which is renamed to file, so that the textfile collector never reads a partial file.
+func writeMetricsFile(file string, r *DiagnoseReport, now time.Time) error {
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create metrics file %s: %v", file, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := WriteMetrics(tmp, r, now); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write metrics file %s: %v", file, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics file %s: %v", file, err)
+	}
+	// the textfile collector runs as another user, the metrics are not sensitive
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write metrics file %s: %v", file, err)
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return fmt.Errorf("failed to write metrics file %s: %v", file, err)
+	}
+	return nil
+}
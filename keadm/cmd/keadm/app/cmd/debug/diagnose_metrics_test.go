@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func newMetricsTestReport() *DiagnoseReport {
+	r := NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputJSON, &bytes.Buffer{})
+	r.SetNodeName("edge-node-1")
+	r.Pass("edgecore", "edgecore is running\n")
+	r.Pass("container", "containerConditions nginx is ready\n")
+	r.Failf("container", "containerConditions redis is not ready\n")
+	_ = r.Fail("cloudhub", networkError(errors.New("cloudcore websocket connection failed")))
+	return r
+}
+
+func TestWriteMetrics(t *testing.T) {
+	now := time.Unix(1735830245, 0)
+	buf := &bytes.Buffer{}
+	require.NoError(t, WriteMetrics(buf, newMetricsTestReport(), now))
+
+	assert.Equal(t, `# HELP kubeedge_diagnose_check Whether the check of the last diagnose run is of the status.
+# TYPE kubeedge_diagnose_check gauge
+kubeedge_diagnose_check{diagnose="node",node="edge-node-1",check="edgecore",status="pass"} 1
+kubeedge_diagnose_check{diagnose="node",node="edge-node-1",check="edgecore",status="warn"} 0
+kubeedge_diagnose_check{diagnose="node",node="edge-node-1",check="edgecore",status="fail"} 0
+kubeedge_diagnose_check{diagnose="node",node="edge-node-1",check="edgecore",status="skip"} 0
+kubeedge_diagnose_check{diagnose="node",node="edge-node-1",check="container",status="pass"} 0
+kubeedge_diagnose_check{diagnose="node",node="edge-node-1",check="container",status="warn"} 0
+kubeedge_diagnose_check{diagnose="node",node="edge-node-1",check="container",status="fail"} 1
+kubeedge_diagnose_check{diagnose="node",node="edge-node-1",check="container",status="skip"} 0
+kubeedge_diagnose_check{diagnose="node",node="edge-node-1",check="cloudhub",status="pass"} 0
+kubeedge_diagnose_check{diagnose="node",node="edge-node-1",check="cloudhub",status="warn"} 0
+kubeedge_diagnose_check{diagnose="node",node="edge-node-1",check="cloudhub",status="fail"} 1
+kubeedge_diagnose_check{diagnose="node",node="edge-node-1",check="cloudhub",status="skip"} 0
+# HELP kubeedge_diagnose_success Whether all the checks of the last diagnose run passed.
+# TYPE kubeedge_diagnose_success gauge
+kubeedge_diagnose_success{diagnose="node",node="edge-node-1"} 0
+# HELP kubeedge_diagnose_health_score The health score from 0 to 100 of the weighted statuses of the checks of the last diagnose run.
+# TYPE kubeedge_diagnose_health_score gauge
+kubeedge_diagnose_health_score{diagnose="node",node="edge-node-1"} 45
+# HELP kubeedge_diagnose_last_run_timestamp_seconds The unix time of the last diagnose run.
+# TYPE kubeedge_diagnose_last_run_timestamp_seconds gauge
+kubeedge_diagnose_last_run_timestamp_seconds{diagnose="node",node="edge-node-1"} 1735830245
+`, buf.String())
+}
+
+func TestWriteMetricsEscapeLabels(t *testing.T) {
+	r := NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputJSON, &bytes.Buffer{})
+	r.NodeName = `edge "1"`
+	r.Pass(`a\b`, "passed\n")
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, WriteMetrics(buf, r, time.Unix(0, 0)))
+	assert.Contains(t, buf.String(), `kubeedge_diagnose_check{diagnose="node",node="edge \"1\"",check="a\\b",status="pass"} 1`)
+	assert.Contains(t, buf.String(), `kubeedge_diagnose_success{diagnose="node",node="edge \"1\""} 1`)
+}
+
+func TestWriteMetricsFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "kubeedge.prom")
+	require.NoError(t, writeMetricsFile(file, newMetricsTestReport(), time.Unix(0, 0)))
+
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `kubeedge_diagnose_success{diagnose="node",node="edge-node-1"} 0`)
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	// the temporary file is renamed to the metrics file
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	err = writeMetricsFile(filepath.Join(dir, "not-exist", "kubeedge.prom"), newMetricsTestReport(), time.Unix(0, 0))
+	assert.ErrorContains(t, err, "failed to create metrics file")
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubeedge/api/apis/common/constants"
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/util/files"
+)
+
+const (
+	ModuleEdged       = "edged"
+	ModuleEdgeHub     = "edgehub"
+	ModuleEventBus    = "eventbus"
+	ModuleMetaManager = "metamanager"
+	ModuleDeviceTwin  = "devicetwin"
+	ModuleServiceBus  = "servicebus"
+	ModuleEdgeStream  = "edgestream"
+)
+
+// moduleDiagnoser diagnoses a single edgecore module
+type moduleDiagnoser struct {
+	// enabled returns whether the module is enabled in the edgecore config
+	enabled func(cfg *v1alpha2.EdgeCoreConfig) bool
+	// target returns the targets the probe checks
+	target func(cfg *v1alpha2.EdgeCoreConfig) string
+	// probe checks whether the module is alive, it returns the probed targets
+	probe func(cfg *v1alpha2.EdgeCoreConfig, ob *common.CheckOptions) (string, error)
+}
+
+var (
+	// diagnoseModules is the ordered list of modules diagnosed by default
+	diagnoseModules = []string{
+		ModuleEdged,
+		ModuleEdgeHub,
+		ModuleEventBus,
+		ModuleMetaManager,
+		ModuleDeviceTwin,
+		ModuleServiceBus,
+		ModuleEdgeStream,
+	}
+
+	moduleDiagnosers = map[string]moduleDiagnoser{
+		ModuleEdged: {
+			enabled: func(cfg *v1alpha2.EdgeCoreConfig) bool {
+				return cfg.Modules.Edged != nil && cfg.Modules.Edged.Enable
+			},
+			target: edgedTarget,
+			probe:  probeEdged,
+		},
+		ModuleEdgeHub: {
+			enabled: func(cfg *v1alpha2.EdgeCoreConfig) bool {
+				return cfg.Modules.EdgeHub != nil && cfg.Modules.EdgeHub.Enable
+			},
+			target: edgeHubTarget,
+			probe:  probeEdgeHub,
+		},
+		ModuleEventBus: {
+			enabled: func(cfg *v1alpha2.EdgeCoreConfig) bool {
+				return cfg.Modules.EventBus != nil && cfg.Modules.EventBus.Enable
+			},
+			target: eventBusTarget,
+			probe:  probeEventBus,
+		},
+		ModuleMetaManager: {
+			enabled: func(cfg *v1alpha2.EdgeCoreConfig) bool {
+				return cfg.Modules.MetaManager != nil && cfg.Modules.MetaManager.Enable
+			},
+			target: metaManagerTarget,
+			probe:  probeMetaManager,
+		},
+		ModuleDeviceTwin: {
+			enabled: func(cfg *v1alpha2.EdgeCoreConfig) bool {
+				return cfg.Modules.DeviceTwin != nil && cfg.Modules.DeviceTwin.Enable
+			},
+			target: deviceTwinTarget,
+			probe:  probeDeviceTwin,
+		},
+		ModuleServiceBus: {
+			enabled: func(cfg *v1alpha2.EdgeCoreConfig) bool {
+				return cfg.Modules.ServiceBus != nil && cfg.Modules.ServiceBus.Enable
+			},
+			target: serviceBusTarget,
+			probe:  probeServiceBus,
+		},
+		ModuleEdgeStream: {
+			enabled: func(cfg *v1alpha2.EdgeCoreConfig) bool {
+				return cfg.Modules.EdgeStream != nil && cfg.Modules.EdgeStream.Enable
+			},
+			target: edgeStreamTarget,
+			probe:  probeEdgeStream,
+		},
+	}
+)
+
+// DiagnoseModule diagnoses the edgecore modules by name, all modules are diagnosed if names is empty.
+// A module is reported disabled or enabled, and the liveness of an enabled module is probed.
+func DiagnoseModule(ops *common.DiagnoseOptions, names []string, r *DiagnoseReport) error {
+	names, err := diagnoseModuleNames(names)
+	if err != nil {
+		return r.Fail(common.ArgDiagnoseModule, err)
+	}
+
+	if !util.EdgecoreConfigExists(ops.Config) {
+		return r.Fail("config", newDiagnoseExitError(DiagnoseExitCodeConfigNotFound,
+			fmt.Errorf("edge config is not exists")))
+	}
+	edgeconfig, err := util.ParseEdgecoreConfig(ops.Config)
+	if err != nil {
+		return r.Fail("config", fmt.Errorf("parse edgecore config failed"))
+	}
+
+	var failed []string
+	var firstErr error
+	for _, name := range names {
+		d := moduleDiagnosers[name]
+		if !d.enabled(edgeconfig) {
+			r.Skipf(name, "%s: disabled", name)
+			continue
+		}
+		target, err := d.probe(edgeconfig, ops.CheckOptions)
+		if err != nil {
+			failed = append(failed, name)
+			if firstErr == nil {
+				firstErr = err
+			}
+			r.Failf(name, "%s: enabled, probe %s failed, %v\n", name, target, err)
+			continue
+		}
+		r.Pass(name, "%s: enabled, probe %s success\n", name, target)
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+	// the exit code is the one of the first failed probe, e.g. the network one of an unreachable server
+	err = fmt.Errorf("module %s probe failed", strings.Join(failed, ","))
+	var exitErr *DiagnoseExitError
+	if errors.As(firstErr, &exitErr) {
+		err = newDiagnoseExitError(exitErr.ExitCode(), err)
+	}
+	return newDiagnoseError("module", err)
+}
+
+// diagnoseModuleNames returns the modules of names, or all the modules if names is empty
+func diagnoseModuleNames(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return diagnoseModules, nil
+	}
+	for _, name := range names {
+		if _, ok := moduleDiagnosers[name]; !ok {
+			return nil, fmt.Errorf("unsupported module %s, supported modules are %s", name, strings.Join(diagnoseModules, "|"))
+		}
+	}
+	return names, nil
+}
+
+// edgedTarget returns the url of the edged server, which serves the kubelet read-only port
+func edgedTarget(cfg *v1alpha2.EdgeCoreConfig) string {
+	address, port := "127.0.0.1", int32(constants.ServerPort)
+	if kc := cfg.Modules.Edged.TailoredKubeletConfig; kc != nil {
+		if kc.Address != "" && kc.Address != "0.0.0.0" && kc.Address != "::" {
+			address = kc.Address
+		}
+		if kc.ReadOnlyPort != 0 {
+			port = kc.ReadOnlyPort
+		}
+	}
+	return "http://" + net.JoinHostPort(address, strconv.Itoa(int(port)))
+}
+
+// probeEdged checks the edged server
+func probeEdged(cfg *v1alpha2.EdgeCoreConfig, ob *common.CheckOptions) (string, error) {
+	return probeHTTP(edgedTarget(cfg), ob)
+}
+
+// edgeHubTarget returns the url of the cloudhub server which edgehub connects to
+func edgeHubTarget(cfg *v1alpha2.EdgeCoreConfig) string {
+	ws := cfg.Modules.EdgeHub.WebSocket
+	if ws == nil || !ws.Enable {
+		return "websocket"
+	}
+	return "https://" + ws.Server
+}
+
+// probeEdgeHub checks the cloudhub server which edgehub connects to
+func probeEdgeHub(cfg *v1alpha2.EdgeCoreConfig, ob *common.CheckOptions) (string, error) {
+	ws := cfg.Modules.EdgeHub.WebSocket
+	if ws == nil || !ws.Enable {
+		return edgeHubTarget(cfg), fmt.Errorf("websocket is not enabled")
+	}
+	return probeHTTP(edgeHubTarget(cfg), ob)
+}
+
+// probeHTTP checks the http server, the probed target is described with the status of the final response
+func probeHTTP(target string, ob *common.CheckOptions) (string, error) {
+	res, err := checkHTTPConn(target, NewHTTPCheckOptions(ob))
+	if err != nil {
+		return target, networkError(err)
+	}
+	return fmt.Sprintf("%s (%s)", target, res.summary(target)), nil
+}
+
+// eventBusTarget returns the mqtt brokers used by the configured mqtt mode
+func eventBusTarget(cfg *v1alpha2.EdgeCoreConfig) string {
+	return strings.Join(MQTTBrokers(cfg.Modules.EventBus), ",")
+}
+
+// probeEventBus checks the mqtt brokers used by the configured mqtt mode
+func probeEventBus(cfg *v1alpha2.EdgeCoreConfig, ob *common.CheckOptions) (string, error) {
+	for _, broker := range MQTTBrokers(cfg.Modules.EventBus) {
+		u, err := url.Parse(broker)
+		if err != nil {
+			return broker, fmt.Errorf("invalid mqtt server %s: %v", broker, err)
+		}
+		if err := CheckTCP(u.Host, ob.Timeout); err != nil {
+			return broker, networkError(err)
+		}
+	}
+	return eventBusTarget(cfg), nil
+}
+
+// metaManagerDataSource returns the database of metamanager
+func metaManagerDataSource(cfg *v1alpha2.EdgeCoreConfig) string {
+	if cfg.DataBase != nil && cfg.DataBase.DataSource != "" {
+		return cfg.DataBase.DataSource
+	}
+	return v1alpha2.DataBaseDataSource
+}
+
+// metaManagerTarget returns the database and the metaserver if it is enabled
+func metaManagerTarget(cfg *v1alpha2.EdgeCoreConfig) string {
+	if ms := cfg.Modules.MetaManager.MetaServer; ms != nil && ms.Enable {
+		return metaManagerDataSource(cfg) + "," + ms.Server
+	}
+	return metaManagerDataSource(cfg)
+}
+
+// probeMetaManager checks the database and the metaserver if it is enabled
+func probeMetaManager(cfg *v1alpha2.EdgeCoreConfig, ob *common.CheckOptions) (string, error) {
+	dataSource := metaManagerDataSource(cfg)
+	if !files.FileExists(dataSource) {
+		return dataSource, fmt.Errorf("dataSource is not exists")
+	}
+
+	ms := cfg.Modules.MetaManager.MetaServer
+	if ms == nil || !ms.Enable {
+		return dataSource, nil
+	}
+	return metaManagerTarget(cfg), networkError(CheckTCP(ms.Server, ob.Timeout))
+}
+
+// deviceTwinTarget returns the DMI socket which the mappers connect to
+func deviceTwinTarget(cfg *v1alpha2.EdgeCoreConfig) string {
+	return "unix://" + cfg.Modules.DeviceTwin.DMISockPath
+}
+
+// probeDeviceTwin checks the DMI socket which the mappers connect to
+func probeDeviceTwin(cfg *v1alpha2.EdgeCoreConfig, ob *common.CheckOptions) (string, error) {
+	conn, err := net.DialTimeout("unix", cfg.Modules.DeviceTwin.DMISockPath, time.Duration(ob.Timeout)*time.Second)
+	if err != nil {
+		return deviceTwinTarget(cfg), networkError(fmt.Errorf("connect fail: %s", err.Error()))
+	}
+	return deviceTwinTarget(cfg), conn.Close()
+}
+
+// serviceBusTarget returns the address of the local http server of servicebus
+func serviceBusTarget(cfg *v1alpha2.EdgeCoreConfig) string {
+	sb := cfg.Modules.ServiceBus
+	return net.JoinHostPort(sb.Server, strconv.Itoa(sb.Port))
+}
+
+// probeServiceBus checks the local http server of servicebus
+func probeServiceBus(cfg *v1alpha2.EdgeCoreConfig, ob *common.CheckOptions) (string, error) {
+	address := serviceBusTarget(cfg)
+	return address, networkError(CheckTCP(address, ob.Timeout))
+}
+
+// edgeStreamTarget returns the tunnel server of cloudstream
+func edgeStreamTarget(cfg *v1alpha2.EdgeCoreConfig) string {
+	return cfg.Modules.EdgeStream.TunnelServer
+}
+
+// probeEdgeStream checks the tunnel to cloudstream is established
+func probeEdgeStream(cfg *v1alpha2.EdgeCoreConfig, ob *common.CheckOptions) (string, error) {
+	return edgeStreamTarget(cfg), networkError(CheckEdgeStream(io.Discard, cfg.Modules.EdgeStream, NewHTTPCheckOptions(ob)))
+}
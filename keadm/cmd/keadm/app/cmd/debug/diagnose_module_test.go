@@ -0,0 +1,144 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/common/constants"
+	cfgv1alpha2 "github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/util/files"
+)
+
+func TestDiagnoseModule(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	addr := listener.Addr().(*net.TCPAddr)
+
+	cfg := cfgv1alpha2.NewDefaultEdgeCoreConfig()
+	cfg.Modules.ServiceBus.Enable = true
+	cfg.Modules.ServiceBus.Server = "127.0.0.1"
+	cfg.Modules.ServiceBus.Port = addr.Port
+	cfg.Modules.EventBus.Enable = true
+	cfg.Modules.EventBus.MqttMode = cfgv1alpha2.MqttModeExternal
+	cfg.Modules.EventBus.MqttServerExternal = "tcp://" + addr.String()
+	cfg.Modules.EdgeStream.Enable = false
+
+	patches := gomonkey.NewPatches()
+	defer patches.Reset()
+	patches.ApplyFunc(files.FileExists, func(path string) bool {
+		return path == constants.EdgecoreConfigPath
+	})
+	patches.ApplyFunc(util.ParseEdgecoreConfig, func(_edgecorePath string) (*cfgv1alpha2.EdgeCoreConfig, error) {
+		return cfg, nil
+	})
+
+	ops := NewDiagnoseOptions()
+
+	t.Run("unsupported module", func(t *testing.T) {
+		err := DiagnoseModule(ops, []string{"cloudhub"}, newTestReport())
+		require.ErrorContains(t, err, "unsupported module cloudhub")
+	})
+
+	t.Run("edge config is not exists", func(t *testing.T) {
+		err := DiagnoseModule(&common.DiagnoseOptions{
+			Config:       "config/edgecore.yaml",
+			CheckOptions: ops.CheckOptions,
+		}, []string{ModuleServiceBus}, newTestReport())
+		var exitErr *DiagnoseExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, DiagnoseExitCodeConfigNotFound, exitErr.ExitCode())
+	})
+
+	t.Run("enabled and disabled modules", func(t *testing.T) {
+		r := newTestReport()
+		err := DiagnoseModule(ops, []string{ModuleServiceBus, ModuleEventBus, ModuleEdgeStream}, r)
+		require.NoError(t, err)
+		assert.Equal(t, []common.DiagnoseResult{
+			{
+				Check:   ModuleServiceBus,
+				Status:  common.DiagnoseStatusPass,
+				Message: "servicebus: enabled, probe " + addr.String() + " success",
+			},
+			{
+				Check:   ModuleEventBus,
+				Status:  common.DiagnoseStatusPass,
+				Message: "eventbus: enabled, probe tcp://" + addr.String() + " success",
+			},
+			{
+				Check:   ModuleEdgeStream,
+				Status:  common.DiagnoseStatusSkip,
+				Message: "edgestream: disabled",
+			},
+		}, r.Results)
+	})
+
+	t.Run("module probe failed", func(t *testing.T) {
+		closed, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		port := closed.Addr().(*net.TCPAddr).Port
+		closed.Close()
+
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+		patches.ApplyFunc(util.ParseEdgecoreConfig, func(_edgecorePath string) (*cfgv1alpha2.EdgeCoreConfig, error) {
+			failedCfg := cfgv1alpha2.NewDefaultEdgeCoreConfig()
+			failedCfg.Modules.ServiceBus.Enable = true
+			failedCfg.Modules.ServiceBus.Server = "127.0.0.1"
+			failedCfg.Modules.ServiceBus.Port = port
+			return failedCfg, nil
+		})
+
+		r := newTestReport()
+		err = DiagnoseModule(ops, []string{ModuleServiceBus}, r)
+		require.ErrorContains(t, err, "module servicebus probe failed")
+		var exitErr *DiagnoseExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, DiagnoseExitCodeNetwork, exitErr.ExitCode())
+		require.Len(t, r.Results, 1)
+		assert.Equal(t, common.DiagnoseStatusFail, r.Results[0].Status)
+		assert.Contains(t, r.Results[0].Message, "127.0.0.1:"+strconv.Itoa(port))
+	})
+
+	t.Run("local probe failed", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+		patches.ApplyFunc(util.ParseEdgecoreConfig, func(_edgecorePath string) (*cfgv1alpha2.EdgeCoreConfig, error) {
+			failedCfg := cfgv1alpha2.NewDefaultEdgeCoreConfig()
+			failedCfg.DataBase.DataSource = "/nonexistent/edgecore.db"
+			return failedCfg, nil
+		})
+
+		r := newTestReport()
+		err := DiagnoseModule(ops, []string{ModuleMetaManager}, r)
+		require.ErrorContains(t, err, "module metamanager probe failed")
+		var exitErr *DiagnoseExitError
+		assert.False(t, errors.As(err, &exitErr))
+		require.Len(t, r.Results, 1)
+		assert.Equal(t, "metamanager: enabled, probe /nonexistent/edgecore.db failed, dataSource is not exists", r.Results[0].Message)
+	})
+}
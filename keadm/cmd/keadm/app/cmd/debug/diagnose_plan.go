@@ -0,0 +1,421 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeedge/api/apis/common/constants"
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+)
+
+// DiagnosePlanStep is a check the diagnose would run and the endpoint or path it targets
+type DiagnosePlanStep struct {
+	Check  string `json:"check"`
+	Target string `json:"target"`
+}
+
+// DiagnosePlan is the ordered checks of a diagnose printed in dry-run mode
+type DiagnosePlan struct {
+	Diagnose string             `json:"diagnose"`
+	Steps    []DiagnosePlanStep `json:"steps"`
+}
+
+func (p *DiagnosePlan) add(check, format string, a ...interface{}) {
+	p.Steps = append(p.Steps, DiagnosePlanStep{Check: check, Target: fmt.Sprintf(format, a...)})
+}
+
+// DryRunDiagnose prints the checks the diagnose object use would run and their targets in order,
+// the targets are derived from the edgecore config, and none of the checks is run.
+// In the go-template output mode the template is executed on the plan instead of the report.
+func DryRunDiagnose(use string, ops *common.DiagnoseOptions, args []string, w io.Writer) error {
+	plan := &DiagnosePlan{Diagnose: use}
+	switch use {
+	case common.ArgDiagnoseNode, common.ArgDiagnoseDeployment, common.ArgDiagnoseWorkload, common.ArgDiagnoseDevice:
+		if err := planDiagnoseNodeFromConfig(ops, plan); err != nil {
+			return err
+		}
+	case common.ArgDiagnosePod:
+		if ops.DBPath != "" {
+			plan.add("database", "%s", ops.DBPath)
+		} else if err := planDiagnoseNodeFromConfig(ops, plan); err != nil {
+			return err
+		}
+	case common.ArgDiagnoseInstall:
+		planDiagnoseInstall(ops, plan)
+	case common.ArgDiagnoseAll:
+		planDiagnoseInstall(ops, plan)
+		if err := planDiagnoseNodeFromConfig(ops, plan); err != nil {
+			return err
+		}
+	case common.ArgDiagnoseModule:
+		if err := planDiagnoseModule(ops, args, plan); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("--dry-run is not supported by diagnose %s", use)
+	}
+	planDiagnoseObject(use, ops, args, plan)
+	if ops.Output == common.DiagnoseOutputGoTemplate {
+		tmpl, err := parseDiagnoseTemplate(ops.Template)
+		if err != nil {
+			return err
+		}
+		return executeDiagnoseTemplate(w, tmpl, plan)
+	}
+	return writeDiagnosePlan(w, ops.Output, plan)
+}
+
+// planDiagnoseNodeFromConfig parses the edgecore config of the node and adds the node checks to the plan
+func planDiagnoseNodeFromConfig(ops *common.DiagnoseOptions, plan *DiagnosePlan) error {
+	ex, err := diagnoseExecutor(ops)
+	if err != nil {
+		return fmt.Errorf("find the container of edgecore failed: %v", err)
+	}
+	if ops.Config != util.EdgecoreConfigStdin && !ex.FileExists(ops.Config) {
+		return fmt.Errorf("edge config %s is not exists, the checks are planned from it", ops.Config)
+	}
+	edgeconfig, err := ex.ParseEdgecoreConfig(ops.Config)
+	if err != nil {
+		return fmt.Errorf("parse edgecore config %s failed: %v", ops.Config, err)
+	}
+	planDiagnoseNode(ops, edgeconfig, plan)
+	return nil
+}
+
+// planDiagnoseNode adds the checks of DiagnoseNode to the plan in the same order as they are run
+func planDiagnoseNode(ops *common.DiagnoseOptions, edgeconfig *v1alpha2.EdgeCoreConfig, plan *DiagnosePlan) {
+	switch {
+	case ops.Host != "":
+		plan.add("edgecore", "process %s on %s", constants.KubeEdgeBinaryName, ops.Host)
+	case ops.Container != "":
+		plan.add("edgecore", "process %s in container %s", constants.KubeEdgeBinaryName, ops.Container)
+	default:
+		plan.add("edgecore", "process %s", constants.KubeEdgeBinaryName)
+	}
+	plan.add("edgecore-user", "user of process %s is %s", constants.KubeEdgeBinaryName, edgecoreUser)
+	plan.add("config", "%s", ops.Config)
+	dataSource := v1alpha2.DataBaseDataSource
+	if edgeconfig.DataBase != nil && edgeconfig.DataBase.DataSource != "" {
+		dataSource = edgeconfig.DataBase.DataSource
+	}
+	plan.add("database", "%s", dataSource)
+	// the other checks are skipped on the remote node
+	if ops.Host != "" {
+		return
+	}
+
+	// edgecore in the container is not managed by systemd
+	if ops.Container == "" {
+		plan.add("config-drift", "config flag and start time of process %s, modification time of %s", constants.KubeEdgeBinaryName, ops.Config)
+		plan.add("systemd", "edgecore.service")
+		plan.add("edgecore-log", "panics and fatal errors of the last %v in %s or the journal of edgecore.service",
+			ops.CheckOptions.LogWindow, ops.CheckOptions.EdgecoreLogFile)
+	}
+	plan.add("file-limits", "open files of edgecore in %s, fs.file-nr, fs.inotify.max_user_instances, fs.inotify.max_user_watches", procDir)
+	plan.add("conntrack", "%s, %s", conntrackCountKey, conntrackMaxKey)
+	plan.add("node", "default/node/%s in %s", DiagnoseNodeName(ops.Config), dataSource)
+	if edged := edgeconfig.Modules.Edged; edged != nil && edged.Enable {
+		plan.add("node-labels", "labels and taints of default/node/%s in %s", DiagnoseNodeName(ops.Config), dataSource)
+	}
+	plan.add("disk-paths", "%s", strings.Join(edgecoreDiskPaths(edgeconfig), ", "))
+	plan.add("writable-dirs", "%s", strings.Join(edgecoreWritableDirs(edgeconfig), ", "))
+
+	endpoint := constants.DefaultRemoteRuntimeEndpoint
+	if edged := edgeconfig.Modules.Edged; edged != nil && edged.TailoredKubeletConfig != nil &&
+		edged.TailoredKubeletConfig.ContainerRuntimeEndpoint != "" {
+		endpoint = edged.TailoredKubeletConfig.ContainerRuntimeEndpoint
+	}
+	plan.add("runtime", "%s", endpoint)
+
+	if edged := edgeconfig.Modules.Edged; edged != nil && edged.Enable && edged.TailoredKubeletConfig != nil {
+		kubelet := edged.TailoredKubeletConfig
+		confDir, binDir := ops.CheckOptions.CNIConfDir, ops.CheckOptions.CNIBinDir
+		if confDir == "" {
+			confDir = constants.DefaultCNIConfDir
+		}
+		if binDir == "" {
+			binDir = constants.DefaultCNIBinDir
+		}
+		plan.add("kubelet", "processes named %s, kubelet.service", kubeletProcessName)
+		driver := kubelet.CgroupDriver
+		if driver == "" {
+			driver = defaultCgroupDriver
+		}
+		plan.add("cgroup-driver", "cgroup driver %s of edged, runtime config of %s", driver, endpoint)
+		plan.add("cni", "config dir %s, plugin dirs %s", confDir, binDir)
+		plan.add("pod-dns", "%s", podDNSTarget(kubelet))
+		plan.add("dns-addon", "pods of %s in %s", dnsAddonNames(), dataSource)
+		if kubelet.ReadOnlyPort == 0 {
+			plan.add("edged", "read-only port is disabled")
+		} else {
+			plan.add("edged", "tcp://%s", edgedPortAddress(kubelet.Address, kubelet.ReadOnlyPort))
+		}
+		plan.add("eviction", "memory.available of %s", procMeminfo)
+		plan.add("pod-dirs", "%s", filepath.Join(edgedRootDir(edged), "pods"))
+		maxPods := kubelet.MaxPods
+		if maxPods <= 0 {
+			maxPods = defaultMaxPods
+		}
+		plan.add("max-pods", "active pods in %s against maxPods %d of edged", dataSource, maxPods)
+		plan.add("host-paths", "hostPath volumes of the active pods in %s", dataSource)
+		if len(ops.CheckOptions.Registries) > 0 {
+			plan.add("registry", "%s", strings.Join(ops.CheckOptions.Registries, ", "))
+		} else {
+			plan.add("registry", "registries of the images of the cached pods in %s", dataSource)
+		}
+	}
+
+	if eb := edgeconfig.Modules.EventBus; eb != nil && eb.Enable {
+		plan.add("mqtt", "%s", strings.Join(MQTTBrokers(eb), ", "))
+	} else {
+		plan.add("mqtt", "eventbus is disabled")
+	}
+	if sb := edgeconfig.Modules.ServiceBus; sb != nil && sb.Enable {
+		plan.add("servicebus", "tcp://%s", edgedPortAddress(sb.Server, int32(sb.Port)))
+	}
+
+	eh := edgeconfig.Modules.EdgeHub
+	wsEnabled := eh != nil && eh.WebSocket != nil && eh.WebSocket.Enable
+	quicEnabled := eh != nil && eh.Quic != nil && eh.Quic.Enable
+	if !wsEnabled && !quicEnabled {
+		plan.add("cloudhub", "edgehub is not enabled, the diagnose stops here")
+		return
+	}
+	if wsEnabled {
+		plan.add("cloudhub-dns", "%s", eh.WebSocket.Server)
+		plan.add("cloudhub", "%s", edgeHubWebSocketURL(edgeconfig))
+	} else {
+		plan.add("cloudhub-dns", "%s", eh.Quic.Server)
+	}
+	if quicEnabled {
+		plan.add("cloudhub-quic", "quic://%s", eh.Quic.Server)
+	}
+	plan.add("node-identity", "machine id, system uuid and internal ip of node %s in the apiserver of %s, or of default/node/%s in %s without it",
+		DiagnoseNodeName(ops.Config), ops.KubeConfig, DiagnoseNodeName(ops.Config), dataSource)
+	if es := edgeconfig.Modules.EdgeStream; es != nil && es.Enable {
+		plan.add("edgestream", "%s", edgeStreamTunnelURL(es))
+	}
+	plan.add("version", "edgecore --version, deployment kubeedge/cloudcore in the apiserver of %s", ops.KubeConfig)
+	if wsEnabled {
+		plan.add("cloudhub-latency", "https://%s", eh.WebSocket.Server)
+		plan.add("mtu", "path MTU of tcp://%s, at least %d", eh.WebSocket.Server, ops.CheckOptions.MinMTU)
+	}
+	plan.add("timesync", "ntpd, chronyd or systemd-timesyncd process")
+	if ops.CheckOptions.ExpectedTZ != "" {
+		plan.add("timezone", "%s/localtime against %s", hostEtcDir, ops.CheckOptions.ExpectedTZ)
+	} else {
+		plan.add("timezone", "%s/localtime", hostEtcDir)
+	}
+	if wsEnabled {
+		plan.add("clock", "https://%s", eh.WebSocket.Server)
+	} else {
+		plan.add("clock", "cloudhub server is not specified")
+	}
+}
+
+// planDiagnoseInstall adds the install checks selected by the options to the plan in the order their results are recorded
+func planDiagnoseInstall(ops *common.DiagnoseOptions, plan *DiagnosePlan) {
+	opts := *ops.CheckOptions
+	if opts.Config == "" {
+		opts.Config = ops.Config
+	}
+	var edgeconfig *v1alpha2.EdgeCoreConfig
+	if util.EdgecoreConfigExists(opts.Config) {
+		edgeconfig, _ = util.ParseEdgecoreConfig(opts.Config)
+	}
+
+	for _, c := range installCheckers {
+		if checkerSkipReason(c, &opts) != "" {
+			continue
+		}
+		switch c.Name() {
+		case common.ArgCheckCPU:
+			plan.add(c.Name(), "logical cores and cpu usage, at least %d cores", opts.MinCPU)
+		case common.ArgCheckMemory:
+			plan.add(c.Name(), "memory and memory usage, at least %d MB", opts.MinMemory)
+		case common.ArgCheckDisk:
+			plan.add(c.Name(), "system disk and disk usage, at least %d MB", opts.MinDisk)
+		case "disk-paths":
+			plan.add(c.Name(), "%s", strings.Join(edgecoreDiskPaths(edgeconfig), ", "))
+		case "kernel":
+			plan.add(c.Name(), "kernel modules %s, sysctls %s", strings.Join(opts.KernelModules, ", "), strings.Join(opts.Sysctls, ", "))
+		case "swap":
+			plan.add(c.Name(), "%s", procSwaps)
+		case common.ArgCheckDNS:
+			if opts.DNSIP != "" {
+				plan.add(c.Name(), "%s by the nameserver %s", opts.Domain, opts.DNSIP)
+			} else {
+				plan.add(c.Name(), "%s by the nameservers of %s", opts.Domain, common.PathDNSResolv)
+			}
+		case common.ArgCheckNetwork:
+			plan.add(c.Name(), "%s", networkCheckTargets(&opts, edgeconfig))
+		case "timesync":
+			plan.add(c.Name(), "ntpd, chronyd or systemd-timesyncd process")
+		case "timezone":
+			if opts.ExpectedTZ != "" {
+				plan.add(c.Name(), "%s/localtime against %s", hostEtcDir, opts.ExpectedTZ)
+			} else {
+				plan.add(c.Name(), "%s/localtime", hostEtcDir)
+			}
+		case "clock":
+			if server, _ := clockSkewCheckOptions(&opts); server != "" {
+				plan.add(c.Name(), "https://%s", server)
+			} else {
+				plan.add(c.Name(), "cloudhub server is not specified")
+			}
+		case common.ArgCheckPID:
+			plan.add(c.Name(), "maximum PIDs and running processes")
+		case common.ArgCheckCert:
+			plan.add(c.Name(), "certificates of %s", opts.Config)
+		}
+	}
+}
+
+// networkCheckTargets returns the targets of the network check, the cloudhub server is read from the edgecore config
+// if it is not specified
+func networkCheckTargets(opts *common.CheckOptions, edgeconfig *v1alpha2.EdgeCoreConfig) string {
+	var targets []string
+	if opts.IP != "" {
+		targets = append(targets, "ping "+opts.IP)
+	} else {
+		targets = append(targets, "ping the nameserver of "+common.PathDNSResolv)
+	}
+	server := opts.CloudHubServer
+	if server == "" && edgeconfig != nil && edgeconfig.Modules.EdgeHub != nil && edgeconfig.Modules.EdgeHub.WebSocket != nil {
+		server = edgeconfig.Modules.EdgeHub.WebSocket.Server
+	}
+	if server != "" {
+		targets = append(targets, "https://"+server)
+	}
+	edgecoreServer := opts.EdgecoreServer
+	if edgecoreServer == "" {
+		edgecoreServer = common.EdgeCoreServer
+	}
+	return strings.Join(append(targets, "http://"+edgecoreServer), ", ")
+}
+
+// planDiagnoseModule adds the probes of the modules to the plan, all the modules are planned if names is empty
+func planDiagnoseModule(ops *common.DiagnoseOptions, names []string, plan *DiagnosePlan) error {
+	names, err := diagnoseModuleNames(names)
+	if err != nil {
+		return err
+	}
+	if !util.EdgecoreConfigExists(ops.Config) {
+		return fmt.Errorf("edge config %s is not exists, the checks are planned from it", ops.Config)
+	}
+	edgeconfig, err := util.ParseEdgecoreConfig(ops.Config)
+	if err != nil {
+		return fmt.Errorf("parse edgecore config %s failed: %v", ops.Config, err)
+	}
+	plan.add("config", "%s", ops.Config)
+	for _, name := range names {
+		d := moduleDiagnosers[name]
+		if !d.enabled(edgeconfig) {
+			plan.add(name, "%s is disabled", name)
+			continue
+		}
+		plan.add(name, "%s", d.target(edgeconfig))
+	}
+	return nil
+}
+
+// planDiagnoseObject adds the schema check of the database and the checks of the pods, the deployment or the device
+// following the node checks to the plan
+func planDiagnoseObject(use string, ops *common.DiagnoseOptions, args []string, plan *DiagnosePlan) {
+	switch use {
+	case common.ArgDiagnosePod, common.ArgDiagnoseDeployment, common.ArgDiagnoseWorkload, common.ArgDiagnoseDevice,
+		common.ArgDiagnoseAll:
+		plan.add("database-schema", "%d tables of the edgecore models", len(edgecoreModels))
+	}
+	switch use {
+	case common.ArgDiagnoseAll:
+		plan.add("pods", "pods in %s of the database", namespaceScope(ops))
+	case common.ArgDiagnosePod:
+		switch {
+		case ops.UID != "":
+			plan.add("pod", "pod of uid %s in %s", ops.UID, namespaceScope(ops))
+		case ops.LabelSelector != "":
+			plan.add("pod", "pods matching %s in %s", ops.LabelSelector, namespaceScope(ops))
+		default:
+			for _, name := range args {
+				plan.add("pod", "%s/pod/%s", ops.Namespace, name)
+			}
+		}
+		if ops.Live {
+			plan.add("live", "pods in the apiserver of %s", ops.KubeConfig)
+		}
+	case common.ArgDiagnoseDeployment:
+		if len(args) > 0 {
+			plan.add("deployment", "%s/%s/%s", ops.Namespace, resourceTypeDeployment, args[0])
+		}
+	case common.ArgDiagnoseWorkload:
+		if len(args) > 1 && strings.ToLower(args[0]) == workloadKindDeployment {
+			plan.add("deployment", "%s/%s/%s", ops.Namespace, resourceTypeDeployment, args[1])
+		} else if len(args) > 1 {
+			plan.add("workload", "pods owned by %s %s in %s", args[0], args[1], ops.Namespace)
+		}
+	case common.ArgDiagnoseDevice:
+		if len(args) > 0 {
+			plan.add("device", "device %s", args[0])
+			plan.add("twin", "twins of device %s", args[0])
+		}
+	}
+}
+
+// writeDiagnosePlan writes the plan as a table in text output mode, or as a json or yaml document,
+// or as a json line per step in jsonl output mode
+func writeDiagnosePlan(w io.Writer, output string, plan *DiagnosePlan) error {
+	switch output {
+	case common.DiagnoseOutputJSONL:
+		for _, step := range plan.Steps {
+			if err := writeJSONLine(w, step); err != nil {
+				return err
+			}
+		}
+		return nil
+	case common.DiagnoseOutputJSON:
+		data, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal diagnose plan: %v", err)
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	case common.DiagnoseOutputYAML:
+		data, err := yaml.Marshal(plan)
+		if err != nil {
+			return fmt.Errorf("failed to marshal diagnose plan: %v", err)
+		}
+		_, err = w.Write(data)
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "STEP\tCHECK\tTARGET\n")
+	for i, step := range plan.Steps {
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", i+1, step.Check, step.Target)
+	}
+	return tw.Flush()
+}
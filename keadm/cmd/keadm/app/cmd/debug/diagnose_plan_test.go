@@ -0,0 +1,259 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func newPlanEdgeCoreConfig() *v1alpha2.EdgeCoreConfig {
+	cfg := &v1alpha2.EdgeCoreConfig{
+		DataBase: &v1alpha2.DataBase{DataSource: "/var/lib/kubeedge/edgecore.db"},
+		Modules: &v1alpha2.Modules{
+			Edged: &v1alpha2.Edged{
+				Enable: true,
+				TailoredKubeletConfig: &v1alpha2.TailoredKubeletConfiguration{
+					ContainerRuntimeEndpoint: "unix:///run/containerd/containerd.sock",
+					ReadOnlyPort:             10350,
+				},
+			},
+			EventBus:   &v1alpha2.EventBus{Enable: true, MqttMode: v1alpha2.MqttModeExternal, MqttServerExternal: "tcp://127.0.0.1:1883"},
+			ServiceBus: &v1alpha2.ServiceBus{Enable: true, Server: "127.0.0.1", Port: 9060},
+			EdgeHub: &v1alpha2.EdgeHub{
+				ProjectID: "e632aba927ea4ac2b575ec1603d56f10",
+				WebSocket: &v1alpha2.EdgeHubWebSocket{Enable: true, Server: "10.0.0.1:10000"},
+			},
+			EdgeStream: &v1alpha2.EdgeStream{Enable: true, TunnelServer: "10.0.0.1:10004"},
+		},
+	}
+	cfg.Modules.Edged.HostnameOverride = "edge-node"
+	return cfg
+}
+
+func writePlanEdgeCoreConfig(path string) error {
+	data, err := yaml.Marshal(newPlanEdgeCoreConfig())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func TestPlanDiagnoseNode(t *testing.T) {
+	ops := NewDiagnoseOptions()
+	ops.Config = "/etc/kubeedge/config/edgecore.yaml"
+
+	t.Run("local node", func(t *testing.T) {
+		plan := &DiagnosePlan{Diagnose: common.ArgDiagnoseNode}
+		planDiagnoseNode(ops, newPlanEdgeCoreConfig(), plan)
+
+		var checks []string
+		for _, step := range plan.Steps {
+			checks = append(checks, step.Check)
+		}
+		assert.Equal(t, []string{"edgecore", "edgecore-user", "config", "database", "config-drift", "systemd", "edgecore-log", "file-limits", "conntrack", "node", "node-labels", "disk-paths", "writable-dirs", "runtime",
+			"kubelet", "cgroup-driver", "cni", "pod-dns", "dns-addon", "edged", "eviction", "pod-dirs", "max-pods", "host-paths", "registry", "mqtt", "servicebus", "cloudhub-dns", "cloudhub", "node-identity", "edgestream", "version",
+			"cloudhub-latency", "mtu", "timesync", "timezone", "clock"}, checks)
+		assert.Contains(t, plan.Steps, DiagnosePlanStep{Check: "runtime", Target: "unix:///run/containerd/containerd.sock"})
+		assert.Contains(t, plan.Steps, DiagnosePlanStep{Check: "edged", Target: "tcp://127.0.0.1:10350"})
+		assert.Contains(t, plan.Steps, DiagnosePlanStep{Check: "pod-dns", Target: "resolvConf <empty>, clusterDNS <not set>"})
+		assert.Contains(t, plan.Steps, DiagnosePlanStep{Check: "mqtt", Target: "tcp://127.0.0.1:1883"})
+		assert.Contains(t, plan.Steps, DiagnosePlanStep{Check: "servicebus", Target: "tcp://127.0.0.1:9060"})
+		assert.Contains(t, plan.Steps, DiagnosePlanStep{Check: "cloudhub",
+			Target: "wss://10.0.0.1:10000/e632aba927ea4ac2b575ec1603d56f10/edge-node/events"})
+		assert.Contains(t, plan.Steps, DiagnosePlanStep{Check: "edgestream", Target: "wss://10.0.0.1:10004/v1/kubeedge/connect"})
+	})
+
+	t.Run("remote node", func(t *testing.T) {
+		remote := *ops
+		remote.Host = "user@edge1"
+		plan := &DiagnosePlan{Diagnose: common.ArgDiagnoseNode}
+		planDiagnoseNode(&remote, newPlanEdgeCoreConfig(), plan)
+		assert.Equal(t, []DiagnosePlanStep{
+			{Check: "edgecore", Target: "process edgecore on user@edge1"},
+			{Check: "edgecore-user", Target: "user of process edgecore is root"},
+			{Check: "config", Target: "/etc/kubeedge/config/edgecore.yaml"},
+			{Check: "database", Target: "/var/lib/kubeedge/edgecore.db"},
+		}, plan.Steps)
+	})
+
+	t.Run("container", func(t *testing.T) {
+		inContainer := *ops
+		inContainer.Container = "edgecore"
+		plan := &DiagnosePlan{Diagnose: common.ArgDiagnoseNode}
+		planDiagnoseNode(&inContainer, newPlanEdgeCoreConfig(), plan)
+		assert.Equal(t, DiagnosePlanStep{Check: "edgecore", Target: "process edgecore in container edgecore"}, plan.Steps[0])
+		for _, step := range plan.Steps {
+			assert.NotContains(t, []string{"config-drift", "systemd", "edgecore-log"}, step.Check)
+		}
+	})
+
+	t.Run("edgehub disabled", func(t *testing.T) {
+		cfg := newPlanEdgeCoreConfig()
+		cfg.Modules.EdgeHub.WebSocket.Enable = false
+		plan := &DiagnosePlan{Diagnose: common.ArgDiagnoseNode}
+		planDiagnoseNode(ops, cfg, plan)
+		assert.Equal(t, "cloudhub", plan.Steps[len(plan.Steps)-1].Check)
+		assert.Contains(t, plan.Steps[len(plan.Steps)-1].Target, "edgehub is not enabled")
+	})
+}
+
+func TestDryRunDiagnose(t *testing.T) {
+	t.Run("pods in the database of --db-path", func(t *testing.T) {
+		ops := NewDiagnoseOptions()
+		ops.DBPath = "/tmp/edgecore.db"
+		ops.Output = common.DiagnoseOutputJSON
+		buf := &bytes.Buffer{}
+		require.NoError(t, DryRunDiagnose(common.ArgDiagnosePod, ops, []string{"nginx"}, buf))
+
+		plan := &DiagnosePlan{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), plan))
+		assert.Equal(t, &DiagnosePlan{Diagnose: common.ArgDiagnosePod, Steps: []DiagnosePlanStep{
+			{Check: "database", Target: "/tmp/edgecore.db"},
+			{Check: "database-schema", Target: "7 tables of the edgecore models"},
+			{Check: "pod", Target: "default/pod/nginx"},
+		}}, plan)
+	})
+
+	t.Run("pod of the uid", func(t *testing.T) {
+		ops := NewDiagnoseOptions()
+		ops.DBPath = "/tmp/edgecore.db"
+		ops.UID = "uid-1"
+		ops.AllNamespaces = true
+		buf := &bytes.Buffer{}
+		require.NoError(t, DryRunDiagnose(common.ArgDiagnosePod, ops, nil, buf))
+		assert.Contains(t, buf.String(), "pod of uid uid-1 in all namespaces")
+	})
+
+	t.Run("plan by the go template", func(t *testing.T) {
+		ops := NewDiagnoseOptions()
+		ops.DBPath = "/tmp/edgecore.db"
+		ops.Output = common.DiagnoseOutputGoTemplate
+		ops.Template = `{{range .steps}}{{.check}} {{end}}`
+		buf := &bytes.Buffer{}
+		require.NoError(t, DryRunDiagnose(common.ArgDiagnosePod, ops, []string{"nginx"}, buf))
+		assert.Equal(t, "database database-schema pod ", buf.String())
+	})
+
+	t.Run("config is not exists", func(t *testing.T) {
+		ops := NewDiagnoseOptions()
+		ops.Config = filepath.Join(t.TempDir(), "edgecore.yaml")
+		err := DryRunDiagnose(common.ArgDiagnoseNode, ops, nil, &bytes.Buffer{})
+		require.ErrorContains(t, err, "is not exists, the checks are planned from it")
+	})
+
+	t.Run("install", func(t *testing.T) {
+		ops := NewDiagnoseOptions()
+		ops.Config = filepath.Join(t.TempDir(), "edgecore.yaml")
+		ops.CheckOptions.IP = "10.0.0.2"
+		ops.CheckOptions.CloudHubServer = "10.0.0.1:10000"
+		ops.CheckOptions.Domain = "kubeedge.io"
+		ops.CheckOptions.DNSIP = "8.8.8.8"
+		ops.CheckOptions.Skip = []string{"cpu"}
+		buf := &bytes.Buffer{}
+		ops.Output = common.DiagnoseOutputJSON
+		require.NoError(t, DryRunDiagnose(common.ArgDiagnoseInstall, ops, nil, buf))
+
+		plan := &DiagnosePlan{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), plan))
+		var checks []string
+		for _, step := range plan.Steps {
+			checks = append(checks, step.Check)
+		}
+		assert.Equal(t, []string{"mem", "disk", "disk-paths", "kernel", "swap", "dns", "network", "timesync", "timezone", "clock", "pid", "cert"}, checks)
+		assert.Contains(t, plan.Steps, DiagnosePlanStep{Check: "dns", Target: "kubeedge.io by the nameserver 8.8.8.8"})
+		assert.Contains(t, plan.Steps, DiagnosePlanStep{Check: "network", Target: "ping 10.0.0.2, https://10.0.0.1:10000, http://127.0.0.1:10350"})
+		assert.Contains(t, plan.Steps, DiagnosePlanStep{Check: "clock", Target: "https://10.0.0.1:10000"})
+		assert.Empty(t, ops.CheckOptions.Config)
+	})
+
+	t.Run("module", func(t *testing.T) {
+		ops := NewDiagnoseOptions()
+		ops.Config = filepath.Join(t.TempDir(), "edgecore.yaml")
+		require.NoError(t, writePlanEdgeCoreConfig(ops.Config))
+		buf := &bytes.Buffer{}
+		ops.Output = common.DiagnoseOutputJSON
+		require.NoError(t, DryRunDiagnose(common.ArgDiagnoseModule, ops, []string{ModuleEdged, ModuleEdgeHub, ModuleDeviceTwin}, buf))
+
+		plan := &DiagnosePlan{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), plan))
+		assert.Equal(t, []DiagnosePlanStep{
+			{Check: "config", Target: ops.Config},
+			{Check: ModuleEdged, Target: "http://127.0.0.1:10350"},
+			{Check: ModuleEdgeHub, Target: "edgehub is disabled"},
+			{Check: ModuleDeviceTwin, Target: "unix:///etc/kubeedge/dmi.sock"},
+		}, plan.Steps)
+	})
+
+	t.Run("unsupported module", func(t *testing.T) {
+		err := DryRunDiagnose(common.ArgDiagnoseModule, NewDiagnoseOptions(), []string{"gpu"}, &bytes.Buffer{})
+		require.ErrorContains(t, err, "unsupported module gpu")
+	})
+
+	t.Run("all", func(t *testing.T) {
+		ops := NewDiagnoseOptions()
+		ops.Config = filepath.Join(t.TempDir(), "edgecore.yaml")
+		require.NoError(t, writePlanEdgeCoreConfig(ops.Config))
+		ops.AllNamespaces = true
+		buf := &bytes.Buffer{}
+		ops.Output = common.DiagnoseOutputJSON
+		require.NoError(t, DryRunDiagnose(common.ArgDiagnoseAll, ops, nil, buf))
+
+		plan := &DiagnosePlan{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), plan))
+		assert.Equal(t, "cpu", plan.Steps[0].Check)
+		assert.Contains(t, plan.Steps, DiagnosePlanStep{Check: "network", Target: "ping the nameserver of /etc/resolv.conf, https://10.0.0.1:10000, http://127.0.0.1:10350"})
+		assert.Contains(t, plan.Steps, DiagnosePlanStep{Check: "edgecore", Target: "process edgecore"})
+		assert.Equal(t, DiagnosePlanStep{Check: "pods", Target: "pods in all namespaces of the database"}, plan.Steps[len(plan.Steps)-1])
+	})
+
+	t.Run("unsupported diagnose", func(t *testing.T) {
+		err := DryRunDiagnose(common.ArgDiagnoseCluster, NewDiagnoseOptions(), nil, &bytes.Buffer{})
+		require.EqualError(t, err, "--dry-run is not supported by diagnose cluster")
+	})
+}
+
+func TestWriteDiagnosePlan(t *testing.T) {
+	plan := &DiagnosePlan{Diagnose: common.ArgDiagnoseNode, Steps: []DiagnosePlanStep{
+		{Check: "edgecore", Target: "process edgecore"},
+		{Check: "config", Target: "/etc/kubeedge/config/edgecore.yaml"},
+	}}
+	buf := &bytes.Buffer{}
+	require.NoError(t, writeDiagnosePlan(buf, common.DiagnoseOutputText, plan))
+	assert.Equal(t, "STEP  CHECK     TARGET\n"+
+		"1     edgecore  process edgecore\n"+
+		"2     config    /etc/kubeedge/config/edgecore.yaml\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, writeDiagnosePlan(buf, common.DiagnoseOutputYAML, plan))
+	assert.Contains(t, buf.String(), "- check: edgecore\n  target: process edgecore\n")
+
+	buf.Reset()
+	require.NoError(t, writeDiagnosePlan(buf, common.DiagnoseOutputJSONL, plan))
+	assert.Equal(t, `{"check":"edgecore","target":"process edgecore"}`+"\n"+
+		`{"check":"config","target":"/etc/kubeedge/config/edgecore.yaml"}`+"\n", buf.String())
+}
@@ -0,0 +1,719 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/version"
+)
+
+// DiagnoseReport collects the results of a diagnose run.
+// In text output mode every result is printed as soon as it is recorded,
+// in json, yaml and go-template output mode the whole report is printed once by Print,
+// in jsonl output mode every result is written as a json line as soon as it is recorded and Print writes the summary line.
+type DiagnoseReport struct {
+	Header   *DiagnoseReportHeader `json:"header,omitempty"`
+	NodeName string                `json:"nodeName"`
+	Diagnose string                `json:"diagnose"`
+	// OverallStatus, Summary and HealthScore roll up the statuses of the checks, they are set when the report is printed
+	OverallStatus string                 `json:"overallStatus,omitempty"`
+	Summary       *DiagnoseReportSummary `json:"summary,omitempty"`
+	// HealthScore is the score from 0 to 100 of the weighted statuses of the checks, see healthScore
+	HealthScore *int                    `json:"healthScore,omitempty"`
+	Results     []common.DiagnoseResult `json:"results"`
+
+	output    string
+	verbosity DiagnoseVerbosity
+	out       io.Writer
+	// file is the report file which the report is teed to
+	file   io.Writer
+	header *DiagnoseReportHeader
+	// template is executed on the report in the go-template output mode
+	template *template.Template
+	// ctx stops the checks which are not started yet when it is done
+	ctx context.Context
+	// explain records the remediation hints of the failed checks
+	explain bool
+	// scoreWeights overrides the weights of the checks in the health score
+	scoreWeights map[string]int
+}
+
+// DiagnoseReportSummary counts the checks of a report by status,
+// a check recorded more than once is counted once by its most severe status
+type DiagnoseReportSummary struct {
+	Pass int `json:"pass"`
+	Warn int `json:"warn"`
+	Fail int `json:"fail"`
+	// Skip counts the checks which are not run, so a report of the passed checks only is not taken as a full pass
+	Skip int `json:"skip"`
+}
+
+// diagnoseReportLine is the line of the header of the report file and the last line of the summary of a report
+// in jsonl mode, which are told from the lines of the results by having no check
+type diagnoseReportLine struct {
+	Header        *DiagnoseReportHeader  `json:"header,omitempty"`
+	NodeName      string                 `json:"nodeName,omitempty"`
+	Diagnose      string                 `json:"diagnose"`
+	OverallStatus string                 `json:"overallStatus,omitempty"`
+	Summary       *DiagnoseReportSummary `json:"summary,omitempty"`
+	HealthScore   *int                   `json:"healthScore,omitempty"`
+}
+
+// DiagnoseVerbosity is the level of the informational output of a report in text mode
+type DiagnoseVerbosity int
+
+const (
+	// DiagnoseVerbosityQuiet prints only the final result of the diagnose
+	DiagnoseVerbosityQuiet DiagnoseVerbosity = iota - 1
+	// DiagnoseVerbosityNormal prints the results of the checks, it is the default level
+	DiagnoseVerbosityNormal
+	// DiagnoseVerbosityVerbose prints the extra details of the checks as well, e.g. the parsed config values
+	DiagnoseVerbosityVerbose
+)
+
+// diagnoseVerbosity returns the verbosity of the report selected by the options
+func diagnoseVerbosity(ops *common.DiagnoseOptions) DiagnoseVerbosity {
+	switch {
+	case ops.Quiet:
+		return DiagnoseVerbosityQuiet
+	case ops.Verbose:
+		return DiagnoseVerbosityVerbose
+	}
+	return DiagnoseVerbosityNormal
+}
+
+// DiagnoseReportHeader describes where and when a report file is generated
+type DiagnoseReportHeader struct {
+	KeadmVersion string `json:"keadmVersion"`
+	Hostname     string `json:"hostname"`
+	Timestamp    string `json:"timestamp"`
+	Config       string `json:"config"`
+}
+
+// NewDiagnoseReportHeader returns the header of a report file generated now with the edgecore config
+func NewDiagnoseReportHeader(config string) *DiagnoseReportHeader {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return &DiagnoseReportHeader{
+		KeadmVersion: version.Get().String(),
+		Hostname:     hostname,
+		Timestamp:    time.Now().Format(time.RFC3339),
+		Config:       config,
+	}
+}
+
+// NewDiagnoseReport returns a report of the diagnose object use in the given output format
+func NewDiagnoseReport(use, output string, out io.Writer) *DiagnoseReport {
+	if output == "" {
+		output = common.DiagnoseOutputText
+	}
+	return &DiagnoseReport{
+		Diagnose: use,
+		Results:  []common.DiagnoseResult{},
+		output:   output,
+		out:      out,
+	}
+}
+
+// SetContext sets the context of the checks, Run and RunAll start no more checks once ctx is done
+func (r *DiagnoseReport) SetContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
+// canceled returns the error of the context if it is done before the check is started
+func (r *DiagnoseReport) canceled(check string) error {
+	if r.ctx == nil || r.ctx.Err() == nil {
+		return nil
+	}
+	return fmt.Errorf("diagnose is canceled before the %s check: %w", check, r.ctx.Err())
+}
+
+// SetNodeName sets the name of the node the report is generated on, in text mode the name is printed as the header.
+func (r *DiagnoseReport) SetNodeName(nodeName string) {
+	r.NodeName = nodeName
+	r.Printf("Node: %s\n", nodeName)
+}
+
+// DiagnoseNodeName returns the node name in the edgecore config, or the hostname if it is not set in the config
+func DiagnoseNodeName(config string) string {
+	if util.EdgecoreConfigExists(config) {
+		if edgeconfig, err := util.ParseEdgecoreConfig(config); err == nil &&
+			edgeconfig.Modules.Edged != nil && edgeconfig.Modules.Edged.HostnameOverride != "" {
+			return edgeconfig.Modules.Edged.HostnameOverride
+		}
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return hostname
+}
+
+// diagnoseOutputs are the output formats of the diagnose commands
+var diagnoseOutputs = []string{common.DiagnoseOutputText, common.DiagnoseOutputJSON, common.DiagnoseOutputYAML}
+
+// diagnoseReportOutputs are the output formats of a diagnose report
+var diagnoseReportOutputs = append(slices.Clone(diagnoseOutputs), common.DiagnoseOutputJSONL, common.DiagnoseOutputGoTemplate)
+
+// ValidateDiagnoseOutput checks whether the output format is supported
+func ValidateDiagnoseOutput(output string) error {
+	return validateOutput(output, diagnoseOutputs)
+}
+
+// validateOutput checks whether the output format is one of the formats, the default format is empty
+func validateOutput(output string, formats []string) error {
+	if output == "" || slices.Contains(formats, output) {
+		return nil
+	}
+	return fmt.Errorf("unsupported output format %q, supported formats are %s", output, strings.Join(formats, "|"))
+}
+
+// validateDiagnoseReportOutput checks the output format and the template of a diagnose report,
+// which supports the jsonl and go-template output formats besides the formats of ValidateDiagnoseOutput
+func validateDiagnoseReportOutput(output, text string) error {
+	if output == common.DiagnoseOutputGoTemplate {
+		_, err := parseDiagnoseTemplate(text)
+		return err
+	}
+	if text != "" {
+		return fmt.Errorf("--template is only supported by the %s output format", common.DiagnoseOutputGoTemplate)
+	}
+	return validateOutput(output, diagnoseReportOutputs)
+}
+
+// parseDiagnoseTemplate parses the go template of the go-template output format,
+// a missing key of the data fails the execution instead of printing "<no value>"
+func parseDiagnoseTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		return nil, fmt.Errorf("--template is required by the %s output format", common.DiagnoseOutputGoTemplate)
+	}
+	tmpl, err := template.New("diagnose").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// SetTemplate parses the go template which Print executes on the report in the go-template output mode,
+// it does nothing in the other output modes.
+func (r *DiagnoseReport) SetTemplate(text string) error {
+	if r.output != common.DiagnoseOutputGoTemplate {
+		return nil
+	}
+	tmpl, err := parseDiagnoseTemplate(text)
+	if err != nil {
+		return err
+	}
+	r.template = tmpl
+	return nil
+}
+
+// TeeFile tees the report to the report file f which begins with the header.
+// In text and jsonl mode the output is written to f as soon as it is printed, otherwise the whole report is written by Print.
+func (r *DiagnoseReport) TeeFile(f io.Writer, header *DiagnoseReportHeader) error {
+	r.file = f
+	r.header = header
+	if r.output == common.DiagnoseOutputJSONL {
+		return writeJSONLine(f, &diagnoseReportLine{Header: header, Diagnose: r.Diagnose})
+	}
+	if !r.IsText() {
+		return nil
+	}
+	_, err := fmt.Fprintf(f, "# keadm version: %s\n# hostname: %s\n# timestamp: %s\n# config: %s\n\n",
+		header.KeadmVersion, header.Hostname, header.Timestamp, header.Config)
+	r.out = io.MultiWriter(r.out, f)
+	return err
+}
+
+// SetExplain sets whether the remediation hints of the failed checks are recorded in the results,
+// in text mode they are printed by PrintHints once the diagnose is done.
+func (r *DiagnoseReport) SetExplain(explain bool) {
+	r.explain = explain
+}
+
+// SetScoreWeights sets the weights of the checks in the health score which override the default weights
+func (r *DiagnoseReport) SetScoreWeights(weights map[string]int) {
+	r.scoreWeights = weights
+}
+
+// hint returns the remediation hint of the failed check with the error code in explain mode
+func (r *DiagnoseReport) hint(check, code string) string {
+	if !r.explain {
+		return ""
+	}
+	return checkHint(check, code)
+}
+
+// PrintHints prints the remediation hints of the failed checks in text mode unless quiet,
+// a check failed more than once is hinted once.
+func (r *DiagnoseReport) PrintHints() {
+	var lines []string
+	hinted := make(map[string]bool)
+	for _, result := range r.Results {
+		if result.Hint == "" || hinted[result.Check] {
+			continue
+		}
+		hinted[result.Check] = true
+		lines = append(lines, fmt.Sprintf("  %s: %s\n", result.Check, result.Hint))
+	}
+	if len(lines) > 0 {
+		r.Printf("\nHints:\n%s", strings.Join(lines, ""))
+	}
+}
+
+// PrintSkipped prints the checks which are skipped and the reasons in text mode unless quiet,
+// so that the succeed message is not taken for all the checks passing.
+func (r *DiagnoseReport) PrintSkipped() {
+	checks, statuses := r.checkStatuses()
+	reasons := make(map[string]string)
+	for _, result := range r.Results {
+		if result.Status == common.DiagnoseStatusSkip {
+			reasons[result.Check] = result.Message
+		}
+	}
+	var lines []string
+	for _, check := range checks {
+		if statuses[check] == common.DiagnoseStatusSkip {
+			lines = append(lines, fmt.Sprintf("  %s: %s\n", check, reasons[check]))
+		}
+	}
+	if len(lines) > 0 {
+		r.Printf("\n%d checks are skipped:\n%s", len(lines), strings.Join(lines, ""))
+	}
+}
+
+// IsText returns whether the report is printed in text mode
+func (r *DiagnoseReport) IsText() bool {
+	return r.output == common.DiagnoseOutputText
+}
+
+// SetVerbosity sets the level of the informational output in text mode
+func (r *DiagnoseReport) SetVerbosity(verbosity DiagnoseVerbosity) {
+	r.verbosity = verbosity
+}
+
+// logs returns whether the output of the level is printed
+func (r *DiagnoseReport) logs(level DiagnoseVerbosity) bool {
+	return r.IsText() && r.verbosity >= level
+}
+
+// Printf prints the formatted message in text mode unless quiet, the message is not recorded as a result.
+func (r *DiagnoseReport) Printf(format string, a ...interface{}) {
+	if r.logs(DiagnoseVerbosityNormal) {
+		fmt.Fprintf(r.out, format, a...)
+	}
+}
+
+// Debugf prints the formatted extra detail in verbose text mode, the message is not recorded as a result.
+func (r *DiagnoseReport) Debugf(format string, a ...interface{}) {
+	if r.logs(DiagnoseVerbosityVerbose) {
+		fmt.Fprintf(r.out, format, a...)
+	}
+}
+
+// DebugWriter returns the writer of the extra details of the checks, what is written to it is printed the same as Debugf
+func (r *DiagnoseReport) DebugWriter() io.Writer {
+	if r.logs(DiagnoseVerbosityVerbose) {
+		return r.out
+	}
+	return io.Discard
+}
+
+// Summaryf prints the formatted final result in text mode at every verbosity.
+func (r *DiagnoseReport) Summaryf(format string, a ...interface{}) {
+	if r.logs(DiagnoseVerbosityQuiet) {
+		fmt.Fprintf(r.out, format, a...)
+	}
+}
+
+// TextWriter returns the writer of the text output, which discards everything if the report is not in text mode or quiet
+func (r *DiagnoseReport) TextWriter() io.Writer {
+	if r.logs(DiagnoseVerbosityNormal) {
+		return r.out
+	}
+	return io.Discard
+}
+
+// Pass records a passed check, in text mode the formatted message is printed as is.
+func (r *DiagnoseReport) Pass(check, format string, a ...interface{}) {
+	r.record(check, common.DiagnoseStatusPass, fmt.Sprintf(format, a...))
+}
+
+// Warnf records a warning that does not fail the diagnose,
+// in text mode the formatted message is printed with the WARNING prefix.
+func (r *DiagnoseReport) Warnf(check, format string, a ...interface{}) {
+	msg := fmt.Sprintf(format, a...)
+	r.Printf("WARNING: %s", msg)
+	r.add(common.DiagnoseResult{
+		Check:   check,
+		Status:  common.DiagnoseStatusWarn,
+		Message: strings.TrimSpace(msg),
+	})
+}
+
+// Skipf records a check which is not run with the formatted reason,
+// in text mode the check and the reason are printed with the SKIPPED prefix.
+func (r *DiagnoseReport) Skipf(check, format string, a ...interface{}) {
+	r.SkipAll([]string{check}, format, a...)
+}
+
+// SkipAll records the checks which are not run for the same formatted reason, they are printed on one line in text mode.
+func (r *DiagnoseReport) SkipAll(checks []string, format string, a ...interface{}) {
+	reason := fmt.Sprintf(format, a...)
+	r.Printf("SKIPPED: %s: %s\n", strings.Join(checks, ", "), reason)
+	for _, check := range checks {
+		r.add(common.DiagnoseResult{
+			Check:   check,
+			Status:  common.DiagnoseStatusSkip,
+			Message: reason,
+		})
+	}
+}
+
+// Failf records a failed finding that does not stop the diagnose,
+// in text mode the formatted message is printed as is.
+func (r *DiagnoseReport) Failf(check, format string, a ...interface{}) {
+	r.record(check, common.DiagnoseStatusFail, fmt.Sprintf(format, a...))
+}
+
+// Fail records the failed check and returns err.
+// The error is not printed, it is up to the caller to print the error which stops the diagnose.
+// The returned error is a *DiagnoseError, whose code is recorded in the result as well.
+func (r *DiagnoseReport) Fail(check string, err error) error {
+	err = newDiagnoseError(check, err)
+	r.add(common.DiagnoseResult{
+		Check:   check,
+		Status:  common.DiagnoseStatusFail,
+		Code:    diagnoseErrorCode(err),
+		Message: err.Error(),
+		Hint:    r.hint(check, diagnoseErrorCode(err)),
+	})
+	return err
+}
+
+// Run runs the check fn, and records what fn writes to w as the detail of the result.
+// In text mode the detail is printed as is. The check is not run if the context of the report is done,
+// and it is recorded as skipped if it is not supported on the platform.
+func (r *DiagnoseReport) Run(check string, fn func(w io.Writer) error) error {
+	if err := r.canceled(check); err != nil {
+		return err
+	}
+	buf := &bytes.Buffer{}
+	start := time.Now()
+	err := platformSkip(check)
+	if err == nil {
+		err = fn(buf)
+	}
+	return r.recordRun(check, buf, time.Since(start), err)
+}
+
+// platformSkip returns the reason of skipping the check if it is one of platformUnsupportedChecks, or nil
+func platformSkip(check string) error {
+	if platformUnsupportedChecks[check] {
+		return skippedError("not supported on %s", runtime.GOOS)
+	}
+	return nil
+}
+
+// ReportCheck is a check run by RunAll
+type ReportCheck struct {
+	Name string
+	Fn   func(w io.Writer) error
+}
+
+// RunAll runs the independent checks concurrently with at most limit checks at a time,
+// the results are recorded in the order of the checks as if they were run by Run one by one.
+// Every check is run, the errors of all the failed checks are joined. No check is run if the context of the report is done.
+func (r *DiagnoseReport) RunAll(limit int, checks []ReportCheck) error {
+	if len(checks) > 0 {
+		if err := r.canceled(checks[0].Name); err != nil {
+			return err
+		}
+	}
+	bufs := make([]*bytes.Buffer, len(checks))
+	errs := make([]error, len(checks))
+	elapsed := make([]time.Duration, len(checks))
+	var g errgroup.Group
+	g.SetLimit(limit)
+	for i, c := range checks {
+		bufs[i] = &bytes.Buffer{}
+		g.Go(func() error {
+			start := time.Now()
+			if errs[i] = platformSkip(c.Name); errs[i] == nil {
+				errs[i] = c.Fn(bufs[i])
+			}
+			elapsed[i] = time.Since(start)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	var failed []error
+	for i, c := range checks {
+		if err := r.recordRun(c.Name, bufs[i], elapsed[i], errs[i]); err != nil {
+			failed = append(failed, err)
+		}
+	}
+	return errors.Join(failed...)
+}
+
+// recordRun records the result of a check run which took elapsed, in text mode the detail buf is printed
+// unless quiet, with the elapsed time as the suffix of its last line, e.g. (1.2s).
+// The elapsed time of a check without detail is only printed in verbose mode.
+func (r *DiagnoseReport) recordRun(check string, buf *bytes.Buffer, elapsed time.Duration, err error) error {
+	var werr error
+	if detail := strings.TrimRight(buf.String(), "\n"); detail != "" && r.logs(DiagnoseVerbosityNormal) {
+		_, werr = fmt.Fprintf(r.out, "%s (%.1fs)\n", detail, elapsed.Seconds())
+	} else if detail == "" && r.logs(DiagnoseVerbosityVerbose) {
+		_, werr = fmt.Fprintf(r.out, "check %s took %.1fs\n", check, elapsed.Seconds())
+	}
+	if werr != nil {
+		return werr
+	}
+
+	result := common.DiagnoseResult{
+		Check:     check,
+		Status:    common.DiagnoseStatusPass,
+		Detail:    strings.TrimSpace(buf.String()),
+		ElapsedMs: elapsed.Milliseconds(),
+	}
+	if err != nil && isDiagnoseSkipped(err) {
+		// the check found it does not apply, it is neither passed nor failed
+		r.Printf("SKIPPED: %s: %s\n", check, err.Error())
+		result.Status = common.DiagnoseStatusSkip
+		result.Message = err.Error()
+		r.add(result)
+		return nil
+	}
+	if err != nil && isDiagnoseWarning(err) {
+		// the warning is recorded as a non-fatal finding, the check does not fail
+		for _, line := range strings.Split(err.Error(), "\n") {
+			r.Printf("WARNING: %s\n", line)
+		}
+		result.Status = common.DiagnoseStatusWarn
+		result.Message = err.Error()
+		r.add(result)
+		return nil
+	}
+	if err != nil {
+		err = newDiagnoseError(check, err)
+		result.Status = common.DiagnoseStatusFail
+		result.Code = diagnoseErrorCode(err)
+		result.Message = err.Error()
+		result.Hint = r.hint(check, result.Code)
+	}
+	r.add(result)
+	return err
+}
+
+// checkStatuses returns the checks in the order they are first recorded and the status of each check,
+// a check recorded more than once has the most severe status of its results, e.g. failed if any of them is failed.
+func (r *DiagnoseReport) checkStatuses() ([]string, map[string]string) {
+	var checks []string
+	statuses := make(map[string]string, len(r.Results))
+	for _, result := range r.Results {
+		status, ok := statuses[result.Check]
+		if !ok {
+			checks = append(checks, result.Check)
+		}
+		if !ok || statusSeverity(result.Status) > statusSeverity(status) {
+			statuses[result.Check] = result.Status
+		}
+	}
+	return checks, statuses
+}
+
+// statusSeverity orders the statuses of the results, skip < pass < warn < fail
+func statusSeverity(status string) int {
+	switch status {
+	case common.DiagnoseStatusFail:
+		return 2
+	case common.DiagnoseStatusWarn:
+		return 1
+	case common.DiagnoseStatusSkip:
+		return -1
+	}
+	return 0
+}
+
+// summarize counts the checks of the report by status, and rolls them up into the overall status:
+// unhealthy if any check fails, degraded if any check warns but none fails, and healthy otherwise.
+// The skipped checks are counted but do not change the overall status.
+func (r *DiagnoseReport) summarize() (string, *DiagnoseReportSummary) {
+	checks, statuses := r.checkStatuses()
+	summary := &DiagnoseReportSummary{}
+	for _, check := range checks {
+		switch statuses[check] {
+		case common.DiagnoseStatusFail:
+			summary.Fail++
+		case common.DiagnoseStatusWarn:
+			summary.Warn++
+		case common.DiagnoseStatusSkip:
+			summary.Skip++
+		default:
+			summary.Pass++
+		}
+	}
+	switch {
+	case summary.Fail > 0:
+		return common.DiagnoseOverallUnhealthy, summary
+	case summary.Warn > 0:
+		return common.DiagnoseOverallDegraded, summary
+	}
+	return common.DiagnoseOverallHealthy, summary
+}
+
+// healthScore returns the health score of the checks of the report with the weights of the report
+func (r *DiagnoseReport) healthScore() int {
+	checks, statuses := r.checkStatuses()
+	return healthScore(checks, statuses, r.scoreWeights)
+}
+
+func (r *DiagnoseReport) record(check, status, msg string) {
+	if r.logs(DiagnoseVerbosityNormal) {
+		fmt.Fprint(r.out, msg)
+	}
+	result := common.DiagnoseResult{
+		Check:   check,
+		Status:  status,
+		Message: strings.TrimSpace(msg),
+	}
+	if status == common.DiagnoseStatusFail {
+		result.Hint = r.hint(check, DiagnoseErrorCodeCheckFailed)
+	}
+	r.add(result)
+}
+
+// add appends the result to the report, in jsonl mode the result is written as a json line as soon as it is added
+func (r *DiagnoseReport) add(result common.DiagnoseResult) {
+	r.Results = append(r.Results, result)
+	if r.output != common.DiagnoseOutputJSONL {
+		return
+	}
+	if err := r.writeLine(result); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+	}
+}
+
+// writeLine writes v as a json line to the output and the report file
+func (r *DiagnoseReport) writeLine(v interface{}) error {
+	if err := writeJSONLine(r.out, v); err != nil {
+		return err
+	}
+	if r.file == nil {
+		return nil
+	}
+	return writeJSONLine(r.file, v)
+}
+
+// writeJSONLine writes v as a json object on a single line
+func writeJSONLine(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal diagnose result: %v", err)
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// Print prints the report in json or yaml format or by the go template, it prints nothing in text mode.
+// In jsonl mode the results are already written, and Print writes the line of the overall status and the summary.
+// The report is written to the report file with the header as well if it is teed to a file.
+func (r *DiagnoseReport) Print() error {
+	if r.IsText() {
+		return nil
+	}
+	r.OverallStatus, r.Summary = r.summarize()
+	score := r.healthScore()
+	r.HealthScore = &score
+	if r.output == common.DiagnoseOutputJSONL {
+		return r.writeLine(&diagnoseReportLine{
+			NodeName: r.NodeName, Diagnose: r.Diagnose, OverallStatus: r.OverallStatus, Summary: r.Summary, HealthScore: r.HealthScore,
+		})
+	}
+	if err := r.write(r.out, r); err != nil {
+		return err
+	}
+	if r.file == nil {
+		return nil
+	}
+	withHeader := *r
+	withHeader.Header = r.header
+	return r.write(r.file, &withHeader)
+}
+
+func (r *DiagnoseReport) write(w io.Writer, report *DiagnoseReport) error {
+	var data []byte
+	var err error
+	switch r.output {
+	case common.DiagnoseOutputJSON:
+		data, err = json.MarshalIndent(report, "", "  ")
+		data = append(data, '\n')
+	case common.DiagnoseOutputYAML:
+		data, err = yaml.Marshal(report)
+	case common.DiagnoseOutputGoTemplate:
+		return executeDiagnoseTemplate(w, r.template, report)
+	default:
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal diagnose report: %v", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// executeDiagnoseTemplate executes the go template on the json fields of data, the same as -o json prints them,
+// and writes the output to w once it succeeds
+func executeDiagnoseTemplate(w io.Writer, tmpl *template.Template, data interface{}) error {
+	if tmpl == nil {
+		return fmt.Errorf("--template is required by the %s output format", common.DiagnoseOutputGoTemplate)
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal diagnose report: %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return fmt.Errorf("failed to marshal diagnose report: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, fields); err != nil {
+		return fmt.Errorf("failed to execute the template: %v", err)
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
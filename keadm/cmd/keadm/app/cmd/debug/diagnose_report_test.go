@@ -0,0 +1,563 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func TestValidateDiagnoseOutput(t *testing.T) {
+	for _, output := range []string{"", common.DiagnoseOutputText, common.DiagnoseOutputJSON, common.DiagnoseOutputYAML} {
+		assert.NoError(t, ValidateDiagnoseOutput(output))
+	}
+	assert.ErrorContains(t, ValidateDiagnoseOutput("wide"), "unsupported output format")
+}
+
+func TestValidateDiagnoseReportOutput(t *testing.T) {
+	assert.NoError(t, validateDiagnoseReportOutput(common.DiagnoseOutputJSON, ""))
+	assert.NoError(t, validateDiagnoseReportOutput(common.DiagnoseOutputJSONL, ""))
+	assert.NoError(t, validateDiagnoseReportOutput(common.DiagnoseOutputGoTemplate, "{{.diagnose}}"))
+	assert.EqualError(t, validateDiagnoseReportOutput(common.DiagnoseOutputGoTemplate, ""),
+		"--template is required by the go-template output format")
+	assert.ErrorContains(t, validateDiagnoseReportOutput(common.DiagnoseOutputGoTemplate, "{{range .results}}"), "invalid template")
+	assert.EqualError(t, validateDiagnoseReportOutput(common.DiagnoseOutputText, "{{.diagnose}}"),
+		"--template is only supported by the go-template output format")
+	assert.EqualError(t, validateDiagnoseReportOutput("wide", ""),
+		`unsupported output format "wide", supported formats are text|json|yaml|jsonl|go-template`)
+}
+
+func TestDiagnoseReportTemplate(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputGoTemplate, buf)
+	require.NoError(t, r.SetTemplate(`{{.overallStatus}}{{range .results}} {{.check}}={{.status}}{{end}}{{"\n"}}`))
+	r.Pass("edgecore", "edgecore is running\n")
+	r.Warnf("disk", "disk is almost full\n")
+	assert.False(t, r.IsText())
+	assert.Empty(t, buf.String())
+
+	require.NoError(t, r.Print())
+	assert.Equal(t, "degraded edgecore=pass disk=warn\n", buf.String())
+
+	t.Run("missing key", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputGoTemplate, buf)
+		require.NoError(t, r.SetTemplate(`{{range .results}}{{.name}}{{end}}`))
+		r.Pass("edgecore", "edgecore is running\n")
+		require.ErrorContains(t, r.Print(), "failed to execute the template")
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("template is ignored in the other output modes", func(t *testing.T) {
+		r := NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputJSON, &bytes.Buffer{})
+		require.NoError(t, r.SetTemplate(""))
+		assert.Nil(t, r.template)
+	})
+}
+
+func TestDiagnoseReportText(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewDiagnoseReport(common.ArgDiagnoseNode, "", buf)
+
+	r.Pass("edgecore", "edgecore is running\n")
+	r.Failf("container", "containerConditions %v is not ready\n", "nginx")
+	err := r.Run("cpu", func(w io.Writer) error {
+		fmt.Fprintf(w, "CPU total: %v core\n", 4)
+		return nil
+	})
+	require.NoError(t, err)
+	err = r.Fail("cloudhub", errors.New("cloudcore websocket connection failed"))
+	require.ErrorContains(t, err, "cloudcore websocket connection failed")
+	require.NoError(t, r.Print())
+
+	assert.Equal(t, "edgecore is running\ncontainerConditions nginx is not ready\nCPU total: 4 core (0.0s)\n", buf.String())
+	assert.Equal(t, []common.DiagnoseResult{
+		{Check: "edgecore", Status: common.DiagnoseStatusPass, Message: "edgecore is running"},
+		{Check: "container", Status: common.DiagnoseStatusFail, Message: "containerConditions nginx is not ready"},
+		{Check: "cpu", Status: common.DiagnoseStatusPass, Detail: "CPU total: 4 core"},
+		{Check: "cloudhub", Status: common.DiagnoseStatusFail, Code: DiagnoseErrorCodeCheckFailed, Message: "cloudcore websocket connection failed"},
+	}, r.Results)
+}
+
+func TestDiagnoseReportExplain(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewDiagnoseReport(common.ArgDiagnoseNode, "", buf)
+	r.SetExplain(true)
+
+	r.Pass("edgecore", "edgecore is running\n")
+	r.Failf("runtime", "containerd is not running\n")
+	r.Failf("runtime", "the runtime socket is not exists\n")
+	err := r.Run("swap", func(w io.Writer) error {
+		return errors.New("swap is enabled")
+	})
+	require.Error(t, err)
+	_ = r.Fail("database", newPermissionDeniedError("dataSource", "/var/lib/kubeedge/edgecore.db", os.ErrPermission))
+	r.Failf("unknown", "a check without a hint failed\n")
+	r.PrintHints()
+
+	assert.Empty(t, r.Results[0].Hint)
+	assert.Equal(t, checkHints["runtime"], r.Results[1].Hint)
+	assert.Equal(t, checkHints["swap"], r.Results[3].Hint)
+	assert.Equal(t, diagnoseErrorHints[DiagnoseErrorCodePermissionDenied], r.Results[4].Hint)
+	assert.Empty(t, r.Results[5].Hint)
+	assert.Contains(t, buf.String(), "\nHints:\n  runtime: "+checkHints["runtime"]+"\n  swap: "+checkHints["swap"]+
+		"\n  database: "+diagnoseErrorHints[DiagnoseErrorCodePermissionDenied]+"\n")
+	assert.Equal(t, 1, strings.Count(buf.String(), "runtime: "))
+
+	t.Run("no hints without explain", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnoseNode, "", buf)
+		r.Failf("runtime", "containerd is not running\n")
+		r.PrintHints()
+		assert.Empty(t, r.Results[0].Hint)
+		assert.NotContains(t, buf.String(), "Hints:")
+	})
+}
+
+func TestDiagnoseReportPrint(t *testing.T) {
+	cases := []struct {
+		output    string
+		unmarshal func([]byte, interface{}) error
+	}{
+		{
+			output:    common.DiagnoseOutputJSON,
+			unmarshal: json.Unmarshal,
+		},
+		{
+			output: common.DiagnoseOutputYAML,
+			unmarshal: func(data []byte, v interface{}) error {
+				return yaml.Unmarshal(data, v)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.output, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			r := NewDiagnoseReport(common.ArgDiagnoseInstall, c.output, buf)
+			err := r.Run("disk", func(w io.Writer) error {
+				fmt.Fprintln(w, "Disk total: 10.00 MB")
+				return errors.New("disk check failed")
+			})
+			require.ErrorContains(t, err, "disk check failed")
+			assert.Empty(t, buf.String())
+
+			require.NoError(t, r.Print())
+			var got DiagnoseReport
+			require.NoError(t, c.unmarshal(buf.Bytes(), &got))
+			assert.Equal(t, common.ArgDiagnoseInstall, got.Diagnose)
+			assert.Equal(t, []common.DiagnoseResult{
+				{
+					Check:   "disk",
+					Status:  common.DiagnoseStatusFail,
+					Code:    DiagnoseErrorCodeCheckFailed,
+					Message: "disk check failed",
+					Detail:  "Disk total: 10.00 MB",
+				},
+			}, got.Results)
+		})
+	}
+}
+
+func TestDiagnoseReportJSONL(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputJSONL, buf)
+	r.SetNodeName("edge-node")
+	assert.Empty(t, buf.String())
+
+	r.Pass("edgecore", "edgecore is running\n")
+	// every result is written as soon as it is recorded
+	assert.Equal(t, `{"check":"edgecore","status":"pass","message":"edgecore is running"}`+"\n", buf.String())
+	r.Warnf("config", "config is modified\n")
+	err := r.Run("cloudhub", func(w io.Writer) error {
+		fmt.Fprintln(w, "dial cloudhub")
+		return errors.New("cloudhub is unreachable")
+	})
+	require.Error(t, err)
+	require.NoError(t, r.Print())
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 4)
+	var result common.DiagnoseResult
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &result))
+	assert.Equal(t, common.DiagnoseResult{Check: "config", Status: common.DiagnoseStatusWarn, Message: "config is modified"}, result)
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &result))
+	assert.Equal(t, "cloudhub", result.Check)
+	assert.Equal(t, common.DiagnoseStatusFail, result.Status)
+	assert.Equal(t, "dial cloudhub", result.Detail)
+	assert.Equal(t, `{"nodeName":"edge-node","diagnose":"node","overallStatus":"unhealthy","summary":{"pass":1,"warn":1,"fail":1,"skip":0},"healthScore":50}`,
+		lines[3])
+	assert.Len(t, r.Results, 3)
+}
+
+func TestDiagnoseReportSummary(t *testing.T) {
+	cases := []struct {
+		name            string
+		results         []common.DiagnoseResult
+		expectedOverall string
+		expectedSummary DiagnoseReportSummary
+	}{
+		{
+			name:            "no checks",
+			expectedOverall: common.DiagnoseOverallHealthy,
+		},
+		{
+			name: "all passed",
+			results: []common.DiagnoseResult{
+				{Check: "cpu", Status: common.DiagnoseStatusPass},
+				{Check: "mem", Status: common.DiagnoseStatusPass},
+			},
+			expectedOverall: common.DiagnoseOverallHealthy,
+			expectedSummary: DiagnoseReportSummary{Pass: 2},
+		},
+		{
+			name: "warned",
+			results: []common.DiagnoseResult{
+				{Check: "cpu", Status: common.DiagnoseStatusPass},
+				{Check: "cert", Status: common.DiagnoseStatusWarn},
+				{Check: "cert", Status: common.DiagnoseStatusPass},
+			},
+			expectedOverall: common.DiagnoseOverallDegraded,
+			expectedSummary: DiagnoseReportSummary{Pass: 1, Warn: 1},
+		},
+		{
+			name: "failed",
+			results: []common.DiagnoseResult{
+				{Check: "cpu", Status: common.DiagnoseStatusPass},
+				{Check: "cert", Status: common.DiagnoseStatusWarn},
+				{Check: "pod", Status: common.DiagnoseStatusPass},
+				{Check: "pod", Status: common.DiagnoseStatusFail},
+				{Check: "pod", Status: common.DiagnoseStatusWarn},
+			},
+			expectedOverall: common.DiagnoseOverallUnhealthy,
+			expectedSummary: DiagnoseReportSummary{Pass: 1, Warn: 1, Fail: 1},
+		},
+		{
+			name: "skipped",
+			results: []common.DiagnoseResult{
+				{Check: "cpu", Status: common.DiagnoseStatusPass},
+				{Check: "servicebus", Status: common.DiagnoseStatusSkip},
+				{Check: "pod", Status: common.DiagnoseStatusSkip},
+				{Check: "pod", Status: common.DiagnoseStatusPass},
+			},
+			expectedOverall: common.DiagnoseOverallHealthy,
+			expectedSummary: DiagnoseReportSummary{Pass: 2, Skip: 1},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputJSON, &bytes.Buffer{})
+			r.Results = append(r.Results, c.results...)
+			overall, summary := r.summarize()
+			assert.Equal(t, c.expectedOverall, overall)
+			assert.Equal(t, c.expectedSummary, *summary)
+		})
+	}
+
+	t.Run("printed report", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputJSON, buf)
+		r.Pass("edgecore", "edgecore is running\n")
+		r.Failf("container", "containerConditions %v is not ready\n", "nginx")
+		require.NoError(t, r.Print())
+
+		var got map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		assert.Equal(t, common.DiagnoseOverallUnhealthy, got["overallStatus"])
+		assert.Equal(t, map[string]interface{}{"pass": float64(1), "warn": float64(0), "fail": float64(1), "skip": float64(0)}, got["summary"])
+	})
+}
+
+func TestDiagnoseReportTeeFile(t *testing.T) {
+	header := &DiagnoseReportHeader{
+		KeadmVersion: "v1.20.0",
+		Hostname:     "edge-node",
+		Timestamp:    "2025-01-01T00:00:00Z",
+		Config:       "/etc/kubeedge/config/edgecore.yaml",
+	}
+
+	t.Run("text", func(t *testing.T) {
+		out, file := &bytes.Buffer{}, &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputText, out)
+		require.NoError(t, r.TeeFile(file, header))
+		r.Pass("edgecore", "edgecore is running\n")
+		require.NoError(t, r.Print())
+
+		assert.Equal(t, "edgecore is running\n", out.String())
+		assert.Equal(t, "# keadm version: v1.20.0\n# hostname: edge-node\n# timestamp: 2025-01-01T00:00:00Z\n"+
+			"# config: /etc/kubeedge/config/edgecore.yaml\n\nedgecore is running\n", file.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out, file := &bytes.Buffer{}, &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputJSON, out)
+		require.NoError(t, r.TeeFile(file, header))
+		r.Pass("edgecore", "edgecore is running\n")
+		require.NoError(t, r.Print())
+
+		var stdout, report DiagnoseReport
+		require.NoError(t, json.Unmarshal(out.Bytes(), &stdout))
+		assert.Nil(t, stdout.Header)
+		require.NoError(t, json.Unmarshal(file.Bytes(), &report))
+		assert.Equal(t, header, report.Header)
+		assert.Equal(t, stdout.Results, report.Results)
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		out, file := &bytes.Buffer{}, &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputJSONL, out)
+		require.NoError(t, r.TeeFile(file, header))
+		r.Pass("edgecore", "edgecore is running\n")
+		require.NoError(t, r.Print())
+
+		lines := strings.SplitN(file.String(), "\n", 2)
+		var line diagnoseReportLine
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &line))
+		assert.Equal(t, header, line.Header)
+		assert.Equal(t, out.String(), lines[1])
+	})
+}
+
+func TestDiagnoseReportVerbosity(t *testing.T) {
+	cases := []struct {
+		verbosity DiagnoseVerbosity
+		expected  string
+	}{
+		{verbosity: DiagnoseVerbosityQuiet, expected: "summary\n"},
+		{verbosity: DiagnoseVerbosityNormal, expected: "info\nedgecore is running\nCPU total: 4 core (0.0s)\ntext\nsummary\n"},
+		{verbosity: DiagnoseVerbosityVerbose, expected: "info\ndetail\nedgecore is running\nCPU total: 4 core (0.0s)\ncheck swap took 0.0s\ntext\nsummary\n"},
+	}
+	for _, c := range cases {
+		buf := &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputText, buf)
+		r.SetVerbosity(c.verbosity)
+
+		r.Printf("info\n")
+		r.Debugf("detail\n")
+		r.Pass("edgecore", "edgecore is running\n")
+		require.NoError(t, r.Run("cpu", func(w io.Writer) error {
+			fmt.Fprintf(w, "CPU total: %v core\n", 4)
+			return nil
+		}))
+		// the elapsed time of a check without detail is only printed in verbose mode
+		require.NoError(t, r.Run("swap", func(w io.Writer) error {
+			return nil
+		}))
+		fmt.Fprint(r.TextWriter(), "text\n")
+		r.Summaryf("summary\n")
+
+		assert.Equal(t, c.expected, buf.String())
+		// the results are recorded at every verbosity
+		assert.Len(t, r.Results, 3)
+	}
+}
+
+func TestDiagnoseVerbosity(t *testing.T) {
+	assert.Equal(t, DiagnoseVerbosityNormal, diagnoseVerbosity(&common.DiagnoseOptions{}))
+	assert.Equal(t, DiagnoseVerbosityQuiet, diagnoseVerbosity(&common.DiagnoseOptions{Quiet: true}))
+	assert.Equal(t, DiagnoseVerbosityVerbose, diagnoseVerbosity(&common.DiagnoseOptions{Verbose: true}))
+}
+
+func TestDiagnoseReportNodeName(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputText, buf)
+	r.SetNodeName("edge-node-1")
+	assert.Equal(t, "Node: edge-node-1\n", buf.String())
+
+	buf.Reset()
+	r = NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputJSON, buf)
+	r.SetNodeName("edge-node-1")
+	require.NoError(t, r.Print())
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, "edge-node-1", got["nodeName"])
+}
+
+func TestDiagnoseNodeName(t *testing.T) {
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+	assert.Equal(t, hostname, DiagnoseNodeName(filepath.Join(t.TempDir(), "edgecore.yaml")))
+
+	config := filepath.Join(t.TempDir(), "edgecore.yaml")
+	require.NoError(t, os.WriteFile(config, []byte("modules:\n  edged:\n    hostnameOverride: edge-node-1\n"), 0600))
+	assert.Equal(t, "edge-node-1", DiagnoseNodeName(config))
+}
+
+func TestDiagnoseReportRunAll(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewDiagnoseReport(common.ArgDiagnoseInstall, common.DiagnoseOutputText, buf)
+
+	// the first check waits for the second one, which only finishes if they run concurrently
+	started := make(chan struct{})
+	err := r.RunAll(2, []ReportCheck{
+		{Name: "slow", Fn: func(w io.Writer) error {
+			<-started
+			fmt.Fprintln(w, "slow check done")
+			return errors.New("slow check failed")
+		}},
+		{Name: "fast", Fn: func(w io.Writer) error {
+			close(started)
+			fmt.Fprintln(w, "fast check done")
+			return nil
+		}},
+		{Name: "another", Fn: func(w io.Writer) error {
+			return errors.New("another check failed")
+		}},
+	})
+	require.EqualError(t, err, "slow check failed\nanother check failed")
+	assert.Equal(t, "slow check done (0.0s)\nfast check done (0.0s)\n", buf.String())
+	assert.Equal(t, []common.DiagnoseResult{
+		{Check: "slow", Status: common.DiagnoseStatusFail, Code: DiagnoseErrorCodeCheckFailed, Message: "slow check failed", Detail: "slow check done"},
+		{Check: "fast", Status: common.DiagnoseStatusPass, Detail: "fast check done"},
+		{Check: "another", Status: common.DiagnoseStatusFail, Code: DiagnoseErrorCodeCheckFailed, Message: "another check failed"},
+	}, r.Results)
+}
+
+func TestDiagnoseReportElapsed(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputText, buf)
+	require.NoError(t, r.Run("cloudhub", func(w io.Writer) error {
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprintln(w, "cloudcore websocket connection success")
+		return nil
+	}))
+	require.Len(t, r.Results, 1)
+	assert.GreaterOrEqual(t, r.Results[0].ElapsedMs, int64(20))
+	assert.Regexp(t, `^cloudcore websocket connection success \(\d+\.\ds\)\n$`, buf.String())
+
+	data, err := json.Marshal(r.Results[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"elapsedMs":`)
+}
+
+func TestDiagnoseReportContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := newTestReport()
+	r.SetContext(ctx)
+	require.NoError(t, r.Run("edgecore", func(w io.Writer) error {
+		cancel()
+		return nil
+	}))
+	err := r.Run("cloudhub", func(w io.Writer) error {
+		t.Fatal("the check is run after the context is canceled")
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.EqualError(t, err, "diagnose is canceled before the cloudhub check: context canceled")
+	require.ErrorIs(t, r.RunAll(2, []ReportCheck{{Name: "cpu", Fn: func(w io.Writer) error { return nil }}}), context.Canceled)
+	assert.Len(t, r.Results, 1)
+}
+
+func TestDiagnoseReportWarning(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewDiagnoseReport(common.ArgDiagnoseInstall, common.DiagnoseOutputText, buf)
+
+	require.NoError(t, r.Run("disk", func(w io.Writer) error {
+		fmt.Fprintln(w, "disk usage rate: 0.85")
+		return warningError(errors.New("disk usage rate 0.85 is above 0.8"))
+	}))
+	r.Warnf("pod", "Pod %s restarted %d times\n", "nginx", 3)
+
+	assert.Equal(t, "disk usage rate: 0.85 (0.0s)\nWARNING: disk usage rate 0.85 is above 0.8\nWARNING: Pod nginx restarted 3 times\n", buf.String())
+	assert.Equal(t, []common.DiagnoseResult{
+		{Check: "disk", Status: common.DiagnoseStatusWarn, Message: "disk usage rate 0.85 is above 0.8", Detail: "disk usage rate: 0.85"},
+		{Check: "pod", Status: common.DiagnoseStatusWarn, Message: "Pod nginx restarted 3 times"},
+	}, r.Results)
+}
+
+func TestDiagnoseReportSkipped(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputText, buf)
+
+	require.NoError(t, r.Run("mqtt", func(w io.Writer) error {
+		return skippedError("eventbus is disabled")
+	}))
+	r.Skipf("servicebus", "%s is disabled", "servicebus")
+	r.SkipAll([]string{"cloudhub-latency", "mtu"}, "websocket is disabled")
+	r.Pass("edgecore", "edgecore is running\n")
+	r.PrintSkipped()
+
+	assert.Equal(t, "SKIPPED: mqtt: eventbus is disabled\n"+
+		"SKIPPED: servicebus: servicebus is disabled\n"+
+		"SKIPPED: cloudhub-latency, mtu: websocket is disabled\nedgecore is running\n"+
+		"\n4 checks are skipped:\n  mqtt: eventbus is disabled\n  servicebus: servicebus is disabled\n"+
+		"  cloudhub-latency: websocket is disabled\n  mtu: websocket is disabled\n", buf.String())
+	assert.Equal(t, []common.DiagnoseResult{
+		{Check: "mqtt", Status: common.DiagnoseStatusSkip, Message: "eventbus is disabled"},
+		{Check: "servicebus", Status: common.DiagnoseStatusSkip, Message: "servicebus is disabled"},
+		{Check: "cloudhub-latency", Status: common.DiagnoseStatusSkip, Message: "websocket is disabled"},
+		{Check: "mtu", Status: common.DiagnoseStatusSkip, Message: "websocket is disabled"},
+		{Check: "edgecore", Status: common.DiagnoseStatusPass, Message: "edgecore is running"},
+	}, r.Results)
+	overall, summary := r.summarize()
+	assert.Equal(t, common.DiagnoseOverallHealthy, overall)
+	assert.Equal(t, DiagnoseReportSummary{Pass: 1, Skip: 4}, *summary)
+
+	t.Run("nothing skipped", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputText, buf)
+		r.Pass("edgecore", "edgecore is running\n")
+		r.PrintSkipped()
+		assert.Equal(t, "edgecore is running\n", buf.String())
+	})
+}
+
+func TestDiagnoseReportPlatformSkip(t *testing.T) {
+	saved := platformUnsupportedChecks
+	platformUnsupportedChecks = map[string]bool{"swap": true}
+	defer func() {
+		platformUnsupportedChecks = saved
+	}()
+
+	r := newTestReport()
+	var run bool
+	require.NoError(t, r.Run("swap", func(w io.Writer) error {
+		run = true
+		return errors.New("swap is enabled")
+	}))
+	require.NoError(t, r.RunAll(2, []ReportCheck{
+		{Name: "swap", Fn: func(w io.Writer) error {
+			run = true
+			return nil
+		}},
+		{Name: "cpu", Fn: func(w io.Writer) error {
+			fmt.Fprintln(w, "cpu is checked")
+			return nil
+		}},
+	}))
+	assert.False(t, run)
+	reason := "not supported on " + runtime.GOOS
+	assert.Equal(t, common.DiagnoseResult{Check: "swap", Status: common.DiagnoseStatusSkip, Message: reason}, r.Results[0])
+	assert.Equal(t, common.DiagnoseResult{Check: "swap", Status: common.DiagnoseStatusSkip, Message: reason}, r.Results[1])
+	assert.Equal(t, common.DiagnoseStatusPass, r.Results[2].Status)
+}
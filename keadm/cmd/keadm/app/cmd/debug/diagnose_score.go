@@ -0,0 +1,96 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+const (
+	// defaultScoreWeight is the weight of the checks in the health score which are not in criticalChecks
+	defaultScoreWeight = 1
+	// criticalScoreWeight is the weight of criticalChecks, without which the node runs no pod or is cut off from the cloud
+	criticalScoreWeight = 5
+)
+
+// criticalChecks are the checks weighted by criticalScoreWeight in the health score
+var criticalChecks = map[string]bool{
+	"edgecore": true,
+	"config":   true,
+	"database": true,
+	"node":     true,
+	"runtime":  true,
+	"edged":    true,
+	"cloudhub": true,
+}
+
+// healthScore rolls up the statuses of the checks into a score from 0 to 100. Every check contributes its weight,
+// a passed check all of it, a warned check half of it and a failed check none of it, and the score is the percentage
+// of the weights contributed. The skipped checks and the checks of weight 0 are not scored, and the score is 100
+// if no check is scored. The weight of a check is the one of weights if it is set there, criticalScoreWeight if it is
+// one of criticalChecks, and defaultScoreWeight otherwise.
+func healthScore(checks []string, statuses map[string]string, weights map[string]int) int {
+	var total, earned float64
+	for _, check := range checks {
+		status := statuses[check]
+		if status == common.DiagnoseStatusSkip {
+			continue
+		}
+		weight := float64(scoreWeight(check, weights))
+		total += weight
+		switch status {
+		case common.DiagnoseStatusPass:
+			earned += weight
+		case common.DiagnoseStatusWarn:
+			earned += weight / 2
+		}
+	}
+	if total == 0 {
+		return 100
+	}
+	return int(math.Round(100 * earned / total))
+}
+
+// scoreWeight returns the weight of the check in the health score
+func scoreWeight(check string, weights map[string]int) int {
+	if weight, ok := weights[check]; ok {
+		return weight
+	}
+	if criticalChecks[check] {
+		return criticalScoreWeight
+	}
+	return defaultScoreWeight
+}
+
+// validateScoreWeights rejects the negative weights of --score-weights
+func validateScoreWeights(weights map[string]int) error {
+	checks := make([]string, 0, len(weights))
+	for check := range weights {
+		checks = append(checks, check)
+	}
+	sort.Strings(checks)
+	for _, check := range checks {
+		if weights[check] < 0 {
+			return fmt.Errorf("invalid --score-weights %s=%d, the weight must not be negative", check, weights[check])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func TestHealthScore(t *testing.T) {
+	cases := []struct {
+		name     string
+		statuses map[string]string
+		weights  map[string]int
+		expected int
+	}{
+		{
+			name:     "no checks",
+			expected: 100,
+		},
+		{
+			name: "all passed",
+			statuses: map[string]string{
+				"edgecore": common.DiagnoseStatusPass,
+				"swap":     common.DiagnoseStatusPass,
+			},
+			expected: 100,
+		},
+		{
+			name: "critical check failed",
+			statuses: map[string]string{
+				"cloudhub": common.DiagnoseStatusFail,
+				"swap":     common.DiagnoseStatusPass,
+			},
+			expected: 17,
+		},
+		{
+			name: "check warned",
+			statuses: map[string]string{
+				"edgecore": common.DiagnoseStatusPass,
+				"swap":     common.DiagnoseStatusWarn,
+			},
+			expected: 92,
+		},
+		{
+			name: "skipped checks are not scored",
+			statuses: map[string]string{
+				"edgecore":   common.DiagnoseStatusPass,
+				"servicebus": common.DiagnoseStatusSkip,
+			},
+			expected: 100,
+		},
+		{
+			name: "weights override the defaults",
+			statuses: map[string]string{
+				"cloudhub": common.DiagnoseStatusFail,
+				"swap":     common.DiagnoseStatusPass,
+				"mtu":      common.DiagnoseStatusFail,
+			},
+			weights:  map[string]int{"cloudhub": 0, "swap": 3},
+			expected: 75,
+		},
+		{
+			name: "all checks of weight 0",
+			statuses: map[string]string{
+				"swap": common.DiagnoseStatusFail,
+			},
+			weights:  map[string]int{"swap": 0},
+			expected: 100,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			checks := make([]string, 0, len(c.statuses))
+			for check := range c.statuses {
+				checks = append(checks, check)
+			}
+			assert.Equal(t, c.expected, healthScore(checks, c.statuses, c.weights))
+		})
+	}
+}
+
+func TestDiagnoseReportHealthScore(t *testing.T) {
+	r := NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputJSON, &bytes.Buffer{})
+	r.Pass("edgecore", "edgecore is running\n")
+	r.Failf("cloudhub", "cloudhub is unreachable\n")
+	require.NoError(t, r.Print())
+	require.NotNil(t, r.HealthScore)
+	assert.Equal(t, 50, *r.HealthScore)
+
+	r.SetScoreWeights(map[string]int{"cloudhub": 0})
+	require.NoError(t, r.Print())
+	assert.Equal(t, 100, *r.HealthScore)
+}
+
+func TestValidateScoreWeights(t *testing.T) {
+	require.NoError(t, validateScoreWeights(nil))
+	require.NoError(t, validateScoreWeights(map[string]int{"cloudhub": 10, "swap": 0}))
+	require.EqualError(t, validateScoreWeights(map[string]int{"swap": -1}),
+		"invalid --score-weights swap=-1, the weight must not be negative")
+}
@@ -17,18 +17,31 @@ limitations under the License.
 package debug
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/agiledragon/gomonkey/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/kubeedge/api/apis/common/constants"
 	cfgv1alpha2 "github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
 	"github.com/kubeedge/kubeedge/pkg/util/files"
@@ -46,6 +59,11 @@ func TestNewDiagnose(t *testing.T) {
 
 	subcommands := cmd.Commands()
 	assert.NotNil(subcommands)
+
+	output := cmd.PersistentFlags().Lookup(common.FlagNameOutput)
+	assert.NotNil(output)
+	assert.Equal("Indicate the output format. Currently supports formats such as text|json|yaml|jsonl|go-template, "+
+		"the json lines format streams a line per check result", output.Usage)
 }
 
 func TestNewSubDiagnose(t *testing.T) {
@@ -60,46 +78,205 @@ func TestNewSubDiagnose(t *testing.T) {
 		{
 			use: common.ArgDiagnoseNode,
 			expectedDefValue: map[string]string{
-				common.EdgecoreConfig: constants.EdgecoreConfigPath,
+				"proxy":                    "",
+				"watch":                    "false",
+				"interval":                 "5s",
+				"max-skew":                 "1m0s",
+				"max-latency":              "1s",
+				"mqtt-connect":             "false",
+				"domain":                   "",
+				"host":                     "",
+				"dry-run":                  "false",
+				"quiet":                    "false",
+				common.EdgecoreConfig:      constants.EdgecoreConfigPath,
+				"timeout":                  "3",
+				"insecure-skip-tls-verify": "false",
 			},
 			expectedShorthand: map[string]string{
-				common.EdgecoreConfig: "c",
+				"proxy":                    "",
+				"watch":                    "w",
+				"interval":                 "",
+				"max-skew":                 "",
+				"max-latency":              "",
+				"mqtt-connect":             "",
+				"domain":                   "d",
+				"host":                     "",
+				"dry-run":                  "",
+				"quiet":                    "q",
+				common.EdgecoreConfig:      "c",
+				"timeout":                  "",
+				"insecure-skip-tls-verify": "",
 			},
 			expectedUsage: map[string]string{
-				common.EdgecoreConfig: fmt.Sprintf("Specify configuration file, default is %s", constants.EdgecoreConfigPath),
+				"proxy":                    "the proxy of the http checks, which overrides the HTTP_PROXY and HTTPS_PROXY env vars (e.g. http://proxy:3128)",
+				"watch":                    "re-run the diagnose every interval until interrupted, the failures do not stop the watch",
+				"interval":                 "the interval of the diagnose in watch mode",
+				"max-skew":                 "fail the clock skew check if the clock of the node differs from cloudcore by more than the duration",
+				"max-latency":              "fail the cloudhub latency check if the tls handshake time or the first byte latency exceeds the duration, 0 disables it",
+				"domain":                   "the domain the nameservers of the resolvConf of edged resolve in the pod dns check, default is kubeedge.io",
+				"mqtt-connect":             "send a CONNECT packet to the mqtt brokers of eventbus besides the tcp connection check",
+				"host":                     "diagnose the remote node over ssh instead of the local node (e.g. user@edge1), the ssh config and keys of the user are used",
+				"dry-run":                  "print the ordered checks and their targets derived from the edgecore config without running them",
+				"quiet":                    "print only the final result of the diagnose, the exit code tells whether it fails",
+				common.EdgecoreConfig:      fmt.Sprintf("Specify configuration file, default is %s", constants.EdgecoreConfigPath),
+				"timeout":                  "specify the timeout in seconds of the network checks",
+				"insecure-skip-tls-verify": "skip the verification of the server certificates in the network checks, which is insecure and only for testing",
 			},
 		},
 		{
 			use: common.ArgDiagnosePod,
 			expectedDefValue: map[string]string{
-				"namespace": "default",
+				"namespace":                "default",
+				"config":                   constants.EdgecoreConfigPath,
+				"selector":                 "",
+				"all-namespaces":           "false",
+				"uid":                      "",
+				"since":                    "0s",
+				"db-path":                  "",
+				"logs":                     "false",
+				"tail":                     "20",
+				"dry-run":                  "false",
+				"timeout":                  "3",
+				"insecure-skip-tls-verify": "false",
+			},
+			expectedShorthand: map[string]string{
+				"namespace":                "n",
+				"config":                   "c",
+				"selector":                 "l",
+				"all-namespaces":           "A",
+				"uid":                      "",
+				"since":                    "",
+				"db-path":                  "",
+				"logs":                     "",
+				"tail":                     "",
+				"dry-run":                  "",
+				"timeout":                  "",
+				"insecure-skip-tls-verify": "",
+			},
+			expectedUsage: map[string]string{
+				"namespace":                "specify namespace",
+				"config":                   fmt.Sprintf("Specify configuration file, default is %s", constants.EdgecoreConfigPath),
+				"selector":                 "Selector (label query) to filter the pods to diagnose, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)",
+				"all-namespaces":           "diagnose the pods matching the label selector or the pod of --uid in all the namespaces in the edge database",
+				"uid":                      "diagnose the cached pod of the uid instead of a pod name, e.g. the uid of the exact pod instance in an event or a log line",
+				"db-path":                  "diagnose the pods in the edgecore database directly, e.g. a database copied from the node, the node diagnose and the config are skipped",
+				"logs":                     "print the last lines of the logs of the containers which are not ready, which are read from the log files of the containers on the node",
+				"tail":                     "the number of the last lines of the container logs printed by --logs",
+				"dry-run":                  "print the ordered checks and their targets derived from the edgecore config without running them",
+				"since":                    "Only report the container restarts within the duration as current problems, the older ones are reported as stabilized (e.g. 1h)",
+				"timeout":                  "specify the timeout in seconds of the network checks",
+				"insecure-skip-tls-verify": "skip the verification of the server certificates in the network checks, which is insecure and only for testing",
+			},
+		},
+		{
+			use: common.ArgDiagnoseDeployment,
+			expectedDefValue: map[string]string{
+				"namespace":                "default",
+				"since":                    "0s",
+				"timeout":                  "3",
+				"insecure-skip-tls-verify": "false",
+			},
+			expectedShorthand: map[string]string{
+				"namespace":                "n",
+				"since":                    "",
+				"timeout":                  "",
+				"insecure-skip-tls-verify": "",
+			},
+			expectedUsage: map[string]string{
+				"namespace":                "specify namespace",
+				"since":                    "Only report the container restarts within the duration as current problems, the older ones are reported as stabilized (e.g. 1h)",
+				"timeout":                  "specify the timeout in seconds of the network checks",
+				"insecure-skip-tls-verify": "skip the verification of the server certificates in the network checks, which is insecure and only for testing",
+			},
+		},
+		{
+			use: common.ArgDiagnoseModule,
+			expectedDefValue: map[string]string{
+				common.EdgecoreConfig:      constants.EdgecoreConfigPath,
+				"timeout":                  "3",
+				"insecure-skip-tls-verify": "false",
+			},
+			expectedShorthand: map[string]string{
+				common.EdgecoreConfig:      "c",
+				"timeout":                  "",
+				"insecure-skip-tls-verify": "",
+			},
+			expectedUsage: map[string]string{
+				common.EdgecoreConfig:      fmt.Sprintf("Specify configuration file, default is %s", constants.EdgecoreConfigPath),
+				"timeout":                  "specify the timeout in seconds of the network checks",
+				"insecure-skip-tls-verify": "skip the verification of the server certificates in the network checks, which is insecure and only for testing",
+			},
+		},
+		{
+			use: common.ArgDiagnoseAll,
+			expectedDefValue: map[string]string{
+				common.EdgecoreConfig:      constants.EdgecoreConfigPath,
+				"fail-fast":                "false",
+				"namespace":                "default",
+				"all-namespaces":           "false",
+				"timeout":                  "3",
+				"insecure-skip-tls-verify": "false",
 			},
 			expectedShorthand: map[string]string{
-				"namespace": "n",
+				common.EdgecoreConfig:      "c",
+				"fail-fast":                "",
+				"namespace":                "n",
+				"all-namespaces":           "A",
+				"timeout":                  "",
+				"insecure-skip-tls-verify": "",
 			},
 			expectedUsage: map[string]string{
-				"namespace": "specify namespace",
+				common.EdgecoreConfig:      fmt.Sprintf("Specify configuration file, default is %s", constants.EdgecoreConfigPath),
+				"fail-fast":                "stop the diagnose at the first failure",
+				"namespace":                "diagnose only the pods of the namespace, the pods of all the namespaces are diagnosed if it is not specified",
+				"all-namespaces":           "diagnose the pods of all the namespaces in the edge database, which is the default",
+				"timeout":                  "specify the timeout in seconds of the network checks",
+				"insecure-skip-tls-verify": "skip the verification of the server certificates in the network checks, which is insecure and only for testing",
 			},
 		},
 		{
 			use: common.ArgDiagnoseInstall,
 			expectedDefValue: map[string]string{
-				"dns-ip":           "",
-				"domain":           "",
-				"ip":               "",
-				"cloud-hub-server": "",
+				"dns-ip":                   "",
+				"config":                   constants.EdgecoreConfigPath,
+				"domain":                   "",
+				"ip":                       "",
+				"cloud-hub-server":         "",
+				"cert-warn-days":           "30",
+				"retries":                  "1",
+				"verbose":                  "false",
+				"only":                     "[]",
+				"skip":                     "[]",
+				"timeout":                  "3",
+				"insecure-skip-tls-verify": "false",
 			},
 			expectedShorthand: map[string]string{
-				"dns-ip":           "D",
-				"domain":           "d",
-				"ip":               "i",
-				"cloud-hub-server": "s",
+				"cert-warn-days":           "",
+				"config":                   "c",
+				"retries":                  "",
+				"verbose":                  "v",
+				"only":                     "",
+				"skip":                     "",
+				"timeout":                  "",
+				"insecure-skip-tls-verify": "",
+				"dns-ip":                   "D",
+				"domain":                   "d",
+				"ip":                       "i",
+				"cloud-hub-server":         "s",
 			},
 			expectedUsage: map[string]string{
-				"dns-ip":           "specify test dns server ip",
-				"domain":           "specify test domain",
-				"ip":               "specify test ip",
-				"cloud-hub-server": "specify cloudhub server",
+				"dns-ip":                   "specify test dns server ip",
+				"config":                   fmt.Sprintf("Specify configuration file, default is %s", constants.EdgecoreConfigPath),
+				"domain":                   "specify test domain",
+				"ip":                       "specify test ip",
+				"cloud-hub-server":         "specify cloudhub server",
+				"cert-warn-days":           "warn in the certificate check if a certificate expires within the specified days",
+				"retries":                  "the number of attempts of the network connectivity checks, with exponential backoff between attempts",
+				"only":                     "run only the install checks of the comma-separated names, the checks are: cpu, mem, disk, disk-paths, kernel, swap, dns, network, timesync, timezone, clock, pid, cert",
+				"skip":                     "skip the install checks of the comma-separated names, e.g. the network checks on an air-gapped node",
+				"verbose":                  "print the extra details of the checks, e.g. the parsed config values and each attempt of the network connectivity checks",
+				"timeout":                  "specify the timeout in seconds of the network checks",
+				"insecure-skip-tls-verify": "skip the verification of the server certificates in the network checks, which is insecure and only for testing",
 			},
 		},
 	}
@@ -110,7 +287,7 @@ func TestNewSubDiagnose(t *testing.T) {
 				Use:  test.use,
 				Desc: fmt.Sprintf("Diagnose %s", test.use),
 			}
-			cmd := NewSubDiagnose(diagnoseObj)
+			cmd := NewSubDiagnose(diagnoseObj, NewDiagnoseOptions())
 
 			assert.NotNil(cmd)
 			assert.Equal(diagnoseObj.Use, cmd.Use)
@@ -133,6 +310,39 @@ func TestNewSubDiagnose(t *testing.T) {
 	}
 }
 
+func TestNewSubDiagnoseNetworkFlags(t *testing.T) {
+	for _, use := range []string{common.ArgDiagnoseNode, common.ArgDiagnoseAll, common.ArgDiagnoseInstall} {
+		t.Run(use, func(t *testing.T) {
+			cmd := NewSubDiagnose(Diagnose{Use: use}, NewDiagnoseOptions())
+			retries := cmd.Flags().Lookup("retries")
+			require.NotNil(t, retries)
+			assert.Equal(t, "1", retries.DefValue)
+			assert.Equal(t, "the number of attempts of the network connectivity checks, with exponential backoff between attempts", retries.Usage)
+			verbose := cmd.Flags().Lookup("verbose")
+			require.NotNil(t, verbose)
+			assert.Equal(t, "v", verbose.Shorthand)
+		})
+	}
+}
+
+func TestNewSubDiagnoseSharedFlags(t *testing.T) {
+	cases := map[string][]string{
+		common.ArgDiagnoseNode:    {"config", "max-skew", "expected-tz", "max-latency", "min-mtu", "min-disk-free", "cni-conf-dir", "dry-run"},
+		common.ArgDiagnoseAll:     {"config", "max-skew", "max-latency", "min-disk-free", "cni-bin-dir", "min-cpu", "kernel-modules", "only", "dry-run"},
+		common.ArgDiagnoseInstall: {"config", "max-skew", "min-disk-free", "min-memory", "kernel-modules", "sysctls", "skip", "dry-run"},
+		common.ArgDiagnoseModule:  {"config", "dry-run"},
+		common.ArgDiagnosePod:     {"config", "since", "dry-run"},
+	}
+	for use, flags := range cases {
+		t.Run(use, func(t *testing.T) {
+			cmd := NewSubDiagnose(Diagnose{Use: use}, NewDiagnoseOptions())
+			for _, name := range flags {
+				assert.NotNilf(t, cmd.Flags().Lookup(name), "flag %s is not registered", name)
+			}
+		})
+	}
+}
+
 func TestNewDiagnoseOptions(t *testing.T) {
 	assert := assert.New(t)
 
@@ -141,8 +351,11 @@ func TestNewDiagnoseOptions(t *testing.T) {
 
 	assert.Equal("default", do.Namespace)
 	assert.Equal(constants.EdgecoreConfigPath, do.Config)
+	assert.Equal(common.DiagnoseOutputText, do.Output)
 	assert.Equal("", do.CheckOptions.IP)
 	assert.Equal(3, do.CheckOptions.Timeout)
+	assert.Equal(common.DefaultCertWarnDays, do.CheckOptions.CertWarnDays)
+	assert.Equal(1, do.CheckOptions.Retries)
 }
 
 func TestExecuteDiagnose(t *testing.T) {
@@ -161,7 +374,7 @@ func TestExecuteDiagnose(t *testing.T) {
 		patches := gomonkey.NewPatches()
 		defer patches.Reset()
 
-		patches.ApplyFunc(DiagnoseNode, func(_ops *common.DiagnoseOptions) error {
+		patches.ApplyFunc(DiagnoseNode, func(_ops *common.DiagnoseOptions, _r *DiagnoseReport) error {
 			return nil
 		})
 		patches.ApplyFunc(util.PrintSucceed, func(cmd, s string) {
@@ -171,7 +384,8 @@ func TestExecuteDiagnose(t *testing.T) {
 		})
 
 		var da Diagnose
-		da.ExecuteDiagnose(common.ArgDiagnoseNode, opts, nil)
+		err := da.ExecuteDiagnose(common.ArgDiagnoseNode, opts, nil)
+		assert.NoError(t, err)
 		assert.True(t, mustCallPrintSuccessed)
 	})
 
@@ -181,10 +395,10 @@ func TestExecuteDiagnose(t *testing.T) {
 		patches := gomonkey.NewPatches()
 		defer patches.Reset()
 
-		patches.ApplyFunc(DiagnoseNode, func(_ops *common.DiagnoseOptions) error {
+		patches.ApplyFunc(DiagnoseNode, func(_ops *common.DiagnoseOptions, _r *DiagnoseReport) error {
 			return nil
 		})
-		patches.ApplyFunc(DiagnosePod, func(_ops *common.DiagnoseOptions, _podName string) error {
+		patches.ApplyFunc(DiagnosePod, func(_ops *common.DiagnoseOptions, _podName string, _r *DiagnoseReport) error {
 			mustCallDiagnosePod = true
 			return nil
 		})
@@ -195,7 +409,8 @@ func TestExecuteDiagnose(t *testing.T) {
 		})
 
 		var da Diagnose
-		da.ExecuteDiagnose(common.ArgDiagnosePod, opts, []string{"test-pod"})
+		err := da.ExecuteDiagnose(common.ArgDiagnosePod, opts, []string{"test-pod"})
+		assert.NoError(t, err)
 		assert.True(t, mustCallPrintSuccessed)
 		assert.True(t, mustCallDiagnosePod)
 	})
@@ -206,10 +421,10 @@ func TestExecuteDiagnose(t *testing.T) {
 		patches := gomonkey.NewPatches()
 		defer patches.Reset()
 
-		patches.ApplyFunc(DiagnoseNode, func(_ops *common.DiagnoseOptions) error {
+		patches.ApplyFunc(DiagnoseNode, func(_ops *common.DiagnoseOptions, _r *DiagnoseReport) error {
 			return errors.New("test error")
 		})
-		patches.ApplyFunc(DiagnosePod, func(_ops *common.DiagnoseOptions, _podName string) error {
+		patches.ApplyFunc(DiagnosePod, func(_ops *common.DiagnoseOptions, _podName string, _r *DiagnoseReport) error {
 			mustCallDiagnosePod = true
 			return nil
 		})
@@ -220,7 +435,10 @@ func TestExecuteDiagnose(t *testing.T) {
 		})
 
 		var da Diagnose
-		da.ExecuteDiagnose(common.ArgDiagnosePod, opts, []string{"test-pod"})
+		err := da.ExecuteDiagnose(common.ArgDiagnosePod, opts, []string{"test-pod"})
+		var exitErr *DiagnoseExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, DiagnoseExitCodeGeneric, exitErr.ExitCode())
 		assert.True(t, mustCallPrintFail)
 		assert.False(t, mustCallDiagnosePod)
 	})
@@ -231,7 +449,7 @@ func TestExecuteDiagnose(t *testing.T) {
 		patches := gomonkey.NewPatches()
 		defer patches.Reset()
 
-		patches.ApplyFunc(DiagnoseInstall, func(_ob *common.CheckOptions) error {
+		patches.ApplyFunc(DiagnoseInstall, func(_ob *common.CheckOptions, _r *DiagnoseReport) error {
 			return nil
 		})
 		patches.ApplyFunc(util.PrintSucceed, func(cmd, s string) {
@@ -241,9 +459,314 @@ func TestExecuteDiagnose(t *testing.T) {
 		})
 
 		var da Diagnose
-		da.ExecuteDiagnose(common.ArgDiagnoseInstall, opts, nil)
+		err := da.ExecuteDiagnose(common.ArgDiagnoseInstall, opts, nil)
+		assert.NoError(t, err)
 		assert.True(t, mustCallPrintSuccessed)
 	})
+
+	t.Run("using the json output", func(t *testing.T) {
+		var calledPrintFail bool
+
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		patches.ApplyFunc(DiagnoseNode, func(_ops *common.DiagnoseOptions, r *DiagnoseReport) error {
+			r.Pass("edgecore", "edgecore is running\n")
+			return r.Fail("config", newDiagnoseExitError(DiagnoseExitCodeConfigNotFound,
+				errors.New("edge config is not exists")))
+		})
+		patches.ApplyFunc(util.PrintFail, func(cmd, s string) {
+			calledPrintFail = true
+		})
+
+		oldStdout := os.Stdout
+		rp, wp, err := os.Pipe()
+		require.NoError(t, err)
+		os.Stdout = wp
+
+		var da Diagnose
+		exitErr := da.ExecuteDiagnose(common.ArgDiagnoseNode, &common.DiagnoseOptions{
+			Config: constants.EdgecoreConfigPath,
+			Output: common.DiagnoseOutputJSON,
+		}, nil)
+
+		wp.Close()
+		os.Stdout = oldStdout
+		var buf bytes.Buffer
+		_, err = buf.ReadFrom(rp)
+		require.NoError(t, err)
+
+		var report DiagnoseReport
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+		assert.Equal(t, common.ArgDiagnoseNode, report.Diagnose)
+		assert.Equal(t, []common.DiagnoseResult{
+			{Check: "edgecore", Status: common.DiagnoseStatusPass, Message: "edgecore is running"},
+			{Check: "config", Status: common.DiagnoseStatusFail, Code: DiagnoseErrorCodeConfigNotFound, Message: "edge config is not exists"},
+		}, report.Results)
+		assert.False(t, calledPrintFail)
+		assert.Equal(t, DiagnoseExitCodeConfigNotFound, exitErr.(*DiagnoseExitError).ExitCode())
+	})
+
+	t.Run("using the report file", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		patches.ApplyFunc(DiagnoseNode, func(_ops *common.DiagnoseOptions, r *DiagnoseReport) error {
+			r.Pass("edgecore", "edgecore is running\n")
+			return nil
+		})
+		patches.ApplyFunc(util.PrintSucceed, func(cmd, s string) {})
+
+		reportFile := filepath.Join(t.TempDir(), "report.yaml")
+		var da Diagnose
+		err := da.ExecuteDiagnose(common.ArgDiagnoseNode, &common.DiagnoseOptions{
+			Config:     constants.EdgecoreConfigPath,
+			Output:     common.DiagnoseOutputText,
+			ReportFile: reportFile,
+		}, nil)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(reportFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "# config: "+constants.EdgecoreConfigPath)
+		assert.Contains(t, string(data), "edgecore is running\n")
+	})
+
+	t.Run("pod name is required", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+		patches.ApplyFunc(util.PrintFail, func(cmd, s string) {})
+
+		var da Diagnose
+		err := da.ExecuteDiagnose(common.ArgDiagnosePod, opts, nil)
+		require.ErrorContains(t, err, "you must specify a pod name")
+	})
+
+	t.Run("all namespaces requires a label selector", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+		patches.ApplyFunc(util.PrintFail, func(cmd, s string) {})
+
+		var da Diagnose
+		allNsOpts := *opts
+		allNsOpts.AllNamespaces = true
+		err := da.ExecuteDiagnose(common.ArgDiagnosePod, &allNsOpts, []string{"nginx"})
+		require.ErrorContains(t, err, "--all-namespaces only supports the pods of a label selector")
+	})
+
+	t.Run("uid is exclusive with a pod name", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+		patches.ApplyFunc(util.PrintFail, func(cmd, s string) {})
+
+		var da Diagnose
+		uidOpts := *opts
+		uidOpts.UID = "53c5e5d1-7d3c-4b8a-9a43-3f1b8f0a3e7c"
+		err := da.ExecuteDiagnose(common.ArgDiagnosePod, &uidOpts, []string{"nginx"})
+		require.ErrorContains(t, err, "--uid is not supported together with a pod name")
+	})
+
+	t.Run("report is not supported in watch mode", func(t *testing.T) {
+		var da Diagnose
+		err := da.ExecuteDiagnose(common.ArgDiagnoseNode, &common.DiagnoseOptions{Watch: true, ReportFile: "report.txt"}, nil)
+		require.ErrorContains(t, err, "--report is not supported together with --watch")
+	})
+
+	t.Run("deployment name is required", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+		patches.ApplyFunc(util.PrintFail, func(cmd, s string) {})
+
+		var da Diagnose
+		err := da.ExecuteDiagnose(common.ArgDiagnoseDeployment, opts, nil)
+		require.ErrorContains(t, err, "you must specify a deployment name")
+	})
+
+	t.Run("unsupported output format", func(t *testing.T) {
+		var da Diagnose
+		err := da.ExecuteDiagnose(common.ArgDiagnoseNode, &common.DiagnoseOptions{Output: "wide"}, nil)
+		require.ErrorContains(t, err, "unsupported output format")
+	})
+
+	t.Run("go template is required", func(t *testing.T) {
+		var da Diagnose
+		err := da.ExecuteDiagnose(common.ArgDiagnoseNode, &common.DiagnoseOptions{Output: common.DiagnoseOutputGoTemplate}, nil)
+		require.ErrorContains(t, err, "--template is required by the go-template output format")
+	})
+
+	t.Run("invalid options in the json output", func(t *testing.T) {
+		oldStdout := os.Stdout
+		rp, wp, err := os.Pipe()
+		require.NoError(t, err)
+		os.Stdout = wp
+
+		var da Diagnose
+		diagnoseErr := da.ExecuteDiagnose(common.ArgDiagnoseNode, &common.DiagnoseOptions{
+			Output:  common.DiagnoseOutputJSON,
+			Verbose: true,
+			Quiet:   true,
+		}, nil)
+
+		wp.Close()
+		os.Stdout = oldStdout
+		var buf bytes.Buffer
+		_, err = buf.ReadFrom(rp)
+		require.NoError(t, err)
+		require.EqualError(t, diagnoseErr, "--verbose and --quiet are mutually exclusive")
+
+		var report DiagnoseReport
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &report), "stdout is not json: %q", buf.String())
+		assert.Equal(t, []common.DiagnoseResult{
+			{Check: "options", Status: common.DiagnoseStatusFail, Code: DiagnoseErrorCodeCheckFailed,
+				Message: "--verbose and --quiet are mutually exclusive"},
+		}, report.Results)
+	})
+
+	t.Run("every line of the output is terminated", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		patches.ApplyFunc(DiagnoseNode, func(_ops *common.DiagnoseOptions, r *DiagnoseReport) error {
+			r.Pass("edgecore", "edgecore is running\n")
+			r.Pass("cloudhub", "cloudcore websocket connection success\n")
+			return nil
+		})
+		patches.ApplyFunc(initPodDatabase, func(_ops *common.DiagnoseOptions, _r *DiagnoseReport) error {
+			return nil
+		})
+		patches.ApplyFunc(QueryPodEventsFromDatabase, func(_namespace, _podName string) ([]v1.Event, error) {
+			return nil, nil
+		})
+		patches.ApplyFunc(QueryPodFromDatabase, func(_namespace, _podName string, _r *DiagnoseReport) (*v1.PodStatus, error) {
+			return &v1.PodStatus{
+				Phase:      v1.PodRunning,
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+				ContainerStatuses: []v1.ContainerStatus{{
+					Name: "nginx", Ready: true, State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+				}},
+			}, nil
+		})
+
+		oldStdout := os.Stdout
+		rp, wp, err := os.Pipe()
+		require.NoError(t, err)
+		os.Stdout = wp
+
+		var da Diagnose
+		diagnoseErr := da.ExecuteDiagnose(common.ArgDiagnosePod, &common.DiagnoseOptions{
+			Config:       constants.EdgecoreConfigPath,
+			Namespace:    "default",
+			CheckOptions: &common.CheckOptions{},
+		}, []string{"nginx"})
+
+		wp.Close()
+		os.Stdout = oldStdout
+		var buf bytes.Buffer
+		_, err = buf.ReadFrom(rp)
+		require.NoError(t, err)
+		require.NoError(t, diagnoseErr)
+
+		out := buf.String()
+		require.True(t, strings.HasSuffix(out, "\n"), "the output is not terminated: %q", out)
+		lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+		assert.Contains(t, lines, "Pod nginx is Ready")
+		assert.Contains(t, lines, "cloudcore websocket connection success")
+		// util.PrintSucceed prints a blank line before the result box, which is eaten by an unterminated message
+		box := slices.IndexFunc(lines, func(line string) bool { return strings.HasPrefix(line, "|") })
+		require.Greater(t, box, 0)
+		assert.Equal(t, "", lines[box-1], "line %q is not terminated before the result", lines[box-1])
+	})
+
+	t.Run("pod in the database of --db-path", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		var calledDiagnoseNode bool
+		patches.ApplyFunc(DiagnoseNode, func(_ops *common.DiagnoseOptions, _r *DiagnoseReport) error {
+			calledDiagnoseNode = true
+			return nil
+		})
+		var dataSource string
+		patches.ApplyFunc(InitDB, func(_driverName, _dbName, source string) error {
+			dataSource = source
+			return nil
+		})
+		patches.ApplyFunc(diagnosePodStatus, func(_namespace, _podName string, _since time.Duration, _r *DiagnoseReport) error {
+			return nil
+		})
+		patches.ApplyFunc(QueryPodEventsFromDatabase, func(_namespace, _podName string) ([]v1.Event, error) {
+			return nil, nil
+		})
+		patches.ApplyFunc(util.PrintSucceed, func(cmd, s string) {})
+
+		dbPath := filepath.Join(t.TempDir(), "edgecore.db")
+		var da Diagnose
+		err := da.ExecuteDiagnose(common.ArgDiagnosePod, &common.DiagnoseOptions{
+			Config: filepath.Join(t.TempDir(), "not-exist.yaml"), Namespace: "default", DBPath: dbPath,
+		}, []string{"nginx"})
+		require.ErrorContains(t, err, fmt.Sprintf("database %s is not exists", dbPath))
+
+		require.NoError(t, os.WriteFile(dbPath, nil, 0600))
+		err = da.ExecuteDiagnose(common.ArgDiagnosePod, &common.DiagnoseOptions{
+			Config: filepath.Join(t.TempDir(), "not-exist.yaml"), Namespace: "default", DBPath: dbPath,
+		}, []string{"nginx"})
+		require.NoError(t, err)
+		assert.False(t, calledDiagnoseNode)
+		assert.Equal(t, dbPath, dataSource)
+	})
+
+	t.Run("verbose and quiet", func(t *testing.T) {
+		var da Diagnose
+		err := da.ExecuteDiagnose(common.ArgDiagnoseNode, &common.DiagnoseOptions{Verbose: true, Quiet: true}, nil)
+		require.ErrorContains(t, err, "--verbose and --quiet are mutually exclusive")
+	})
+
+	t.Run("config of stdin on a remote node", func(t *testing.T) {
+		var da Diagnose
+		err := da.ExecuteDiagnose(common.ArgDiagnoseNode, &common.DiagnoseOptions{Config: util.EdgecoreConfigStdin, Host: "root@10.0.0.2"}, nil)
+		require.ErrorContains(t, err, "--config - is not supported together with --host")
+	})
+
+	t.Run("container on a remote node", func(t *testing.T) {
+		var da Diagnose
+		err := da.ExecuteDiagnose(common.ArgDiagnoseNode, &common.DiagnoseOptions{Container: "edgecore", Host: "root@10.0.0.2"}, nil)
+		require.ErrorContains(t, err, "--container is not supported together with --host")
+	})
+
+	t.Run("unknown install check", func(t *testing.T) {
+		var da Diagnose
+		opts := NewDiagnoseOptions()
+		opts.CheckOptions.Skip = []string{"network", "gpu"}
+		err := da.ExecuteDiagnose(common.ArgDiagnoseAll, opts, nil)
+		require.ErrorContains(t, err, "unknown check gpu")
+		var exitErr *DiagnoseExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, DiagnoseExitCodeGeneric, exitErr.ExitCode())
+	})
+
+	t.Run("warnings as errors", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+		patches.ApplyFunc(DiagnoseInstall, func(_ob *common.CheckOptions, r *DiagnoseReport) error {
+			return r.Run("cert", func(w io.Writer) error {
+				return warningError(errors.New("certificate will expire in 10 days"))
+			})
+		})
+		patches.ApplyFunc(util.PrintSucceed, func(cmd, s string) {})
+		patches.ApplyFunc(util.PrintFail, func(cmd, s string) {})
+
+		var da Diagnose
+		require.NoError(t, da.ExecuteDiagnose(common.ArgDiagnoseInstall, opts, nil))
+
+		warnOpts := *opts
+		warnOpts.WarningsAsErrors = true
+		err := da.ExecuteDiagnose(common.ArgDiagnoseInstall, &warnOpts, nil)
+		require.ErrorContains(t, err, "1 checks warned, which fail the diagnose with --warnings-as-errors")
+	})
+}
+
+func newTestReport() *DiagnoseReport {
+	return NewDiagnoseReport("test", common.DiagnoseOutputText, io.Discard)
 }
 
 func TestDiagnoseNode(t *testing.T) {
@@ -257,6 +780,12 @@ func TestDiagnoseNode(t *testing.T) {
 		func(string) (bool, error) {
 			return true, nil
 		})
+	globpatches.ApplyFunc(processUsers, func(_name string) ([]string, error) {
+		return []string{"root"}, nil
+	})
+	globpatches.ApplyFunc(checkFileReadable, func(_path string) error {
+		return nil
+	})
 	globpatches.ApplyFunc(files.FileExists, func(path string) bool {
 		switch path {
 		case constants.EdgecoreConfigPath:
@@ -269,12 +798,112 @@ func TestDiagnoseNode(t *testing.T) {
 	globpatches.ApplyFunc(util.ParseEdgecoreConfig, func(_edgecorePath string) (*cfgv1alpha2.EdgeCoreConfig, error) {
 		return cfgv1alpha2.NewDefaultEdgeCoreConfig(), nil
 	})
-	globpatches.ApplyFunc(CheckHTTP, func(_url string) error {
+	globpatches.ApplyFunc(CheckWebSocket, func(_url string, _opts CloudHubCheckOptions) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckCgroupDriver, func(_w io.Writer, _endpoint, _driver string, _timeout int) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckContainerRuntime, func(_w io.Writer, _endpoint string, _timeout int) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckMQTT, func(_w io.Writer, _eb *cfgv1alpha2.EventBus, _timeout int, _connect bool) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckClockSkew, func(_w io.Writer, _server string, _opts CloudHubCheckOptions, _maxSkew time.Duration) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckTimeSync, func(_w io.Writer) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckTimeZone, func(_w io.Writer, _etcDir, _expected string) error {
+		return nil
+	})
+	globpatches.ApplyFunc(ValidateEdgecoreConfig, func(_c *cfgv1alpha2.EdgeCoreConfig) field.ErrorList {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckPathDisk, func(_w io.Writer, _paths []string, _minFreeMB int) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckWritableDirs, func(_w io.Writer, _dirs []string) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckEdgedPort, func(_w io.Writer, _address string, _port int32, _timeout int) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckConfigDrift, func(_w io.Writer, _configPath string, _c *cfgv1alpha2.EdgeCoreConfig) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckSystemd, func(_w io.Writer) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckFileLimits, func(_w io.Writer) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckConntrack, func(_w io.Writer, _sysDir string) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckEdgecoreLog, func(_w io.Writer, _logFile string, _window time.Duration) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckCloudHubDNS, func(_w io.Writer, _server, _family string) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckEvictionPressure, func(_w io.Writer, _kubelet *cfgv1alpha2.TailoredKubeletConfiguration) error {
+		return nil
+	})
+	globpatches.ApplyFunc(InitDB, func(_driverName, _dbName, _dataSource string) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckCachedNode, func(_w io.Writer, _nodeName string) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckNodeLabelsTaints, func(_w io.Writer, _nodeName string, _labels map[string]string, _taints []v1.Taint) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckNodeIdentity, func(_w io.Writer, _nodeName, _kubeConfig string) error {
+		return nil
+	})
+	globpatches.ApplyFunc(QueryPodsFromDatabase, func(_namespace string) ([]v1.Pod, error) {
+		return nil, nil
+	})
+	globpatches.ApplyFunc(CheckCNI, func(_w io.Writer, _confDir, _binDirs string) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckKubeletConflict, func(_w io.Writer) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckDNSAddon, func(_w io.Writer, _kubelet *cfgv1alpha2.TailoredKubeletConfiguration, _timeout int) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckPathMTU, func(_w io.Writer, _server string, _timeout, _minMTU int, _cniConfDir string) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckHostPaths, func(_w io.Writer) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckMaxPods, func(_w io.Writer, _kubelet *cfgv1alpha2.TailoredKubeletConfiguration) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckOrphanedPodDirs, func(_w io.Writer, _rootDir string) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckPodDNS, func(_w io.Writer, _kubelet *cfgv1alpha2.TailoredKubeletConfiguration, _domain string, _timeout int) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckVersionSkew, func(_w io.Writer, _kubeConfig string) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckEdgeStream, func(_w io.Writer, _es *cfgv1alpha2.EdgeStream, _opts HTTPCheckOptions) error {
+		return nil
+	})
+	globpatches.ApplyFunc(CheckCloudHubLatency, func(_w io.Writer, _server string, _opts CloudHubCheckOptions, _maxLatency time.Duration) error {
 		return nil
 	})
 
 	opts := &common.DiagnoseOptions{
-		Config: constants.EdgecoreConfigPath,
+		Config:       constants.EdgecoreConfigPath,
+		CheckOptions: &common.CheckOptions{Timeout: 3},
 	}
 
 	t.Run("get edgecore status fail", func(t *testing.T) {
@@ -286,7 +915,7 @@ func TestDiagnoseNode(t *testing.T) {
 				return false, errors.New("test error")
 			})
 
-		err := DiagnoseNode(opts)
+		err := DiagnoseNode(opts, newTestReport())
 		require.ErrorContains(t, err, "get edgecore status fail")
 	})
 
@@ -298,15 +927,33 @@ func TestDiagnoseNode(t *testing.T) {
 			func(string) (bool, error) {
 				return false, nil
 			})
-		err := DiagnoseNode(opts)
-		require.ErrorContains(t, err, "edgecore is not running")
+		err := DiagnoseNode(opts, newTestReport())
+		require.ErrorContains(t, err, "edgecore is not running, detection mode: host process")
+	})
+
+	t.Run("container of edgecore is not found", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		patches.ApplyFunc(NewContainerExecutor, func(name, _endpoint string, _timeout int) (Executor, error) {
+			return nil, fmt.Errorf("no running container %s is found", name)
+		})
+		inContainer := *opts
+		inContainer.Container = "edgecore"
+		r := newTestReport()
+		err := DiagnoseNode(&inContainer, r)
+		require.ErrorContains(t, err, "find the container of edgecore failed: no running container edgecore is found")
+		assert.Equal(t, "edgecore", r.Results[0].Check)
 	})
 
 	t.Run("edge config is not exists", func(t *testing.T) {
 		err := DiagnoseNode(&common.DiagnoseOptions{
 			Config: "config/edgecore.yaml",
-		})
+		}, newTestReport())
 		require.ErrorContains(t, err, "edge config is not exists")
+		var exitErr *DiagnoseExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, DiagnoseExitCodeConfigNotFound, exitErr.ExitCode())
 	})
 
 	t.Run("parse edgecore config failed", func(t *testing.T) {
@@ -316,7 +963,7 @@ func TestDiagnoseNode(t *testing.T) {
 		patches.ApplyFunc(util.ParseEdgecoreConfig, func(_edgecorePath string) (*cfgv1alpha2.EdgeCoreConfig, error) {
 			return nil, errors.New("test error")
 		})
-		err := DiagnoseNode(opts)
+		err := DiagnoseNode(opts, newTestReport())
 		require.ErrorContains(t, err, "parse edgecore config failed")
 	})
 
@@ -330,53 +977,516 @@ func TestDiagnoseNode(t *testing.T) {
 			return cfg, nil
 		})
 
-		err := DiagnoseNode(opts)
+		err := DiagnoseNode(opts, newTestReport())
 		require.ErrorContains(t, err, "dataSource is not exists")
 	})
 
-	t.Run("edgehub is not enable", func(t *testing.T) {
+	t.Run("container runtime is not running", func(t *testing.T) {
 		patches := gomonkey.NewPatches()
 		defer patches.Reset()
 
-		patches.ApplyFunc(util.ParseEdgecoreConfig, func(_edgecorePath string) (*cfgv1alpha2.EdgeCoreConfig, error) {
-			cfg := cfgv1alpha2.NewDefaultEdgeCoreConfig()
-			cfg.Modules.EdgeHub.WebSocket.Enable = false
-			return cfg, nil
+		var gotEndpoint string
+		patches.ApplyFunc(CheckContainerRuntime, func(_w io.Writer, endpoint string, _timeout int) error {
+			gotEndpoint = endpoint
+			return fmt.Errorf("container runtime endpoint %s is not exists", endpoint)
 		})
 
-		err := DiagnoseNode(opts)
-		require.ErrorContains(t, err, "edgehub is not enable")
+		r := newTestReport()
+		err := DiagnoseNode(opts, r)
+		require.ErrorContains(t, err, "container runtime endpoint")
+		assert.Equal(t, constants.DefaultRemoteRuntimeEndpoint, gotEndpoint)
+		assert.Equal(t, "runtime", r.Results[len(r.Results)-1].Check)
 	})
 
-	t.Run("cloudcore websocket connection failed", func(t *testing.T) {
+	t.Run("disk of the database is full", func(t *testing.T) {
 		patches := gomonkey.NewPatches()
 		defer patches.Reset()
 
-		patches.ApplyFunc(CheckHTTP, func(_url string) error {
-			return errors.New("test error")
+		var gotPaths []string
+		patches.ApplyFunc(CheckPathDisk, func(_w io.Writer, paths []string, _minFreeMB int) error {
+			gotPaths = paths
+			return errors.New("disk free of /var/lib/kubeedge/edgecore.db is 1.00 MB, less than 512 MB")
 		})
 
-		err := DiagnoseNode(opts)
-		require.ErrorContains(t, err, "cloudcore websocket connection failed")
+		err := DiagnoseNode(opts, newTestReport())
+		require.ErrorContains(t, err, "less than 512 MB")
+		assert.Equal(t, []string{cfgv1alpha2.DataBaseDataSource, common.KubeEdgeLogPath,
+			cfgv1alpha2.NewDefaultEdgeCoreConfig().Modules.Edged.TailoredKubeletConfig.PodLogsDir}, gotPaths)
 	})
 
-	t.Run("diagnose node successful", func(t *testing.T) {
-		err := DiagnoseNode(opts)
-		require.NoError(t, err)
-	})
-}
+	t.Run("filesystem of the database is read-only", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
 
-func TestDiagnosePod(t *testing.T) {
-	globpatches := gomonkey.NewPatches()
-	defer globpatches.Reset()
+		var gotDirs []string
+		patches.ApplyFunc(CheckWritableDirs, func(_w io.Writer, dirs []string) error {
+			gotDirs = dirs
+			return errors.New("dir /var/lib/kubeedge is on a read-only filesystem")
+		})
 
-	globpatches.ApplyFunc(InitDB, func(_driverName, _dbName, _dataSource string) error {
-		return nil
+		r := newTestReport()
+		err := DiagnoseNode(opts, r)
+		require.ErrorContains(t, err, "read-only filesystem")
+		assert.Equal(t, []string{filepath.Dir(cfgv1alpha2.DataBaseDataSource), constants.DefaultRootDir}, gotDirs)
+		assert.Equal(t, "writable-dirs", r.Results[len(r.Results)-1].Check)
 	})
 
-	ops := &common.DiagnoseOptions{
-		Namespace: "default",
-		DBPath:    "/var/lib/kubeedge/edgecore.db",
+	t.Run("edge config is invalid", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		patches.ApplyFunc(ValidateEdgecoreConfig, func(_c *cfgv1alpha2.EdgeCoreConfig) field.ErrorList {
+			return field.ErrorList{
+				field.Invalid(field.NewPath("messageQPS"), -1, "MessageQPS must not be a negative number"),
+				field.Required(field.NewPath("modules", "edgeHub", "httpServer"), "test error"),
+			}
+		})
+
+		r := newTestReport()
+		err := DiagnoseNode(opts, r)
+		require.EqualError(t, err, fmt.Sprintf("edge config %s has 2 invalid values, edgecore fails to start with it", opts.Config))
+		results := r.Results[len(r.Results)-2:]
+		assert.Equal(t, common.DiagnoseResult{
+			Check:   "config",
+			Status:  common.DiagnoseStatusFail,
+			Message: "messageQPS: Invalid value: -1: MessageQPS must not be a negative number",
+		}, results[0])
+		assert.Equal(t, "modules.edgeHub.httpServer: Required value: test error", results[1].Message)
+	})
+
+	t.Run("mqtt broker is not reachable", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		patches.ApplyFunc(CheckMQTT, func(_w io.Writer, _eb *cfgv1alpha2.EventBus, _timeout int, _connect bool) error {
+			return errors.New("check mqtt broker tcp://127.0.0.1:1883 (mode external) failed, connect fail: test error")
+		})
+
+		r := newTestReport()
+		err := DiagnoseNode(opts, r)
+		require.ErrorContains(t, err, "check mqtt broker tcp://127.0.0.1:1883 (mode external) failed")
+		var exitErr *DiagnoseExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, DiagnoseExitCodeNetwork, exitErr.ExitCode())
+		var diagnoseErr *DiagnoseError
+		require.ErrorAs(t, err, &diagnoseErr)
+		assert.Equal(t, DiagnoseErrorCodeNetworkUnreachable, diagnoseErr.Code)
+		assert.Equal(t, "mqtt", diagnoseErr.Check)
+		last := r.Results[len(r.Results)-1]
+		assert.Equal(t, DiagnoseErrorCodeNetworkUnreachable, last.Code)
+	})
+
+	t.Run("edgehub is not enable", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		patches.ApplyFunc(util.ParseEdgecoreConfig, func(_edgecorePath string) (*cfgv1alpha2.EdgeCoreConfig, error) {
+			cfg := cfgv1alpha2.NewDefaultEdgeCoreConfig()
+			cfg.Modules.EdgeHub.WebSocket.Enable = false
+			return cfg, nil
+		})
+
+		err := DiagnoseNode(opts, newTestReport())
+		require.ErrorContains(t, err, "edgehub is not enable")
+	})
+
+	t.Run("edgehub is not configured", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		patches.ApplyFunc(util.ParseEdgecoreConfig, func(_edgecorePath string) (*cfgv1alpha2.EdgeCoreConfig, error) {
+			cfg := cfgv1alpha2.NewDefaultEdgeCoreConfig()
+			cfg.Modules.EdgeHub = nil
+			return cfg, nil
+		})
+		var calledWebSocket bool
+		patches.ApplyFunc(CheckWebSocket, func(_url string, _opts CloudHubCheckOptions) error {
+			calledWebSocket = true
+			return nil
+		})
+
+		r := newTestReport()
+		require.NoError(t, DiagnoseNode(opts, r))
+		assert.False(t, calledWebSocket)
+		results := r.Results[len(r.Results)-11:]
+		for i, check := range []string{"cloudhub-dns", "cloudhub", "cloudhub-quic", "node-identity", "edgestream", "version",
+			"cloudhub-latency", "mtu", "clock"} {
+			assert.Equal(t, common.DiagnoseResult{Check: check, Status: common.DiagnoseStatusSkip, Message: "edgehub is not configured"}, results[i])
+		}
+		assert.Equal(t, "timesync", results[9].Check)
+		assert.Equal(t, "timezone", results[10].Check)
+	})
+
+	t.Run("cloudcore websocket connection failed", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		var gotURL string
+		var gotTimeout int
+		patches.ApplyFunc(CheckWebSocket, func(url string, opts CloudHubCheckOptions) error {
+			gotURL = url
+			gotTimeout = opts.Timeout
+			return errors.New(" connection timed out after 3s")
+		})
+
+		err := DiagnoseNode(opts, newTestReport())
+		require.ErrorContains(t, err, "cloudcore websocket connection failed, connection timed out after 3s")
+		assert.Equal(t, opts.CheckOptions.Timeout, gotTimeout)
+		cfg := cfgv1alpha2.NewDefaultEdgeCoreConfig()
+		assert.Equal(t, fmt.Sprintf("wss://%s/%s/%s/events", cfg.Modules.EdgeHub.WebSocket.Server,
+			cfg.Modules.EdgeHub.ProjectID, cfg.Modules.Edged.HostnameOverride), gotURL)
+		var exitErr *DiagnoseExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, DiagnoseExitCodeNetwork, exitErr.ExitCode())
+	})
+
+	t.Run("cloudcore websocket connection is retried", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+		defer func(backoff time.Duration) { retryBackoff = backoff }(retryBackoff)
+		retryBackoff = time.Millisecond
+
+		var attempts int
+		patches.ApplyFunc(CheckWebSocket, func(_url string, _opts CloudHubCheckOptions) error {
+			attempts++
+			return errors.New(" connection timed out after 3s")
+		})
+
+		retryOpts := *opts
+		checkOpts := *opts.CheckOptions
+		checkOpts.Retries = 3
+		retryOpts.CheckOptions = &checkOpts
+		err := DiagnoseNode(&retryOpts, newTestReport())
+		require.ErrorContains(t, err, "cloudcore websocket connection failed, connection timed out after 3s (after 3 attempts)")
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("edge autonomy", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		patches.ApplyFunc(CheckWebSocket, func(_url string, _opts CloudHubCheckOptions) error {
+			return errors.New(" connection timed out after 3s")
+		})
+		patches.ApplyFunc(QueryPodsFromDatabase, func(_namespace string) ([]v1.Pod, error) {
+			return []v1.Pod{*newLivePod("nginx-1", v1.PodRunning, nil), *newLivePod("nginx-2", v1.PodRunning, nil)}, nil
+		})
+		var calledVersionSkew bool
+		patches.ApplyFunc(CheckVersionSkew, func(_w io.Writer, _kubeConfig string) error {
+			calledVersionSkew = true
+			return nil
+		})
+
+		buf := &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputText, buf)
+		require.NoError(t, DiagnoseNode(opts, r))
+		assert.False(t, calledVersionSkew)
+		assert.Contains(t, buf.String(), "WARNING: cloudcore websocket connection failed, connection timed out after 3s\n")
+		assert.Contains(t, buf.String(), "WARNING: edgecore is disconnected from cloudcore but serves 2 cached pods in edge autonomy mode")
+
+		results := r.Results[len(r.Results)-10:]
+		assert.Equal(t, "cloudhub", results[0].Check)
+		assert.Equal(t, common.DiagnoseStatusWarn, results[0].Status)
+		assert.Equal(t, "autonomy", results[1].Check)
+		assert.Equal(t, common.DiagnoseStatusWarn, results[1].Status)
+		// the checks which need the connection to cloudcore are reported as skipped
+		for i, check := range []string{"node-identity", "edgestream", "version", "cloudhub-latency", "mtu", "clock"} {
+			assert.Equal(t, common.DiagnoseResult{Check: check, Status: common.DiagnoseStatusSkip, Message: "cloudcore is unreachable"}, results[2+i])
+		}
+		assert.Equal(t, "timesync", results[8].Check)
+		assert.Equal(t, "timezone", results[9].Check)
+	})
+
+	t.Run("diagnose node successful", func(t *testing.T) {
+		err := DiagnoseNode(opts, newTestReport())
+		require.NoError(t, err)
+	})
+
+	t.Run("checks of the disabled modules are skipped", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		patches.ApplyFunc(util.ParseEdgecoreConfig, func(_edgecorePath string) (*cfgv1alpha2.EdgeCoreConfig, error) {
+			cfg := cfgv1alpha2.NewDefaultEdgeCoreConfig()
+			cfg.Modules.Edged.Enable = false
+			cfg.Modules.ServiceBus.Enable = false
+			return cfg, nil
+		})
+
+		r := newTestReport()
+		require.NoError(t, DiagnoseNode(opts, r))
+		reasons := make(map[string]string)
+		for _, result := range r.Results {
+			if result.Status == common.DiagnoseStatusSkip {
+				reasons[result.Check] = result.Message
+			}
+		}
+		assert.Equal(t, "edged is disabled", reasons["node-labels"])
+		for _, check := range edgedChecks {
+			assert.Equal(t, "edged is disabled or has no kubelet config", reasons[check])
+		}
+		assert.Equal(t, "servicebus is disabled", reasons["servicebus"])
+		_, summary := r.summarize()
+		assert.Equal(t, len(reasons), summary.Skip)
+	})
+
+	t.Run("edgehub uses quic", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		patches.ApplyFunc(util.ParseEdgecoreConfig, func(_edgecorePath string) (*cfgv1alpha2.EdgeCoreConfig, error) {
+			cfg := cfgv1alpha2.NewDefaultEdgeCoreConfig()
+			cfg.Modules.EdgeHub.WebSocket.Enable = false
+			cfg.Modules.EdgeHub.Quic.Enable = true
+			return cfg, nil
+		})
+		var gotAddress string
+		patches.ApplyFunc(CheckQUIC, func(address string, _opts CloudHubCheckOptions) error {
+			gotAddress = address
+			return nil
+		})
+
+		r := newTestReport()
+		err := DiagnoseNode(opts, r)
+		require.NoError(t, err)
+		assert.Equal(t, cfgv1alpha2.NewDefaultEdgeCoreConfig().Modules.EdgeHub.Quic.Server, gotAddress)
+		assert.Equal(t, "cloudhub-quic", r.Results[len(r.Results)-9].Check)
+		assert.Equal(t, "node-identity", r.Results[len(r.Results)-8].Check)
+		assert.Equal(t, common.DiagnoseResult{Check: "edgestream", Status: common.DiagnoseStatusSkip, Message: "edgestream is disabled"}, r.Results[len(r.Results)-7])
+		assert.Equal(t, "version", r.Results[len(r.Results)-6].Check)
+		// the latency and mtu checks go through the websocket server
+		assert.Equal(t, common.DiagnoseResult{Check: "cloudhub-latency", Status: common.DiagnoseStatusSkip, Message: "websocket is disabled"}, r.Results[len(r.Results)-5])
+		assert.Equal(t, common.DiagnoseResult{Check: "mtu", Status: common.DiagnoseStatusSkip, Message: "websocket is disabled"}, r.Results[len(r.Results)-4])
+		assert.Equal(t, "timesync", r.Results[len(r.Results)-3].Check)
+		assert.Equal(t, "timezone", r.Results[len(r.Results)-2].Check)
+	})
+
+	t.Run("clock skew exceeds the max skew", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		var gotServer string
+		var gotMaxSkew time.Duration
+		patches.ApplyFunc(CheckClockSkew, func(_w io.Writer, server string, _opts CloudHubCheckOptions, maxSkew time.Duration) error {
+			gotServer, gotMaxSkew = server, maxSkew
+			return errors.New("clock skew with cloudhub server 127.0.0.1:10000 is 2m0s, exceeds the max skew 1m0s")
+		})
+
+		r := newTestReport()
+		err := DiagnoseNode(&common.DiagnoseOptions{
+			Config:       constants.EdgecoreConfigPath,
+			CheckOptions: &common.CheckOptions{Timeout: 3, MaxClockSkew: time.Minute},
+		}, r)
+		require.ErrorContains(t, err, "exceeds the max skew 1m0s")
+		assert.Equal(t, cfgv1alpha2.NewDefaultEdgeCoreConfig().Modules.EdgeHub.WebSocket.Server, gotServer)
+		assert.Equal(t, time.Minute, gotMaxSkew)
+		assert.Equal(t, "clock", r.Results[len(r.Results)-1].Check)
+	})
+
+	t.Run("websocket and quic are checked independently", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		patches.ApplyFunc(util.ParseEdgecoreConfig, func(_edgecorePath string) (*cfgv1alpha2.EdgeCoreConfig, error) {
+			cfg := cfgv1alpha2.NewDefaultEdgeCoreConfig()
+			cfg.Modules.EdgeHub.Quic.Enable = true
+			return cfg, nil
+		})
+		patches.ApplyFunc(CheckWebSocket, func(_url string, _opts CloudHubCheckOptions) error {
+			return errors.New(" connect fail: test error")
+		})
+		patches.ApplyFunc(CheckQUIC, func(_address string, _opts CloudHubCheckOptions) error {
+			return nil
+		})
+
+		r := newTestReport()
+		err := DiagnoseNode(opts, r)
+		require.EqualError(t, err, "cloudcore websocket connection failed, connect fail: test error")
+		var exitErr *DiagnoseExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, DiagnoseExitCodeNetwork, exitErr.ExitCode())
+		results := r.Results[len(r.Results)-2:]
+		assert.Equal(t, "cloudhub", results[0].Check)
+		assert.Equal(t, common.DiagnoseStatusFail, results[0].Status)
+		assert.Equal(t, "cloudhub-quic", results[1].Check)
+		assert.Equal(t, common.DiagnoseStatusPass, results[1].Status)
+	})
+
+	t.Run("insecure skip tls verify", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		var gotInsecure bool
+		patches.ApplyFunc(CheckWebSocket, func(_url string, opts CloudHubCheckOptions) error {
+			gotInsecure = opts.InsecureSkipTLSVerify
+			return nil
+		})
+
+		buf := &bytes.Buffer{}
+		err := DiagnoseNode(&common.DiagnoseOptions{
+			Config:       constants.EdgecoreConfigPath,
+			CheckOptions: &common.CheckOptions{Timeout: 3, InsecureSkipTLSVerify: true},
+		}, NewDiagnoseReport(common.ArgDiagnoseNode, common.DiagnoseOutputText, buf))
+		require.NoError(t, err)
+		assert.True(t, gotInsecure)
+		assert.Contains(t, buf.String(), "WARNING: --insecure-skip-tls-verify is set")
+	})
+}
+
+func TestDiagnoseNodePermissionDenied(t *testing.T) {
+	patches := gomonkey.NewPatches()
+	defer patches.Reset()
+
+	patches.ApplyFunc(util.GetOSInterface, func() common.OSTypeInstaller {
+		return &util.DebOS{}
+	})
+	patches.ApplyMethodFunc(reflect.TypeOf(&util.DebOS{}), "IsKubeEdgeProcessRunning",
+		func(string) (bool, error) {
+			return true, nil
+		})
+	patches.ApplyFunc(processUsers, func(_name string) ([]string, error) {
+		return []string{"root"}, nil
+	})
+	patches.ApplyFunc(files.FileExists, func(_path string) bool {
+		return true
+	})
+	patches.ApplyFunc(util.ParseEdgecoreConfig, func(_edgecorePath string) (*cfgv1alpha2.EdgeCoreConfig, error) {
+		return cfgv1alpha2.NewDefaultEdgeCoreConfig(), nil
+	})
+	patches.ApplyFunc(ValidateEdgecoreConfig, func(_c *cfgv1alpha2.EdgeCoreConfig) field.ErrorList {
+		return nil
+	})
+	var denied string
+	patches.ApplyFunc(checkFileReadable, func(path string) error {
+		if path == denied {
+			return &os.PathError{Op: "open", Path: path, Err: os.ErrPermission}
+		}
+		return nil
+	})
+
+	for _, c := range []struct {
+		name  string
+		path  string
+		check string
+	}{
+		{name: "config", path: constants.EdgecoreConfigPath, check: "config"},
+		{name: "database", path: cfgv1alpha2.DataBaseDataSource, check: "database"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			denied = c.path
+			ops := NewDiagnoseOptions()
+			r := newTestReport()
+			err := DiagnoseNode(ops, r)
+			require.ErrorContains(t, err, c.path+" is not readable by the user running the diagnose")
+			assert.Equal(t, DiagnoseExitCodePermissionDenied, toDiagnoseExitError(err).(*DiagnoseExitError).ExitCode())
+
+			last := r.Results[len(r.Results)-1]
+			assert.Equal(t, c.check, last.Check)
+			assert.Equal(t, DiagnoseErrorCodePermissionDenied, last.Code)
+		})
+	}
+}
+
+func TestNewDiagnoseError(t *testing.T) {
+	assert.NoError(t, newDiagnoseError("cpu", nil))
+
+	cases := []struct {
+		name         string
+		err          error
+		expectedCode string
+	}{
+		{
+			name:         "generic",
+			err:          errors.New("cpu check failed"),
+			expectedCode: DiagnoseErrorCodeCheckFailed,
+		},
+		{
+			name:         "network",
+			err:          networkError(errors.New("cloudcore websocket connection failed")),
+			expectedCode: DiagnoseErrorCodeNetworkUnreachable,
+		},
+		{
+			name:         "config not found",
+			err:          newDiagnoseExitError(DiagnoseExitCodeConfigNotFound, errors.New("edge config is not exists")),
+			expectedCode: DiagnoseErrorCodeConfigNotFound,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := newDiagnoseError("check", c.err)
+			var diagnoseErr *DiagnoseError
+			require.ErrorAs(t, err, &diagnoseErr)
+			assert.Equal(t, c.expectedCode, diagnoseErr.Code)
+			assert.Equal(t, "check", diagnoseErr.Check)
+			// the error string is kept as is
+			assert.Equal(t, c.err.Error(), err.Error())
+			assert.ErrorIs(t, err, c.err)
+			// the diagnose error is not wrapped twice
+			assert.Same(t, diagnoseErr, newDiagnoseError("other", err))
+		})
+	}
+
+	assert.NoError(t, networkError(nil))
+}
+
+func TestIsDiagnoseWarning(t *testing.T) {
+	warning := warningError(errors.New("clock skew is near the max skew"))
+	assert.True(t, isDiagnoseWarning(warning))
+	assert.True(t, isDiagnoseWarning(errors.Join(warning, warningError(errors.New("disk is nearly full")))))
+	assert.False(t, isDiagnoseWarning(errors.Join(warning, errors.New("cpu check failed"))))
+	assert.False(t, isDiagnoseWarning(errors.New("cpu check failed")))
+	assert.Nil(t, warningError(nil))
+}
+
+func TestInitPodDatabase(t *testing.T) {
+	var dataSource string
+	patches := gomonkey.ApplyFunc(InitDB, func(_driverName, _dbName, source string) error {
+		dataSource = source
+		return nil
+	})
+	defer patches.Reset()
+
+	dir := t.TempDir()
+	config := filepath.Join(dir, "edgecore.yaml")
+	dbPath := filepath.Join(dir, "edgecore.db")
+	require.NoError(t, os.WriteFile(config, []byte("database:\n  dataSource: "+dbPath+"\n"), 0600))
+
+	t.Run("data source in the config", func(t *testing.T) {
+		ops := &common.DiagnoseOptions{Config: config}
+		r := newTestReport()
+		require.NoError(t, initPodDatabase(ops, r))
+		assert.Equal(t, dbPath, dataSource)
+		assert.Equal(t, dbPath, ops.DBPath)
+		assert.Equal(t, fmt.Sprintf("Database %s is exist", dbPath), r.Results[0].Message)
+	})
+
+	t.Run("default data source without config", func(t *testing.T) {
+		ops := &common.DiagnoseOptions{Config: filepath.Join(dir, "not-exist.yaml")}
+		require.NoError(t, initPodDatabase(ops, newTestReport()))
+		assert.Equal(t, cfgv1alpha2.DataBaseDataSource, dataSource)
+	})
+
+	t.Run("data source set by the node diagnose", func(t *testing.T) {
+		ops := &common.DiagnoseOptions{Config: config, DBPath: "/tmp/node.db"}
+		require.NoError(t, initPodDatabase(ops, newTestReport()))
+		assert.Equal(t, "/tmp/node.db", dataSource)
+	})
+}
+
+func TestDiagnosePod(t *testing.T) {
+	globpatches := gomonkey.NewPatches()
+	defer globpatches.Reset()
+
+	globpatches.ApplyFunc(InitDB, func(_driverName, _dbName, _dataSource string) error {
+		return nil
+	})
+	globpatches.ApplyFunc(QueryPodEventsFromDatabase, func(_namespace, _podName string) ([]v1.Event, error) {
+		return nil, nil
+	})
+
+	ops := &common.DiagnoseOptions{
+		Namespace: "default",
+		DBPath:    "/var/lib/kubeedge/edgecore.db",
 	}
 
 	t.Run("failed to initialize database", func(t *testing.T) {
@@ -387,7 +1497,7 @@ func TestDiagnosePod(t *testing.T) {
 			return errors.New("test error")
 		})
 
-		err := DiagnosePod(ops, "test-pod")
+		err := DiagnosePod(ops, "test-pod", newTestReport())
 		require.ErrorContains(t, err, "failed to initialize database")
 	})
 
@@ -395,11 +1505,11 @@ func TestDiagnosePod(t *testing.T) {
 		patches := gomonkey.NewPatches()
 		defer patches.Reset()
 
-		patches.ApplyFunc(QueryPodFromDatabase, func(_namespace, _podName string) (*v1.PodStatus, error) {
+		patches.ApplyFunc(QueryPodFromDatabase, func(_namespace, _podName string, _r *DiagnoseReport) (*v1.PodStatus, error) {
 			return nil, errors.New("pod status query failed")
 		})
 
-		err := DiagnosePod(ops, "test-pod")
+		err := DiagnosePod(ops, "test-pod", newTestReport())
 		require.ErrorContains(t, err, "pod status query failed")
 	})
 
@@ -481,11 +1591,11 @@ func TestDiagnosePod(t *testing.T) {
 				patches := gomonkey.NewPatches()
 				defer patches.Reset()
 
-				patches.ApplyFunc(QueryPodFromDatabase, func(_namespace, _podName string) (*v1.PodStatus, error) {
+				patches.ApplyFunc(QueryPodFromDatabase, func(_namespace, _podName string, _r *DiagnoseReport) (*v1.PodStatus, error) {
 					return &cases[i], nil
 				})
 
-				err := DiagnosePod(ops, "test-pod")
+				err := DiagnosePod(ops, "test-pod", newTestReport())
 				require.ErrorContains(t, err, "pod test-pod is not Ready")
 			})
 		}
@@ -495,7 +1605,7 @@ func TestDiagnosePod(t *testing.T) {
 		patches := gomonkey.NewPatches()
 		defer patches.Reset()
 
-		patches.ApplyFunc(QueryPodFromDatabase, func(_namespace, _podName string) (*v1.PodStatus, error) {
+		patches.ApplyFunc(QueryPodFromDatabase, func(_namespace, _podName string, _r *DiagnoseReport) (*v1.PodStatus, error) {
 			return &v1.PodStatus{
 				Phase: "Running",
 				Conditions: []v1.PodCondition{
@@ -514,9 +1624,449 @@ func TestDiagnosePod(t *testing.T) {
 			}, nil
 		})
 
-		err := DiagnosePod(ops, "test-pod")
+		err := DiagnosePod(ops, "test-pod", newTestReport())
 		require.NoError(t, err)
 	})
+
+	t.Run("container status summary", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		patches.ApplyFunc(QueryPodFromDatabase, func(_namespace, _podName string, _r *DiagnoseReport) (*v1.PodStatus, error) {
+			waiting := func(reason string) v1.ContainerState {
+				return v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: reason}}
+			}
+			return &v1.PodStatus{
+				Phase:      "Running",
+				Conditions: []v1.PodCondition{{Type: "Ready", Status: "False"}},
+				InitContainerStatuses: []v1.ContainerStatus{
+					{Name: "init1", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Completed"}}},
+					{Name: "init2", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+				},
+				ContainerStatuses: []v1.ContainerStatus{
+					{Name: "container1", Ready: true},
+					{Name: "container2", Ready: true},
+					{Name: "container3", Ready: true},
+					{Name: "container4", State: waiting("CrashLoopBackOff"), RestartCount: 4},
+				},
+			}, nil
+		})
+
+		buf := &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnosePod, common.DiagnoseOutputText, buf)
+		err := DiagnosePod(ops, "test-pod", r)
+		require.ErrorContains(t, err, "pod test-pod is not Ready")
+		assert.Contains(t, buf.String(), "initContainerConditions init1 Completed\n")
+		assert.Contains(t, buf.String(), "initContainerConditions init2 is running, RestartCount: 0 \n")
+
+		summaries := make(map[string]common.DiagnoseResult)
+		for _, result := range r.Results {
+			if result.Check == "containers" || result.Check == "init-containers" {
+				summaries[result.Check] = result
+			}
+		}
+		assert.Equal(t, common.DiagnoseResult{
+			Check:   "init-containers",
+			Status:  common.DiagnoseStatusFail,
+			Message: "1/2 init containers completed, 1 running",
+		}, summaries["init-containers"])
+		assert.Equal(t, common.DiagnoseResult{
+			Check:   "containers",
+			Status:  common.DiagnoseStatusFail,
+			Message: "3/4 containers ready, 1 waiting (CrashLoopBackOff)",
+		}, summaries["containers"])
+	})
+
+	t.Run("container restarts since", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		now := time.Now()
+		patches.ApplyFunc(QueryPodFromDatabase, func(_namespace, _podName string, _r *DiagnoseReport) (*v1.PodStatus, error) {
+			return &v1.PodStatus{
+				Phase: "Running",
+				Conditions: []v1.PodCondition{
+					{
+						Type:   "Ready",
+						Status: "True",
+					},
+				},
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:  "stable",
+						Ready: true,
+						LastTerminationState: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-2 * time.Hour))},
+						},
+						RestartCount: 5,
+					},
+					{
+						Name:  "recent",
+						Ready: true,
+						LastTerminationState: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-time.Minute))},
+						},
+						RestartCount: 1,
+					},
+					{
+						Name:  "completed",
+						Ready: false,
+						State: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-2 * time.Hour))},
+						},
+					},
+				},
+			}, nil
+		})
+
+		r := newTestReport()
+		err := DiagnosePod(&common.DiagnoseOptions{
+			Namespace: "default",
+			DBPath:    "/var/lib/kubeedge/edgecore.db",
+			Since:     time.Hour,
+		}, "test-pod", r)
+		require.NoError(t, err)
+
+		var containers []common.DiagnoseResult
+		for _, result := range r.Results {
+			if result.Check == "container" {
+				containers = append(containers, result)
+			}
+		}
+		require.Len(t, containers, 3)
+		assert.Equal(t, common.DiagnoseStatusPass, containers[0].Status)
+		assert.Contains(t, containers[0].Message, "containerConditions stable is ready, restarts stabilized")
+		assert.Equal(t, common.DiagnoseStatusFail, containers[1].Status)
+		assert.Contains(t, containers[1].Message, "containerConditions recent is ready, but restarted at")
+		assert.Equal(t, common.DiagnoseStatusPass, containers[2].Status)
+		assert.Contains(t, containers[2].Message, "stabilized for more than 1h0m0s")
+	})
+}
+
+func TestDiagnosePodsBySelector(t *testing.T) {
+	patches := gomonkey.NewPatches()
+	defer patches.Reset()
+
+	patches.ApplyFunc(InitDB, func(_driverName, _dbName, _dataSource string) error {
+		return nil
+	})
+	patches.ApplyFunc(QueryPodsFromDatabase, func(_namespace string) ([]v1.Pod, error) {
+		return []v1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "nginx-1", Labels: map[string]string{"app": "nginx"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "nginx-2", Labels: map[string]string{"app": "nginx"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "redis-1", Labels: map[string]string{"app": "redis"}}},
+		}, nil
+	})
+	var diagnosed []string
+	patches.ApplyFunc(QueryPodFromDatabase, func(_namespace, podName string, _r *DiagnoseReport) (*v1.PodStatus, error) {
+		diagnosed = append(diagnosed, podName)
+		status := v1.ConditionTrue
+		if podName == "nginx-2" {
+			status = v1.ConditionFalse
+		}
+		return &v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}},
+		}, nil
+	})
+
+	t.Run("invalid selector", func(t *testing.T) {
+		err := DiagnosePodsBySelector(&common.DiagnoseOptions{Namespace: "default", LabelSelector: "app in"}, newTestReport())
+		require.ErrorContains(t, err, "invalid label selector")
+	})
+
+	t.Run("no pods matched", func(t *testing.T) {
+		err := DiagnosePodsBySelector(&common.DiagnoseOptions{Namespace: "default", LabelSelector: "app=mysql"}, newTestReport())
+		require.ErrorContains(t, err, "not find pods matching app=mysql")
+	})
+
+	t.Run("diagnose matched pods", func(t *testing.T) {
+		diagnosed = nil
+		buf := &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnosePod, common.DiagnoseOutputText, buf)
+
+		err := DiagnosePodsBySelector(&common.DiagnoseOptions{Namespace: "default", LabelSelector: "app=nginx"}, r)
+		require.ErrorContains(t, err, "pod nginx-2 is not Ready")
+		assert.Equal(t, []string{"nginx-1", "nginx-2"}, diagnosed)
+		assert.Contains(t, buf.String(), "NAMESPACE  POD      READY  MESSAGE")
+		assert.Contains(t, buf.String(), "default    nginx-2  False  pod nginx-2 is not Ready")
+
+		last := r.Results[len(r.Results)-1]
+		assert.Equal(t, common.DiagnoseResult{
+			Check:   "pods",
+			Status:  common.DiagnoseStatusFail,
+			Message: "1/2 pods matching app=nginx are Ready",
+		}, last)
+	})
+
+	t.Run("diagnose matched pods in all namespaces", func(t *testing.T) {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+		var queried string
+		patches.ApplyFunc(QueryPodsFromDatabase, func(namespace string) ([]v1.Pod, error) {
+			queried = namespace
+			return []v1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "nginx-1", Labels: map[string]string{"app": "nginx"}}},
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "nginx-2", Labels: map[string]string{"app": "nginx"}}},
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "nginx-3", Labels: map[string]string{"app": "nginx"}}},
+			}, nil
+		})
+		buf := &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnosePod, common.DiagnoseOutputText, buf)
+
+		ops := &common.DiagnoseOptions{Namespace: "default", LabelSelector: "app=nginx", AllNamespaces: true}
+		err := DiagnosePodsBySelector(ops, r)
+		require.EqualError(t, err, "pod test/nginx-2 is not Ready")
+		assert.Equal(t, metav1.NamespaceAll, queried)
+		assert.Contains(t, buf.String(), "Pods matching app=nginx by namespace: prod: 2, test: 1")
+		assert.Contains(t, buf.String(), "test       nginx-2  False  pod nginx-2 is not Ready")
+
+		ops.LabelSelector = "app=mysql"
+		err = DiagnosePodsBySelector(ops, newTestReport())
+		require.ErrorContains(t, err, "not find pods matching app=mysql in all namespaces")
+	})
+}
+
+func TestDiagnosePodByUID(t *testing.T) {
+	patches := gomonkey.NewPatches()
+	defer patches.Reset()
+
+	patches.ApplyFunc(InitDB, func(_driverName, _dbName, _dataSource string) error {
+		return nil
+	})
+	patches.ApplyFunc(QueryPodEventsFromDatabase, func(_namespace, _podName string) ([]v1.Event, error) {
+		return nil, nil
+	})
+	var queried string
+	patches.ApplyFunc(QueryPodsFromDatabase, func(namespace string) ([]v1.Pod, error) {
+		queried = namespace
+		var pods []v1.Pod
+		for _, pod := range []v1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx-1", UID: "uid-1"}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "nginx-2", UID: "uid-2"}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "nginx-2", UID: "uid-2"}},
+		} {
+			if namespace == metav1.NamespaceAll || pod.Namespace == namespace {
+				pods = append(pods, pod)
+			}
+		}
+		return pods, nil
+	})
+	var diagnosed []string
+	patches.ApplyFunc(QueryPodFromDatabase, func(namespace, podName string, _r *DiagnoseReport) (*v1.PodStatus, error) {
+		diagnosed = append(diagnosed, namespace+"/"+podName)
+		return &v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+		}, nil
+	})
+
+	t.Run("diagnose the pod of the uid", func(t *testing.T) {
+		diagnosed = nil
+		buf := &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnosePod, common.DiagnoseOutputText, buf)
+
+		err := DiagnosePodByUID(&common.DiagnoseOptions{Namespace: "default", UID: "uid-1"}, r)
+		require.NoError(t, err)
+		assert.Equal(t, "default", queried)
+		assert.Equal(t, []string{"default/nginx-1"}, diagnosed)
+		assert.Contains(t, buf.String(), "Pod of uid uid-1 is default/nginx-1\n")
+	})
+
+	t.Run("no pod of the uid", func(t *testing.T) {
+		diagnosed = nil
+		err := DiagnosePodByUID(&common.DiagnoseOptions{Namespace: "default", UID: "uid-2"}, newTestReport())
+		require.EqualError(t, err, "not find pod of uid uid-2 in namespace default, the pod may be deleted or recreated with another uid")
+		assert.Empty(t, diagnosed)
+	})
+
+	t.Run("multiple pods of the uid", func(t *testing.T) {
+		diagnosed = nil
+		err := DiagnosePodByUID(&common.DiagnoseOptions{Namespace: "default", UID: "uid-2", AllNamespaces: true}, newTestReport())
+		require.EqualError(t, err, "2 pods of uid uid-2 are found in all namespaces: prod/nginx-2,test/nginx-2")
+		assert.Equal(t, metav1.NamespaceAll, queried)
+		assert.Empty(t, diagnosed)
+	})
+}
+
+func TestNamespacePodCounts(t *testing.T) {
+	assert.Equal(t, "", namespacePodCounts(nil))
+	assert.Equal(t, "default: 1, prod: 2", namespacePodCounts([]podRef{
+		{namespace: "prod", name: "a"}, {namespace: "default", name: "b"}, {namespace: "prod", name: "c"},
+	}))
+	assert.Equal(t, "namespace prod", namespaceScope(&common.DiagnoseOptions{Namespace: "prod"}))
+	assert.Equal(t, "all namespaces", namespaceScope(&common.DiagnoseOptions{Namespace: "prod", AllNamespaces: true}))
+}
+
+func TestDiagnosePods(t *testing.T) {
+	patches := gomonkey.NewPatches()
+	defer patches.Reset()
+
+	patches.ApplyFunc(InitDB, func(_driverName, _dbName, _dataSource string) error {
+		return nil
+	})
+	patches.ApplyFunc(QueryPodEventsFromDatabase, func(_namespace, _podName string) ([]v1.Event, error) {
+		return nil, nil
+	})
+	var diagnosed []string
+	patches.ApplyFunc(QueryPodFromDatabase, func(_namespace, podName string, _r *DiagnoseReport) (*v1.PodStatus, error) {
+		diagnosed = append(diagnosed, podName)
+		if podName == "pod-b" {
+			return nil, fmt.Errorf("not find default/pod/%s in datebase", podName)
+		}
+		return &v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+		}, nil
+	})
+
+	t.Run("single pod", func(t *testing.T) {
+		diagnosed = nil
+		r := newTestReport()
+		require.NoError(t, DiagnosePods(&common.DiagnoseOptions{Namespace: "default"}, []string{"pod-a"}, r))
+		assert.Equal(t, []string{"pod-a"}, diagnosed)
+		assert.Equal(t, "ready", r.Results[len(r.Results)-1].Check)
+	})
+
+	t.Run("multiple pods continue past the failed ones", func(t *testing.T) {
+		diagnosed = nil
+		buf := &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnosePod, common.DiagnoseOutputText, buf)
+
+		err := DiagnosePods(&common.DiagnoseOptions{Namespace: "default"}, []string{"pod-a", "pod-b", "pod-c"}, r)
+		require.EqualError(t, err, "pod pod-b is not Ready")
+		assert.Equal(t, []string{"pod-a", "pod-b", "pod-c"}, diagnosed)
+		assert.Contains(t, buf.String(), "default    pod-b  False  not find default/pod/pod-b in datebase")
+		assert.Equal(t, common.DiagnoseResult{
+			Check:   "pods",
+			Status:  common.DiagnoseStatusFail,
+			Message: "2/3 pods are Ready",
+		}, r.Results[len(r.Results)-1])
+	})
+}
+
+func TestQueryPodFromLockedDatabase(t *testing.T) {
+	dataSource := filepath.Join(t.TempDir(), "edgecore.db")
+	db, err := sql.Open("sqlite3", dataSource)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("CREATE TABLE meta (key TEXT PRIMARY KEY, type TEXT, value TEXT)")
+	require.NoError(t, err)
+	require.NoError(t, InitDB("sqlite3", "locked-db", dataSource))
+
+	// a busy edgecore holds the lock of the database, which blocks the readers
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.ExecContext(context.Background(), "BEGIN EXCLUSIVE")
+	require.NoError(t, err)
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), "ROLLBACK")
+	}()
+
+	defaultTimeout := dbQueryTimeout
+	dbQueryTimeout = 100 * time.Millisecond
+	defer func() {
+		dbQueryTimeout = defaultTimeout
+	}()
+
+	queries := map[string]func() error{
+		"pod": func() error {
+			_, err := QueryPodFromDatabase("default", "nginx", newTestReport())
+			return err
+		},
+		"pods": func() error {
+			_, err := QueryPodsFromDatabase("default")
+			return err
+		},
+		"pods of all namespaces": func() error {
+			_, err := QueryPodsFromDatabase(metav1.NamespaceAll)
+			return err
+		},
+		"node": func() error {
+			_, err := QueryNodeFromDatabase("default", "edge-node")
+			return err
+		},
+	}
+	for name, query := range queries {
+		t.Run(name, func(t *testing.T) {
+			start := time.Now()
+			require.ErrorContains(t, query(), "database query timed out after 100ms")
+			assert.Less(t, time.Since(start), 2*time.Second)
+		})
+	}
+}
+
+func TestQueryPodFromCorruptDatabase(t *testing.T) {
+	dataSource := filepath.Join(t.TempDir(), "edgecore.db")
+	db, err := sql.Open("sqlite3", dataSource)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("CREATE TABLE meta (key TEXT PRIMARY KEY, type TEXT, value TEXT)")
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO meta (key, type, value) VALUES
+		('default/pod/nginx', 'pod', '{"metadata":{"name":"nginx"},"status":{"phase":"Running"}}'),
+		('default/podstatus/nginx', 'podstatus', '{"Status":{"phase":"Running","conditions":[{"type":'),
+		('default/pod/redis', 'pod', '{"metadata":{"name":"redis"},"status":{"phase":1}}')`)
+	require.NoError(t, err)
+	require.NoError(t, InitDB("sqlite3", "corrupt-db", dataSource))
+
+	cases := []struct {
+		podName string
+		key     string
+	}{
+		{podName: "nginx", key: "default/podstatus/nginx"},
+		{podName: "redis", key: "default/pod/redis"},
+	}
+	for _, c := range cases {
+		t.Run(c.podName, func(t *testing.T) {
+			status, err := QueryPodFromDatabase("default", c.podName, newTestReport())
+			assert.Nil(t, status)
+			require.ErrorContains(t, err, "cached pod data is corrupt for "+c.key)
+			var corruptErr *CorruptPodDataError
+			require.ErrorAs(t, err, &corruptErr)
+			assert.Equal(t, c.key, corruptErr.Key)
+			assert.Equal(t, DiagnoseErrorCodeCorruptData, diagnoseErrorCode(err))
+		})
+	}
+
+	t.Run("corrupt pod is failed with the code", func(t *testing.T) {
+		r := newTestReport()
+		require.Error(t, diagnosePodStatus("default", "redis", 0, r))
+		last := r.Results[len(r.Results)-1]
+		assert.Equal(t, common.DiagnoseStatusFail, last.Status)
+		assert.Equal(t, DiagnoseErrorCodeCorruptData, last.Code)
+	})
+}
+
+func TestQueryPodsFromDatabase(t *testing.T) {
+	patches := gomonkey.NewPatches()
+	defer patches.Reset()
+
+	var gotKey, gotCondition string
+	patches.ApplyFunc(dao.QueryAllMetaWithContext, func(_ctx context.Context, key, condition string) (*[]dao.Meta, error) {
+		gotKey, gotCondition = key, condition
+		return &[]dao.Meta{
+			{Key: "test/pod/nginx-1", Value: `{"metadata":{"name":"nginx-1","labels":{"app":"nginx"}}}`},
+			{Key: "test/pod/nginx-2", Value: `{"metadata":{"labels":{"app":"nginx"}}}`},
+		}, nil
+	})
+
+	pods, err := QueryPodsFromDatabase("test")
+	require.NoError(t, err)
+	assert.Equal(t, "key__startswith", gotKey)
+	assert.Equal(t, "test/pod/", gotCondition)
+	require.Len(t, pods, 2)
+	assert.Equal(t, "nginx-1", pods[0].Name)
+	assert.Equal(t, "nginx-2", pods[1].Name)
+	assert.Equal(t, "test", pods[1].Namespace)
+	assert.Equal(t, "nginx", pods[1].Labels["app"])
+
+	pods, err = QueryPodsFromDatabase(metav1.NamespaceAll)
+	require.NoError(t, err)
+	assert.Equal(t, "type", gotKey)
+	assert.Equal(t, "pod", gotCondition)
+	require.Len(t, pods, 2)
 }
 
 func TestDiagnoseInstall(t *testing.T) {
@@ -524,60 +2074,96 @@ func TestDiagnoseInstall(t *testing.T) {
 	defer patches.Reset()
 
 	const (
-		cpuError     = "cpu check failed"
-		memoryError  = "memory check failed"
-		diskError    = "disk check failed"
-		dnsError     = "dns specify check failed"
-		networkError = "network check failed"
-		pidError     = "pid check failed"
+		cpuError      = "cpu check failed"
+		memoryError   = "memory check failed"
+		diskError     = "disk check failed"
+		dnsError      = "dns specify check failed"
+		networkError  = "network check failed"
+		clockError    = "clock skew check failed"
+		diskPathError = "disk paths check failed"
+		pidError      = "pid check failed"
+		certError     = "cert check failed"
+		kernelError   = "kernel check failed"
 	)
 
 	funcsFake := &struct {
-		checkCPUError     bool
-		checkMemoryError  bool
-		checkDiskError    bool
-		checkDNSError     bool
-		checkNetWorkError bool
-		checkPidError     bool
+		checkCPUError      bool
+		checkMemoryError   bool
+		checkDiskError     bool
+		checkDNSError      bool
+		checkNetWorkError  bool
+		checkClockError    bool
+		checkDiskPathError bool
+		checkPidError      bool
+		checkCertError     bool
+		checkKernelError   bool
 	}{}
 
-	patches.ApplyFunc(CheckCPU, func() error {
+	patches.ApplyFunc(CheckCPU, func(_w io.Writer, _ int) error {
 		if funcsFake.checkCPUError {
 			return errors.New(cpuError)
 		}
 		return nil
 	})
-	patches.ApplyFunc(CheckMemory, func() error {
+	patches.ApplyFunc(CheckMemory, func(_w io.Writer, _ int) error {
 		if funcsFake.checkMemoryError {
 			return errors.New(memoryError)
 		}
 		return nil
 	})
-	patches.ApplyFunc(CheckDisk, func() error {
+	patches.ApplyFunc(CheckDisk, func(_w io.Writer, _ int) error {
 		if funcsFake.checkDiskError {
 			return errors.New(diskError)
 		}
 		return nil
 	})
-	patches.ApplyFunc(CheckDNSSpecify, func(_domain, _dnsIP string) error {
+	patches.ApplyFunc(CheckPathDisk, func(_w io.Writer, _paths []string, _minFreeMB int) error {
+		if funcsFake.checkDiskPathError {
+			return errors.New(diskPathError)
+		}
+		return nil
+	})
+	patches.ApplyFunc(CheckKernel, func(_w io.Writer, _modules, _sysctls []string) error {
+		if funcsFake.checkKernelError {
+			return errors.New(kernelError)
+		}
+		return nil
+	})
+	patches.ApplyFunc(CheckSwap, func(_w io.Writer, _failSwapOn bool) error {
+		return nil
+	})
+	patches.ApplyFunc(CheckDNSSpecify, func(_w io.Writer, _domain, _dnsIP, _family string) error {
 		if funcsFake.checkDNSError {
 			return errors.New(dnsError)
 		}
 		return nil
 	})
-	patches.ApplyFunc(CheckNetWork, func(_ip string, _timeout int, _cloudHub, _edgeCore, _config string) error {
+	patches.ApplyFunc(CheckNetWork, func(_w io.Writer, _ip string, _opts HTTPCheckOptions, _cloudHub, _edgeCore, _config string) error {
 		if funcsFake.checkNetWorkError {
 			return errors.New(networkError)
 		}
 		return nil
 	})
-	patches.ApplyFunc(CheckPid, func() error {
+	patches.ApplyFunc(CheckClockSkew, func(_w io.Writer, _server string, _opts CloudHubCheckOptions, _maxSkew time.Duration) error {
+		if funcsFake.checkClockError {
+			return errors.New(clockError)
+		}
+		return nil
+	})
+	patches.ApplyFunc(CheckPid, func(_w io.Writer) error {
 		if funcsFake.checkPidError {
 			return errors.New(pidError)
 		}
 		return nil
 	})
 
+	patches.ApplyFunc(CheckCertExpiry, func(_w io.Writer, _config string, _warnDays int) error {
+		if funcsFake.checkCertError {
+			return errors.New(certError)
+		}
+		return nil
+	})
+
 	opts := &common.CheckOptions{
 		IP:      "127.0.0.1",
 		Timeout: 3,
@@ -590,7 +2176,7 @@ func TestDiagnoseInstall(t *testing.T) {
 		defer func() {
 			funcsFake.checkCPUError = false
 		}()
-		err := DiagnoseInstall(opts)
+		err := DiagnoseInstall(opts, newTestReport())
 		require.ErrorContains(t, err, cpuError)
 	})
 
@@ -600,7 +2186,7 @@ func TestDiagnoseInstall(t *testing.T) {
 			funcsFake.checkMemoryError = false
 		}()
 
-		err := DiagnoseInstall(opts)
+		err := DiagnoseInstall(opts, newTestReport())
 		require.ErrorContains(t, err, memoryError)
 	})
 
@@ -610,17 +2196,27 @@ func TestDiagnoseInstall(t *testing.T) {
 			funcsFake.checkDiskError = false
 		}()
 
-		err := DiagnoseInstall(opts)
+		err := DiagnoseInstall(opts, newTestReport())
 		require.ErrorContains(t, err, diskError)
 	})
 
+	t.Run(diskPathError, func(t *testing.T) {
+		funcsFake.checkDiskPathError = true
+		defer func() {
+			funcsFake.checkDiskPathError = false
+		}()
+
+		err := DiagnoseInstall(opts, newTestReport())
+		require.ErrorContains(t, err, diskPathError)
+	})
+
 	t.Run(dnsError, func(t *testing.T) {
 		funcsFake.checkDNSError = true
 		defer func() {
 			funcsFake.checkDNSError = false
 		}()
 
-		err := DiagnoseInstall(opts)
+		err := DiagnoseInstall(opts, newTestReport())
 		require.ErrorContains(t, err, dnsError)
 	})
 
@@ -630,8 +2226,21 @@ func TestDiagnoseInstall(t *testing.T) {
 			funcsFake.checkNetWorkError = false
 		}()
 
-		err := DiagnoseInstall(opts)
+		err := DiagnoseInstall(opts, newTestReport())
 		require.ErrorContains(t, err, networkError)
+		var exitErr *DiagnoseExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, DiagnoseExitCodeNetwork, exitErr.ExitCode())
+	})
+
+	t.Run(clockError, func(t *testing.T) {
+		funcsFake.checkClockError = true
+		defer func() {
+			funcsFake.checkClockError = false
+		}()
+
+		err := DiagnoseInstall(opts, newTestReport())
+		require.ErrorContains(t, err, clockError)
 	})
 
 	t.Run(pidError, func(t *testing.T) {
@@ -640,12 +2249,97 @@ func TestDiagnoseInstall(t *testing.T) {
 			funcsFake.checkPidError = false
 		}()
 
-		err := DiagnoseInstall(opts)
+		err := DiagnoseInstall(opts, newTestReport())
 		require.ErrorContains(t, err, pidError)
 	})
 
+	t.Run(certError, func(t *testing.T) {
+		funcsFake.checkCertError = true
+		defer func() {
+			funcsFake.checkCertError = false
+		}()
+
+		err := DiagnoseInstall(opts, newTestReport())
+		require.ErrorContains(t, err, certError)
+	})
+
+	t.Run(kernelError, func(t *testing.T) {
+		funcsFake.checkKernelError = true
+		defer func() {
+			funcsFake.checkKernelError = false
+		}()
+
+		err := DiagnoseInstall(opts, newTestReport())
+		require.ErrorContains(t, err, kernelError)
+	})
+
+	t.Run("every failure is reported", func(t *testing.T) {
+		funcsFake.checkCPUError = true
+		funcsFake.checkNetWorkError = true
+		defer func() {
+			funcsFake.checkCPUError = false
+			funcsFake.checkNetWorkError = false
+		}()
+
+		r := newTestReport()
+		err := DiagnoseInstall(opts, r)
+		require.ErrorContains(t, err, cpuError)
+		require.ErrorContains(t, err, networkError)
+		var exitErr *DiagnoseExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, DiagnoseExitCodeNetwork, exitErr.ExitCode())
+
+		var checks []string
+		for _, result := range r.Results {
+			checks = append(checks, result.Check)
+		}
+		assert.Equal(t, []string{common.ArgCheckCPU, common.ArgCheckMemory, common.ArgCheckDisk, "disk-paths", "kernel", "swap",
+			common.ArgCheckDNS, common.ArgCheckNetwork, "timesync", "timezone", "clock", common.ArgCheckPID, common.ArgCheckCert}, checks)
+	})
+
+	t.Run("skipped checks are not run", func(t *testing.T) {
+		funcsFake.checkNetWorkError = true
+		defer func() {
+			funcsFake.checkNetWorkError = false
+		}()
+
+		skipOpts := *opts
+		skipOpts.Skip = []string{common.ArgCheckNetwork}
+		r := newTestReport()
+		require.NoError(t, DiagnoseInstall(&skipOpts, r))
+		// the skipped check is reported as skipped instead of run
+		for _, result := range r.Results {
+			if result.Check == common.ArgCheckNetwork {
+				assert.Equal(t, common.DiagnoseStatusSkip, result.Status)
+				assert.Equal(t, "deselected by --skip", result.Message)
+			}
+		}
+	})
+
+	t.Run("unknown check", func(t *testing.T) {
+		onlyOpts := *opts
+		onlyOpts.Only = []string{"gpu"}
+		err := DiagnoseInstall(&onlyOpts, newTestReport())
+		require.ErrorContains(t, err, "unknown check gpu")
+	})
+
 	t.Run("diagnose install successful", func(t *testing.T) {
-		err := DiagnoseInstall(opts)
+		err := DiagnoseInstall(opts, newTestReport())
 		require.NoError(t, err)
 	})
 }
+
+func TestValidateEdgecoreConfig(t *testing.T) {
+	cfg := cfgv1alpha2.NewDefaultEdgeCoreConfig()
+	cfg.DataBase.DataSource = filepath.Join(t.TempDir(), "edgecore.db")
+	assert.Empty(t, ValidateEdgecoreConfig(cfg))
+
+	cfg.Modules.EdgeHub.MessageQPS = -1
+	cfg.Modules.EdgeHub.WebSocket.Server = ""
+	cfg.Modules.EdgeHub.Heartbeat = 0
+	var fields []string
+	for _, e := range ValidateEdgecoreConfig(cfg) {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{"messageQPS", "modules.edgeHub.websocket.server", "modules.edgeHub.heartbeat"}, fields)
+}
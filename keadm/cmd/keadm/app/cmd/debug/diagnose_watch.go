@@ -0,0 +1,93 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+const (
+	// defaultWatchInterval is the default interval of diagnose node --watch
+	defaultWatchInterval = 5 * time.Second
+	// clearScreen moves the cursor to the top left and clears the terminal
+	clearScreen = "\033[H\033[2J"
+)
+
+// watchDiagnoseNode re-runs DiagnoseNode every interval until ctx is done.
+// A failed diagnose does not stop the watch, the error of the last diagnose is returned when ctx is done.
+func watchDiagnoseNode(ctx context.Context, ops *common.DiagnoseOptions, out io.Writer) error {
+	interval := ops.WatchInterval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	nodeName := diagnoseNodeName(ops)
+	for {
+		r := NewDiagnoseReport(common.ArgDiagnoseNode, ops.Output, out)
+		r.SetVerbosity(diagnoseVerbosity(ops))
+		r.SetExplain(ops.Explain)
+		r.SetScoreWeights(ops.ScoreWeights)
+		if err := r.SetTemplate(ops.Template); err != nil {
+			return err
+		}
+		r.Printf("%sEvery %v: keadm debug diagnose node\n", clearScreen, interval)
+		r.SetNodeName(nodeName)
+		r.Printf("\n")
+		err := DiagnoseNode(ops, r)
+		if ops.MetricsFile != "" {
+			if merr := writeMetricsFile(ops.MetricsFile, r, time.Now()); merr != nil {
+				fmt.Fprintln(os.Stderr, merr.Error())
+			}
+		}
+		if r.IsText() {
+			r.PrintHints()
+			if err != nil {
+				r.Printf("%v\n", err)
+			}
+			r.Summaryf("\n%s\n", watchStatusLine(time.Now(), r))
+		} else if perr := r.Print(); perr != nil {
+			return perr
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchStatusLine returns a one line status of the report, e.g.
+// "2025-01-02T15:04:05Z edgecore=pass config=pass cloudhub=fail",
+// a check recorded more than once is failed if any of its results is failed.
+func watchStatusLine(now time.Time, r *DiagnoseReport) string {
+	checks, statuses := r.checkStatuses()
+	line := []string{now.Format(time.RFC3339)}
+	for _, check := range checks {
+		line = append(line, fmt.Sprintf("%s=%s", check, statuses[check]))
+	}
+	return strings.Join(line, " ")
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func TestWatchDiagnoseNode(t *testing.T) {
+	patches := gomonkey.NewPatches()
+	defer patches.Reset()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var runs int
+	patches.ApplyFunc(DiagnoseNode, func(_ops *common.DiagnoseOptions, r *DiagnoseReport) error {
+		runs++
+		r.Pass("edgecore", "edgecore is running\n")
+		if runs == 1 {
+			return r.Fail("cloudhub", errors.New("cloudcore websocket connection failed"))
+		}
+		r.Pass("cloudhub", "cloudcore websocket connection success\n")
+		// the user cancels the watch once cloudhub is back
+		cancel()
+		return nil
+	})
+
+	buf := &bytes.Buffer{}
+	err := watchDiagnoseNode(ctx, &common.DiagnoseOptions{
+		Output:        common.DiagnoseOutputText,
+		WatchInterval: 10 * time.Millisecond,
+	}, buf)
+	require.NoError(t, err)
+	assert.Equal(t, 2, runs)
+
+	out := buf.String()
+	assert.Equal(t, 2, strings.Count(out, clearScreen+"Every 10ms: keadm debug diagnose node\n"))
+	assert.Contains(t, out, "cloudcore websocket connection failed\n")
+	assert.Contains(t, out, " edgecore=pass cloudhub=fail\n")
+	assert.Contains(t, out, " edgecore=pass cloudhub=pass\n")
+}
+
+func TestWatchStatusLine(t *testing.T) {
+	r := newTestReport()
+	r.Pass("edgecore", "edgecore is running\n")
+	r.Fail("cloudhub", errors.New("cloudcore websocket connection failed"))
+	r.Pass("cloudhub-quic", "cloudcore quic connection success\n")
+	r.Failf("container", "containerConditions a is not ready\n")
+	r.Pass("container", "containerConditions b is ready\n")
+
+	now := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	assert.Equal(t, fmt.Sprintf("%s edgecore=pass cloudhub=fail cloudhub-quic=pass container=fail", now.Format(time.RFC3339)),
+		watchStatusLine(now, r))
+}
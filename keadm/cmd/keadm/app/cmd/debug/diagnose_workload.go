@@ -0,0 +1,132 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+const (
+	workloadKindDeployment  = "deployment"
+	workloadKindStatefulSet = "statefulset"
+	workloadKindDaemonSet   = "daemonset"
+	workloadKindJob         = "job"
+)
+
+// workloadKinds are the kinds of the workloads diagnose workload supports
+var workloadKinds = []string{workloadKindDeployment, workloadKindStatefulSet, workloadKindDaemonSet, workloadKindJob}
+
+// workloadOwnerKinds are the kinds of the owner references which the controllers of the workloads set on their pods,
+// the pods of a deployment are owned by its replicasets, which are matched by DiagnoseDeployment instead
+var workloadOwnerKinds = map[string]string{
+	workloadKindStatefulSet: "StatefulSet",
+	workloadKindDaemonSet:   "DaemonSet",
+	workloadKindJob:         "Job",
+}
+
+// DiagnoseWorkload diagnoses the pods of the workload of kind in the database, which are grouped by the owner references
+// cached on the pods, and summarizes the Ready pods on the node at the end. A daemonset is expected to have one pod on
+// the node, and the Succeeded pods of a job are completed rather than not Ready. A deployment is diagnosed by
+// DiagnoseDeployment, since its pods are owned by the replicasets which are not cached on the node.
+func DiagnoseWorkload(ops *common.DiagnoseOptions, kind, name string, r *DiagnoseReport) error {
+	kind = strings.ToLower(kind)
+	if kind == workloadKindDeployment {
+		return DiagnoseDeployment(ops, name, r)
+	}
+	ownerKind, ok := workloadOwnerKinds[kind]
+	if !ok {
+		return r.Fail("workload", fmt.Errorf("unsupported workload kind %s, the kinds are: %s", kind, strings.Join(workloadKinds, ", ")))
+	}
+	if err := initPodDatabase(ops, r); err != nil {
+		return err
+	}
+
+	pods, err := QueryPodsFromDatabase(ops.Namespace)
+	if err != nil {
+		return r.Fail("pod", err)
+	}
+	owned := podsOwnedBy(pods, ownerKind, name)
+	if len(owned) == 0 {
+		if kind == workloadKindDaemonSet {
+			return r.Fail("workload", fmt.Errorf("daemonset %s has no pod on node %s in namespace %s, "+
+				"check the node selector, the affinity and the tolerations of the daemonset against the node", name, r.NodeName, ops.Namespace))
+		}
+		return r.Fail("workload", fmt.Errorf("not find the pods of %s %s in namespace %s", kind, name, ops.Namespace))
+	}
+	r.Pass("workload", "%s %s has %d pods on the node: %s\n", kind, name, len(owned), strings.Join(owned, ", "))
+
+	// the Succeeded pods of a job are done, only the others are diagnosed
+	var completed int
+	if kind == workloadKindJob {
+		var running []string
+		for _, podName := range owned {
+			phase, _, err := cachedPodPhase(ops.Namespace, podName)
+			if err != nil {
+				return r.Fail("pod", err)
+			}
+			if phase == v1.PodSucceeded {
+				completed++
+				r.Pass("pod", "Pod %s of job %s is Succeeded\n", podName, name)
+				continue
+			}
+			running = append(running, podName)
+		}
+		owned = running
+	}
+
+	notReady, err := diagnosePodList(ops.Namespace, owned, ops.Since, r)
+	if err != nil {
+		return err
+	}
+	ready := len(owned) - len(notReady) + completed
+	total := len(owned) + completed
+
+	summary := fmt.Sprintf("%s %s: %d/%d pods on the node are Ready\n", kind, name, ready, total)
+	if kind == workloadKindJob {
+		summary = fmt.Sprintf("job %s: %d/%d pods on the node are Ready or Succeeded\n", name, ready, total)
+	}
+	if kind == workloadKindDaemonSet && total > 1 {
+		r.Warnf("replicas", "daemonset %s has %d pods on node %s, where the daemonset controller keeps one\n", name, total, r.NodeName)
+	}
+	if len(notReady) > 0 {
+		r.Failf("replicas", "%s", summary)
+		return newDiagnoseError("replicas", fmt.Errorf("pod %s is not Ready", strings.Join(notReady, ",")))
+	}
+	r.Pass("replicas", "%s", summary)
+	return nil
+}
+
+// podsOwnedBy returns the sorted names of the pods with an owner reference of ownerKind named name
+func podsOwnedBy(pods []v1.Pod, ownerKind, name string) []string {
+	var names []string
+	for _, pod := range pods {
+		for _, ref := range pod.OwnerReferences {
+			if ref.Kind == ownerKind && ref.Name == name {
+				names = append(names, pod.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+func newOwnedPod(name, ownerKind, ownerName string) v1.Pod {
+	return v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:            name,
+		OwnerReferences: []metav1.OwnerReference{{Kind: ownerKind, Name: ownerName}},
+	}}
+}
+
+func TestDiagnoseWorkload(t *testing.T) {
+	patches := gomonkey.NewPatches()
+	defer patches.Reset()
+
+	patches.ApplyFunc(InitDB, func(_driverName, _dbName, _dataSource string) error {
+		return nil
+	})
+	pods := []v1.Pod{
+		newOwnedPod("web-1", "StatefulSet", "web"),
+		newOwnedPod("web-0", "StatefulSet", "web"),
+		newOwnedPod("kube-proxy-abcde", "DaemonSet", "kube-proxy"),
+		newOwnedPod("migrate-abcde", "Job", "migrate"),
+		newOwnedPod("migrate-fghij", "Job", "migrate"),
+		newOwnedPod("web-2", "StatefulSet", "other"),
+	}
+	patches.ApplyFunc(QueryPodsFromDatabase, func(_namespace string) ([]v1.Pod, error) {
+		return pods, nil
+	})
+	var diagnosed []string
+	patches.ApplyFunc(QueryPodFromDatabase, func(_namespace, podName string, _r *DiagnoseReport) (*v1.PodStatus, error) {
+		diagnosed = append(diagnosed, podName)
+		status := v1.ConditionTrue
+		if podName == "web-1" {
+			status = v1.ConditionFalse
+		}
+		return &v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}},
+		}, nil
+	})
+	patches.ApplyFunc(cachedPodPhase, func(_namespace, podName string) (v1.PodPhase, bool, error) {
+		if podName == "migrate-abcde" {
+			return v1.PodSucceeded, true, nil
+		}
+		return v1.PodRunning, true, nil
+	})
+	opts := &common.DiagnoseOptions{Namespace: "default"}
+
+	t.Run("statefulset", func(t *testing.T) {
+		diagnosed = nil
+		r := newTestReport()
+		err := DiagnoseWorkload(opts, "StatefulSet", "web", r)
+		require.EqualError(t, err, "pod web-1 is not Ready")
+		assert.Equal(t, []string{"web-0", "web-1"}, diagnosed)
+		assert.Equal(t, common.DiagnoseResult{
+			Check:   "replicas",
+			Status:  common.DiagnoseStatusFail,
+			Message: "statefulset web: 1/2 pods on the node are Ready",
+		}, r.Results[len(r.Results)-1])
+	})
+
+	t.Run("daemonset has its pod", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnoseWorkload, common.DiagnoseOutputText, buf)
+		require.NoError(t, DiagnoseWorkload(opts, "daemonset", "kube-proxy", r))
+		assert.Contains(t, buf.String(), "daemonset kube-proxy has 1 pods on the node: kube-proxy-abcde\n")
+		assert.Contains(t, buf.String(), "daemonset kube-proxy: 1/1 pods on the node are Ready\n")
+	})
+
+	t.Run("daemonset has no pod", func(t *testing.T) {
+		r := newTestReport()
+		r.NodeName = "edge-node"
+		err := DiagnoseWorkload(opts, "daemonset", "fluentd", r)
+		require.ErrorContains(t, err, "daemonset fluentd has no pod on node edge-node in namespace default")
+	})
+
+	t.Run("job", func(t *testing.T) {
+		diagnosed = nil
+		buf := &bytes.Buffer{}
+		r := NewDiagnoseReport(common.ArgDiagnoseWorkload, common.DiagnoseOutputText, buf)
+		require.NoError(t, DiagnoseWorkload(opts, "job", "migrate", r))
+		assert.Equal(t, []string{"migrate-fghij"}, diagnosed)
+		assert.Contains(t, buf.String(), "Pod migrate-abcde of job migrate is Succeeded\n")
+		assert.Contains(t, buf.String(), "job migrate: 2/2 pods on the node are Ready or Succeeded\n")
+	})
+
+	t.Run("no pods", func(t *testing.T) {
+		err := DiagnoseWorkload(opts, "job", "backup", newTestReport())
+		require.EqualError(t, err, "not find the pods of job backup in namespace default")
+	})
+
+	t.Run("deployment", func(t *testing.T) {
+		p := gomonkey.ApplyFunc(QueryDeploymentFromDatabase, func(_namespace, _name string) (*appsv1.Deployment, error) {
+			return nil, nil
+		})
+		defer p.Reset()
+		err := DiagnoseWorkload(opts, "deployment", "mysql", newTestReport())
+		require.EqualError(t, err, "not find deployment mysql or its pods in namespace default")
+	})
+
+	t.Run("unsupported kind", func(t *testing.T) {
+		err := DiagnoseWorkload(opts, "cronjob", "backup", newTestReport())
+		require.EqualError(t, err, "unsupported workload kind cronjob, the kinds are: deployment, statefulset, daemonset, job")
+	})
+}
+
+func TestRunDiagnoseWorkloadWithoutArgs(t *testing.T) {
+	err := runDiagnoseWorkload(&common.DiagnoseOptions{}, []string{"daemonset"}, newTestReport())
+	require.ErrorContains(t, err, "you must specify a workload kind and name")
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/process"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/util/files"
+)
+
+// Executor runs the process and file checks of the diagnose on the node,
+// which is the local node, a remote node reached over ssh, or the container edgecore runs in on the local node.
+type Executor interface {
+	// IsProcessRunning returns whether the process proc is running on the node
+	IsProcessRunning(proc string) (bool, error)
+	// FileExists returns whether the file exists on the node
+	FileExists(path string) bool
+	// FileReadable returns nil if the file on the node is readable by the user running the diagnose,
+	// the error wraps os.ErrPermission if the user has no permission to read it
+	FileReadable(path string) error
+	// ProcessUsers returns the users which the processes proc on the node are running as
+	ProcessUsers(proc string) ([]string, error)
+	// ParseEdgecoreConfig parses the edgecore config file on the node
+	ParseEdgecoreConfig(path string) (*v1alpha2.EdgeCoreConfig, error)
+	// NodeName returns the node name in the edgecore config, or the hostname of the node if it is not set
+	NodeName(config string) string
+	// DetectionMode describes where edgecore is detected, e.g. a host process or a container
+	DetectionMode() string
+}
+
+// NewExecutor returns the executor of the remote node host over ssh, or the local executor if host is empty
+func NewExecutor(host string) Executor {
+	if host == "" {
+		return localExecutor{}
+	}
+	return sshExecutor{host: host}
+}
+
+// localExecutor runs the checks on the local node
+type localExecutor struct{}
+
+func (localExecutor) IsProcessRunning(proc string) (bool, error) {
+	return util.GetOSInterface().IsKubeEdgeProcessRunning(proc)
+}
+
+func (localExecutor) FileExists(path string) bool {
+	return files.FileExists(path)
+}
+
+func (localExecutor) FileReadable(path string) error {
+	return checkFileReadable(path)
+}
+
+func (localExecutor) ProcessUsers(proc string) ([]string, error) {
+	return processUsers(proc)
+}
+
+func (localExecutor) ParseEdgecoreConfig(path string) (*v1alpha2.EdgeCoreConfig, error) {
+	return util.ParseEdgecoreConfig(path)
+}
+
+func (localExecutor) NodeName(config string) string {
+	return DiagnoseNodeName(config)
+}
+
+func (localExecutor) DetectionMode() string {
+	return "host process"
+}
+
+// sshExecutor runs the checks on the remote node host with the ssh client,
+// so the ssh config, keys and agent of the user are used for the authentication.
+type sshExecutor struct {
+	// host is the ssh destination, e.g. user@edge1
+	host string
+}
+
+// run runs the shell command on the remote node, the exit code of the command is set in the returned command
+func (e sshExecutor) run(command string) (*util.Command, error) {
+	cmd := &util.Command{
+		// BatchMode fails the connection instead of prompting for a password
+		Cmd: exec.Command("ssh", "-o", "BatchMode=yes", e.host, command),
+	}
+	return cmd, cmd.Exec()
+}
+
+func (e sshExecutor) IsProcessRunning(proc string) (bool, error) {
+	cmd, err := e.run("pidof " + shellQuote(proc))
+	if err == nil {
+		return true, nil
+	}
+	// pidof exits with 1 if the process is not running, ssh exits with 255 if the connection fails
+	if cmd.ExitCode == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("get process %s of remote node %s failed, %v", proc, e.host, err)
+}
+
+func (e sshExecutor) FileExists(path string) bool {
+	_, err := e.run("test -e " + shellQuote(path))
+	return err == nil
+}
+
+func (e sshExecutor) FileReadable(path string) error {
+	cmd, err := e.run("test -r " + shellQuote(path))
+	if err == nil {
+		return nil
+	}
+	// test exits with 1 if the file is not readable, which is either missing or not permitted
+	if cmd.ExitCode == 1 && e.FileExists(path) {
+		return fmt.Errorf("%s of remote node %s is not readable: %w", path, e.host, os.ErrPermission)
+	}
+	return fmt.Errorf("read %s of remote node %s failed, %v", path, e.host, err)
+}
+
+func (e sshExecutor) ProcessUsers(proc string) ([]string, error) {
+	cmd, err := e.run("ps -o user= -C " + shellQuote(proc))
+	if err != nil {
+		return nil, fmt.Errorf("get the users of process %s of remote node %s failed, %v", proc, e.host, err)
+	}
+	return strings.Fields(cmd.GetStdOut()), nil
+}
+
+func (e sshExecutor) ParseEdgecoreConfig(path string) (*v1alpha2.EdgeCoreConfig, error) {
+	cmd, err := e.run("cat " + shellQuote(path))
+	if err != nil {
+		return nil, fmt.Errorf("read configfile %s of remote node %s failed, %v", path, e.host, err)
+	}
+	edgeCoreConfig := v1alpha2.NewDefaultEdgeCoreConfig()
+	if err := yaml.Unmarshal(cmd.StdOut, edgeCoreConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal configfile %s, err: %v", path, err)
+	}
+	return edgeCoreConfig, nil
+}
+
+func (e sshExecutor) NodeName(config string) string {
+	if edgeconfig, err := e.ParseEdgecoreConfig(config); err == nil &&
+		edgeconfig.Modules.Edged != nil && edgeconfig.Modules.Edged.HostnameOverride != "" {
+		return edgeconfig.Modules.Edged.HostnameOverride
+	}
+	if cmd, err := e.run("hostname"); err == nil && cmd.GetStdOut() != "" {
+		return cmd.GetStdOut()
+	}
+	return e.host
+}
+
+func (e sshExecutor) DetectionMode() string {
+	return "host process of remote node " + e.host
+}
+
+// checkFileReadable opens the file to check whether the user running the diagnose can read it
+func checkFileReadable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// processUsers returns the users which the local processes of the name are running as
+func processUsers(name string) ([]string, error) {
+	pids, err := processPids(name)
+	if err != nil {
+		return nil, err
+	}
+	return pidUsers(name, pids)
+}
+
+// pidUsers returns the users which the local processes of the name and the pids are running as
+func pidUsers(name string, pids []int32) ([]string, error) {
+	users := make([]string, 0, len(pids))
+	for _, pid := range pids {
+		proc, err := process.NewProcess(pid)
+		if err != nil {
+			// the process exits after it is listed
+			continue
+		}
+		user, err := proc.Username()
+		if err != nil {
+			return nil, fmt.Errorf("get the user of process %s %d failed: %v", name, pid, err)
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// shellQuote quotes s as a single argument of the remote shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
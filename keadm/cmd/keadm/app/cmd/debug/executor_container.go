@@ -0,0 +1,215 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/util/files"
+)
+
+const (
+	// criPodNamespaceLabel and criPodNameLabel are the labels of the containers created for the pods
+	criPodNamespaceLabel = "io.kubernetes.pod.namespace"
+	criPodNameLabel      = "io.kubernetes.pod.name"
+	// shortContainerIDLen is the length of the container ids printed, the same as crictl ps
+	shortContainerIDLen = 13
+)
+
+// containerExecutor runs the process and file checks in the container which edgecore runs in instead of the host.
+// The files are read through the root of the main process of the container in /proc, and the processes
+// sharing the mount namespace of the main process are the processes of the container.
+type containerExecutor struct {
+	name string
+	id   string
+	// pod is the namespace/name of the pod of the container, it is empty if the container is not created for a pod
+	pod string
+	// pid is the pid of the main process of the container on the host
+	pid int
+}
+
+// NewContainerExecutor finds the running container of the name with the container runtime of endpoint,
+// and returns the executor of the checks in the container. It fails unless exactly one container is found.
+func NewContainerExecutor(name, endpoint string, timeout int) (Executor, error) {
+	rs, endpoint, err := connectContainerRuntime(endpoint, timeout)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	containers, err := rs.ListContainers(ctx, &runtimeapi.ContainerFilter{
+		State: &runtimeapi.ContainerStateValue{State: runtimeapi.ContainerState_CONTAINER_RUNNING},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list containers of container runtime endpoint %s failed, %v", endpoint, err)
+	}
+	var found []*runtimeapi.Container
+	for _, c := range containers {
+		if c.Metadata != nil && c.Metadata.Name == name {
+			found = append(found, c)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no running container %s is found in container runtime endpoint %s", name, endpoint)
+	case 1:
+	default:
+		ids := make([]string, 0, len(found))
+		for _, c := range found {
+			ids = append(ids, shortContainerID(c.Id))
+		}
+		return nil, fmt.Errorf("%d running containers %s are found in container runtime endpoint %s: %s",
+			len(found), name, endpoint, strings.Join(ids, ", "))
+	}
+
+	c := found[0]
+	status, err := rs.ContainerStatus(ctx, c.Id, true)
+	if err != nil {
+		return nil, fmt.Errorf("get status of container %s %s failed, %v", name, shortContainerID(c.Id), err)
+	}
+	pid, err := containerPid(status.GetInfo())
+	if err != nil {
+		return nil, fmt.Errorf("get pid of container %s %s failed, %v", name, shortContainerID(c.Id), err)
+	}
+	e := containerExecutor{name: name, id: c.Id, pid: pid}
+	if podName := c.Labels[criPodNameLabel]; podName != "" {
+		e.pod = c.Labels[criPodNamespaceLabel] + "/" + podName
+	}
+	return e, nil
+}
+
+// containerPid returns the pid of the main process of the container in the verbose info of its status,
+// which containerd and cri-o report as the pid of the json of the info key
+func containerPid(info map[string]string) (int, error) {
+	data, ok := info["info"]
+	if !ok {
+		return 0, fmt.Errorf("the container runtime reports no verbose info")
+	}
+	var verbose struct {
+		Pid int `json:"pid"`
+	}
+	if err := json.Unmarshal([]byte(data), &verbose); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal verbose info: %v", err)
+	}
+	if verbose.Pid <= 0 {
+		return 0, fmt.Errorf("the container runtime reports no pid in the verbose info")
+	}
+	return verbose.Pid, nil
+}
+
+// shortContainerID truncates the container id the same as crictl ps
+func shortContainerID(id string) string {
+	if len(id) > shortContainerIDLen {
+		return id[:shortContainerIDLen]
+	}
+	return id
+}
+
+// path returns the host path of the path in the container, stdin is read by keadm itself
+func (e containerExecutor) path(path string) string {
+	if path == util.EdgecoreConfigStdin {
+		return path
+	}
+	return filepath.Join(procDir, strconv.Itoa(e.pid), "root", path)
+}
+
+// processPids returns the pids of the processes proc in the container
+func (e containerExecutor) processPids(proc string) ([]int32, error) {
+	mntNS, err := os.Readlink(filepath.Join(procDir, strconv.Itoa(e.pid), "ns", "mnt"))
+	if err != nil {
+		return nil, fmt.Errorf("read mount namespace of container %s failed: %v", e.name, err)
+	}
+	pids, err := processPids(proc)
+	if err != nil {
+		return nil, err
+	}
+	var inContainer []int32
+	for _, pid := range pids {
+		// the processes exiting while listing have no namespace, skip them
+		if ns, err := os.Readlink(filepath.Join(procDir, strconv.Itoa(int(pid)), "ns", "mnt")); err == nil && ns == mntNS {
+			inContainer = append(inContainer, pid)
+		}
+	}
+	return inContainer, nil
+}
+
+func (e containerExecutor) IsProcessRunning(proc string) (bool, error) {
+	pids, err := e.processPids(proc)
+	return len(pids) > 0, err
+}
+
+func (e containerExecutor) FileExists(path string) bool {
+	return files.FileExists(e.path(path))
+}
+
+func (e containerExecutor) FileReadable(path string) error {
+	return checkFileReadable(e.path(path))
+}
+
+func (e containerExecutor) ProcessUsers(proc string) ([]string, error) {
+	pids, err := e.processPids(proc)
+	if err != nil {
+		return nil, err
+	}
+	return pidUsers(proc, pids)
+}
+
+func (e containerExecutor) ParseEdgecoreConfig(path string) (*v1alpha2.EdgeCoreConfig, error) {
+	return util.ParseEdgecoreConfig(e.path(path))
+}
+
+func (e containerExecutor) NodeName(config string) string {
+	return DiagnoseNodeName(e.path(config))
+}
+
+func (e containerExecutor) DetectionMode() string {
+	mode := fmt.Sprintf("container %s %s", e.name, shortContainerID(e.id))
+	if e.pod != "" {
+		mode += " of pod " + e.pod
+	}
+	return mode
+}
+
+// hostEdgecoreConfig points the paths of the database and edged in the edgecore config to their host paths,
+// so the checks on the host read the files edgecore reads in the container
+func (e containerExecutor) hostEdgecoreConfig(edgeconfig *v1alpha2.EdgeCoreConfig) {
+	if edgeconfig.DataBase == nil {
+		edgeconfig.DataBase = &v1alpha2.DataBase{}
+	}
+	dataSource := v1alpha2.DataBaseDataSource
+	if edgeconfig.DataBase.DataSource != "" {
+		dataSource = edgeconfig.DataBase.DataSource
+	}
+	edgeconfig.DataBase.DataSource = e.path(dataSource)
+	if edged := edgeconfig.Modules.Edged; edged != nil {
+		edged.RootDirectory = e.path(edgedRootDir(edged))
+		if kubelet := edged.TailoredKubeletConfig; kubelet != nil && kubelet.PodLogsDir != "" {
+			kubelet.PodLogsDir = e.path(kubelet.PodLogsDir)
+		}
+	}
+}
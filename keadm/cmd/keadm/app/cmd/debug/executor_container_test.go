@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/shirou/gopsutil/v3/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// fakeContainerServer lists the containers and reports the verbose info of their status
+type fakeContainerServer struct {
+	fakeRuntimeServer
+	containers []*runtimeapi.Container
+	info       map[string]string
+}
+
+func (s *fakeContainerServer) ListContainers(_ context.Context, _ *runtimeapi.ListContainersRequest) (*runtimeapi.ListContainersResponse, error) {
+	return &runtimeapi.ListContainersResponse{Containers: s.containers}, nil
+}
+
+func (s *fakeContainerServer) ContainerStatus(_ context.Context, _ *runtimeapi.ContainerStatusRequest) (*runtimeapi.ContainerStatusResponse, error) {
+	return &runtimeapi.ContainerStatusResponse{Info: s.info}, nil
+}
+
+func newFakeContainer(id, name string) *runtimeapi.Container {
+	return &runtimeapi.Container{
+		Id:       id,
+		Metadata: &runtimeapi.ContainerMetadata{Name: name},
+		State:    runtimeapi.ContainerState_CONTAINER_RUNNING,
+		Labels:   map[string]string{criPodNamespaceLabel: "kubeedge", criPodNameLabel: "edgecore-x7k2p"},
+	}
+}
+
+func TestNewContainerExecutor(t *testing.T) {
+	pid := os.Getpid()
+	info := map[string]string{"info": `{"pid":` + strconv.Itoa(pid) + `}`}
+
+	t.Run("found", func(t *testing.T) {
+		endpoint := serveFakeRuntime(t, &fakeContainerServer{
+			containers: []*runtimeapi.Container{newFakeContainer("0123456789abcdef", "edgecore"), newFakeContainer("fedcba", "mosquitto")},
+			info:       info,
+		})
+		ex, err := NewContainerExecutor("edgecore", endpoint, 2)
+		require.NoError(t, err)
+		assert.Equal(t, containerExecutor{name: "edgecore", id: "0123456789abcdef", pod: "kubeedge/edgecore-x7k2p", pid: pid}, ex)
+		assert.Equal(t, "container edgecore 0123456789abc of pod kubeedge/edgecore-x7k2p", ex.DetectionMode())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		endpoint := serveFakeRuntime(t, &fakeContainerServer{containers: []*runtimeapi.Container{newFakeContainer("fedcba", "mosquitto")}})
+		_, err := NewContainerExecutor("edgecore", endpoint, 2)
+		require.ErrorContains(t, err, "no running container edgecore is found in container runtime endpoint")
+	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		endpoint := serveFakeRuntime(t, &fakeContainerServer{
+			containers: []*runtimeapi.Container{newFakeContainer("aaa", "edgecore"), newFakeContainer("bbb", "edgecore")},
+		})
+		_, err := NewContainerExecutor("edgecore", endpoint, 2)
+		require.ErrorContains(t, err, "2 running containers edgecore are found")
+		assert.ErrorContains(t, err, "aaa, bbb")
+	})
+
+	t.Run("no pid", func(t *testing.T) {
+		endpoint := serveFakeRuntime(t, &fakeContainerServer{containers: []*runtimeapi.Container{newFakeContainer("aaa", "edgecore")}})
+		_, err := NewContainerExecutor("edgecore", endpoint, 2)
+		require.EqualError(t, err, "get pid of container edgecore aaa failed, the container runtime reports no verbose info")
+	})
+}
+
+func TestContainerPid(t *testing.T) {
+	pid, err := containerPid(map[string]string{"info": `{"sandboxID":"abc","pid":1234}`})
+	require.NoError(t, err)
+	assert.Equal(t, 1234, pid)
+
+	_, err = containerPid(map[string]string{"info": `{"pid":0}`})
+	assert.EqualError(t, err, "the container runtime reports no pid in the verbose info")
+	_, err = containerPid(map[string]string{"info": `not json`})
+	assert.ErrorContains(t, err, "failed to unmarshal verbose info")
+}
+
+func TestContainerExecutor(t *testing.T) {
+	// the test process plays the main process of the container, whose root is the root of the host
+	ex := containerExecutor{name: "edgecore", id: "aaa", pid: os.Getpid()}
+	root := filepath.Join(procDir, strconv.Itoa(os.Getpid()), "root")
+
+	dir := t.TempDir()
+	config := filepath.Join(dir, "edgecore.yaml")
+	require.NoError(t, os.WriteFile(config, []byte("modules:\n  edged:\n    hostnameOverride: edge-node-1\n"), 0600))
+
+	assert.Equal(t, filepath.Join(root, config), ex.path(config))
+	assert.Equal(t, "-", ex.path("-"))
+	assert.True(t, ex.FileExists(config))
+	assert.False(t, ex.FileExists(filepath.Join(dir, "not-exist")))
+	assert.NoError(t, ex.FileReadable(config))
+	assert.Equal(t, "edge-node-1", ex.NodeName(config))
+	assert.Equal(t, "container edgecore aaa", ex.DetectionMode())
+
+	name, err := process.NewProcess(int32(os.Getpid()))
+	require.NoError(t, err)
+	procName, err := name.Name()
+	require.NoError(t, err)
+	running, err := ex.IsProcessRunning(procName)
+	require.NoError(t, err)
+	assert.True(t, running)
+	running, err = ex.IsProcessRunning("not-exist-process-name")
+	require.NoError(t, err)
+	assert.False(t, running)
+}
+
+func TestContainerExecutorHostEdgecoreConfig(t *testing.T) {
+	ex := containerExecutor{name: "edgecore", pid: 1234}
+	edgeconfig := newPlanEdgeCoreConfig()
+	edgeconfig.DataBase = nil
+	ex.hostEdgecoreConfig(edgeconfig)
+	assert.Equal(t, "/proc/1234/root/var/lib/kubeedge/edgecore.db", edgeconfig.DataBase.DataSource)
+	assert.Equal(t, "/proc/1234/root/var/lib/kubelet", edgeconfig.Modules.Edged.RootDirectory)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSSH puts a fake ssh client running script in the PATH
+func fakeSSH(t *testing.T, script string) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ssh"), []byte(script), 0700))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestNewExecutor(t *testing.T) {
+	assert.Equal(t, localExecutor{}, NewExecutor(""))
+	assert.Equal(t, sshExecutor{host: "user@edge1"}, NewExecutor("user@edge1"))
+}
+
+func TestSSHExecutor(t *testing.T) {
+	// the arguments are: -o BatchMode=yes <host> <command>, run the command with the local shell
+	fakeSSH(t, "#!/bin/sh\nshift 3\nexec sh -c \"$1\"\n")
+	ex := NewExecutor("user@edge1")
+
+	dir := t.TempDir()
+	config := filepath.Join(dir, "edge core.yaml")
+	require.NoError(t, os.WriteFile(config, []byte("modules:\n  edged:\n    hostnameOverride: edge-node-1\n"), 0600))
+
+	t.Run("file exists", func(t *testing.T) {
+		assert.True(t, ex.FileExists(config))
+		assert.False(t, ex.FileExists(filepath.Join(dir, "not-exist")))
+	})
+
+	t.Run("process is not running", func(t *testing.T) {
+		running, err := ex.IsProcessRunning("not-exist-process-name")
+		require.NoError(t, err)
+		assert.False(t, running)
+	})
+
+	t.Run("parse edgecore config", func(t *testing.T) {
+		edgeconfig, err := ex.ParseEdgecoreConfig(config)
+		require.NoError(t, err)
+		assert.Equal(t, "edge-node-1", edgeconfig.Modules.Edged.HostnameOverride)
+		assert.Equal(t, "edge-node-1", ex.NodeName(config))
+
+		_, err = ex.ParseEdgecoreConfig(filepath.Join(dir, "not-exist"))
+		assert.ErrorContains(t, err, "of remote node user@edge1 failed")
+	})
+
+	t.Run("node name from hostname", func(t *testing.T) {
+		hostname, err := os.Hostname()
+		require.NoError(t, err)
+		assert.Equal(t, hostname, ex.NodeName(filepath.Join(dir, "not-exist")))
+	})
+}
+
+func TestSSHExecutorFileReadable(t *testing.T) {
+	// the file without the read permission fails test -r but passes test -e
+	fakeSSH(t, "#!/bin/sh\ncase \"$4\" in\n\"test -r '/etc/denied'\") exit 1 ;;\n\"test -r '/etc/missing'\"|\"test -e '/etc/missing'\") exit 1 ;;\nesac\nexit 0\n")
+	ex := NewExecutor("user@edge1")
+
+	assert.NoError(t, ex.FileReadable("/etc/readable"))
+	err := ex.FileReadable("/etc/denied")
+	assert.ErrorIs(t, err, os.ErrPermission)
+	err = ex.FileReadable("/etc/missing")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, os.ErrPermission)
+}
+
+func TestSSHExecutorProcessUsers(t *testing.T) {
+	fakeSSH(t, "#!/bin/sh\necho root\necho edge\n")
+	users, err := NewExecutor("user@edge1").ProcessUsers("edgecore")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"root", "edge"}, users)
+}
+
+func TestCheckFileReadable(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "edgecore.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("modules: {}\n"), 0600))
+	assert.NoError(t, checkFileReadable(file))
+	assert.ErrorIs(t, checkFileReadable(filepath.Join(t.TempDir(), "not-exist")), os.ErrNotExist)
+}
+
+func TestSSHExecutorConnectFail(t *testing.T) {
+	fakeSSH(t, "#!/bin/sh\nexit 255\n")
+
+	ex := NewExecutor("user@edge1")
+	_, err := ex.IsProcessRunning("edgecore")
+	assert.ErrorContains(t, err, "get process edgecore of remote node user@edge1 failed")
+	assert.False(t, ex.FileExists("/etc/kubeedge/config/edgecore.yaml"))
+	assert.Equal(t, "user@edge1", ex.NodeName("/etc/kubeedge/config/edgecore.yaml"))
+}
+
+func TestShellQuote(t *testing.T) {
+	assert.Equal(t, `'/etc/kubeedge'`, shellQuote("/etc/kubeedge"))
+	assert.Equal(t, `'it'\''s'`, shellQuote("it's"))
+}
@@ -16,9 +16,12 @@ package debug
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/beego/beego/v2/client/orm"
 	"github.com/spf13/cobra"
@@ -39,6 +42,7 @@ import (
 	"github.com/kubeedge/kubeedge/common/constants"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
 	commonmsg "github.com/kubeedge/kubeedge/edge/pkg/common/message"
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtclient"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
 )
 
@@ -453,8 +457,44 @@ func isFileExist(path string) bool {
 	return err == nil || os.IsExist(err)
 }
 
-// InitDB Init DB info
+// registerModelOnce guards the registration of the model, which panics if it is registered twice
+var registerModelOnce sync.Once
+
+// dbQueryTimeout is the timeout of the queries of the edgecore database
+var dbQueryTimeout = 3 * time.Second
+
+// registeredDBs are the aliases of the databases registered by InitDB by their dataSource
+var (
+	registeredDBsLock sync.Mutex
+	registeredDBs     = map[string]string{}
+)
+
+// InitDB Init DB info, the registered db is reused if InitDB is called again with the same dataSource
 func InitDB(driverName, dbName, dataSource string) error {
+	registeredDBsLock.Lock()
+	defer registeredDBsLock.Unlock()
+
+	if alias, ok := registeredDBs[dataSource]; ok {
+		dbm.DBAccess = orm.NewOrmUsingDB(alias)
+		return nil
+	}
+	// the alias of an orm db can not be registered again, another dataSource is registered under a new alias
+	alias := dbName
+	for i := 1; ; i++ {
+		if _, err := orm.GetDB(alias); err != nil {
+			break
+		}
+		alias = fmt.Sprintf("%s-%d", dbName, i)
+	}
+	if err := initDB(driverName, alias, dataSource); err != nil {
+		return err
+	}
+	registeredDBs[dataSource] = alias
+	return nil
+}
+
+func initDB(driverName, dbName, dataSource string) error {
+
 	if err := orm.RegisterDriver(driverName, orm.DRSqlite); err != nil {
 		return fmt.Errorf("failed to register driver: %v ", err)
 	}
@@ -464,7 +504,10 @@ func InitDB(driverName, dbName, dataSource string) error {
 		dataSource); err != nil {
 		return fmt.Errorf("failed to register db: %v ", err)
 	}
-	orm.RegisterModel(new(dao.Meta))
+	registerModelOnce.Do(func() {
+		orm.RegisterModel(new(dao.Meta))
+		orm.RegisterModel(new(dtclient.Device), new(dtclient.DeviceAttr), new(dtclient.DeviceTwin))
+	})
 
 	// create orm
 	defer func() {
@@ -477,6 +520,48 @@ func InitDB(driverName, dbName, dataSource string) error {
 	return nil
 }
 
+// queryMeta queries the values of the meta matching the condition in the edgecore database until ctx is done
+func queryMeta(ctx context.Context, key, condition string) (*[]string, error) {
+	return waitDBQuery(ctx, func() (*[]string, error) {
+		return dao.QueryMetaWithContext(ctx, key, condition)
+	})
+}
+
+// queryAllMeta queries the meta matching the condition in the edgecore database until ctx is done
+func queryAllMeta(ctx context.Context, key, condition string) (*[]dao.Meta, error) {
+	return waitDBQuery(ctx, func() (*[]dao.Meta, error) {
+		return dao.QueryAllMetaWithContext(ctx, key, condition)
+	})
+}
+
+// waitDBQuery waits for the query of the edgecore database until ctx is done, a query blocked by a database
+// locked by a busy edgecore or a corrupted database fails once the deadline is exceeded.
+// The goroutine of a query timed out is left running until sqlite gives up at its busy timeout.
+func waitDBQuery[T any](ctx context.Context, query func() (T, error)) (T, error) {
+	type queryResult struct {
+		values T
+		err    error
+	}
+	// sqlite waits for the lock in its busy handler without checking ctx, so the query is not waited for once ctx is done
+	done := make(chan queryResult, 1)
+	go func() {
+		values, err := query()
+		done <- queryResult{values: values, err: err}
+	}()
+
+	var result queryResult
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		result.err = ctx.Err()
+	}
+	if errors.Is(result.err, context.DeadlineExceeded) {
+		var zero T
+		return zero, fmt.Errorf("database query timed out after %v, the database may be locked by a busy edgecore or corrupted", dbQueryTimeout)
+	}
+	return result.values, result.err
+}
+
 // IsExistName verify the filed in the resNames exists in the name
 func isExistName(resNames []string, name string) bool {
 	value := false
@@ -14,17 +14,20 @@ limitations under the License.
 package debug
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/agiledragon/gomonkey/v2"
 	"github.com/beego/beego/v2/client/orm"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +37,7 @@ import (
 
 	"github.com/kubeedge/beehive/pkg/core/model"
 	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
 )
 
@@ -1148,7 +1152,7 @@ func TestInitDB(t *testing.T) {
 			patches := tc.setupMocks()
 			defer patches.Reset()
 
-			err := InitDB("sqlite3", "default", "/path/to/db")
+			err := InitDB("sqlite3", "default", filepath.Join("/path/to", tc.name))
 
 			if tc.expectErr {
 				assert.Error(t, err)
@@ -1159,6 +1163,46 @@ func TestInitDB(t *testing.T) {
 	}
 }
 
+func TestInitDBTwice(t *testing.T) {
+	dataSource := filepath.Join(t.TempDir(), "edgecore.db")
+
+	assert.NoError(t, InitDB("sqlite3", "init-twice", dataSource))
+	assert.NotNil(t, dbm.DBAccess)
+
+	// the registered db is reused instead of being registered again
+	assert.NoError(t, InitDB("sqlite3", "init-twice", dataSource))
+	assert.NotNil(t, dbm.DBAccess)
+}
+
+func TestInitDBAnotherDataSource(t *testing.T) {
+	dir := t.TempDir()
+	newDB := func(name string) string {
+		dataSource := filepath.Join(dir, name+".db")
+		db, err := sql.Open("sqlite3", dataSource)
+		require.NoError(t, err)
+		defer db.Close()
+		_, err = db.Exec("CREATE TABLE meta (key TEXT PRIMARY KEY, type TEXT, value TEXT)")
+		require.NoError(t, err)
+		_, err = db.Exec("INSERT INTO meta VALUES (?, ?, '{}')", "default/pod/"+name, model.ResourceTypePod)
+		require.NoError(t, err)
+		return dataSource
+	}
+	first, second := newDB("first"), newDB("second")
+
+	for _, name := range []string{"first", "second", "first"} {
+		dataSource := first
+		if name == "second" {
+			dataSource = second
+		}
+		// the database of the dataSource is queried instead of the one registered first under the alias
+		require.NoError(t, InitDB("sqlite3", "init-another", dataSource))
+		metas, err := dao.QueryAllMeta("type", model.ResourceTypePod)
+		require.NoError(t, err)
+		require.Len(t, *metas, 1)
+		assert.Equal(t, "default/pod/"+name, (*metas)[0].Key)
+	}
+}
+
 func TestIsFileExist(t *testing.T) {
 	testCases := []struct {
 		name     string
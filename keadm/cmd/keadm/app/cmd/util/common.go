@@ -28,6 +28,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/blang/semver"
 	"github.com/spf13/pflag"
@@ -37,11 +38,13 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
 
 	apiconsts "github.com/kubeedge/api/apis/common/constants"
 	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
 	"github.com/kubeedge/kubeedge/common/constants"
 	types "github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/pkg/util/files"
 	pkgversion "github.com/kubeedge/kubeedge/pkg/version"
 )
 
@@ -123,21 +126,6 @@ func GetPackageManager() string {
 	}
 }
 
-// GetOSInterface helps in returning OS specific object which implements OSTypeInstaller interface.
-func GetOSInterface() types.OSTypeInstaller {
-	switch GetPackageManager() {
-	case APT:
-		return &DebOS{}
-	case YUM:
-		return &RpmOS{}
-	case PACMAN:
-		return &PacmanOS{}
-	default:
-		fmt.Println("Failed to detect supported package manager command(apt, yum, pacman), exit")
-		panic("Failed to detect supported package manager command(apt, yum, pacman), exit")
-	}
-}
-
 // RunningModule identifies cloudcore/edgecore running or not.
 func RunningModule() (types.ModuleRunning, error) {
 	osType := GetOSInterface()
@@ -548,14 +536,57 @@ func ExecShellFilter(c string) (string, error) {
 	return cmd.GetStdOut(), nil
 }
 
+// EdgecoreConfigStdin is the edgecore config path which is read from stdin instead of a file, e.g. -c -
+const EdgecoreConfigStdin = "-"
+
+var (
+	// stdin is where the edgecore config of EdgecoreConfigStdin is read from
+	stdin           io.Reader = os.Stdin
+	stdinConfigOnce sync.Once
+	stdinConfig     []byte
+	stdinConfigErr  error
+)
+
+// ParseEdgecoreConfig parses the edgecore config file with the defaults applied,
+// the config is read from stdin if edgecorePath is EdgecoreConfigStdin.
 func ParseEdgecoreConfig(edgecorePath string) (*v1alpha2.EdgeCoreConfig, error) {
 	edgeCoreConfig := v1alpha2.NewDefaultEdgeCoreConfig()
-	if err := edgeCoreConfig.Parse(edgecorePath); err != nil {
+	if edgecorePath != EdgecoreConfigStdin {
+		if err := edgeCoreConfig.Parse(edgecorePath); err != nil {
+			return nil, err
+		}
+		return edgeCoreConfig, nil
+	}
+	data, err := ReadEdgecoreConfig(edgecorePath)
+	if err != nil {
 		return nil, err
 	}
+	if err := yaml.Unmarshal(data, edgeCoreConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal configfile from stdin, err: %v", err)
+	}
 	return edgeCoreConfig, nil
 }
 
+// ReadEdgecoreConfig reads the edgecore config file, or stdin if edgecorePath is EdgecoreConfigStdin.
+// stdin is read once, the following reads return the same data since the config is parsed more than once.
+func ReadEdgecoreConfig(edgecorePath string) ([]byte, error) {
+	if edgecorePath != EdgecoreConfigStdin {
+		return os.ReadFile(edgecorePath)
+	}
+	stdinConfigOnce.Do(func() {
+		stdinConfig, stdinConfigErr = io.ReadAll(stdin)
+		if stdinConfigErr != nil {
+			stdinConfigErr = fmt.Errorf("failed to read configfile from stdin, err: %v", stdinConfigErr)
+		}
+	})
+	return stdinConfig, stdinConfigErr
+}
+
+// EdgecoreConfigExists returns whether the edgecore config file exists, the config of stdin always exists
+func EdgecoreConfigExists(edgecorePath string) bool {
+	return edgecorePath == EdgecoreConfigStdin || files.FileExists(edgecorePath)
+}
+
 // PrintFail prints fail
 func PrintFail(cmd string, s string) {
 	v := fmt.Sprintf("|%s %s failed|", s, cmd)
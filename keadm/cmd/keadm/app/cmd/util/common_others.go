@@ -33,6 +33,21 @@ import (
 	types "github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
 )
 
+// GetOSInterface helps in returning OS specific object which implements OSTypeInstaller interface.
+func GetOSInterface() types.OSTypeInstaller {
+	switch GetPackageManager() {
+	case APT:
+		return &DebOS{}
+	case YUM:
+		return &RpmOS{}
+	case PACMAN:
+		return &PacmanOS{}
+	default:
+		fmt.Println("Failed to detect supported package manager command(apt, yum, pacman), exit")
+		panic("Failed to detect supported package manager command(apt, yum, pacman), exit")
+	}
+}
+
 // IsKubeEdgeProcessRunning checks if the given process is running or not
 func IsKubeEdgeProcessRunning(proc string) (bool, error) {
 	procRunning := fmt.Sprintf("pidof %s 2>&1", proc)
@@ -25,6 +25,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/agiledragon/gomonkey/v2"
@@ -686,6 +687,28 @@ func TestParseEdgecoreConfig(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestParseEdgecoreConfigStdin(t *testing.T) {
+	stdin, stdinConfigOnce = strings.NewReader("database:\n  dataSource: /tmp/edgecore.db\n"), sync.Once{}
+	defer func() { stdin, stdinConfigOnce = os.Stdin, sync.Once{} }()
+
+	config, err := ParseEdgecoreConfig(EdgecoreConfigStdin)
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp/edgecore.db", config.DataBase.DataSource)
+	// the defaults are applied to the config of stdin as well
+	assert.NotNil(t, config.Modules.EdgeHub)
+
+	// stdin is read once, the config is parsed again from the same data
+	config, err = ParseEdgecoreConfig(EdgecoreConfigStdin)
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp/edgecore.db", config.DataBase.DataSource)
+	assert.True(t, EdgecoreConfigExists(EdgecoreConfigStdin))
+	assert.False(t, EdgecoreConfigExists(filepath.Join(t.TempDir(), "edgecore.yaml")))
+
+	stdin, stdinConfigOnce = strings.NewReader("database: ["), sync.Once{}
+	_, err = ParseEdgecoreConfig(EdgecoreConfigStdin)
+	assert.ErrorContains(t, err, "failed to unmarshal configfile from stdin")
+}
+
 func TestAskForConfirm(t *testing.T) {
 	patches := gomonkey.NewPatches()
 	defer patches.Reset()
@@ -49,6 +49,11 @@ DownloadFile %s %s
 `
 )
 
+// GetOSInterface returns the OSTypeInstaller of windows, there is no package manager to tell the installers apart
+func GetOSInterface() types.OSTypeInstaller {
+	return &WindowsOS{}
+}
+
 func IsServiceExist(service string) bool {
 	cmd := NewCommand(fmt.Sprintf("Get-Service '%s'", service))
 	_err := cmd.Exec()
@@ -64,6 +69,25 @@ func IsKubeEdgeProcessRunning(proc string) (bool, error) {
 	return false, nil
 }
 
+// IsServiceRunning checks if the windows service is running, IsServiceExist is true for a stopped service as well
+func IsServiceRunning(service string) bool {
+	cmd := NewCommand(fmt.Sprintf("(Get-Service '%s').Status", service))
+	if err := cmd.Exec(); err != nil {
+		return false
+	}
+	return strings.TrimSpace(cmd.GetStdOut()) == "Running"
+}
+
+// IsProcessListed checks if the process proc is listed by tasklist, e.g. edgecore started from a console instead of a service
+func IsProcessListed(proc string) (bool, error) {
+	cmd := NewCommand(fmt.Sprintf("tasklist /NH /FO CSV /FI 'IMAGENAME eq %s.exe'", proc))
+	if err := cmd.Exec(); err != nil {
+		return false, err
+	}
+	// tasklist prints an INFO line instead of the processes if none is matched
+	return strings.Contains(strings.ToLower(cmd.GetStdOut()), strings.ToLower(proc)+".exe"), nil
+}
+
 func HasSystemd() bool {
 	return false
 }
@@ -0,0 +1,76 @@
+//go:build windows
+
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"errors"
+
+	"github.com/blang/semver"
+
+	types "github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+)
+
+// WindowsOS struct objects shall have information of the tools version to be installed
+// on Hosts having Windows, edgecore runs as a service managed by nssm instead of systemd.
+// It implements OSTypeInstaller interface
+type WindowsOS struct {
+	KubeEdgeVersion semver.Version
+	IsEdgeNode      bool
+}
+
+// SetKubeEdgeVersion sets the KubeEdge version for the objects instance
+func (o *WindowsOS) SetKubeEdgeVersion(version semver.Version) {
+	o.KubeEdgeVersion = version
+}
+
+// InstallMQTT is not supported, there is no package of mosquitto to install on Windows
+func (o *WindowsOS) InstallMQTT() error {
+	return errors.New("installing MQTT is not supported on windows")
+}
+
+// IsK8SComponentInstalled checks if said K8S version is already installed in the host
+func (o *WindowsOS) IsK8SComponentInstalled(kubeConfig, master string) error {
+	return isK8SComponentInstalled(kubeConfig, master)
+}
+
+// InstallKubeEdge downloads the provided version of KubeEdge.
+// Untar's in the specified location c:/etc/kubeedge/ and then copies
+// the binary to excecutables' path (eg: c:/usr/local/bin)
+func (o *WindowsOS) InstallKubeEdge(options types.InstallOptions) error {
+	return installKubeEdge(options, o.KubeEdgeVersion)
+}
+
+// RunEdgeCore starts edgecore, which is started by the nssm service on Windows
+func (o *WindowsOS) RunEdgeCore() error {
+	return runEdgeCore()
+}
+
+// KillKubeEdgeBinary will search for KubeEdge process and forcefully kill it
+func (o *WindowsOS) KillKubeEdgeBinary(proc string) error {
+	return KillKubeEdgeBinary(proc)
+}
+
+// IsKubeEdgeProcessRunning checks if the service of the given process is running,
+// or the process is listed by tasklist if it is not started by the service
+func (o *WindowsOS) IsKubeEdgeProcessRunning(proc string) (bool, error) {
+	if IsServiceRunning(proc) {
+		return true, nil
+	}
+	return IsProcessListed(proc)
+}
@@ -17,14 +17,24 @@ limitations under the License.
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app"
 )
 
+// exitCoder is implemented by errors that specify the exit code of keadm, which are printed by the command
+type exitCoder interface {
+	ExitCode() int
+}
+
 func main() {
 	if err := app.Run(); err != nil {
+		var ec exitCoder
+		if errors.As(err, &ec) {
+			os.Exit(ec.ExitCode())
+		}
 		fmt.Println("execute keadm command failed: ", err)
 		os.Exit(1)
 	}